@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/capture"
+	"github.com/scaxe/scaxe-go/pkg/config"
+	_ "github.com/scaxe/scaxe-go/pkg/level/generator"
+	_ "github.com/scaxe/scaxe-go/pkg/level/generator/gorigional"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/raknet"
+	"github.com/scaxe/scaxe-go/pkg/server"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "print":
+		err = runPrint(os.Args[2:])
+	case "replay":
+		err = runReplay(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "capture:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println("Usage:")
+	fmt.Println("  capture print [options] FILE     Pretty-print a packet capture")
+	fmt.Println("  capture replay [options] FILE    Replay client packets against a server")
+	fmt.Println()
+	fmt.Println("Run 'capture print -h' or 'capture replay -h' for options.")
+}
+
+func readCapture(path string) (capture.Header, []capture.Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return capture.Header{}, nil, err
+	}
+	defer f.Close()
+
+	r, err := capture.NewReader(f)
+	if err != nil {
+		return capture.Header{}, nil, err
+	}
+	defer r.Close()
+
+	var records []capture.Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			fmt.Fprintln(os.Stderr, "capture: file is truncated, showing the records that were written")
+			break
+		}
+		if err != nil {
+			return r.Header, records, err
+		}
+		records = append(records, rec)
+	}
+	return r.Header, records, nil
+}
+
+func runPrint(args []string) error {
+	fs := flag.NewFlagSet("print", flag.ExitOnError)
+	dir := fs.String("dir", "", "Only show one direction: in (client to server) or out (server to client)")
+	filter := fs.String("filter", "", "Only show packets whose name contains this text")
+	dump := fs.Bool("hex", false, "Include a hex dump of each packet")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one capture file")
+	}
+
+	logger.SetOutput(io.Discard)
+	header, records, err := readCapture(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Player %s (%s), started %s, %d packets\n\n", header.Player, header.Address, header.Start.Format(time.RFC3339), len(records))
+
+	for _, rec := range records {
+		if (*dir == "in" && rec.Direction != capture.ClientToServer) || (*dir == "out" && rec.Direction != capture.ServerToClient) {
+			continue
+		}
+		name, fields := describe(rec.Data)
+		if *filter != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(*filter)) {
+			continue
+		}
+		fmt.Printf("+%9.3fs %s %-28s %s\n", rec.Time.Sub(header.Start).Seconds(), rec.Direction, name, fields)
+		if *dump {
+			fmt.Print(hex.Dump(rec.Data))
+		}
+	}
+	return nil
+}
+
+func describe(data []byte) (string, string) {
+	pk, err := capture.Decode(data)
+	if pk == nil {
+		return fmt.Sprintf("0x%02x", data[0]), fmt.Sprintf("(%v, %d bytes)", err, len(data))
+	}
+	fields := formatFields(reflect.ValueOf(pk))
+	if err != nil {
+		fields += fmt.Sprintf(" (decode error: %v)", err)
+	}
+	return pk.Name(), fields
+}
+
+func formatFields(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Sprintf("%v", v.Interface())
+	}
+
+	t := v.Type()
+	parts := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Name == "BasePacket" {
+			continue
+		}
+		fv := v.Field(i)
+		var value string
+		switch {
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8 && fv.Len() > 32:
+			value = fmt.Sprintf("<%d bytes>", fv.Len())
+		case fv.Kind() == reflect.Slice && fv.Len() > 16:
+			value = fmt.Sprintf("<%d items>", fv.Len())
+		case fv.Kind() == reflect.String && fv.Len() > 64:
+			value = fmt.Sprintf("%q...", fv.String()[:64])
+		default:
+			value = fmt.Sprintf("%+v", fv.Interface())
+		}
+		parts = append(parts, f.Name+"="+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+type replayConn interface {
+	SendPacket(data []byte)
+	Packets() <-chan []byte
+	Close()
+}
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	addr := fs.String("addr", "", "Server address to replay against; empty starts an in-process test server")
+	speed := fs.Float64("speed", 1, "Playback speed multiplier; 0 sends packets without delays")
+	wait := fs.Duration("wait", 2*time.Second, "How long to keep reading server packets after the last one is sent")
+	levelType := fs.String("level-type", "flat", "Generator for the in-process test server")
+	verbose := fs.Bool("v", false, "Print every packet the server sends back")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one capture file")
+	}
+
+	header, records, err := readCapture(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	logger.SetOutput(io.Discard)
+	if *verbose {
+		logger.SetOutput(os.Stderr)
+	}
+
+	var conn replayConn
+	if *addr != "" {
+		c, err := raknet.Dial(*addr, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		conn = c
+	} else {
+		srv, cleanup, err := startTestServer(*levelType)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		c, err := srv.Network.(*network.MemoryTransport).Dial()
+		if err != nil {
+			return err
+		}
+		conn = c
+	}
+	defer conn.Close()
+
+	received := make(map[string]int)
+	var mu sync.Mutex
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case data := <-conn.Packets():
+				for _, pk := range capture.Split(data) {
+					name, fields := describe(pk)
+					mu.Lock()
+					received[name]++
+					mu.Unlock()
+					if *verbose {
+						fmt.Printf("S->C %-28s %s\n", name, fields)
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	fmt.Printf("Replaying %s (%s)\n", header.Player, fs.Arg(0))
+	sent := 0
+	var first time.Time
+	begin := time.Now()
+	for _, rec := range records {
+		if rec.Direction != capture.ClientToServer {
+			continue
+		}
+		if first.IsZero() {
+			first = rec.Time
+		}
+		if *speed > 0 {
+			due := begin.Add(time.Duration(float64(rec.Time.Sub(first)) / *speed))
+			time.Sleep(time.Until(due))
+		}
+		if *verbose {
+			name, fields := describe(rec.Data)
+			fmt.Printf("C->S %-28s %s\n", name, fields)
+		}
+		conn.SendPacket(rec.Data)
+		sent++
+	}
+	time.Sleep(*wait)
+	close(done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, 0, len(received))
+	total := 0
+	for name, n := range received {
+		names = append(names, name)
+		total += n
+	}
+	sort.Strings(names)
+	fmt.Printf("\nSent %d packets, received %d\n", sent, total)
+	for _, name := range names {
+		fmt.Printf("  %-28s %d\n", name, received[name])
+	}
+	return nil
+}
+
+func startTestServer(levelType string) (*server.Server, func(), error) {
+	dir, err := os.MkdirTemp("", "scaxe-replay-")
+	if err != nil {
+		return nil, nil, err
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		return nil, nil, err
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.NetworkTransport = network.TransportMemory
+	cfg.LevelType = levelType
+	cfg.EnableQuery = false
+	cfg.BackupInterval = 0
+
+	srv := server.NewServer(cfg)
+	cleanup := func() {
+		srv.Stop()
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+	if err := srv.Start(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return srv, cleanup, nil
+}
@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"runtime/debug"
+	"strconv"
 	"syscall"
 
 	"github.com/scaxe/scaxe-go/internal/eula"
@@ -16,6 +18,7 @@ import (
 	_ "github.com/scaxe/scaxe-go/pkg/level/generator"
 	_ "github.com/scaxe/scaxe-go/pkg/level/generator/gorigional"
 	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/proxy"
 	"github.com/scaxe/scaxe-go/pkg/server"
 )
 
@@ -94,6 +97,12 @@ func main() {
 		"maxPlayers", cfg.MaxPlayers,
 		"gamemode", cfg.Gamemode)
 
+	if cfg.ProxyMode {
+		runProxy(cfg)
+		logger.Close()
+		return
+	}
+
 	srv := server.NewServer(cfg)
 
 	if err := srv.Start(); err != nil {
@@ -127,6 +136,47 @@ func main() {
 	logger.Close()
 }
 
+// runProxy starts a proxy in front of the servers listed in proxy-servers
+// instead of a game server.
+func runProxy(cfg *config.ServerConfig) {
+	backends, err := proxy.ParseBackends(cfg.ProxyServers)
+	if err != nil {
+		logger.Error("Failed to read proxy servers", "error", err)
+		os.Exit(1)
+	}
+
+	p, err := proxy.New(proxy.Config{
+		Address:        net.JoinHostPort(cfg.ServerIP, strconv.Itoa(cfg.ServerPort)),
+		Transport:      cfg.NetworkTransport,
+		Secret:         cfg.ProxySecret,
+		Backends:       backends,
+		MOTD:           cfg.MOTD,
+		MaxPlayers:     cfg.MaxPlayers,
+		DuplicateLogin: cfg.DuplicateLogin,
+	})
+	if err != nil {
+		logger.Error("Failed to create proxy", "error", err)
+		os.Exit(1)
+	}
+	if err := p.Start(); err != nil {
+		logger.Error("Failed to start proxy", "error", err)
+		os.Exit(1)
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			p.HandleConsoleCommand(scanner.Text())
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-sigChan
+	logger.Server("Received signal", "signal", sig.String())
+	p.Stop()
+}
+
 func printHelp() {
 	fmt.Println(version.Full())
 	fmt.Println()
@@ -2,8 +2,6 @@ module github.com/scaxe/scaxe-go
 
 go 1.22
 
-require github.com/sandertv/go-raknet v1.15.0
-
 require github.com/google/uuid v1.6.0
 
 require (
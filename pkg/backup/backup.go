@@ -0,0 +1,378 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/scheduler"
+)
+
+const (
+	timeLayout = "20060102-150405"
+	archiveExt = ".tar.gz"
+)
+
+var (
+	ErrInProgress    = errors.New("a backup of this world is already running")
+	ErrUnknownBackup = errors.New("unknown backup")
+	ErrUnknownWorld  = errors.New("unknown world")
+)
+
+type Backup struct {
+	Name    string
+	World   string
+	Created time.Time
+	Size    int64
+	Path    string
+}
+
+type Manager struct {
+	mu        sync.Mutex
+	dir       string
+	worldsDir string
+	retention int
+	active    map[string]bool
+}
+
+func NewManager(dir, worldsDir string, retention int) *Manager {
+	return &Manager{
+		dir:       dir,
+		worldsDir: worldsDir,
+		retention: retention,
+		active:    make(map[string]bool),
+	}
+}
+
+func (m *Manager) GetDir() string {
+	return m.dir
+}
+
+func (m *Manager) SetRetention(retention int) {
+	m.mu.Lock()
+	m.retention = retention
+	m.mu.Unlock()
+}
+
+type createResult struct {
+	backup *Backup
+	err    error
+}
+
+// Create saves lvl, then copies and archives it in the background. It must
+// be called on the main thread, and done runs there once the backup is
+// written.
+func (m *Manager) Create(lvl *level.Level, done func(*Backup, error)) error {
+	m.mu.Lock()
+	if m.active[lvl.Name] {
+		m.mu.Unlock()
+		return ErrInProgress
+	}
+	m.active[lvl.Name] = true
+	m.mu.Unlock()
+
+	created := time.Now()
+	name := lvl.Name + "-" + created.Format(timeLayout)
+	staging := filepath.Join(m.dir, ".staging", name)
+
+	lvl.Save()
+	scheduler.RunAsync(func() interface{} {
+		defer os.RemoveAll(staging)
+		if err := lvl.Snapshot(func(path string) error { return copyDir(path, staging) }); err != nil {
+			return createResult{err: err}
+		}
+		b, err := m.archive(staging, name, lvl.Name, created)
+		return createResult{backup: b, err: err}
+	}, func(result interface{}) {
+		res := result.(createResult)
+		m.mu.Lock()
+		delete(m.active, lvl.Name)
+		m.mu.Unlock()
+
+		if res.err != nil {
+			logger.Error("Backup failed", "world", lvl.Name, "error", res.err)
+		} else {
+			logger.Info("Backup created", "world", lvl.Name, "name", res.backup.Name, "size", res.backup.Size)
+			if pruned := m.Prune(lvl.Name); pruned > 0 {
+				logger.Info("Pruned old backups", "world", lvl.Name, "count", pruned)
+			}
+		}
+		if done != nil {
+			done(res.backup, res.err)
+		}
+	})
+	return nil
+}
+
+func (m *Manager) archive(src, name, world string, created time.Time) (*Backup, error) {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(m.dir, name+archiveExt)
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil || file == src {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		in, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		_, err = io.Copy(tw, in)
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Backup{Name: name, World: world, Created: created, Size: info.Size(), Path: path}, nil
+}
+
+func (m *Manager) List(world string) []*Backup {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return nil
+	}
+	var backups []*Backup
+	for _, e := range entries {
+		b := m.parse(e)
+		if b != nil && (world == "" || b.World == world) {
+			backups = append(backups, b)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Created.After(backups[j].Created) })
+	return backups
+}
+
+func (m *Manager) parse(e os.DirEntry) *Backup {
+	name := strings.TrimSuffix(e.Name(), archiveExt)
+	if e.IsDir() || name == e.Name() || len(name) <= len(timeLayout)+1 {
+		return nil
+	}
+	split := len(name) - len(timeLayout) - 1
+	if name[split] != '-' {
+		return nil
+	}
+	created, err := time.ParseInLocation(timeLayout, name[split+1:], time.Local)
+	if err != nil {
+		return nil
+	}
+	b := &Backup{Name: name, World: name[:split], Created: created, Path: filepath.Join(m.dir, e.Name())}
+	if info, err := e.Info(); err == nil {
+		b.Size = info.Size()
+	}
+	return b
+}
+
+func (m *Manager) Find(name string) *Backup {
+	name = strings.TrimSuffix(name, archiveExt)
+	for _, b := range m.List("") {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+func (m *Manager) Prune(world string) int {
+	m.mu.Lock()
+	retention := m.retention
+	m.mu.Unlock()
+	if retention <= 0 {
+		return 0
+	}
+
+	pruned := 0
+	backups := m.List(world)
+	for i := retention; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil {
+			logger.Warn("Failed to remove old backup", "name", backups[i].Name, "error", err)
+			continue
+		}
+		pruned++
+	}
+	return pruned
+}
+
+// checkWorld only lets a backup restore over its own world or an existing
+// world directory, so the name can never point outside the worlds folder.
+func (m *Manager) checkWorld(b *Backup, world string) error {
+	if world == "" || world == "." || strings.ContainsAny(world, `/\`) || strings.Contains(world, "..") {
+		return fmt.Errorf("%w: %s", ErrUnknownWorld, world)
+	}
+	if world == b.World {
+		return nil
+	}
+	if info, err := os.Stat(filepath.Join(m.worldsDir, world)); err == nil && info.IsDir() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownWorld, world)
+}
+
+func (m *Manager) Restore(b *Backup, world string) error {
+	if err := m.checkWorld(b, world); err != nil {
+		return err
+	}
+	dest := filepath.Join(m.worldsDir, world)
+	tmp := dest + ".restoring"
+	old := dest + ".old"
+
+	os.RemoveAll(tmp)
+	if err := extract(b.Path, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+
+	os.RemoveAll(old)
+	if _, err := os.Stat(dest); err == nil {
+		if err := os.Rename(dest, old); err != nil {
+			os.RemoveAll(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Rename(old, dest)
+		return err
+	}
+	os.RemoveAll(old)
+
+	logger.Info("Restored backup", "name", b.Name, "world", world)
+	return nil
+}
+
+func extract(archive, dest string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in backup: %s", hdr.Name)
+		}
+		target := filepath.Join(dest, rel)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		in, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		return writeFile(target, in)
+	})
+}
+
+func writeFile(path string, r io.Reader) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
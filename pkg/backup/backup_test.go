@@ -0,0 +1,29 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreRejectsUnknownWorlds(t *testing.T) {
+	root := t.TempDir()
+	worlds := filepath.Join(root, "worlds")
+	if err := os.MkdirAll(filepath.Join(worlds, "nether"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(filepath.Join(root, "backups"), worlds, 0)
+	b := &Backup{Name: "world-20240101-000000", World: "world", Path: filepath.Join(root, "missing.tar.gz")}
+
+	for _, world := range []string{"", ".", "..", "../outside", "nested/world", `nested\world`, "unknown"} {
+		if err := m.Restore(b, world); !errors.Is(err, ErrUnknownWorld) {
+			t.Errorf("Restore(%q) = %v, want ErrUnknownWorld", world, err)
+		}
+	}
+	for _, world := range []string{"world", "nether"} {
+		if err := m.checkWorld(b, world); err != nil {
+			t.Errorf("checkWorld(%q) = %v", world, err)
+		}
+	}
+}
@@ -98,3 +98,19 @@ func CraftingTableOnActivate() ActivateResult {
 		InventoryType: InventoryTypeCrafting,
 	}
 }
+
+func EnchantingTableOnActivate() ActivateResult {
+	return ActivateResult{
+		Handled:       true,
+		OpenInventory: true,
+		InventoryType: InventoryTypeEnchant,
+	}
+}
+
+func AnvilOnActivate() ActivateResult {
+	return ActivateResult{
+		Handled:       true,
+		OpenInventory: true,
+		InventoryType: InventoryTypeAnvil,
+	}
+}
@@ -4,10 +4,81 @@ import (
 	"math/rand"
 
 	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
 )
 
 func GetDrops(id uint8, meta uint8, tool item.Item) []item.Item {
+	if enchantment.HasSilkTouch(tool) {
+		if drop, ok := silkTouchDrop(id, meta); ok {
+			return []item.Item{drop}
+		}
+	}
+	drops := baseDrops(id, meta)
+	if fortune := enchantment.FortuneLevel(tool); fortune > 0 {
+		applyFortune(id, fortune, drops)
+	}
+	return drops
+}
+
+func GetDropExperience(id uint8, meta uint8, tool item.Item) int {
+	if len(GetDrops(id, meta, tool)) == 0 {
+		return 0
+	}
+	min, max := 0, 0
+	switch id {
+	case COAL_ORE:
+		min, max = 0, 2
+	case DIAMOND_ORE, EMERALD_ORE:
+		min, max = 3, 7
+	case LAPIS_ORE, NETHER_QUARTZ_ORE:
+		min, max = 2, 5
+	case REDSTONE_ORE, GLOWING_REDSTONE_ORE:
+		min, max = 1, 5
+	case MONSTER_SPAWNER:
+		return 15 + rand.Intn(15) + rand.Intn(15)
+	default:
+		return 0
+	}
+	if enchantment.HasSilkTouch(tool) {
+		return 0
+	}
+	return min + rand.Intn(max-min+1)
+}
+
+func silkTouchDrop(id uint8, meta uint8) (item.Item, bool) {
+	switch id {
+	case GRASS, STONE, COAL_ORE, DIAMOND_ORE, LAPIS_ORE, REDSTONE_ORE,
+		GLASS, GLASS_PANE, CLAY_BLOCK, GLOWSTONE_BLOCK:
+		return item.NewItem(int(id), 0, 1), true
+	case GLOWING_REDSTONE_ORE:
+		return item.NewItem(REDSTONE_ORE, 0, 1), true
+	case LEAVES:
+		return item.NewItem(LEAVES, int(meta&0x03), 1), true
+	}
+	return item.Item{}, false
+}
+
+func applyFortune(id uint8, level int, drops []item.Item) {
+	switch id {
+	case COAL_ORE, DIAMOND_ORE, LAPIS_ORE:
+		for i := range drops {
+			drops[i].Count *= enchantment.FortuneMultiplier(level)
+		}
+	case REDSTONE_ORE, GLOWING_REDSTONE_ORE:
+		for i := range drops {
+			drops[i].Count += rand.Intn(level + 1)
+		}
+	case GLOWSTONE_BLOCK:
+		for i := range drops {
+			drops[i].Count += rand.Intn(level + 1)
+			if drops[i].Count > 4 {
+				drops[i].Count = 4
+			}
+		}
+	}
+}
 
+func baseDrops(id uint8, meta uint8) []item.Item {
 	switch id {
 	case AIR, WATER, STILL_WATER, LAVA, STILL_LAVA, BEDROCK:
 		return []item.Item{}
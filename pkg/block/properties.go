@@ -1,5 +1,7 @@
 package block
 
+import "strings"
+
 type BlockProperty struct {
 	ID               uint8
 	Name             string
@@ -245,3 +247,18 @@ func CanBeFuel(id uint8) bool {
 func GetFuelTime(id uint8) int {
 	return GetProperty(id).FuelTime
 }
+
+func ByName(name string) (uint8, bool) {
+	name = normalizeName(name)
+	for id := range vanillaBlocks {
+		if vanillaBlocks[id].Name != "" && normalizeName(vanillaBlocks[id].Name) == name {
+			return uint8(id), true
+		}
+	}
+	return 0, false
+}
+
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimPrefix(name, "minecraft:"))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
@@ -0,0 +1,233 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+// Capture files are a gzip stream of a small header followed by one record
+// per game packet. Batches are expanded before writing, so every record
+// holds a single packet starting with its ID byte.
+
+const (
+	Extension = ".scxcap"
+	version   = 1
+
+	maxRecordSize = 8 << 20
+)
+
+var magic = []byte("SCXCAP")
+
+var ErrBadMagic = errors.New("capture: not a packet capture file")
+
+type Direction byte
+
+const (
+	ClientToServer Direction = iota
+	ServerToClient
+)
+
+func (d Direction) String() string {
+	if d == ClientToServer {
+		return "C->S"
+	}
+	return "S->C"
+}
+
+type Header struct {
+	Player  string
+	Address string
+	Start   time.Time
+}
+
+type Record struct {
+	Time      time.Time
+	Direction Direction
+	Data      []byte
+}
+
+type Writer struct {
+	gz    *gzip.Writer
+	start time.Time
+}
+
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	buf := new(bytes.Buffer)
+	buf.Write(magic)
+	buf.WriteByte(version)
+	writeString(buf, header.Player)
+	writeString(buf, header.Address)
+	binary.Write(buf, binary.BigEndian, header.Start.UnixNano())
+	if _, err := gz.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return &Writer{gz: gz, start: header.Start}, nil
+}
+
+func (w *Writer) Write(rec Record) error {
+	buf := make([]byte, 0, 1+2*binary.MaxVarintLen64+len(rec.Data))
+	buf = append(buf, byte(rec.Direction))
+	buf = binary.AppendUvarint(buf, uint64(rec.Time.Sub(w.start).Microseconds()))
+	buf = binary.AppendUvarint(buf, uint64(len(rec.Data)))
+	buf = append(buf, rec.Data...)
+	_, err := w.gz.Write(buf)
+	return err
+}
+
+func (w *Writer) Flush() error {
+	return w.gz.Flush()
+}
+
+func (w *Writer) Close() error {
+	return w.gz.Close()
+}
+
+type Reader struct {
+	r      *bufio.Reader
+	gz     *gzip.Reader
+	Header Header
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrBadMagic
+	}
+	br := bufio.NewReader(gz)
+
+	head := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(br, head); err != nil || !bytes.Equal(head[:len(magic)], magic) {
+		return nil, ErrBadMagic
+	}
+	if head[len(magic)] != version {
+		return nil, fmt.Errorf("capture: unsupported version %d", head[len(magic)])
+	}
+
+	rd := &Reader{r: br, gz: gz}
+	if rd.Header.Player, err = readString(br); err != nil {
+		return nil, err
+	}
+	if rd.Header.Address, err = readString(br); err != nil {
+		return nil, err
+	}
+	var start int64
+	if err := binary.Read(br, binary.BigEndian, &start); err != nil {
+		return nil, err
+	}
+	rd.Header.Start = time.Unix(0, start)
+	return rd, nil
+}
+
+// Next returns io.EOF once all records have been read. Captures from a
+// server that did not shut down cleanly end with io.ErrUnexpectedEOF.
+func (r *Reader) Next() (Record, error) {
+	dir, err := r.r.ReadByte()
+	if err != nil {
+		return Record{}, err
+	}
+	offset, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return Record{}, unexpected(err)
+	}
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return Record{}, unexpected(err)
+	}
+	if size > maxRecordSize {
+		return Record{}, fmt.Errorf("capture: record of %d bytes is too large", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return Record{}, unexpected(err)
+	}
+	return Record{
+		Time:      r.Header.Start.Add(time.Duration(offset) * time.Microsecond),
+		Direction: Direction(dir),
+		Data:      data,
+	}, nil
+}
+
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
+
+// Split expands a batch into the raw packets it carries. Any other packet is
+// returned as is.
+func Split(data []byte) [][]byte {
+	if len(data) == 0 || data[0] != protocol.IDBatch {
+		return [][]byte{data}
+	}
+	batch := protocol.NewBatchPacket()
+	if err := batch.Decode(protocol.NewBinaryStreamFromBytes(data[1:])); err != nil {
+		return [][]byte{data}
+	}
+	raw, err := batch.Decompress()
+	if err != nil {
+		return [][]byte{data}
+	}
+
+	var packets [][]byte
+	for len(raw) >= 4 {
+		n := binary.BigEndian.Uint32(raw)
+		if uint64(n) > uint64(len(raw)-4) {
+			break
+		}
+		if n > 0 {
+			packets = append(packets, raw[4:4+n])
+		}
+		raw = raw[4+n:]
+	}
+	return packets
+}
+
+func Decode(data []byte) (protocol.DataPacket, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty packet")
+	}
+	constructor, ok := protocol.PacketPool[data[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown packet 0x%02x", data[0])
+	}
+	pk := constructor()
+	if err := pk.Decode(protocol.NewBinaryStreamFromBytes(data[1:])); err != nil {
+		return pk, err
+	}
+	return pk, nil
+}
+
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	var n [binary.MaxVarintLen64]byte
+	buf.Write(n[:binary.PutUvarint(n[:], uint64(len(s)))])
+	buf.WriteString(s)
+}
+
+func readString(r *bufio.Reader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	if n > 1024 {
+		return "", ErrBadMagic
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
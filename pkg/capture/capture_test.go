@@ -0,0 +1,72 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+func TestWriteRead(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	text := protocol.NewTextPacket()
+	text.TextType = protocol.TextTypeChat
+	text.SourceName = "Alice"
+	text.Message = "hi"
+	stream := protocol.NewBinaryStream()
+	text.Encode(stream)
+	raw := stream.Bytes()
+
+	batch := protocol.NewBatchPacket()
+	payload, err := protocol.CreateBatch([]protocol.DataPacket{text, text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch.Payload = payload
+	stream = protocol.NewBinaryStream()
+	batch.Encode(stream)
+	packets := Split(stream.Bytes())
+	if len(packets) != 2 || !bytes.Equal(packets[0], raw) {
+		t.Fatalf("Split returned %d packets, want 2 copies of the text packet", len(packets))
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Header{Player: "Alice", Address: "127.0.0.1:1234", Start: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(Record{Time: start.Add(1500 * time.Microsecond), Direction: ClientToServer, Data: raw})
+	w.Write(Record{Time: start.Add(time.Second), Direction: ServerToClient, Data: packets[1]})
+	w.Close()
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Header.Player != "Alice" || r.Header.Address != "127.0.0.1:1234" || !r.Header.Start.Equal(start) {
+		t.Fatalf("header = %+v", r.Header)
+	}
+	first, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Direction != ClientToServer || first.Time.Sub(start) != 1500*time.Microsecond || !bytes.Equal(first.Data, raw) {
+		t.Fatalf("first record = %+v", first)
+	}
+	second, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := Decode(second.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pk.(*protocol.TextPacket); second.Direction != ServerToClient || got.Message != "hi" {
+		t.Fatalf("second record = %+v", got)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("Next after last record = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,115 @@
+package capture
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/network"
+)
+
+var (
+	ErrAlreadyRecording = errors.New("capture already running")
+	ErrNotRecording     = errors.New("no capture running")
+)
+
+// Conn wraps a player's connection so that its traffic can be recorded on
+// demand. Outgoing packets are seen through SendPacket; the server reports
+// incoming ones with Received.
+type Conn struct {
+	network.Conn
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *Writer
+	path   string
+}
+
+func Wrap(conn network.Conn) *Conn {
+	return &Conn{Conn: conn}
+}
+
+func (c *Conn) Start(dir, player string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writer != nil {
+		return c.path, ErrAlreadyRecording
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	path := filepath.Join(dir, player+"-"+now.Format("20060102-150405")+Extension)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	writer, err := NewWriter(file, Header{Player: player, Address: c.Address(), Start: now})
+	if err != nil {
+		file.Close()
+		return "", err
+	}
+
+	c.file, c.writer, c.path = file, writer, path
+	logger.Info("Packet capture started", "player", player, "file", path)
+	return path, nil
+}
+
+func (c *Conn) Stop() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writer == nil {
+		return "", ErrNotRecording
+	}
+	err := c.writer.Close()
+	if cerr := c.file.Close(); err == nil {
+		err = cerr
+	}
+	path := c.path
+	c.file, c.writer, c.path = nil, nil, ""
+	logger.Info("Packet capture stopped", "file", path)
+	return path, err
+}
+
+func (c *Conn) Recording() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writer != nil
+}
+
+func (c *Conn) Received(data []byte) {
+	c.record(ClientToServer, data)
+}
+
+func (c *Conn) SendPacket(data []byte) {
+	c.record(ServerToClient, data)
+	c.Conn.SendPacket(data)
+}
+
+func (c *Conn) Close() {
+	if c.Recording() {
+		c.Stop()
+	}
+	c.Conn.Close()
+}
+
+func (c *Conn) record(dir Direction, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writer == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, pk := range Split(data) {
+		if err := c.writer.Write(Record{Time: now, Direction: dir, Data: pk}); err != nil {
+			logger.Error("Packet capture write failed", "file", c.path, "error", err)
+			return
+		}
+	}
+	c.writer.Flush()
+}
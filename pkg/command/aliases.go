@@ -0,0 +1,107 @@
+package command
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const defaultCommandsConfig = `# Command aliases. Each alias runs one or more command lines.
+# $1..$9 are replaced by the alias arguments and $* by all of them;
+# lines without placeholders get the arguments appended.
+aliases:
+  gm: [gamemode]
+  w: [tell]
+  msg: [tell]
+  unban: [pardon]
+  unban-ip: [pardon-ip]
+  "?": [help]
+`
+
+// maxAliasDepth bounds how deeply aliases may expand into other aliases,
+// so a cycle such as a -> b -> a stops instead of overflowing the stack.
+const maxAliasDepth = 8
+
+type commandsConfig struct {
+	Aliases map[string][]string `yaml:"aliases"`
+}
+
+type aliasCommand struct {
+	BaseCommand
+	lines []string
+	cmds  *CommandMap
+}
+
+func (c *aliasCommand) Execute(sender CommandSender, args []string) bool {
+	if atomic.AddInt32(&c.cmds.aliasDepth, 1) > maxAliasDepth {
+		atomic.AddInt32(&c.cmds.aliasDepth, -1)
+		sender.SendMessage("§cAlias /" + c.Name + " expands too deeply")
+		return false
+	}
+	defer atomic.AddInt32(&c.cmds.aliasDepth, -1)
+	ok := true
+	for _, line := range c.lines {
+		if !c.cmds.Dispatch(sender, expandAlias(line, args)) {
+			sender.SendMessage("§cUnknown command in alias /" + c.Name + ": " + line)
+			ok = false
+		}
+	}
+	return ok
+}
+
+func expandAlias(line string, args []string) string {
+	if !strings.Contains(line, "$") {
+		return strings.TrimSpace(line + " " + strings.Join(args, " "))
+	}
+	line = strings.ReplaceAll(line, "$*", strings.Join(args, " "))
+	for i := 9; i >= 1; i-- {
+		value := ""
+		if i <= len(args) {
+			value = args[i-1]
+		}
+		line = strings.ReplaceAll(line, "$"+strconv.Itoa(i), value)
+	}
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func (m *CommandMap) LoadAliases(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data = []byte(defaultCommandsConfig)
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		return err
+	}
+	var cfg commandsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	for alias, lines := range cfg.Aliases {
+		alias = strings.ToLower(alias)
+		if m.GetCommand(alias) != nil {
+			logger.Warn("Alias shadows a registered command", "alias", alias)
+			continue
+		}
+		if len(lines) == 1 && !strings.ContainsAny(lines[0], " $") {
+			if m.GetCommand(lines[0]) == nil {
+				logger.Warn("Alias targets unknown command", "alias", alias, "command", lines[0])
+				continue
+			}
+			m.aliases[alias] = m.GetCommand(lines[0]).GetName()
+			continue
+		}
+		m.commands[alias] = &aliasCommand{
+			BaseCommand: BaseCommand{Name: alias, Description: "Alias for " + strings.Join(lines, "; ")},
+			lines:       lines,
+			cmds:        m,
+		}
+	}
+	logger.Info("Loaded command aliases", "count", len(cfg.Aliases))
+	return nil
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type recordingSender struct {
+	messages []string
+}
+
+func (s *recordingSender) SendMessage(message string)     { s.messages = append(s.messages, message) }
+func (s *recordingSender) GetName() string                { return "tester" }
+func (s *recordingSender) IsOp() bool                     { return true }
+func (s *recordingSender) HasPermission(name string) bool { return true }
+
+type countingCommand struct {
+	BaseCommand
+	calls int
+}
+
+func (c *countingCommand) Execute(sender CommandSender, args []string) bool {
+	c.calls++
+	return true
+}
+
+func TestLoadAliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.yml")
+	config := "aliases:\n  say: [list]\n  loop-a: [loop-b $*]\n  loop-b: [loop-a $*]\n  twice: [list, list]\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewCommandMap()
+	say := &countingCommand{BaseCommand: BaseCommand{Name: "say"}}
+	list := &countingCommand{BaseCommand: BaseCommand{Name: "list"}}
+	m.Register(say)
+	m.Register(list)
+	if err := m.LoadAliases(path); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &recordingSender{}
+	m.Dispatch(sender, "say hi")
+	if say.calls != 1 || list.calls != 0 {
+		t.Fatalf("alias shadowed /say: say=%d list=%d", say.calls, list.calls)
+	}
+
+	m.Dispatch(sender, "twice")
+	if list.calls != 2 {
+		t.Fatalf("/twice ran list %d times, want 2", list.calls)
+	}
+
+	if m.Dispatch(sender, "loop-a") {
+		t.Fatal("recursive alias reported success")
+	}
+	if !strings.Contains(strings.Join(sender.messages, "\n"), "expands too deeply") {
+		t.Fatalf("no recursion error in %q", sender.messages)
+	}
+	if m.aliasDepth != 0 {
+		t.Fatalf("aliasDepth = %d after dispatch, want 0", m.aliasDepth)
+	}
+}
@@ -1,6 +1,11 @@
 package command
 
-import "github.com/scaxe/scaxe-go/pkg/event"
+import (
+	"sort"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/event"
+)
 
 type CommandSender interface {
 	SendMessage(message string)
@@ -22,11 +27,31 @@ type Command interface {
 	GetPermission() string
 }
 
+type Runner interface {
+	GetOverloads() []Overload
+	Run(ctx *Context) bool
+}
+
+type Context struct {
+	Sender   CommandSender
+	Label    string
+	Args     *Args
+	Overload int
+	Map      *CommandMap
+}
+
+func (c *Context) Error(message string) bool {
+	c.Sender.SendMessage("§c" + message)
+	return true
+}
+
 type BaseCommand struct {
 	Name        string
 	Description string
 	Usage       string
 	Permission  string
+	Aliases     []string
+	Overloads   []Overload
 }
 
 func (c *BaseCommand) GetName() string {
@@ -38,37 +63,94 @@ func (c *BaseCommand) GetDescription() string {
 }
 
 func (c *BaseCommand) GetUsage() string {
-	return c.Usage
+	if c.Usage != "" || len(c.Overloads) == 0 {
+		return c.Usage
+	}
+	usages := make([]string, len(c.Overloads))
+	for i, o := range c.Overloads {
+		usages[i] = strings.TrimSpace("/" + c.Name + " " + o.String())
+	}
+	return strings.Join(usages, "\n")
 }
 
 func (c *BaseCommand) GetPermission() string {
 	return c.Permission
 }
 
+func (c *BaseCommand) GetAliases() []string {
+	return c.Aliases
+}
+
+func (c *BaseCommand) GetOverloads() []Overload {
+	return c.Overloads
+}
+
+func (c *BaseCommand) Execute(sender CommandSender, args []string) bool {
+	sender.SendMessage("§cUsage: " + c.GetUsage())
+	return false
+}
+
 type CommandMap struct {
-	commands map[string]Command
+	commands   map[string]Command
+	aliases    map[string]string
+	targets    TargetProvider
+	aliasDepth int32
 }
 
 func NewCommandMap() *CommandMap {
 	return &CommandMap{
 		commands: make(map[string]Command),
+		aliases:  make(map[string]string),
 	}
 }
 
 func (m *CommandMap) Register(cmd Command) {
 	m.commands[cmd.GetName()] = cmd
+	if a, ok := cmd.(interface{ GetAliases() []string }); ok {
+		for _, alias := range a.GetAliases() {
+			m.RegisterAlias(alias, cmd.GetName())
+		}
+	}
 }
 
 func (m *CommandMap) Unregister(name string) {
 	delete(m.commands, name)
+	for alias, target := range m.aliases {
+		if target == name || alias == name {
+			delete(m.aliases, alias)
+		}
+	}
 }
 
 func (m *CommandMap) RegisterAlias(alias string, targetName string) {
-	if cmd, ok := m.commands[targetName]; ok {
-		m.commands[alias] = cmd
+	if _, ok := m.commands[targetName]; ok {
+		m.aliases[strings.ToLower(alias)] = targetName
 	}
 }
 
+func (m *CommandMap) GetCommand(label string) Command {
+	if cmd, ok := m.commands[label]; ok {
+		return cmd
+	}
+	label = strings.ToLower(label)
+	if cmd, ok := m.commands[label]; ok {
+		return cmd
+	}
+	if target, ok := m.aliases[label]; ok {
+		return m.commands[target]
+	}
+	return nil
+}
+
+func (m *CommandMap) GetCommands() []Command {
+	cmds := make([]Command, 0, len(m.commands))
+	for _, cmd := range m.commands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].GetName() < cmds[j].GetName() })
+	return cmds
+}
+
 func (m *CommandMap) Dispatch(sender CommandSender, cmdLine string) bool {
 	if cmdLine == "" {
 		return false
@@ -88,22 +170,7 @@ func (m *CommandMap) Dispatch(sender CommandSender, cmdLine string) bool {
 	}
 	cmdLine = cmdEvt.GetCommand()
 
-	var args []string
-	currentArg := ""
-	for _, char := range cmdLine {
-		if char == ' ' {
-			if currentArg != "" {
-				args = append(args, currentArg)
-				currentArg = ""
-			}
-		} else {
-			currentArg += string(char)
-		}
-	}
-	if currentArg != "" {
-		args = append(args, currentArg)
-	}
-
+	args := strings.Fields(cmdLine)
 	if len(args) == 0 {
 		return false
 	}
@@ -112,13 +179,32 @@ func (m *CommandMap) Dispatch(sender CommandSender, cmdLine string) bool {
 
 	args = args[1:]
 
-	if cmd, ok := m.commands[label]; ok {
+	if cmd := m.GetCommand(label); cmd != nil {
 		if cmd.GetPermission() != "" && !sender.HasPermission(cmd.GetPermission()) {
 			sender.SendMessage("§cYou do not have permission to use this command.")
 			return true
 		}
+		if r, ok := cmd.(Runner); ok && len(r.GetOverloads()) > 0 {
+			return m.run(sender, label, cmd, r, args)
+		}
 		return cmd.Execute(sender, args)
 	}
 
 	return false
 }
+
+func (m *CommandMap) run(sender CommandSender, label string, cmd Command, r Runner, raw []string) bool {
+	var best *ArgError
+	for i, overload := range r.GetOverloads() {
+		args, err := m.parseArgs(sender, overload, raw)
+		if err == nil {
+			return r.Run(&Context{Sender: sender, Label: label, Args: args, Overload: i, Map: m})
+		}
+		if best == nil || err.depth > best.depth {
+			best = err
+		}
+	}
+	sender.SendMessage("§c" + best.Error())
+	sender.SendMessage("§cUsage: " + cmd.GetUsage())
+	return true
+}
@@ -0,0 +1,133 @@
+package defaults
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/scaxe/scaxe-go/pkg/backup"
+	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/level"
+)
+
+type BackupCommand struct {
+	command.BaseCommand
+	server  ServerInterface
+	backups *backup.Manager
+}
+
+func NewBackupCommand(server ServerInterface, backups *backup.Manager) *BackupCommand {
+	return &BackupCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "backup",
+			Description: "Creates a backup of the world",
+			Permission:  "pocketmine.command.backup",
+			Overloads: []command.Overload{
+				{
+					{Name: "action", Type: command.ParamEnum, Values: []string{"list"}},
+					{Name: "world", Type: command.ParamString, Optional: true},
+				},
+				{
+					{Name: "world", Type: command.ParamString, Optional: true},
+				},
+			},
+		},
+		server:  server,
+		backups: backups,
+	}
+}
+
+func (c *BackupCommand) Run(ctx *command.Context) bool {
+	world := ctx.Args.String("world")
+	if ctx.Args.Has("action") {
+		list := c.backups.List(world)
+		if len(list) == 0 {
+			ctx.Sender.SendMessage("§eNo backups found.")
+			return true
+		}
+		ctx.Sender.SendMessage(fmt.Sprintf("§aBackups §7(%d)§a:", len(list)))
+		for _, b := range list {
+			ctx.Sender.SendMessage(fmt.Sprintf("§7- §f%s §7(%.1f MB)", b.Name, float64(b.Size)/(1<<20)))
+		}
+		return true
+	}
+
+	lm := c.server.GetLevelManager()
+	names := []string{world}
+	if world == "" {
+		names = lm.GetLevelNames()
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		lvl, _ := lm.GetLevel(name).(*level.Level)
+		if lvl == nil {
+			ctx.Sender.SendMessage("§cWorld '" + name + "' is not loaded.")
+			continue
+		}
+		sender := ctx.Sender
+		err := c.backups.Create(lvl, func(b *backup.Backup, err error) {
+			if err != nil {
+				sender.SendMessage("§cBackup of " + name + " failed: " + err.Error())
+				return
+			}
+			sender.SendMessage(fmt.Sprintf("§aBackup complete: %s (%.1f MB)", b.Name, float64(b.Size)/(1<<20)))
+		})
+		if err != nil {
+			ctx.Sender.SendMessage("§cCannot back up " + name + ": " + err.Error())
+			continue
+		}
+		ctx.Sender.SendMessage("§aCreating backup of " + name + "...")
+	}
+	return true
+}
+
+type RestoreCommand struct {
+	command.BaseCommand
+	server  ServerInterface
+	backups *backup.Manager
+}
+
+func NewRestoreCommand(server ServerInterface, backups *backup.Manager) *RestoreCommand {
+	return &RestoreCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "restore",
+			Description: "Restores a world from a backup",
+			Permission:  "scaxe.command.restore",
+			Overloads: []command.Overload{{
+				{Name: "backup", Type: command.ParamString},
+				{Name: "world", Type: command.ParamString, Optional: true},
+			}},
+		},
+		server:  server,
+		backups: backups,
+	}
+}
+
+func (c *RestoreCommand) Run(ctx *command.Context) bool {
+	b := c.backups.Find(ctx.Args.String("backup"))
+	if b == nil {
+		return ctx.Error("Unknown backup: " + ctx.Args.String("backup") + " (see /backup list)")
+	}
+	world := ctx.Args.String("world")
+	if world == "" {
+		world = b.World
+	}
+
+	lm := c.server.GetLevelManager()
+	var err error
+	if lvl, _ := lm.GetLevel(world).(*level.Level); lvl != nil {
+		for _, p := range c.server.GetOnlinePlayers() {
+			if p.GetLevel() == interface{}(lvl) {
+				p.SendMessage("§eThe world you are in is being restored from a backup.")
+			}
+		}
+		_, err = lm.ReloadLevel(world, func() error { return c.backups.Restore(b, world) })
+	} else {
+		err = c.backups.Restore(b, world)
+	}
+	if err != nil {
+		return ctx.Error("Restore failed: " + err.Error())
+	}
+	ctx.Sender.SendMessage("§aRestored " + world + " from " + b.Name)
+	return true
+}
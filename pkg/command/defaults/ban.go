@@ -45,7 +45,9 @@ func (c *BanCommand) Execute(sender command.CommandSender, args []string) bool {
 	sender.SendMessage("§aBanned §e" + playerName + "§a: " + reason)
 	c.server.BroadcastMessage("§e" + playerName + " has been banned: " + reason)
 
-	if player := c.server.GetPlayerByName(playerName); player != nil {
+	if bans, ok := c.server.(BanServerInterface); ok {
+		bans.AddBan(playerName, reason, sender.GetName())
+	} else if player := c.server.GetPlayerByName(playerName); player != nil {
 		player.SendMessage("§cYou have been banned: " + reason)
 	}
 
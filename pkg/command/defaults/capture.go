@@ -0,0 +1,49 @@
+package defaults
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/command"
+)
+
+type PacketCapturer interface {
+	StartCapture(player string) (string, error)
+	StopCapture(player string) (string, error)
+}
+
+type CaptureCommand struct {
+	command.BaseCommand
+	capturer PacketCapturer
+}
+
+func NewCaptureCommand(capturer PacketCapturer) *CaptureCommand {
+	return &CaptureCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "capture",
+			Description: "Records a player's packets to a capture file",
+			Permission:  "scaxe.command.capture",
+			Overloads: []command.Overload{{
+				{Name: "action", Type: command.ParamEnum, Values: []string{"start", "stop"}},
+				{Name: "player", Type: command.ParamString},
+			}},
+		},
+		capturer: capturer,
+	}
+}
+
+func (c *CaptureCommand) Run(ctx *command.Context) bool {
+	name := ctx.Args.String("player")
+	if ctx.Args.String("action") == "start" {
+		path, err := c.capturer.StartCapture(name)
+		if err != nil {
+			return ctx.Error("Cannot capture " + name + ": " + err.Error())
+		}
+		ctx.Sender.SendMessage("§aCapturing packets of " + name + " to " + path)
+		return true
+	}
+
+	path, err := c.capturer.StopCapture(name)
+	if err != nil {
+		return ctx.Error("Cannot stop capture of " + name + ": " + err.Error())
+	}
+	ctx.Sender.SendMessage("§aCapture of " + name + " saved to " + path)
+	return true
+}
@@ -0,0 +1,345 @@
+package defaults
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/level/generator/biome"
+	"github.com/scaxe/scaxe-go/pkg/timings"
+	"github.com/scaxe/scaxe-go/pkg/world"
+)
+
+type ChunkInfoCommand struct {
+	command.BaseCommand
+	server ServerInterface
+}
+
+func NewChunkInfoCommand(server ServerInterface) *ChunkInfoCommand {
+	return &ChunkInfoCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "chunkinfo",
+			Description: "Shows chunk information",
+			Permission:  "pocketmine.command.chunkinfo",
+			Overloads: []command.Overload{{
+				{Name: "world", Type: command.ParamString, Optional: true},
+			}},
+		},
+		server: server,
+	}
+}
+
+type chunkStats struct {
+	x, z     int32
+	entities int
+	tiles    int
+}
+
+func (c *ChunkInfoCommand) Run(ctx *command.Context) bool {
+	lvl := senderLevel(c.server, ctx.Sender)
+	if name := ctx.Args.String("world"); name != "" {
+		lvl, _ = c.server.GetLevelManager().GetLevel(name).(*level.Level)
+		if lvl == nil {
+			return ctx.Error("World '" + name + "' is not loaded.")
+		}
+	}
+	if lvl == nil {
+		return ctx.Error("Internal Error: No target level found.")
+	}
+
+	stats := make(map[int64]*chunkStats)
+	dirty := 0
+	for _, chunk := range lvl.GetLoadedChunks() {
+		if chunk.HasChanged() {
+			dirty++
+		}
+		stats[world.ChunkHash(chunk.X, chunk.Z)] = &chunkStats{x: chunk.X, z: chunk.Z}
+	}
+	statsAt := func(cx, cz int32) *chunkStats {
+		hash := world.ChunkHash(cx, cz)
+		if stats[hash] == nil {
+			stats[hash] = &chunkStats{x: cx, z: cz}
+		}
+		return stats[hash]
+	}
+
+	entities := lvl.GetEntities()
+	for _, e := range entities {
+		pos := e.GetPosition()
+		statsAt(int32(math.Floor(pos.X))>>4, int32(math.Floor(pos.Z))>>4).entities++
+	}
+	tiles := lvl.Tiles.GetAllTiles()
+	for _, t := range tiles {
+		x, _, z := t.GetPosition()
+		statsAt(x>>4, z>>4).tiles++
+	}
+
+	ctx.Sender.SendMessage("§a--- Chunk information: " + lvl.Name + " ---")
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Loaded chunks: §f%d §7(dirty: §f%d§7)", lvl.GetLoadedChunkCount(), dirty))
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Entities: §f%d §7Tiles: §f%d", len(entities), len(tiles)))
+
+	if p, ok := ctx.Sender.(command.Positional); ok && senderLevel(c.server, ctx.Sender) == lvl {
+		pos := p.GetPosition()
+		cur := statsAt(int32(math.Floor(pos.X))>>4, int32(math.Floor(pos.Z))>>4)
+		ctx.Sender.SendMessage(fmt.Sprintf("§7Current chunk §f%d, %d§7: §f%d §7entities, §f%d §7tiles", cur.x, cur.z, cur.entities, cur.tiles))
+	}
+
+	busiest := make([]*chunkStats, 0, len(stats))
+	for _, st := range stats {
+		if st.entities+st.tiles > 0 {
+			busiest = append(busiest, st)
+		}
+	}
+	sort.Slice(busiest, func(i, j int) bool {
+		return busiest[i].entities+busiest[i].tiles > busiest[j].entities+busiest[j].tiles
+	})
+	if len(busiest) > 5 {
+		busiest = busiest[:5]
+	}
+	for _, st := range busiest {
+		ctx.Sender.SendMessage(fmt.Sprintf("§7- Chunk §f%d, %d§7: §f%d §7entities, §f%d §7tiles", st.x, st.z, st.entities, st.tiles))
+	}
+	return true
+}
+
+type BiomeCommand struct {
+	command.BaseCommand
+	server ServerInterface
+}
+
+func NewBiomeCommand(server ServerInterface) *BiomeCommand {
+	return &BiomeCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "biome",
+			Description: "Shows biome at current position",
+			Permission:  "pocketmine.command.biome",
+			Overloads: []command.Overload{{
+				{Name: "x", Type: command.ParamString, Optional: true},
+				{Name: "z", Type: command.ParamString, Optional: true},
+			}},
+		},
+		server: server,
+	}
+}
+
+func (c *BiomeCommand) Run(ctx *command.Context) bool {
+	var originX, originZ float64
+	p, positional := ctx.Sender.(command.Positional)
+	if positional {
+		originX, originZ = p.GetPosition().X, p.GetPosition().Z
+	} else if !ctx.Args.Has("z") {
+		return ctx.Error("Usage: " + c.GetUsage())
+	}
+
+	x, errX := command.ParseCoordinate(orDefault(ctx.Args.String("x"), "~"), originX)
+	z, errZ := command.ParseCoordinate(orDefault(ctx.Args.String("z"), "~"), originZ)
+	if errX != nil || errZ != nil {
+		return ctx.Error("Invalid coordinates")
+	}
+
+	lvl := senderLevel(c.server, ctx.Sender)
+	if lvl == nil {
+		return ctx.Error("Internal Error: No target level found.")
+	}
+
+	bx, bz := int32(math.Floor(x)), int32(math.Floor(z))
+	chunk := lvl.GetChunk(bx>>4, bz>>4, false)
+	if chunk == nil {
+		return ctx.Error(fmt.Sprintf("Chunk at %d, %d is not generated", bx, bz))
+	}
+	id := chunk.GetBiomeID(int(bx&15), int(bz&15))
+	ctx.Sender.SendMessage(fmt.Sprintf("§aBiome at %d, %d: §f%s §7(ID %d)", bx, bz, biomeName(id), id))
+	return true
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+func biomeName(id uint8) string {
+	if b, ok := biome.RegisteredBiomes[id]; ok && b.GetName() != "" {
+		return b.GetName()
+	}
+	for _, b := range knownBiomes {
+		if b.ID == id {
+			return b.Name
+		}
+	}
+	return "Unknown"
+}
+
+type DumpMemoryCommand struct {
+	command.BaseCommand
+}
+
+func NewDumpMemoryCommand() *DumpMemoryCommand {
+	return &DumpMemoryCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "dumpmemory",
+			Description: "Dumps memory information",
+			Permission:  "pocketmine.command.dumpmemory",
+			Overloads: []command.Overload{{
+				{Name: "path", Type: command.ParamString, Optional: true},
+			}},
+		},
+	}
+}
+
+func (c *DumpMemoryCommand) Run(ctx *command.Context) bool {
+	dir := ctx.Args.String("path")
+	if dir == "" {
+		dir = filepath.Join("memory_dumps", time.Now().Format("20060102-150405"))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ctx.Error("Failed to create " + dir + ": " + err.Error())
+	}
+
+	runtime.GC()
+	profiles := []struct {
+		file  string
+		name  string
+		debug int
+	}{
+		{"heap.pprof", "heap", 0},
+		{"goroutines.txt", "goroutine", 2},
+		{"allocs.pprof", "allocs", 0},
+	}
+	for _, prof := range profiles {
+		if err := writeProfile(filepath.Join(dir, prof.file), prof.name, prof.debug); err != nil {
+			return ctx.Error("Failed to write " + prof.file + ": " + err.Error())
+		}
+	}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	ctx.Sender.SendMessage("§aMemory dump written to " + dir)
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Heap: §f%.1f MB §7in use, §f%.1f MB §7reserved, §f%d §7goroutines",
+		float64(ms.HeapInuse)/(1<<20), float64(ms.Sys)/(1<<20), runtime.NumGoroutine()))
+	return true
+}
+
+func writeProfile(path, name string, debugLevel int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := pprof.Lookup(name).WriteTo(f, debugLevel); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type GcCommand struct {
+	command.BaseCommand
+}
+
+func NewGcCommand() *GcCommand {
+	return &GcCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "gc",
+			Description: "Forces garbage collection",
+			Usage:       "/gc",
+			Permission:  "pocketmine.command.gc",
+		},
+	}
+}
+
+func (c *GcCommand) Execute(sender command.CommandSender, args []string) bool {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	debug.FreeOSMemory()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	freed := int64(before.HeapAlloc) - int64(after.HeapAlloc)
+	sender.SendMessage("§a--- Garbage collection result ---")
+	sender.SendMessage(fmt.Sprintf("§7Freed: §f%.2f MB §7in §f%s", float64(freed)/(1<<20), elapsed.Round(time.Microsecond)))
+	sender.SendMessage(fmt.Sprintf("§7Heap: §f%.2f MB §7Released to OS: §f%.2f MB", float64(after.HeapAlloc)/(1<<20), float64(after.HeapReleased)/(1<<20)))
+	sender.SendMessage(fmt.Sprintf("§7Goroutines: §f%d", runtime.NumGoroutine()))
+	return true
+}
+
+type TimingsCommand struct {
+	command.BaseCommand
+}
+
+func NewTimingsCommand() *TimingsCommand {
+	return &TimingsCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "timings",
+			Description: "Server timings profiler",
+			Permission:  "pocketmine.command.timings",
+			Overloads: []command.Overload{
+				{{Name: "action", Type: command.ParamEnum, Values: []string{"on", "off", "reset", "report", "spikes"}}},
+				{
+					{Name: "action", Type: command.ParamEnum, Values: []string{"paste"}},
+					{Name: "format", Type: command.ParamEnum, Values: []string{"html", "text"}, Optional: true},
+				},
+			},
+		},
+	}
+}
+
+func (c *TimingsCommand) Run(ctx *command.Context) bool {
+	switch ctx.Args.String("action") {
+	case "on":
+		timings.Enable()
+		ctx.Sender.SendMessage("§aTimings enabled")
+	case "off":
+		timings.Disable()
+		ctx.Sender.SendMessage("§aTimings disabled")
+	case "reset":
+		timings.Reset()
+		ctx.Sender.SendMessage("§aTimings reset")
+	case "report":
+		if timings.Ticks() == 0 {
+			return ctx.Error("No timings recorded. Use /timings on first.")
+		}
+		ctx.Sender.SendMessage(fmt.Sprintf("§a--- Timings (%d ticks) ---", timings.Ticks()))
+		for _, r := range timings.Report() {
+			ctx.Sender.SendMessage(fmt.Sprintf("§7%s%s: §f%.3fms/tick §7(%.1f%%, max %.3fms)",
+				strings.Repeat("  ", r.Depth), r.Name, float64(r.PerTick.Microseconds())/1000, r.Percent, float64(r.Max.Microseconds())/1000))
+		}
+	case "spikes":
+		spikes := timings.Spikes()
+		if len(spikes) == 0 {
+			ctx.Sender.SendMessage(fmt.Sprintf("§aNo lag spikes over %s recorded.", timings.GetSpikeThreshold()))
+			return true
+		}
+		for _, s := range spikes {
+			ctx.Sender.SendMessage(fmt.Sprintf("§eTick %d at %s: %.1fms", s.Tick, s.Time.Format("15:04:05"), float64(s.Duration.Microseconds())/1000))
+			shown := 0
+			for _, r := range s.Timers {
+				if r.Depth == 0 || shown >= 3 {
+					continue
+				}
+				ctx.Sender.SendMessage(fmt.Sprintf("§7  %s: %.1fms", r.Name, float64(r.Total.Microseconds())/1000))
+				shown++
+			}
+		}
+	case "paste":
+		if timings.Ticks() == 0 {
+			return ctx.Error("No timings recorded. Use /timings on first.")
+		}
+		path, err := timings.Export("timings", orDefault(ctx.Args.String("format"), "html"))
+		if err != nil {
+			return ctx.Error("Failed to write timings: " + err.Error())
+		}
+		ctx.Sender.SendMessage("§aTimings written to " + path)
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package defaults
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/timings"
+)
+
+type recordingSender struct {
+	messages []string
+}
+
+func (s *recordingSender) SendMessage(message string)     { s.messages = append(s.messages, message) }
+func (s *recordingSender) GetName() string                { return "tester" }
+func (s *recordingSender) IsOp() bool                     { return true }
+func (s *recordingSender) HasPermission(name string) bool { return true }
+
+func (s *recordingSender) run(m *command.CommandMap, line string) string {
+	s.messages = nil
+	m.Dispatch(s, line)
+	return strings.Join(s.messages, "\n")
+}
+
+func TestTimingsCommand(t *testing.T) {
+	threshold := timings.GetSpikeThreshold()
+	t.Cleanup(func() {
+		timings.Disable()
+		timings.Reset()
+		timings.SetSpikeThreshold(threshold)
+	})
+	m := command.NewCommandMap()
+	m.Register(NewTimingsCommand())
+	sender := &recordingSender{}
+
+	timings.Disable()
+	timings.Reset()
+	if out := sender.run(m, "timings report"); !strings.Contains(out, "No timings recorded") {
+		t.Errorf("report before enabling = %q", out)
+	}
+
+	sender.run(m, "timings on")
+	timings.SetSpikeThreshold(time.Hour)
+	if out := sender.run(m, "timings spikes"); !strings.Contains(out, "No lag spikes over 1h0m0s") {
+		t.Errorf("spikes with none recorded = %q", out)
+	}
+
+	// A threshold of zero makes every tick a spike.
+	timings.SetSpikeThreshold(0)
+	stopTick := timings.Start(timings.FullTick)
+	timings.Start(timings.LevelTick)()
+	stopTick()
+	timings.EndTick(7)
+
+	out := sender.run(m, "timings report")
+	if !strings.Contains(out, "(1 ticks)") || !strings.Contains(out, "§7Full Server Tick:") || !strings.Contains(out, "§7  Level Tick:") {
+		t.Errorf("report = %q", out)
+	}
+	out = sender.run(m, "timings spikes")
+	if !strings.Contains(out, "§eTick 7 at") || !strings.Contains(out, "§7  Level Tick:") || strings.Contains(out, "Full Server Tick:") {
+		t.Errorf("spikes = %q", out)
+	}
+}
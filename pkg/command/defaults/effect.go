@@ -2,8 +2,11 @@ package defaults
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
 )
 
 type EffectCommand struct {
@@ -16,8 +19,20 @@ func NewEffectCommand(server ServerInterface) *EffectCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "effect",
 			Description: "Adds or removes a potion effect",
-			Usage:       "/effect <player> <effect|clear> [duration] [amplifier]",
 			Permission:  "pocketmine.command.effect",
+			Overloads: []command.Overload{
+				{
+					{Name: "player", Type: command.ParamTarget},
+					{Name: "mode", Type: command.ParamEnum, Values: []string{"clear"}},
+				},
+				{
+					{Name: "player", Type: command.ParamTarget},
+					{Name: "effect", Type: command.ParamString},
+					{Name: "seconds", Type: command.ParamInt, Optional: true, Min: 0, Max: 1000000},
+					{Name: "amplifier", Type: command.ParamInt, Optional: true, Min: 0, Max: 255},
+					{Name: "hideParticles", Type: command.ParamBool, Optional: true},
+				},
+			},
 		},
 		server: server,
 	}
@@ -49,55 +64,60 @@ var effectNames = map[string]int{
 	"saturation":      23,
 }
 
-func (c *EffectCommand) Execute(sender command.CommandSender, args []string) bool {
-	if len(args) < 2 {
-		sender.SendMessage("§cUsage: " + c.Usage)
-		return false
+func (c *EffectCommand) Run(ctx *command.Context) bool {
+	var targets []entity.EffectTarget
+	for _, t := range ctx.Args.Targets("player") {
+		if target, ok := t.(entity.EffectTarget); ok {
+			targets = append(targets, target)
+		}
 	}
-
-	playerName := args[0]
-	effectArg := args[1]
-
-	target := c.server.GetPlayerByName(playerName)
-	if target == nil {
-		sender.SendMessage("§cPlayer not found: " + playerName)
-		return true
+	if len(targets) == 0 {
+		return ctx.Error("No entities matched")
 	}
 
-	if effectArg == "clear" {
-
-		sender.SendMessage("§aCleared all effects from " + target.GetName())
+	if ctx.Args.Has("mode") {
+		for _, target := range targets {
+			target.ClearEffects()
+			ctx.Sender.SendMessage("§aCleared all effects from " + target.GetName())
+		}
 		return true
 	}
 
+	effectArg := ctx.Args.String("effect")
 	var effectID int
-	if id, ok := effectNames[effectArg]; ok {
+	if id, ok := effectNames[strings.ToLower(effectArg)]; ok {
 		effectID = id
 	} else if id, err := strconv.Atoi(effectArg); err == nil {
 		effectID = id
-	} else {
-		sender.SendMessage("§cUnknown effect: " + effectArg)
-		return true
 	}
-
-	duration := 600
-	if len(args) >= 3 {
-		if d, err := strconv.Atoi(args[2]); err == nil {
-			duration = d * 20
-		}
+	if !effect.IsValid(effectID) {
+		return ctx.Error("Unknown effect: " + effectArg)
 	}
 
-	amplifier := 0
-	if len(args) >= 4 {
-		if a, err := strconv.Atoi(args[3]); err == nil {
-			amplifier = a
-		}
+	duration := effect.DefaultDuration
+	if ctx.Args.Has("seconds") {
+		duration = ctx.Args.Int("seconds", 0) * 20
 	}
+	amplifier := ctx.Args.Int("amplifier", 0)
+	hideParticles := ctx.Args.Bool("hideParticles", false)
 
-	_ = effectID
-	_ = duration
-	_ = amplifier
+	for _, target := range targets {
+		if duration <= 0 {
+			if !target.RemoveEffect(effectID) {
+				ctx.Sender.SendMessage("§c" + target.GetName() + " does not have " + effect.Name(effectID))
+				continue
+			}
+			ctx.Sender.SendMessage("§aRemoved " + effect.Name(effectID) + " from " + target.GetName())
+			continue
+		}
 
-	sender.SendMessage("§aApplied effect " + effectArg + " to " + target.GetName())
+		e := effect.NewEffect(effectID, duration, amplifier)
+		e.Particles = !hideParticles
+		if !target.AddEffect(e) {
+			ctx.Sender.SendMessage("§cCould not apply " + effect.Name(effectID) + " to " + target.GetName())
+			continue
+		}
+		ctx.Sender.SendMessage("§aApplied " + effect.Name(effectID) + " " + strconv.Itoa(amplifier+1) + " to " + target.GetName() + " for " + strconv.Itoa(duration/20) + " seconds")
+	}
 	return true
 }
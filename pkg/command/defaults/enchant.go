@@ -4,6 +4,8 @@ import (
 	"strconv"
 
 	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+	"github.com/scaxe/scaxe-go/pkg/player"
 )
 
 type EnchantCommand struct {
@@ -23,33 +25,6 @@ func NewEnchantCommand(server ServerInterface) *EnchantCommand {
 	}
 }
 
-var enchantNames = map[string]int{
-	"protection":            0,
-	"fire_protection":       1,
-	"feather_falling":       2,
-	"blast_protection":      3,
-	"projectile_protection": 4,
-	"thorns":                5,
-	"respiration":           6,
-	"aqua_affinity":         8,
-	"sharpness":             9,
-	"smite":                 10,
-	"bane_of_arthropods":    11,
-	"knockback":             12,
-	"fire_aspect":           13,
-	"looting":               14,
-	"efficiency":            15,
-	"silk_touch":            16,
-	"unbreaking":            17,
-	"fortune":               18,
-	"power":                 19,
-	"punch":                 20,
-	"flame":                 21,
-	"infinity":              22,
-	"luck_of_the_sea":       23,
-	"lure":                  24,
-}
-
 func (c *EnchantCommand) Execute(sender command.CommandSender, args []string) bool {
 	if len(args) < 2 {
 		sender.SendMessage("§cUsage: " + c.Usage)
@@ -66,26 +41,46 @@ func (c *EnchantCommand) Execute(sender command.CommandSender, args []string) bo
 		}
 	}
 
-	target := c.server.GetPlayerByName(playerName)
-	if target == nil {
+	found := c.server.GetPlayerByName(playerName)
+	if found == nil {
+		sender.SendMessage("§cPlayer not found: " + playerName)
+		return true
+	}
+	target, ok := found.(*player.Player)
+	if !ok {
 		sender.SendMessage("§cPlayer not found: " + playerName)
 		return true
 	}
 
-	var enchantID int
-	if id, ok := enchantNames[enchantArg]; ok {
-		enchantID = id
-	} else if id, err := strconv.Atoi(enchantArg); err == nil {
-		enchantID = id
-	} else {
+	ench := enchantment.ByName(enchantArg)
+	if ench == nil {
+		if id, err := strconv.Atoi(enchantArg); err == nil {
+			ench = enchantment.Get(id)
+		}
+	}
+	if ench == nil {
 		sender.SendMessage("§cUnknown enchantment: " + enchantArg)
 		return true
 	}
+	if level < 1 || level > ench.MaxLevel {
+		sender.SendMessage("§cLevel must be between 1 and " + strconv.Itoa(ench.MaxLevel))
+		return true
+	}
+
+	held := target.Inventory.GetItemInHand()
+	if held.IsAir() {
+		sender.SendMessage("§c" + target.GetName() + " is not holding an item")
+		return true
+	}
+	if !enchantment.CanAdd(held, ench.ID, level) {
+		sender.SendMessage("§cThe enchantment " + ench.Name + " cannot be applied to this item")
+		return true
+	}
 
-	_ = enchantID
-	_ = level
+	enchantment.Set(&held, ench.ID, level)
+	target.Inventory.SetItemInHand(held)
 
 	sender.SendMessage("§aEnchanted " + target.GetName() + "'s item with " +
-		enchantArg + " level " + strconv.Itoa(level))
+		ench.Name + " level " + strconv.Itoa(level))
 	return true
 }
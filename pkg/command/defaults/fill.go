@@ -3,10 +3,8 @@ package defaults
 import (
 	"fmt"
 	"math"
-	"strconv"
 
 	"github.com/scaxe/scaxe-go/pkg/command"
-	"github.com/scaxe/scaxe-go/pkg/level"
 )
 
 type FillCommand struct {
@@ -19,53 +17,29 @@ func NewFillCommand(server ServerInterface) *FillCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "fill",
 			Description: "Fills a region with blocks",
-			Usage:       "/fill <x1> <y1> <z1> <x2> <y2> <z2> <block> [data]",
 			Permission:  "pocketmine.command.fill",
+			Overloads: []command.Overload{{
+				{Name: "from", Type: command.ParamPosition},
+				{Name: "to", Type: command.ParamPosition},
+				{Name: "block", Type: command.ParamBlock},
+				{Name: "data", Type: command.ParamInt, Optional: true, Min: 0, Max: 15},
+			}},
 		},
 		server: server,
 	}
 }
 
-func (c *FillCommand) Execute(sender command.CommandSender, args []string) bool {
-	if len(args) < 7 {
-		sender.SendMessage("§cUsage: " + c.Usage)
-		return false
-	}
-
-	x1, y1, z1, ok1 := parseLocation(sender, args, 0)
-	x2, y2, z2, ok2 := parseLocation(sender, args, 3)
+func (c *FillCommand) Run(ctx *command.Context) bool {
+	from, to := ctx.Args.Position("from"), ctx.Args.Position("to")
+	x1, y1, z1 := from.X, from.Y, from.Z
+	x2, y2, z2 := to.X, to.Y, to.Z
 
-	if !ok1 || !ok2 {
-		sender.SendMessage("§cInvalid coordinates")
-		return true
-	}
+	b := ctx.Args.Block("block")
+	blockID, blockMeta := b.ID, ctx.Args.Int("data", b.Meta)
 
-	blockID, blockMeta, ok := ParseBlockArg(args[6])
-	if !ok {
-		sender.SendMessage("§cInvalid block ID")
-		return true
-	}
-
-	if len(args) >= 8 {
-		if m, err := strconv.Atoi(args[7]); err == nil {
-			blockMeta = m
-		}
-	}
-
-	var targetLevel *level.Level
-	if la, ok := sender.(LevelAware); ok {
-		if lvl, ok := la.GetLevel().(*level.Level); ok {
-			targetLevel = lvl
-		}
-	}
-	if targetLevel == nil {
-		if defaultLvl, ok := c.server.GetLevelManager().GetDefaultLevel().(*level.Level); ok {
-			targetLevel = defaultLvl
-		}
-	}
+	targetLevel := senderLevel(c.server, ctx.Sender)
 	if targetLevel == nil {
-		sender.SendMessage("§cInternal Error: No target level found.")
-		return true
+		return ctx.Error("Internal Error: No target level found.")
 	}
 
 	minX, maxX := minMax(x1, x2)
@@ -74,11 +48,10 @@ func (c *FillCommand) Execute(sender command.CommandSender, args []string) bool
 
 	volume := (maxX - minX + 1) * (maxY - minY + 1) * (maxZ - minZ + 1)
 	if volume > 32768 {
-		sender.SendMessage(fmt.Sprintf("§cToo many blocks in the specified area (%.0f > 32768)", volume))
-		return true
+		return ctx.Error(fmt.Sprintf("Too many blocks in the specified area (%.0f > 32768)", volume))
 	}
 
-	sender.SendMessage(fmt.Sprintf("§aFilling %.0f blocks...", volume))
+	ctx.Sender.SendMessage(fmt.Sprintf("§aFilling %.0f blocks...", volume))
 
 	count := 0
 	for x := minX; x <= maxX; x++ {
@@ -91,7 +64,7 @@ func (c *FillCommand) Execute(sender command.CommandSender, args []string) bool
 		}
 	}
 
-	sender.SendMessage(fmt.Sprintf("§aSuccessfully filled %d blocks", count))
+	ctx.Sender.SendMessage(fmt.Sprintf("§aSuccessfully filled %d blocks", count))
 	return true
 }
 
@@ -3,7 +3,11 @@ package defaults
 import (
 	"strconv"
 
+	"github.com/scaxe/scaxe-go/pkg/block"
 	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/player"
 )
 
 type GiveCommand struct {
@@ -16,61 +20,38 @@ func NewGiveCommand(server ServerInterface) *GiveCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "give",
 			Description: "Gives items to a player",
-			Usage:       "/give <player> <item[:data]> [amount]",
 			Permission:  "pocketmine.command.give",
+			Overloads: []command.Overload{{
+				{Name: "player", Type: command.ParamTarget},
+				{Name: "item", Type: command.ParamItem},
+				{Name: "amount", Type: command.ParamInt, Optional: true, Min: 1, Max: 64},
+				{Name: "data", Type: command.ParamInt, Optional: true, Min: 0, Max: 32767},
+			}},
 		},
 		server: server,
 	}
 }
 
-func (c *GiveCommand) Execute(sender command.CommandSender, args []string) bool {
-	if len(args) < 2 {
-		sender.SendMessage("§cUsage: " + c.Usage)
-		return false
-	}
-
-	playerName := args[0]
-	itemArg := args[1]
-	amount := 1
-
-	if len(args) >= 3 {
-		if a, err := strconv.Atoi(args[2]); err == nil {
-			amount = a
-		}
-	}
-
-	target := c.server.GetPlayerByName(playerName)
-	if target == nil {
-		sender.SendMessage("§cPlayer not found: " + playerName)
-		return true
-	}
+func (c *GiveCommand) Run(ctx *command.Context) bool {
+	it := ctx.Args.Item("item")
+	it.Count = ctx.Args.Int("amount", 1)
+	it.Meta = ctx.Args.Int("data", it.Meta)
 
-	itemID := 0
-	itemData := 0
-
-	for i, ch := range itemArg {
-		if ch == ':' {
-			if id, err := strconv.Atoi(itemArg[:i]); err == nil {
-				itemID = id
-			}
-			if data, err := strconv.Atoi(itemArg[i+1:]); err == nil {
-				itemData = data
-			}
-			break
+	for _, t := range ctx.Args.Targets("player") {
+		target, ok := t.(*player.Player)
+		if !ok {
+			ctx.Sender.SendMessage("§c" + entity.EntityName(t) + " is not a player")
+			continue
 		}
+		target.DropItem(it)
+		ctx.Sender.SendMessage("§aGave " + strconv.Itoa(it.Count) + " x " + itemName(it) + " to " + target.GetName())
 	}
+	return true
+}
 
-	if itemID == 0 {
-		if id, err := strconv.Atoi(itemArg); err == nil {
-			itemID = id
-		} else {
-			sender.SendMessage("§cInvalid item ID: " + itemArg)
-			return true
-		}
+func itemName(it item.Item) string {
+	if it.ID < 256 {
+		return block.GetName(uint8(it.ID))
 	}
-
-	_ = itemData
-
-	sender.SendMessage("§aGave " + strconv.Itoa(amount) + " x " + itemArg + " to " + target.GetName())
-	return true
+	return it.GetName()
 }
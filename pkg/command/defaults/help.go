@@ -1,6 +1,8 @@
 package defaults
 
 import (
+	"strings"
+
 	"github.com/scaxe/scaxe-go/pkg/command"
 )
 
@@ -13,37 +15,40 @@ func NewHelpCommand() *HelpCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "help",
 			Description: "Shows help for commands",
-			Usage:       "/help [command]",
 			Permission:  "",
+			Overloads: []command.Overload{{
+				{Name: "command", Type: command.ParamString, Optional: true},
+			}},
 		},
 	}
 }
 
-func (c *HelpCommand) Execute(sender command.CommandSender, args []string) bool {
-	if len(args) > 0 {
-		sender.SendMessage("§eHelp for /" + args[0])
+func (c *HelpCommand) Run(ctx *command.Context) bool {
+	if name := ctx.Args.String("command"); name != "" {
+		cmd := ctx.Map.GetCommand(strings.TrimPrefix(name, "/"))
+		if cmd == nil {
+			return ctx.Error("Unknown command: " + name)
+		}
+		ctx.Sender.SendMessage("§e--- Help: /" + cmd.GetName() + " ---")
+		ctx.Sender.SendMessage("§7" + cmd.GetDescription())
+		for _, line := range strings.Split(cmd.GetUsage(), "\n") {
+			if line != "" {
+				ctx.Sender.SendMessage("§7Usage: " + line)
+			}
+		}
+		if a, ok := cmd.(interface{ GetAliases() []string }); ok && len(a.GetAliases()) > 0 {
+			ctx.Sender.SendMessage("§7Aliases: " + strings.Join(a.GetAliases(), ", "))
+		}
 		return true
 	}
 
-	sender.SendMessage("§e--- Available Commands ---")
-	sender.SendMessage("§7/help - Shows this help message")
-	sender.SendMessage("§7/version - Shows server version")
-	sender.SendMessage("§7/list - Lists online players")
-	sender.SendMessage("§7/stop - Stops the server")
-	sender.SendMessage("§7/gamemode - Changes game mode")
-	sender.SendMessage("§7/kick - Kicks a player")
-	sender.SendMessage("§7/kill - Kills a player")
-	sender.SendMessage("§7/tp - Teleports a player")
-	sender.SendMessage("§7/time - Changes the time")
-	sender.SendMessage("§7/say - Broadcasts a message")
-	sender.SendMessage("§7/tell - Sends a private message")
-	sender.SendMessage("§7/me - Broadcasts an action")
-	sender.SendMessage("§7/op - Gives operator status")
-	sender.SendMessage("§7/deop - Removes operator status")
-	sender.SendMessage("§7/difficulty - Sets difficulty")
-	sender.SendMessage("§7/seed - Shows world seed")
-	sender.SendMessage("§7/tps - Shows server TPS")
-	sender.SendMessage("§7/ping - Shows your ping")
-	sender.SendMessage("§e--------------------------")
+	ctx.Sender.SendMessage("§e--- Available Commands ---")
+	for _, cmd := range ctx.Map.GetCommands() {
+		if cmd.GetPermission() != "" && !ctx.Sender.HasPermission(cmd.GetPermission()) {
+			continue
+		}
+		ctx.Sender.SendMessage("§7/" + cmd.GetName() + " - " + cmd.GetDescription())
+	}
+	ctx.Sender.SendMessage("§e--------------------------")
 	return true
 }
@@ -2,6 +2,7 @@ package defaults
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/scaxe/scaxe-go/pkg/command"
@@ -12,6 +13,8 @@ type PluginManagerInterface interface {
 	ReloadPlugin(name string) error
 	EnablePlugin(name string) error
 	DisablePlugin(name string) error
+	IsPluginEnabled(name string) bool
+	GetPluginStatus(name string) (string, error)
 }
 
 var pluginManager PluginManagerInterface
@@ -29,7 +32,7 @@ func NewLuaPluginCommand() *LuaPluginCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "luaplugin",
 			Description: "Manage Lua plugins",
-			Usage:       "/luaplugin <list|reload|disable|enable> [name]",
+			Usage:       "/luaplugin <list|status|reload|disable|enable> [name]",
 			Permission:  "scaxe.command.luaplugin",
 		},
 	}
@@ -42,7 +45,7 @@ func (c *LuaPluginCommand) Execute(sender command.CommandSender, args []string)
 	}
 
 	if len(args) == 0 {
-		sender.SendMessage("§eUsage: /luaplugin <list|reload|disable|enable> [name]")
+		sender.SendMessage("§eUsage: /luaplugin <list|status|reload|disable|enable> [name]")
 		return true
 	}
 
@@ -54,7 +57,31 @@ func (c *LuaPluginCommand) Execute(sender command.CommandSender, args []string)
 		if len(names) == 0 {
 			sender.SendMessage("§eNo Lua plugins loaded")
 		} else {
-			sender.SendMessage(fmt.Sprintf("§aLua Plugins (%d): §f%s", len(names), strings.Join(names, ", ")))
+			sort.Strings(names)
+			colored := make([]string, len(names))
+			for i, name := range names {
+				if pluginManager.IsPluginEnabled(name) {
+					colored[i] = "§a" + name
+				} else {
+					colored[i] = "§c" + name
+				}
+			}
+			sender.SendMessage(fmt.Sprintf("§aLua Plugins (%d): %s", len(names), strings.Join(colored, "§f, ")))
+		}
+
+	case "status":
+		names := args[1:]
+		if len(names) == 0 {
+			names = pluginManager.GetPluginNames()
+			sort.Strings(names)
+		}
+		for _, name := range names {
+			status, err := pluginManager.GetPluginStatus(name)
+			if err != nil {
+				sender.SendMessage(fmt.Sprintf("§c%v", err))
+				continue
+			}
+			sender.SendMessage("§e" + status)
 		}
 
 	case "reload":
@@ -94,7 +121,7 @@ func (c *LuaPluginCommand) Execute(sender command.CommandSender, args []string)
 		}
 
 	default:
-		sender.SendMessage("§cUnknown subcommand. Use: list, reload, disable, enable")
+		sender.SendMessage("§cUnknown subcommand. Use: list, status, reload, disable, enable")
 	}
 
 	return true
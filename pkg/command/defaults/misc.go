@@ -1,9 +1,18 @@
 package defaults
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/scaxe/scaxe-go/pkg/command"
 )
 
+var nativePluginManager PluginManagerInterface
+
+func SetNativePluginManager(pm PluginManagerInterface) {
+	nativePluginManager = pm
+}
+
 type PluginsCommand struct {
 	command.BaseCommand
 }
@@ -13,73 +22,91 @@ func NewPluginsCommand() *PluginsCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "plugins",
 			Description: "Lists all loaded plugins",
-			Usage:       "/plugins",
+			Usage:       "/plugins [status|reload|enable|disable] [name]",
 			Permission:  "pocketmine.command.plugins",
 		},
 	}
 }
 
-func (c *PluginsCommand) Execute(sender command.CommandSender, args []string) bool {
-
-	sender.SendMessage("§aPlugins (0): §7None loaded")
-	sender.SendMessage("§7Plugin system is not yet implemented")
-	return true
-}
-
-type GcCommand struct {
-	command.BaseCommand
-}
-
-func NewGcCommand() *GcCommand {
-	return &GcCommand{
-		BaseCommand: command.BaseCommand{
-			Name:        "gc",
-			Description: "Forces garbage collection",
-			Usage:       "/gc",
-			Permission:  "pocketmine.command.gc",
-		},
+func (c *PluginsCommand) managers() []PluginManagerInterface {
+	var managers []PluginManagerInterface
+	if nativePluginManager != nil {
+		managers = append(managers, nativePluginManager)
 	}
+	if pluginManager != nil {
+		managers = append(managers, pluginManager)
+	}
+	return managers
 }
 
-func (c *GcCommand) Execute(sender command.CommandSender, args []string) bool {
-
-	sender.SendMessage("§aGarbage collection triggered")
-	return true
+func (c *PluginsCommand) find(name string) PluginManagerInterface {
+	for _, pm := range c.managers() {
+		for _, n := range pm.GetPluginNames() {
+			if n == name {
+				return pm
+			}
+		}
+	}
+	return nil
 }
 
-type TimingsCommand struct {
-	command.BaseCommand
-}
+func (c *PluginsCommand) Execute(sender command.CommandSender, args []string) bool {
+	if len(args) == 0 {
+		var entries []string
+		for _, pm := range c.managers() {
+			for _, name := range pm.GetPluginNames() {
+				if pm.IsPluginEnabled(name) {
+					entries = append(entries, "§a"+name)
+				} else {
+					entries = append(entries, "§c"+name)
+				}
+			}
+		}
+		if len(entries) == 0 {
+			sender.SendMessage("§aPlugins (0): §7None loaded")
+			return true
+		}
+		sender.SendMessage(fmt.Sprintf("§fPlugins (%d): %s", len(entries), strings.Join(entries, "§f, ")))
+		return true
+	}
 
-func NewTimingsCommand() *TimingsCommand {
-	return &TimingsCommand{
-		BaseCommand: command.BaseCommand{
-			Name:        "timings",
-			Description: "Server timings profiler",
-			Usage:       "/timings <on|off|paste|reset>",
-			Permission:  "pocketmine.command.timings",
-		},
+	if len(args) < 2 {
+		sender.SendMessage("§eUsage: " + c.Usage)
+		return true
 	}
-}
 
-func (c *TimingsCommand) Execute(sender command.CommandSender, args []string) bool {
-	if len(args) < 1 {
-		sender.SendMessage("§cUsage: " + c.Usage)
-		return false
+	name := args[1]
+	pm := c.find(name)
+	if pm == nil {
+		sender.SendMessage("§cPlugin not found: " + name)
+		return true
 	}
 
-	switch args[0] {
-	case "on":
-		sender.SendMessage("§aTimings enabled")
-	case "off":
-		sender.SendMessage("§aTimings disabled")
-	case "paste":
-		sender.SendMessage("§ePaste not implemented yet")
-	case "reset":
-		sender.SendMessage("§aTimings reset")
+	var err error
+	switch strings.ToLower(args[0]) {
+	case "status":
+		var status string
+		if status, err = pm.GetPluginStatus(name); err == nil {
+			sender.SendMessage("§e" + status)
+		}
+	case "reload":
+		if err = pm.ReloadPlugin(name); err == nil {
+			sender.SendMessage("§aPlugin reloaded: " + name)
+		}
+	case "enable":
+		if err = pm.EnablePlugin(name); err == nil {
+			sender.SendMessage("§aPlugin enabled: " + name)
+		}
+	case "disable":
+		if err = pm.DisablePlugin(name); err == nil {
+			sender.SendMessage("§aPlugin disabled: " + name)
+		}
 	default:
-		sender.SendMessage("§cUnknown option: " + args[0])
+		sender.SendMessage("§cUnknown subcommand. Use: status, reload, enable, disable")
+		return true
+	}
+	if err != nil {
+		sender.SendMessage(fmt.Sprintf("§c%v", err))
 	}
-
 	return true
 }
@@ -0,0 +1,87 @@
+package defaults
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/raknet"
+)
+
+type RakNetProvider interface {
+	RakNetServer() *raknet.Server
+}
+
+type NetStatsCommand struct {
+	command.BaseCommand
+	provider RakNetProvider
+}
+
+func NewNetStatsCommand(provider RakNetProvider) *NetStatsCommand {
+	return &NetStatsCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "netstats",
+			Description: "Shows RakNet limits, counters and blocked addresses",
+			Permission:  "scaxe.command.netstats",
+			Overloads: []command.Overload{
+				{},
+				{
+					{Name: "action", Type: command.ParamEnum, Values: []string{"block"}},
+					{Name: "ip", Type: command.ParamString},
+					{Name: "seconds", Type: command.ParamInt, Optional: true},
+				},
+				{
+					{Name: "action", Type: command.ParamEnum, Values: []string{"unblock"}},
+					{Name: "ip", Type: command.ParamString},
+				},
+			},
+		},
+		provider: provider,
+	}
+}
+
+func (c *NetStatsCommand) Run(ctx *command.Context) bool {
+	rak := c.provider.RakNetServer()
+	if rak == nil {
+		return ctx.Error("The active network transport does not expose RakNet statistics.")
+	}
+
+	ip := ctx.Args.String("ip")
+	switch ctx.Args.String("action") {
+	case "block":
+		seconds := ctx.Args.Int("seconds", int(rak.Limits.BlockDuration/time.Second))
+		if seconds <= 0 {
+			return ctx.Error("Block duration must be positive.")
+		}
+		rak.Block(ip, "manual", time.Duration(seconds)*time.Second)
+		ctx.Sender.SendMessage(fmt.Sprintf("§aBlocked %s for %ds.", ip, seconds))
+		return true
+	case "unblock":
+		if !rak.Unblock(ip) {
+			return ctx.Error(ip + " is not blocked.")
+		}
+		ctx.Sender.SendMessage("§aUnblocked " + ip + ".")
+		return true
+	}
+
+	stats, blocked := rak.GuardStats()
+	limits := rak.Limits
+	ctx.Sender.SendMessage("§6--- RakNet ---")
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Sessions: §f%d§7/§f%d §7(max §f%d§7 per IP)", len(rak.Sessions()), limits.MaxSessions, limits.MaxSessionsPerIP))
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Packets this second: §f%d§7/§f%d §7(limit §f%d§7 per IP)", stats.PacketsThisSecond, limits.GlobalPacketsPerSecond, limits.PacketsPerSecond))
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Dropped: §f%d §7Rate limited: §f%d §7Cookie failures: §f%d", stats.DroppedPackets, stats.RateLimited, stats.CookieFailures))
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Rejected sessions: §f%d §7Timed out: §f%d §7Split violations: §f%d §7Expired splits: §f%d",
+		stats.RejectedSessions, stats.TimedOutSessions, stats.SplitViolations, stats.TimedOutSplits))
+	ctx.Sender.SendMessage(fmt.Sprintf("§7Blocks issued: §f%d §7Tracked addresses: §f%d", stats.BlocksIssued, stats.TrackedAddresses))
+
+	if len(blocked) == 0 {
+		ctx.Sender.SendMessage("§7No blocked addresses.")
+		return true
+	}
+	ctx.Sender.SendMessage(fmt.Sprintf("§6Blocked addresses §7(%d)§6:", len(blocked)))
+	for _, b := range blocked {
+		ctx.Sender.SendMessage(fmt.Sprintf("§7- §f%s §7%s, %ds left, %d packets dropped",
+			b.IP, b.Reason, int(time.Until(b.Until).Seconds()+0.5), b.Dropped))
+	}
+	return true
+}
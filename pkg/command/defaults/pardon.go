@@ -28,6 +28,13 @@ func (c *PardonCommand) Execute(sender command.CommandSender, args []string) boo
 	}
 
 	playerName := args[0]
+	if bans, ok := c.server.(BanServerInterface); ok {
+		if !bans.IsBanned(playerName) {
+			sender.SendMessage("§c" + playerName + " is not banned")
+			return true
+		}
+		bans.RemoveBan(playerName)
+	}
 
 	sender.SendMessage("§aUnbanned §e" + playerName)
 	c.server.BroadcastMessage("§e" + playerName + " has been unbanned")
@@ -0,0 +1,240 @@
+package defaults
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/permission"
+)
+
+type PermCommand struct {
+	command.BaseCommand
+	server ServerInterface
+	store  *permission.Store
+}
+
+func NewPermCommand(server ServerInterface, store *permission.Store) *PermCommand {
+	return &PermCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "perm",
+			Description: "Manages permission groups and player permissions",
+			Usage:       "/perm <groups|group|user|check|reload> ...",
+			Permission:  "scaxe.command.perm",
+		},
+		server: server,
+		store:  store,
+	}
+}
+
+func (c *PermCommand) Execute(sender command.CommandSender, args []string) bool {
+	if len(args) < 1 {
+		sender.SendMessage("§cUsage: " + c.Usage)
+		return false
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "groups":
+		sender.SendMessage("§aGroups (default " + c.store.GetDefaultGroup() + "): " + strings.Join(c.store.GetGroups(), ", "))
+	case "group":
+		return c.executeGroup(sender, args[1:])
+	case "user":
+		return c.executeUser(sender, args[1:])
+	case "check":
+		if len(args) < 3 {
+			sender.SendMessage("§cUsage: /perm check <player> <permission>")
+			return false
+		}
+		target := c.server.GetPlayerByName(args[1])
+		if target == nil {
+			sender.SendMessage("§cPlayer not found: " + args[1])
+			return true
+		}
+		sender.SendMessage("§a" + target.GetName() + " " + args[2] + ": " + strconv.FormatBool(target.HasPermission(args[2])))
+	case "reload":
+		if err := c.store.Reload(); err != nil {
+			sender.SendMessage("§cFailed to reload permissions: " + err.Error())
+			return true
+		}
+		sender.SendMessage("§aPermissions reloaded")
+	default:
+		sender.SendMessage("§cUnknown perm command: " + args[0])
+		return false
+	}
+	return true
+}
+
+func (c *PermCommand) executeGroup(sender command.CommandSender, args []string) bool {
+	if len(args) < 2 {
+		sender.SendMessage("§cUsage: /perm group <group> <create|delete|info|set|unset|parent> ...")
+		return false
+	}
+	group, action := args[0], strings.ToLower(args[1])
+	var err error
+	switch action {
+	case "create":
+		err = c.store.CreateGroup(group)
+	case "delete":
+		err = c.store.DeleteGroup(group)
+	case "info":
+		g := c.store.GetGroup(group)
+		if g == nil {
+			err = permission.ErrUnknownGroup
+			break
+		}
+		sender.SendMessage("§aGroup " + group + " inherits: " + strings.Join(g.Inherits, ", "))
+		sender.SendMessage("§aPermissions: " + strings.Join(g.Permissions, ", "))
+		for world, nodes := range g.Worlds {
+			sender.SendMessage("§a[" + world + "]: " + strings.Join(nodes, ", "))
+		}
+		return true
+	case "set", "unset":
+		if len(args) < 3 {
+			sender.SendMessage("§cUsage: /perm group <group> " + action + " <permission> [world]")
+			return false
+		}
+		world := optArg(args, 3)
+		if action == "set" {
+			node, value := parseNode(args[2])
+			err = c.store.SetGroupPermission(group, world, node, value)
+		} else {
+			err = c.store.UnsetGroupPermission(group, world, args[2])
+		}
+	case "parent":
+		if len(args) < 4 {
+			sender.SendMessage("§cUsage: /perm group <group> parent <add|remove> <parent>")
+			return false
+		}
+		if strings.ToLower(args[2]) == "remove" {
+			err = c.store.RemoveGroupParent(group, args[3])
+		} else {
+			err = c.store.AddGroupParent(group, args[3])
+		}
+	default:
+		sender.SendMessage("§cUnknown group action: " + action)
+		return false
+	}
+	if err != nil {
+		sender.SendMessage("§cFailed to update group " + group + ": " + err.Error())
+		return true
+	}
+	sender.SendMessage("§aUpdated group " + group)
+	return true
+}
+
+func (c *PermCommand) executeUser(sender command.CommandSender, args []string) bool {
+	if len(args) < 2 {
+		sender.SendMessage("§cUsage: /perm user <player> <info|setgroup|addgroup|removegroup|set|unset|settemp> ...")
+		return false
+	}
+	player, action := args[0], strings.ToLower(args[1])
+	var err error
+	switch action {
+	case "info":
+		sender.SendMessage("§a" + player + " groups: " + strings.Join(c.store.GetPlayerGroups(player), ", "))
+		if e := c.store.GetPlayerEntry(player); e != nil {
+			sender.SendMessage("§aPermissions: " + strings.Join(e.Permissions, ", "))
+			for world, nodes := range e.Worlds {
+				sender.SendMessage("§a[" + world + "]: " + strings.Join(nodes, ", "))
+			}
+			for _, t := range e.Timed {
+				name := t.Permission
+				if t.Group != "" {
+					name = "group " + t.Group
+				}
+				sender.SendMessage("§a" + name + " until " + t.Expires.Format(time.RFC3339))
+			}
+		}
+		return true
+	case "setgroup", "addgroup", "removegroup":
+		if len(args) < 3 {
+			sender.SendMessage("§cUsage: /perm user <player> " + action + " <group> [duration]")
+			return false
+		}
+		switch action {
+		case "setgroup":
+			err = c.store.SetPlayerGroup(player, args[2])
+		case "removegroup":
+			err = c.store.RemovePlayerGroup(player, args[2])
+		default:
+			var expires time.Time
+			if d := optArg(args, 3); d != "" {
+				dur, perr := parseDuration(d)
+				if perr != nil {
+					sender.SendMessage("§cInvalid duration: " + d)
+					return true
+				}
+				expires = time.Now().Add(dur)
+			}
+			err = c.store.AddPlayerGroup(player, args[2], expires)
+		}
+	case "set", "unset":
+		if len(args) < 3 {
+			sender.SendMessage("§cUsage: /perm user <player> " + action + " <permission> [world]")
+			return false
+		}
+		world := optArg(args, 3)
+		if action == "set" {
+			node, value := parseNode(args[2])
+			err = c.store.SetPlayerPermission(player, world, node, value, time.Time{})
+		} else {
+			err = c.store.UnsetPlayerPermission(player, world, args[2])
+		}
+	case "settemp":
+		if len(args) < 4 {
+			sender.SendMessage("§cUsage: /perm user <player> settemp <permission> <duration> [world]")
+			return false
+		}
+		dur, perr := parseDuration(args[3])
+		if perr != nil {
+			sender.SendMessage("§cInvalid duration: " + args[3])
+			return true
+		}
+		node, value := parseNode(args[2])
+		err = c.store.SetPlayerPermission(player, optArg(args, 4), node, value, time.Now().Add(dur))
+	default:
+		sender.SendMessage("§cUnknown user action: " + action)
+		return false
+	}
+	if err != nil {
+		sender.SendMessage("§cFailed to update " + player + ": " + err.Error())
+		return true
+	}
+	sender.SendMessage("§aUpdated permissions of " + player)
+	return true
+}
+
+func parseNode(arg string) (string, bool) {
+	if strings.HasPrefix(arg, "-") {
+		return arg[1:], false
+	}
+	return arg, true
+}
+
+func optArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if n := len(s); n > 1 {
+		unit := time.Duration(0)
+		switch s[n-1] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'w':
+			unit = 7 * 24 * time.Hour
+		}
+		if unit > 0 {
+			v, err := strconv.Atoi(s[:n-1])
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,77 @@
+package defaults
+
+import (
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+)
+
+type ServerSwitcher interface {
+	ProxyServers(player string) ([]string, string, error)
+	TransferPlayer(player, server string) error
+}
+
+type ServerCommand struct {
+	command.BaseCommand
+	switcher ServerSwitcher
+}
+
+func NewServerCommand(switcher ServerSwitcher) *ServerCommand {
+	return &ServerCommand{
+		BaseCommand: command.BaseCommand{
+			Name:        "server",
+			Description: "Lists the proxy's servers or moves a player to one",
+			Permission:  "scaxe.command.server",
+			Overloads: []command.Overload{
+				{},
+				{
+					{Name: "name", Type: command.ParamString},
+					{Name: "player", Type: command.ParamString, Optional: true},
+				},
+			},
+		},
+		switcher: switcher,
+	}
+}
+
+func (c *ServerCommand) Run(ctx *command.Context) bool {
+	_, isPlayer := ctx.Sender.(command.PlayerSender)
+	self := ""
+	if isPlayer {
+		self = ctx.Sender.GetName()
+	}
+
+	target := ctx.Args.String("name")
+	if target == "" {
+		servers, current, err := c.switcher.ProxyServers(self)
+		if err != nil {
+			return ctx.Error("Cannot list servers: " + err.Error())
+		}
+		list := make([]string, len(servers))
+		for i, name := range servers {
+			if name == current {
+				list[i] = "§a" + name + "§f"
+			} else {
+				list[i] = name
+			}
+		}
+		ctx.Sender.SendMessage("§6Servers: §f" + strings.Join(list, ", "))
+		return true
+	}
+
+	name := ctx.Args.String("player")
+	if name == "" {
+		if !isPlayer {
+			return ctx.Error("Usage: /server <name> <player>")
+		}
+		name = self
+	} else if !strings.EqualFold(name, self) && !ctx.Sender.HasPermission("scaxe.command.server.other") {
+		return ctx.Error("You do not have permission to move other players.")
+	}
+
+	if err := c.switcher.TransferPlayer(name, target); err != nil {
+		return ctx.Error("Cannot move " + name + ": " + err.Error())
+	}
+	ctx.Sender.SendMessage("§aMoving " + name + " to " + target + "...")
+	return true
+}
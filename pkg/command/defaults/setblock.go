@@ -2,10 +2,9 @@ package defaults
 
 import (
 	"fmt"
-	"strconv"
+	"math"
 
 	"github.com/scaxe/scaxe-go/pkg/command"
-	"github.com/scaxe/scaxe-go/pkg/level"
 )
 
 type SetBlockCommand struct {
@@ -18,67 +17,37 @@ func NewSetBlockCommand(server ServerInterface) *SetBlockCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "setblock",
 			Description: "Sets a block at a position",
-			Usage:       "/setblock <x> <y> <z> <block> [data]",
 			Permission:  "pocketmine.command.setblock",
+			Overloads: []command.Overload{{
+				{Name: "position", Type: command.ParamPosition},
+				{Name: "block", Type: command.ParamBlock},
+				{Name: "data", Type: command.ParamInt, Optional: true, Min: 0, Max: 15},
+			}},
 		},
 		server: server,
 	}
 }
 
-func (c *SetBlockCommand) Execute(sender command.CommandSender, args []string) bool {
-	if len(args) < 4 {
-		sender.SendMessage("§cUsage: " + c.Usage)
-		return false
-	}
-
-	x, y, z, ok := parseLocation(sender, args, 0)
-	if !ok {
-		sender.SendMessage("§cInvalid coordinates")
-		return true
-	}
-
+func (c *SetBlockCommand) Run(ctx *command.Context) bool {
+	pos := ctx.Args.Position("position")
+	x, y, z := math.Floor(pos.X), math.Floor(pos.Y), math.Floor(pos.Z)
 	if y < 0 || y > 128 {
-		sender.SendMessage("§cUnsuccessful: Y coordinate out of bounds (0-128)")
-		return true
+		return ctx.Error("Unsuccessful: Y coordinate out of bounds (0-128)")
 	}
 
-	blockID, blockMeta, ok := ParseBlockArg(args[3])
-	if !ok {
-		sender.SendMessage("§cInvalid block ID")
-		return true
-	}
-
-	if len(args) >= 5 {
-		if m, err := strconv.Atoi(args[4]); err == nil {
-			blockMeta = m
-		}
-	}
-
-	var targetLevel *level.Level
-	if la, ok := sender.(LevelAware); ok {
-		if lvl, ok := la.GetLevel().(*level.Level); ok {
-			targetLevel = lvl
-		}
-	}
-
-	if targetLevel == nil {
-		if defaultLvl, ok := c.server.GetLevelManager().GetDefaultLevel().(*level.Level); ok {
-			targetLevel = defaultLvl
-		}
-	}
+	b := ctx.Args.Block("block")
+	meta := ctx.Args.Int("data", b.Meta)
 
+	targetLevel := senderLevel(c.server, ctx.Sender)
 	if targetLevel == nil {
-		sender.SendMessage("§cInternal Error: No target level found.")
-		return true
+		return ctx.Error("Internal Error: No target level found.")
 	}
 
-	if targetLevel.SetBlock(int32(x), int32(y), int32(z), byte(blockID), byte(blockMeta), true) {
-		sender.SendMessage(fmt.Sprintf("§aBlock placed at %.0f, %.0f, %.0f", x, y, z))
+	if targetLevel.SetBlock(int32(x), int32(y), int32(z), byte(b.ID), byte(meta), true) {
+		ctx.Sender.SendMessage(fmt.Sprintf("§aBlock placed at %.0f, %.0f, %.0f", x, y, z))
 		return true
 	}
-
-	sender.SendMessage("§cUnsuccessful: Could not place block")
-	return true
+	return ctx.Error("Unsuccessful: Could not place block")
 }
 
 type LevelAware interface {
@@ -2,9 +2,9 @@ package defaults
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/entity"
 )
 
 type TeleportCommand struct {
@@ -17,57 +17,57 @@ func NewTeleportCommand(server ServerInterface) *TeleportCommand {
 		BaseCommand: command.BaseCommand{
 			Name:        "tp",
 			Description: "Teleports a player",
-			Usage:       "/tp <x> <y> <z> or /tp <player> or /tp <player> <x> <y> <z>",
 			Permission:  "pocketmine.command.teleport",
+			Aliases:     []string{"teleport"},
+			Overloads: []command.Overload{
+				{{Name: "destination", Type: command.ParamTarget}},
+				{{Name: "victim", Type: command.ParamTarget}, {Name: "destination", Type: command.ParamTarget}},
+				{{Name: "position", Type: command.ParamPosition}},
+				{{Name: "victim", Type: command.ParamTarget}, {Name: "position", Type: command.ParamPosition}},
+			},
 		},
 		server: server,
 	}
 }
 
-func (c *TeleportCommand) Execute(sender command.CommandSender, args []string) bool {
-	if len(args) == 0 {
-		sender.SendMessage("§cUsage: " + c.Usage)
-		return false
-	}
+type teleportable interface {
+	entity.IEntity
+	Teleport(x, y, z float64)
+}
 
-	switch len(args) {
-	case 1:
-		target := c.server.GetPlayerByName(args[0])
-		if target == nil {
-			sender.SendMessage("§cPlayer not found: " + args[0])
-			return true
-		}
-		sender.SendMessage("§aTeleported to " + target.GetName())
+func (c *TeleportCommand) Run(ctx *command.Context) bool {
+	var victims []entity.IEntity
+	if ctx.Args.Has("victim") {
+		victims = ctx.Args.Targets("victim")
+	} else if self, ok := ctx.Sender.(entity.IEntity); ok {
+		victims = []entity.IEntity{self}
+	} else {
+		return ctx.Error("You must specify a player to teleport")
+	}
 
-	case 3:
-		x, err1 := strconv.ParseFloat(args[0], 64)
-		y, err2 := strconv.ParseFloat(args[1], 64)
-		z, err3 := strconv.ParseFloat(args[2], 64)
-		if err1 != nil || err2 != nil || err3 != nil {
-			sender.SendMessage("§cInvalid coordinates")
-			return true
+	dest := ctx.Args.Position("position")
+	destName := ""
+	if dest == nil {
+		targets := ctx.Args.Targets("destination")
+		if len(targets) != 1 {
+			return ctx.Error("Destination must be a single target")
 		}
-		sender.SendMessage(fmt.Sprintf("§aTeleported to %.2f, %.2f, %.2f", x, y, z))
+		pos := targets[0].GetPosition()
+		dest = entity.NewVector3(pos.X, pos.Y, pos.Z)
+		destName = entity.EntityName(targets[0])
+	}
+	if destName == "" {
+		destName = fmt.Sprintf("%.2f, %.2f, %.2f", dest.X, dest.Y, dest.Z)
+	}
 
-	case 4:
-		target := c.server.GetPlayerByName(args[0])
-		if target == nil {
-			sender.SendMessage("§cPlayer not found: " + args[0])
-			return true
-		}
-		x, err1 := strconv.ParseFloat(args[1], 64)
-		y, err2 := strconv.ParseFloat(args[2], 64)
-		z, err3 := strconv.ParseFloat(args[3], 64)
-		if err1 != nil || err2 != nil || err3 != nil {
-			sender.SendMessage("§cInvalid coordinates")
-			return true
+	for _, v := range victims {
+		t, ok := v.(teleportable)
+		if !ok {
+			ctx.Sender.SendMessage("§cCannot teleport " + entity.EntityName(v))
+			continue
 		}
-		sender.SendMessage(fmt.Sprintf("§aTeleported %s to %.2f, %.2f, %.2f", target.GetName(), x, y, z))
-
-	default:
-		sender.SendMessage("§cUsage: " + c.Usage)
-		return false
+		t.Teleport(dest.X, dest.Y, dest.Z)
+		ctx.Sender.SendMessage("§aTeleported " + entity.EntityName(v) + " to " + destName)
 	}
-
 	return true
 }
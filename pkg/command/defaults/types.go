@@ -52,6 +52,7 @@ type LevelManager interface {
 	LoadLevel(name string) (interface{}, error)
 	GenerateLevel(name string, generatorName string, seed int64) (interface{}, error)
 	UnloadLevel(name string) bool
+	ReloadLevel(name string, swap func() error) (interface{}, error)
 }
 
 type Server = ServerInterface
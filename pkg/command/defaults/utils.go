@@ -6,21 +6,21 @@ import (
 
 	"github.com/scaxe/scaxe-go/pkg/command"
 	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/level"
 )
 
 func ParseRelativeCoordinate(val string, relativeTo float64) (float64, error) {
-	if strings.HasPrefix(val, "~") {
-		offsetStr := val[1:]
-		if offsetStr == "" {
-			return relativeTo, nil
-		}
-		offset, err := strconv.ParseFloat(offsetStr, 64)
-		if err != nil {
-			return 0, err
+	return command.ParseCoordinate(val, relativeTo)
+}
+
+func senderLevel(server ServerInterface, sender command.CommandSender) *level.Level {
+	if la, ok := sender.(LevelAware); ok {
+		if lvl, ok := la.GetLevel().(*level.Level); ok && lvl != nil {
+			return lvl
 		}
-		return relativeTo + offset, nil
 	}
-	return strconv.ParseFloat(val, 64)
+	lvl, _ := server.GetLevelManager().GetDefaultLevel().(*level.Level)
+	return lvl
 }
 
 func ParseBlockArg(arg string) (int, int, bool) {
@@ -27,91 +27,6 @@ func (c *RestartCommand) Execute(sender command.CommandSender, args []string) bo
 	return true
 }
 
-type BackupCommand struct {
-	command.BaseCommand
-}
-
-func NewBackupCommand() *BackupCommand {
-	return &BackupCommand{
-		BaseCommand: command.BaseCommand{
-			Name:        "backup",
-			Description: "Creates a backup of the world",
-			Usage:       "/backup",
-			Permission:  "pocketmine.command.backup",
-		},
-	}
-}
-
-func (c *BackupCommand) Execute(sender command.CommandSender, args []string) bool {
-	sender.SendMessage("§aCreating world backup...")
-
-	sender.SendMessage("§aBackup complete!")
-	return true
-}
-
-type ChunkInfoCommand struct {
-	command.BaseCommand
-	server ServerInterface
-}
-
-func NewChunkInfoCommand(server ServerInterface) *ChunkInfoCommand {
-	return &ChunkInfoCommand{
-		BaseCommand: command.BaseCommand{
-			Name:        "chunkinfo",
-			Description: "Shows chunk information",
-			Usage:       "/chunkinfo",
-			Permission:  "pocketmine.command.chunkinfo",
-		},
-		server: server,
-	}
-}
-
-func (c *ChunkInfoCommand) Execute(sender command.CommandSender, args []string) bool {
-	sender.SendMessage("§aChunk information:")
-	sender.SendMessage("§7Loaded chunks: (calculate from level)")
-	return true
-}
-
-type BiomeCommand struct {
-	command.BaseCommand
-}
-
-func NewBiomeCommand() *BiomeCommand {
-	return &BiomeCommand{
-		BaseCommand: command.BaseCommand{
-			Name:        "biome",
-			Description: "Shows biome at current position",
-			Usage:       "/biome",
-			Permission:  "pocketmine.command.biome",
-		},
-	}
-}
-
-func (c *BiomeCommand) Execute(sender command.CommandSender, args []string) bool {
-	sender.SendMessage("§aBiome: Plains")
-	return true
-}
-
-type DumpMemoryCommand struct {
-	command.BaseCommand
-}
-
-func NewDumpMemoryCommand() *DumpMemoryCommand {
-	return &DumpMemoryCommand{
-		BaseCommand: command.BaseCommand{
-			Name:        "dumpmemory",
-			Description: "Dumps memory information",
-			Usage:       "/dumpmemory",
-			Permission:  "pocketmine.command.dumpmemory",
-		},
-	}
-}
-
-func (c *DumpMemoryCommand) Execute(sender command.CommandSender, args []string) bool {
-	sender.SendMessage("§aMemory dump complete")
-	return true
-}
-
 type BanCidCommand struct {
 	command.BaseCommand
 	server ServerInterface
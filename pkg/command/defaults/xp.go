@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/player"
 )
 
 type XpCommand struct {
@@ -32,8 +33,13 @@ func (c *XpCommand) Execute(sender command.CommandSender, args []string) bool {
 	amountArg := args[0]
 	playerName := args[1]
 
-	target := c.server.GetPlayerByName(playerName)
-	if target == nil {
+	found := c.server.GetPlayerByName(playerName)
+	if found == nil {
+		sender.SendMessage("§cPlayer not found: " + playerName)
+		return true
+	}
+	target, ok := found.(*player.Player)
+	if !ok {
 		sender.SendMessage("§cPlayer not found: " + playerName)
 		return true
 	}
@@ -51,8 +57,14 @@ func (c *XpCommand) Execute(sender command.CommandSender, args []string) bool {
 	}
 
 	if isLevels {
+		target.AddXPLevel(amount)
 		sender.SendMessage("§aGave " + strconv.Itoa(amount) + " levels to " + target.GetName())
 	} else {
+		if amount < 0 {
+			sender.SendMessage("§cCannot give negative experience points")
+			return true
+		}
+		target.AddXP(amount)
 		sender.SendMessage("§aGave " + strconv.Itoa(amount) + " experience to " + target.GetName())
 	}
 
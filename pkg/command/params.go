@@ -0,0 +1,305 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/block"
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+type ParamType int
+
+const (
+	ParamString ParamType = iota
+	ParamText
+	ParamInt
+	ParamFloat
+	ParamBool
+	ParamEnum
+	ParamTarget
+	ParamPosition
+	ParamBlock
+	ParamItem
+)
+
+var paramTypeNames = map[ParamType]string{
+	ParamString:   "string",
+	ParamText:     "message",
+	ParamInt:      "int",
+	ParamFloat:    "float",
+	ParamBool:     "bool",
+	ParamTarget:   "target",
+	ParamPosition: "x y z",
+	ParamBlock:    "block",
+	ParamItem:     "item",
+}
+
+type Parameter struct {
+	Name     string
+	Type     ParamType
+	Optional bool
+	Min      int
+	Max      int
+	Values   []string
+}
+
+func (p Parameter) String() string {
+	typ := paramTypeNames[p.Type]
+	if p.Type == ParamEnum {
+		typ = strings.Join(p.Values, "|")
+	}
+	if p.Optional {
+		return "[" + p.Name + ": " + typ + "]"
+	}
+	return "<" + p.Name + ": " + typ + ">"
+}
+
+type Overload []Parameter
+
+func (o Overload) String() string {
+	parts := make([]string, len(o))
+	for i, p := range o {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+type Positional interface {
+	GetPosition() *entity.Vector3
+}
+
+type BlockArg struct {
+	ID   int
+	Meta int
+}
+
+type Args struct {
+	values map[string]interface{}
+}
+
+func (a *Args) Has(name string) bool {
+	_, ok := a.values[name]
+	return ok
+}
+
+func (a *Args) String(name string) string {
+	s, _ := a.values[name].(string)
+	return s
+}
+
+func (a *Args) Int(name string, def int) int {
+	if v, ok := a.values[name].(int); ok {
+		return v
+	}
+	return def
+}
+
+func (a *Args) Float(name string, def float64) float64 {
+	if v, ok := a.values[name].(float64); ok {
+		return v
+	}
+	return def
+}
+
+func (a *Args) Bool(name string, def bool) bool {
+	if v, ok := a.values[name].(bool); ok {
+		return v
+	}
+	return def
+}
+
+func (a *Args) Targets(name string) []entity.IEntity {
+	t, _ := a.values[name].([]entity.IEntity)
+	return t
+}
+
+func (a *Args) Position(name string) *entity.Vector3 {
+	v, _ := a.values[name].(*entity.Vector3)
+	return v
+}
+
+func (a *Args) Block(name string) BlockArg {
+	b, _ := a.values[name].(BlockArg)
+	return b
+}
+
+func (a *Args) Item(name string) item.Item {
+	it, _ := a.values[name].(item.Item)
+	return it
+}
+
+type ArgError struct {
+	Message string
+	depth   int
+}
+
+func (e *ArgError) Error() string {
+	return e.Message
+}
+
+func argErrorf(depth int, format string, a ...interface{}) *ArgError {
+	return &ArgError{Message: fmt.Sprintf(format, a...), depth: depth}
+}
+
+func (m *CommandMap) parseArgs(sender CommandSender, overload Overload, raw []string) (*Args, *ArgError) {
+	args := &Args{values: make(map[string]interface{})}
+	pos := 0
+	for i, p := range overload {
+		if pos >= len(raw) {
+			if p.Optional {
+				break
+			}
+			return nil, argErrorf(i, "Missing argument %s", p)
+		}
+		tok := raw[pos]
+		switch p.Type {
+		case ParamString:
+			args.values[p.Name] = tok
+		case ParamText:
+			args.values[p.Name] = strings.Join(raw[pos:], " ")
+			pos = len(raw) - 1
+		case ParamInt:
+			v, err := strconv.Atoi(tok)
+			if err != nil {
+				return nil, argErrorf(i, "Invalid integer for %s: %s", p.Name, tok)
+			}
+			if p.Max > p.Min && (v < p.Min || v > p.Max) {
+				return nil, argErrorf(i+1, "%s must be between %d and %d", p.Name, p.Min, p.Max)
+			}
+			args.values[p.Name] = v
+		case ParamFloat:
+			v, err := strconv.ParseFloat(tok, 64)
+			if err != nil {
+				return nil, argErrorf(i, "Invalid number for %s: %s", p.Name, tok)
+			}
+			args.values[p.Name] = v
+		case ParamBool:
+			v, err := strconv.ParseBool(tok)
+			if err != nil {
+				return nil, argErrorf(i, "Invalid boolean for %s: %s", p.Name, tok)
+			}
+			args.values[p.Name] = v
+		case ParamEnum:
+			value, ok := matchEnum(p.Values, tok)
+			if !ok {
+				return nil, argErrorf(i, "Unknown value for %s: %s (expected %s)", p.Name, tok, strings.Join(p.Values, ", "))
+			}
+			args.values[p.Name] = value
+		case ParamTarget:
+			targets, err := m.resolveTargets(sender, tok)
+			if err != nil {
+				return nil, argErrorf(i+1, "%s", err.Error())
+			}
+			args.values[p.Name] = targets
+		case ParamPosition:
+			if pos+3 > len(raw) {
+				return nil, argErrorf(i, "Missing argument %s", p)
+			}
+			v, ok := parsePosition(sender, raw[pos:pos+3])
+			if !ok {
+				return nil, argErrorf(i, "Invalid position for %s: %s", p.Name, strings.Join(raw[pos:pos+3], " "))
+			}
+			args.values[p.Name] = v
+			pos += 2
+		case ParamBlock:
+			b, ok := parseBlock(tok)
+			if !ok {
+				return nil, argErrorf(i+1, "Unknown block: %s", tok)
+			}
+			args.values[p.Name] = b
+		case ParamItem:
+			it, ok := parseItem(tok)
+			if !ok {
+				return nil, argErrorf(i+1, "Unknown item: %s", tok)
+			}
+			args.values[p.Name] = it
+		}
+		pos++
+	}
+	if pos < len(raw) {
+		return nil, argErrorf(len(overload), "Too many arguments: %s", strings.Join(raw[pos:], " "))
+	}
+	return args, nil
+}
+
+func matchEnum(values []string, tok string) (string, bool) {
+	for _, v := range values {
+		if strings.EqualFold(v, tok) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func parsePosition(sender CommandSender, raw []string) (*entity.Vector3, bool) {
+	origin := &entity.Vector3{}
+	if p, ok := sender.(Positional); ok && p.GetPosition() != nil {
+		origin = p.GetPosition()
+	}
+	x, err1 := ParseCoordinate(raw[0], origin.X)
+	y, err2 := ParseCoordinate(raw[1], origin.Y)
+	z, err3 := ParseCoordinate(raw[2], origin.Z)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, false
+	}
+	return entity.NewVector3(x, y, z), true
+}
+
+func ParseCoordinate(val string, relativeTo float64) (float64, error) {
+	if !strings.HasPrefix(val, "~") {
+		return strconv.ParseFloat(val, 64)
+	}
+	if val == "~" {
+		return relativeTo, nil
+	}
+	offset, err := strconv.ParseFloat(val[1:], 64)
+	if err != nil {
+		return 0, err
+	}
+	return relativeTo + offset, nil
+}
+
+func splitMeta(tok string) (string, int, bool) {
+	tok = strings.TrimPrefix(strings.ToLower(tok), "minecraft:")
+	i := strings.LastIndex(tok, ":")
+	if i < 0 {
+		return tok, 0, true
+	}
+	meta, err := strconv.Atoi(tok[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return tok[:i], meta, true
+}
+
+func parseBlock(tok string) (BlockArg, bool) {
+	name, meta, ok := splitMeta(tok)
+	if !ok {
+		return BlockArg{}, false
+	}
+	if id, err := strconv.Atoi(name); err == nil {
+		return BlockArg{ID: id, Meta: meta}, id >= 0 && id < 256
+	}
+	id, ok := block.ByName(name)
+	return BlockArg{ID: int(id), Meta: meta}, ok
+}
+
+func parseItem(tok string) (item.Item, bool) {
+	name, meta, ok := splitMeta(tok)
+	if !ok {
+		return item.Item{}, false
+	}
+	if id, err := strconv.Atoi(name); err == nil {
+		return item.NewItem(id, meta, 1), id > 0
+	}
+	if id, ok := item.ByName(name); ok {
+		return item.NewItem(id, meta, 1), true
+	}
+	if id, ok := block.ByName(name); ok && id != 0 {
+		return item.NewItem(int(id), meta, 1), true
+	}
+	return item.Item{}, false
+}
@@ -0,0 +1,232 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/entity"
+)
+
+type TargetProvider interface {
+	GetTargetPlayers() []entity.IEntity
+	GetTargetEntities(sender CommandSender) []entity.IEntity
+}
+
+type selector struct {
+	kind   byte
+	params map[string]string
+}
+
+func (m *CommandMap) SetTargetProvider(provider TargetProvider) {
+	m.targets = provider
+}
+
+func (m *CommandMap) resolveTargets(sender CommandSender, tok string) ([]entity.IEntity, error) {
+	if m.targets == nil {
+		return nil, errors.New("Target selectors are not available")
+	}
+	if !strings.HasPrefix(tok, "@") {
+		if p := findPlayer(m.targets.GetTargetPlayers(), tok); p != nil {
+			return []entity.IEntity{p}, nil
+		}
+		return nil, fmt.Errorf("Player not found: %s", tok)
+	}
+	sel, err := parseSelector(tok)
+	if err != nil {
+		return nil, err
+	}
+	targets := sel.resolve(sender, m.targets)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("No targets matched selector %s", tok)
+	}
+	return targets, nil
+}
+
+func findPlayer(players []entity.IEntity, name string) entity.IEntity {
+	var prefixed entity.IEntity
+	lower := strings.ToLower(name)
+	for _, p := range players {
+		pname := strings.ToLower(entity.EntityName(p))
+		if pname == lower {
+			return p
+		}
+		if prefixed == nil && strings.HasPrefix(pname, lower) {
+			prefixed = p
+		}
+	}
+	return prefixed
+}
+
+func parseSelector(tok string) (*selector, error) {
+	if len(tok) < 2 || strings.IndexByte("aeprs", tok[1]) < 0 {
+		return nil, fmt.Errorf("Invalid selector: %s", tok)
+	}
+	sel := &selector{kind: tok[1], params: make(map[string]string)}
+	rest := tok[2:]
+	if rest == "" {
+		return sel, nil
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+		return nil, fmt.Errorf("Invalid selector: %s", tok)
+	}
+	for _, kv := range strings.Split(rest[1:len(rest)-1], ",") {
+		if kv == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid selector argument: %s", kv)
+		}
+		sel.params[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return sel, nil
+}
+
+func (s *selector) resolve(sender CommandSender, provider TargetProvider) []entity.IEntity {
+	var candidates []entity.IEntity
+	switch s.kind {
+	case 's':
+		if e, ok := sender.(entity.IEntity); ok {
+			candidates = []entity.IEntity{e}
+		}
+	case 'e':
+		candidates = append(provider.GetTargetPlayers(), provider.GetTargetEntities(sender)...)
+	default:
+		candidates = provider.GetTargetPlayers()
+	}
+	if s.kind != 's' {
+		candidates = sameLevel(sender, candidates)
+	}
+
+	origin := &entity.Vector3{}
+	if p, ok := sender.(Positional); ok && p.GetPosition() != nil {
+		origin = entity.NewVector3(p.GetPosition().X, p.GetPosition().Y, p.GetPosition().Z)
+	}
+	s.applyOrigin(origin)
+
+	var matched []entity.IEntity
+	for _, e := range candidates {
+		if s.matches(e, origin) {
+			matched = append(matched, e)
+		}
+	}
+
+	count := -1
+	switch s.kind {
+	case 'p':
+		count = 1
+		sort.SliceStable(matched, func(i, j int) bool {
+			return matched[i].GetPosition().Distance(origin) < matched[j].GetPosition().Distance(origin)
+		})
+	case 'r':
+		count = 1
+		rand.Shuffle(len(matched), func(i, j int) { matched[i], matched[j] = matched[j], matched[i] })
+	}
+	if c, err := strconv.Atoi(s.params["c"]); err == nil && c > 0 {
+		count = c
+	}
+	if count >= 0 && len(matched) > count {
+		matched = matched[:count]
+	}
+	return matched
+}
+
+type levelHolder interface {
+	GetLevel() interface{}
+}
+
+// sameLevel keeps the candidates in the sender's level. Senders without
+// a level, such as the console, see every candidate.
+func sameLevel(sender CommandSender, candidates []entity.IEntity) []entity.IEntity {
+	lh, ok := sender.(levelHolder)
+	if !ok || lh.GetLevel() == nil {
+		return candidates
+	}
+	lvl := lh.GetLevel()
+	var out []entity.IEntity
+	for _, e := range candidates {
+		if eh, ok := e.(levelHolder); !ok || eh.GetLevel() == lvl {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (s *selector) applyOrigin(origin *entity.Vector3) {
+	for key, target := range map[string]*float64{"x": &origin.X, "y": &origin.Y, "z": &origin.Z} {
+		if v, ok := s.params[key]; ok {
+			if f, err := ParseCoordinate(v, *target); err == nil {
+				*target = f
+			}
+		}
+	}
+}
+
+func (s *selector) matches(e entity.IEntity, origin *entity.Vector3) bool {
+	dist := e.GetPosition().Distance(origin)
+	if r, err := strconv.ParseFloat(s.params["r"], 64); err == nil && dist > r {
+		return false
+	}
+	if rm, err := strconv.ParseFloat(s.params["rm"], 64); err == nil && dist < rm {
+		return false
+	}
+	if v, ok := s.params["type"]; ok && !negatable(v, func(t string) bool { return matchesType(e, t) }) {
+		return false
+	}
+	if v, ok := s.params["name"]; ok && !negatable(v, func(n string) bool { return strings.EqualFold(entity.EntityName(e), n) }) {
+		return false
+	}
+	if v, ok := s.params["m"]; ok {
+		g, isPlayer := e.(interface{ GetGamemode() int })
+		mode, err := strconv.Atoi(v)
+		if !isPlayer || err != nil || g.GetGamemode() != mode {
+			return false
+		}
+	}
+	if !s.inLevelRange(e) {
+		return false
+	}
+	return true
+}
+
+func (s *selector) inLevelRange(e entity.IEntity) bool {
+	_, hasMin := s.params["lm"]
+	_, hasMax := s.params["l"]
+	if !hasMin && !hasMax {
+		return true
+	}
+	xp, ok := e.(interface{ GetXPLevel() int })
+	if !ok {
+		return false
+	}
+	if lm, err := strconv.Atoi(s.params["lm"]); err == nil && xp.GetXPLevel() < lm {
+		return false
+	}
+	if l, err := strconv.Atoi(s.params["l"]); err == nil && xp.GetXPLevel() > l {
+		return false
+	}
+	return true
+}
+
+func negatable(value string, match func(string) bool) bool {
+	if strings.HasPrefix(value, "!") {
+		return !match(value[1:])
+	}
+	return match(value)
+}
+
+func matchesType(e entity.IEntity, typ string) bool {
+	typ = strings.ToLower(strings.TrimPrefix(typ, "minecraft:"))
+	if id, err := strconv.Atoi(typ); err == nil {
+		return e.GetNetworkID() == id
+	}
+	if _, ok := e.(PlayerSender); ok {
+		return typ == "player"
+	}
+	name := strings.ToLower(strings.ReplaceAll(entity.EntityName(e), " ", "_"))
+	return name == typ && name != ""
+}
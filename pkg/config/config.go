@@ -30,9 +30,61 @@ type ServerConfig struct {
 	Hardcore    bool
 	PvP         bool
 
+	DuplicateLogin string
+
 	ViewDistance int
 	TickRate     int
 
+	// NetworkTransport is "raknet", or "memory" and "proxy" for tests and
+	// servers behind the proxy.
+	NetworkTransport string
+
+	ProxyMode             bool
+	ProxyServers          string
+	ProxySecret           string
+	ProxyTrustedAddresses string
+
+	RakNetMaxSessions            int
+	RakNetMaxSessionsPerIP       int
+	RakNetPacketsPerSecond       int
+	RakNetGlobalPacketsPerSecond int
+	RakNetMaxSplitPackets        int
+	RakNetMaxSplitCount          int
+	RakNetMaxSplitBytes          int
+	RakNetSplitTimeout           int
+	RakNetSessionTimeout         int
+	RakNetHandshakeTimeout       int
+	RakNetBlockDuration          int
+	RakNetCookies                bool
+
+	EnableQuery    bool
+	QueryRateLimit int
+
+	EnableRcon         bool
+	RconPort           int
+	RconPassword       string
+	RconMaxConnections int
+	RconMaxAttempts    int
+	RconLockout        int
+
+	LuaCallbackTimeout int
+	LuaMemoryLimit     int
+	LuaMaxFaults       int
+
+	BackupInterval  int
+	BackupRetention int
+
+	Timings               bool
+	TimingsSpikeThreshold int
+	PprofAddress          string
+	MetricsAddress        string
+
+	WebAdminAddress string
+	WebAdminToken   string
+
+	PacketCaptureDir     string
+	PacketCapturePlayers string
+
 	DebugMode       bool
 	DebugItemPickup bool
 	DebugRaknet     bool
@@ -63,6 +115,7 @@ func DefaultConfig() *ServerConfig {
 		AllowFlight:     false,
 		Hardcore:        false,
 		PvP:             true,
+		DuplicateLogin:  "kick-old",
 		ViewDistance:    8,
 		TickRate:        20,
 		DebugMode:       false,
@@ -73,6 +126,42 @@ func DefaultConfig() *ServerConfig {
 		DebugEntity:     false,
 		DebugPlayer:     false,
 		Properties:      make(map[string]string),
+
+		LuaCallbackTimeout: 50,
+		LuaMemoryLimit:     16,
+		LuaMaxFaults:       5,
+
+		BackupInterval:  60,
+		BackupRetention: 10,
+
+		TimingsSpikeThreshold: 100,
+
+		PacketCaptureDir: "captures",
+
+		NetworkTransport: "raknet",
+
+		ProxyTrustedAddresses: "127.0.0.1",
+
+		RakNetMaxSessions:            200,
+		RakNetMaxSessionsPerIP:       5,
+		RakNetPacketsPerSecond:       1500,
+		RakNetGlobalPacketsPerSecond: 30000,
+		RakNetMaxSplitPackets:        16,
+		RakNetMaxSplitCount:          512,
+		RakNetMaxSplitBytes:          2097152,
+		RakNetSplitTimeout:           10,
+		RakNetSessionTimeout:         30,
+		RakNetHandshakeTimeout:       10,
+		RakNetBlockDuration:          60,
+		RakNetCookies:                true,
+
+		EnableQuery:    true,
+		QueryRateLimit: 10,
+
+		RconPort:           19132,
+		RconMaxConnections: 5,
+		RconMaxAttempts:    5,
+		RconLockout:        10,
 	}
 }
 
@@ -167,6 +256,9 @@ func Load(path string) (*ServerConfig, error) {
 		case "white-list":
 			cfg.WhiteList = parseBool(value)
 			logger.Debug("Config.Load", "key", key, "value", cfg.WhiteList)
+		case "duplicate-login":
+			cfg.DuplicateLogin = value
+			logger.Debug("Config.Load", "key", key, "value", value)
 		case "allow-flight":
 			cfg.AllowFlight = parseBool(value)
 			logger.Debug("Config.Load", "key", key, "value", cfg.AllowFlight)
@@ -181,6 +273,164 @@ func Load(path string) (*ServerConfig, error) {
 				cfg.ViewDistance = v
 				logger.Debug("Config.Load", "key", key, "value", v)
 			}
+		case "lua-callback-timeout":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.LuaCallbackTimeout = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "lua-memory-limit":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.LuaMemoryLimit = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "lua-max-faults":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.LuaMaxFaults = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "backup-interval":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.BackupInterval = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "backup-retention":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.BackupRetention = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "timings":
+			cfg.Timings = parseBool(value)
+			logger.Debug("Config.Load", "key", key, "value", cfg.Timings)
+		case "timings-spike-threshold":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.TimingsSpikeThreshold = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "network-transport":
+			cfg.NetworkTransport = value
+			logger.Debug("Config.Load", "key", key, "value", value)
+		case "proxy-mode":
+			cfg.ProxyMode = parseBool(value)
+			logger.Debug("Config.Load", "key", key, "value", cfg.ProxyMode)
+		case "proxy-servers":
+			cfg.ProxyServers = value
+			logger.Debug("Config.Load", "key", key, "value", value)
+		case "proxy-secret":
+			cfg.ProxySecret = value
+			logger.Debug("Config.Load", "key", key, "value", "********")
+		case "proxy-trusted-addresses":
+			cfg.ProxyTrustedAddresses = value
+			logger.Debug("Config.Load", "key", key, "value", value)
+		case "raknet.max-sessions":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetMaxSessions = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.max-sessions-per-ip":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetMaxSessionsPerIP = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.packets-per-second":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetPacketsPerSecond = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.global-packets-per-second":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetGlobalPacketsPerSecond = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.max-split-packets":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetMaxSplitPackets = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.max-split-count":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetMaxSplitCount = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.max-split-bytes":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetMaxSplitBytes = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.split-timeout":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetSplitTimeout = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.session-timeout":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetSessionTimeout = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.handshake-timeout":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetHandshakeTimeout = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.block-duration":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RakNetBlockDuration = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "raknet.cookies":
+			cfg.RakNetCookies = parseBool(value)
+			logger.Debug("Config.Load", "key", key, "value", cfg.RakNetCookies)
+		case "enable-query":
+			cfg.EnableQuery = parseBool(value)
+			logger.Debug("Config.Load", "key", key, "value", cfg.EnableQuery)
+		case "query-rate-limit":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.QueryRateLimit = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "enable-rcon":
+			cfg.EnableRcon = parseBool(value)
+			logger.Debug("Config.Load", "key", key, "value", cfg.EnableRcon)
+		case "rcon.port":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RconPort = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "rcon.password":
+			cfg.RconPassword = value
+			logger.Debug("Config.Load", "key", key, "value", "********")
+		case "rcon.max-connections":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RconMaxConnections = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "rcon.max-attempts":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RconMaxAttempts = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "rcon.lockout":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.RconLockout = v
+				logger.Debug("Config.Load", "key", key, "value", v)
+			}
+		case "pprof-address":
+			cfg.PprofAddress = value
+			logger.Debug("Config.Load", "key", key, "value", value)
+		case "metrics-address":
+			cfg.MetricsAddress = value
+			logger.Debug("Config.Load", "key", key, "value", value)
+		case "web-admin-address":
+			cfg.WebAdminAddress = value
+			logger.Debug("Config.Load", "key", key, "value", value)
+		case "web-admin-token":
+			cfg.WebAdminToken = value
+			logger.Debug("Config.Load", "key", key, "value", "********")
+		case "packet-capture-dir":
+			cfg.PacketCaptureDir = value
+			logger.Debug("Config.Load", "key", key, "value", value)
+		case "packet-capture-players":
+			cfg.PacketCapturePlayers = value
+			logger.Debug("Config.Load", "key", key, "value", value)
 		case "debug":
 			cfg.DebugMode = parseBool(value)
 			logger.Debug("Config.Load", "key", key, "value", cfg.DebugMode)
@@ -241,10 +491,49 @@ func (c *ServerConfig) Save(path string) error {
 		fmt.Sprintf("spawn-protection=%d", c.SpawnProtection),
 		fmt.Sprintf("online-mode=%t", c.OnlineMode),
 		fmt.Sprintf("white-list=%t", c.WhiteList),
+		fmt.Sprintf("duplicate-login=%s", c.DuplicateLogin),
 		fmt.Sprintf("allow-flight=%t", c.AllowFlight),
 		fmt.Sprintf("hardcore=%t", c.Hardcore),
 		fmt.Sprintf("pvp=%t", c.PvP),
 		fmt.Sprintf("view-distance=%d", c.ViewDistance),
+		fmt.Sprintf("lua-callback-timeout=%d", c.LuaCallbackTimeout),
+		fmt.Sprintf("lua-memory-limit=%d", c.LuaMemoryLimit),
+		fmt.Sprintf("lua-max-faults=%d", c.LuaMaxFaults),
+		fmt.Sprintf("backup-interval=%d", c.BackupInterval),
+		fmt.Sprintf("backup-retention=%d", c.BackupRetention),
+		fmt.Sprintf("timings=%t", c.Timings),
+		fmt.Sprintf("timings-spike-threshold=%d", c.TimingsSpikeThreshold),
+		fmt.Sprintf("network-transport=%s", c.NetworkTransport),
+		fmt.Sprintf("proxy-mode=%t", c.ProxyMode),
+		fmt.Sprintf("proxy-servers=%s", c.ProxyServers),
+		fmt.Sprintf("proxy-secret=%s", c.ProxySecret),
+		fmt.Sprintf("proxy-trusted-addresses=%s", c.ProxyTrustedAddresses),
+		fmt.Sprintf("raknet.max-sessions=%d", c.RakNetMaxSessions),
+		fmt.Sprintf("raknet.max-sessions-per-ip=%d", c.RakNetMaxSessionsPerIP),
+		fmt.Sprintf("raknet.packets-per-second=%d", c.RakNetPacketsPerSecond),
+		fmt.Sprintf("raknet.global-packets-per-second=%d", c.RakNetGlobalPacketsPerSecond),
+		fmt.Sprintf("raknet.max-split-packets=%d", c.RakNetMaxSplitPackets),
+		fmt.Sprintf("raknet.max-split-count=%d", c.RakNetMaxSplitCount),
+		fmt.Sprintf("raknet.max-split-bytes=%d", c.RakNetMaxSplitBytes),
+		fmt.Sprintf("raknet.split-timeout=%d", c.RakNetSplitTimeout),
+		fmt.Sprintf("raknet.session-timeout=%d", c.RakNetSessionTimeout),
+		fmt.Sprintf("raknet.handshake-timeout=%d", c.RakNetHandshakeTimeout),
+		fmt.Sprintf("raknet.block-duration=%d", c.RakNetBlockDuration),
+		fmt.Sprintf("raknet.cookies=%t", c.RakNetCookies),
+		fmt.Sprintf("enable-query=%t", c.EnableQuery),
+		fmt.Sprintf("query-rate-limit=%d", c.QueryRateLimit),
+		fmt.Sprintf("enable-rcon=%t", c.EnableRcon),
+		fmt.Sprintf("rcon.port=%d", c.RconPort),
+		fmt.Sprintf("rcon.password=%s", c.RconPassword),
+		fmt.Sprintf("rcon.max-connections=%d", c.RconMaxConnections),
+		fmt.Sprintf("rcon.max-attempts=%d", c.RconMaxAttempts),
+		fmt.Sprintf("rcon.lockout=%d", c.RconLockout),
+		fmt.Sprintf("pprof-address=%s", c.PprofAddress),
+		fmt.Sprintf("metrics-address=%s", c.MetricsAddress),
+		fmt.Sprintf("web-admin-address=%s", c.WebAdminAddress),
+		fmt.Sprintf("web-admin-token=%s", c.WebAdminToken),
+		fmt.Sprintf("packet-capture-dir=%s", c.PacketCaptureDir),
+		fmt.Sprintf("packet-capture-players=%s", c.PacketCapturePlayers),
 		fmt.Sprintf("debug=%t", c.DebugMode),
 		fmt.Sprintf("debug-item-pickup=%t", c.DebugItemPickup),
 		fmt.Sprintf("debug-raknet=%t", c.DebugRaknet),
@@ -5,7 +5,7 @@ import (
 )
 
 type DamageableEntity interface {
-	Attack(damage float64, source interface{}) bool
+	AttackByEntity(damage float64, attacker interface{}) bool
 	IsAlive() bool
 }
 
@@ -181,7 +181,7 @@ func (a *AttackEnemyBehavior) attackTarget() {
 	damage := 2.0
 
 	if damageable, ok := a.Enemy.(DamageableEntity); ok {
-		damageable.Attack(damage, a.Mob)
+		damageable.AttackByEntity(damage, a.Mob)
 	}
 }
 
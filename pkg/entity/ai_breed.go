@@ -0,0 +1,245 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+type itemHolder interface {
+	GetItemInHand() item.Item
+}
+
+type AIMate struct {
+	BaseAIGoal
+	animal    *Animal
+	speed     float64
+	partner   *Animal
+	spawnBaby int
+}
+
+func NewAIMate(a *Animal, speed float64) *AIMate {
+	g := &AIMate{
+		animal: a,
+		speed:  speed,
+	}
+	g.SetMutexBits(3)
+	return g
+}
+func (g *AIMate) ShouldExecute() bool {
+	if !g.animal.IsInLove() || g.animal.Entity.Level == nil {
+		return false
+	}
+	g.partner = g.findPartner()
+	return g.partner != nil
+}
+func (g *AIMate) findPartner() *Animal {
+	e := g.animal.Entity
+	bb := e.BoundingBox.Grow(8.0, 8.0, 8.0)
+
+	var closest *Animal
+	closestDistSq := math.MaxFloat64
+	for _, ne := range e.Level.GetNearbyEntities(bb, g.animal.outer) {
+		b, ok := ne.(Breedable)
+		if !ok {
+			continue
+		}
+		other := b.GetAnimal()
+		if other == g.animal || other.Entity.NetworkID != e.NetworkID || !other.IsInLove() {
+			continue
+		}
+		if d := e.Position.DistanceSquared(other.Entity.Position); d < closestDistSq {
+			closestDistSq = d
+			closest = other
+		}
+	}
+	return closest
+}
+func (g *AIMate) ShouldContinueExecuting() bool {
+	return g.partner != nil && !g.partner.Entity.Closed && g.partner.IsInLove() && g.spawnBaby < 60
+}
+
+func (g *AIMate) ResetTask() {
+	g.partner = nil
+	g.spawnBaby = 0
+}
+func (g *AIMate) UpdateTask() {
+	p := g.partner.Entity
+	e := g.animal.Entity
+	e.LookHelper.SetLookPosition(p.Position.X, p.Position.Y+p.EyeHeight, p.Position.Z, 10.0, 40.0)
+	e.MoveHelper.SetMoveTo(p.Position.X, p.Position.Y, p.Position.Z, g.speed)
+	g.spawnBaby++
+	if g.spawnBaby >= 60 && e.Position.DistanceSquared(p.Position) < 9.0 {
+		g.breed()
+	}
+}
+func (g *AIMate) breed() {
+	baby := CreatePassiveMob(g.animal.Entity.NetworkID)
+	if baby == nil {
+		return
+	}
+
+	g.animal.SetInLove(false)
+	g.animal.AnimalAge = BreedingCooldown
+	g.partner.SetInLove(false)
+	g.partner.AnimalAge = BreedingCooldown
+
+	if inheritor, ok := baby.(interface{ InheritFrom(a, b Breedable) }); ok {
+		inheritor.InheritFrom(g.animal.outer, g.partner.outer)
+	}
+
+	e := g.animal.Entity
+	ba := baby.GetAnimal()
+	ba.SetBaby(true)
+	ba.Entity.SetPosition(NewVector3(e.Position.X, e.Position.Y, e.Position.Z))
+	ba.Entity.Yaw = rand.Float64() * 360
+	ba.Entity.Level = e.Level
+
+	e.Level.UpdateEntityMetadata(g.animal.outer)
+	e.Level.UpdateEntityMetadata(g.partner.outer)
+	e.Level.SpawnEntity(baby)
+	DropExperience(e.Level, e.Position, RandomExperience(BreedingExperienceMin, BreedingExperienceMax))
+}
+
+type AITempt struct {
+	BaseAIGoal
+	animal   *Animal
+	speed    float64
+	player   IEntity
+	cooldown int
+}
+
+func NewAITempt(a *Animal, speed float64) *AITempt {
+	g := &AITempt{
+		animal: a,
+		speed:  speed,
+	}
+	g.SetMutexBits(3)
+	return g
+}
+func (g *AITempt) ShouldExecute() bool {
+	if g.cooldown > 0 {
+		g.cooldown--
+		return false
+	}
+	if g.animal.FeedFoodID <= 0 || g.animal.Entity.Level == nil {
+		return false
+	}
+	g.player = g.findTempter()
+	return g.player != nil
+}
+func (g *AITempt) findTempter() IEntity {
+	e := g.animal.Entity
+	var closest IEntity
+	closestDistSq := 10.0 * 10.0
+	for _, pl := range e.Level.GetPlayers() {
+		if !g.isTempting(pl) {
+			continue
+		}
+		if d := e.Position.DistanceSquared(pl.GetPosition()); d < closestDistSq {
+			closestDistSq = d
+			closest = pl
+		}
+	}
+	return closest
+}
+func (g *AITempt) isTempting(pl IEntity) bool {
+	holder, ok := pl.(itemHolder)
+	if !ok {
+		return false
+	}
+	return g.animal.CanBeFedWith(holder.GetItemInHand().ID)
+}
+func (g *AITempt) ShouldContinueExecuting() bool {
+	if g.player == nil || !g.isTempting(g.player) {
+		return false
+	}
+	return g.animal.Entity.Position.DistanceSquared(g.player.GetPosition()) < 10.0*10.0
+}
+
+func (g *AITempt) ResetTask() {
+	g.player = nil
+	g.cooldown = 100
+	g.animal.Entity.MoveHelper.IsMoving = false
+}
+func (g *AITempt) UpdateTask() {
+	e := g.animal.Entity
+	pos := g.player.GetPosition()
+	e.LookHelper.SetLookPosition(pos.X, pos.Y+g.player.GetEyeHeight(), pos.Z, 30.0, 40.0)
+	if e.Position.DistanceSquared(pos) < 2.5*2.5 {
+		e.MoveHelper.IsMoving = false
+		return
+	}
+	e.MoveHelper.SetMoveTo(pos.X, pos.Y, pos.Z, g.speed)
+}
+
+type AIFollowParent struct {
+	BaseAIGoal
+	animal *Animal
+	speed  float64
+	parent *Animal
+	delay  int
+}
+
+func NewAIFollowParent(a *Animal, speed float64) *AIFollowParent {
+	g := &AIFollowParent{
+		animal: a,
+		speed:  speed,
+	}
+	g.SetMutexBits(1)
+	return g
+}
+func (g *AIFollowParent) ShouldExecute() bool {
+	if !g.animal.IsBaby() || g.animal.Entity.Level == nil {
+		return false
+	}
+	e := g.animal.Entity
+	bb := e.BoundingBox.Grow(8.0, 4.0, 8.0)
+
+	var closest *Animal
+	closestDistSq := math.MaxFloat64
+	for _, ne := range e.Level.GetNearbyEntities(bb, g.animal.outer) {
+		b, ok := ne.(Breedable)
+		if !ok {
+			continue
+		}
+		other := b.GetAnimal()
+		if other.Entity.NetworkID != e.NetworkID || other.IsBaby() {
+			continue
+		}
+		if d := e.Position.DistanceSquared(other.Entity.Position); d < closestDistSq {
+			closestDistSq = d
+			closest = other
+		}
+	}
+	if closest == nil || closestDistSq < 9.0 {
+		return false
+	}
+	g.parent = closest
+	return true
+}
+func (g *AIFollowParent) ShouldContinueExecuting() bool {
+	if !g.animal.IsBaby() || g.parent == nil || g.parent.Entity.Closed {
+		return false
+	}
+	d := g.animal.Entity.Position.DistanceSquared(g.parent.Entity.Position)
+	return d >= 9.0 && d <= 256.0
+}
+
+func (g *AIFollowParent) StartExecuting() {
+	g.delay = 0
+}
+
+func (g *AIFollowParent) ResetTask() {
+	g.parent = nil
+}
+func (g *AIFollowParent) UpdateTask() {
+	g.delay--
+	if g.delay > 0 {
+		return
+	}
+	g.delay = 10
+	p := g.parent.Entity.Position
+	g.animal.Entity.MoveHelper.SetMoveTo(p.X, p.Y, p.Z, g.speed)
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/block"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+type AIEatGrass struct {
+	BaseAIGoal
+	sheep *Sheep
+	timer int
+}
+
+func NewAIEatGrass(s *Sheep) *AIEatGrass {
+	g := &AIEatGrass{sheep: s}
+	g.SetMutexBits(7)
+	return g
+}
+func (g *AIEatGrass) ShouldExecute() bool {
+	chance := 1000
+	if g.sheep.IsBaby() {
+		chance = 50
+	}
+	if rand.Intn(chance) != 0 || g.sheep.Animal.Entity.Level == nil {
+		return false
+	}
+	x, y, z := g.feetPosition()
+	lvl := g.sheep.Animal.Entity.Level
+	if lvl.GetBlock(x, y, z).ID == block.TALL_GRASS {
+		return true
+	}
+	return lvl.GetBlock(x, y-1, z).ID == block.GRASS
+}
+func (g *AIEatGrass) feetPosition() (int32, int32, int32) {
+	pos := g.sheep.Animal.Entity.Position
+	return int32(math.Floor(pos.X)), int32(math.Floor(pos.Y)), int32(math.Floor(pos.Z))
+}
+
+func (g *AIEatGrass) StartExecuting() {
+	e := g.sheep.Animal.Entity
+	g.timer = 40
+	e.MoveHelper.IsMoving = false
+	e.Level.BroadcastEntityEvent(e.ID, protocol.EntityEventEatGrass)
+}
+
+func (g *AIEatGrass) ShouldContinueExecuting() bool {
+	return g.timer > 0
+}
+
+func (g *AIEatGrass) ResetTask() {
+	g.timer = 0
+}
+func (g *AIEatGrass) UpdateTask() {
+	g.timer--
+	if g.timer != 4 {
+		return
+	}
+	lvl := g.sheep.Animal.Entity.Level
+	x, y, z := g.feetPosition()
+	if lvl.GetBlock(x, y, z).ID == block.TALL_GRASS {
+		lvl.UpdateBlock(x, y, z, block.AIR, 0)
+		g.sheep.OnEatGrass()
+	} else if lvl.GetBlock(x, y-1, z).ID == block.GRASS {
+		lvl.UpdateBlock(x, y-1, z, block.DIRT, 0)
+		g.sheep.OnEatGrass()
+	}
+}
@@ -0,0 +1,93 @@
+package entity
+
+type AISit struct {
+	BaseAIGoal
+	tameable *TameableAnimal
+}
+
+func NewAISit(t *TameableAnimal) *AISit {
+	g := &AISit{tameable: t}
+	g.SetMutexBits(5)
+	return g
+}
+func (g *AISit) ShouldExecute() bool {
+	return g.tameable.IsTamed() && g.tameable.IsSitting() && !g.tameable.Animal.Entity.IsInWater()
+}
+
+func (g *AISit) ShouldContinueExecuting() bool {
+	return g.tameable.IsSitting()
+}
+
+func (g *AISit) StartExecuting() {
+	e := g.tameable.Animal.Entity
+	e.MoveHelper.IsMoving = false
+	e.Motion.X = 0
+	e.Motion.Z = 0
+}
+
+type AIFollowOwner struct {
+	BaseAIGoal
+	tameable *TameableAnimal
+	speed    float64
+	owner    IEntity
+	delay    int
+}
+
+func NewAIFollowOwner(t *TameableAnimal, speed float64) *AIFollowOwner {
+	g := &AIFollowOwner{
+		tameable: t,
+		speed:    speed,
+	}
+	g.SetMutexBits(3)
+	return g
+}
+func (g *AIFollowOwner) ShouldExecute() bool {
+	if g.tameable.IsSitting() {
+		return false
+	}
+	owner := g.tameable.findOwner()
+	if owner == nil {
+		return false
+	}
+	if g.tameable.Animal.Entity.Position.DistanceSquared(owner.GetPosition()) < 10.0*10.0 {
+		return false
+	}
+	g.owner = owner
+	return true
+}
+func (g *AIFollowOwner) ShouldContinueExecuting() bool {
+	if g.owner == nil || g.tameable.IsSitting() {
+		return false
+	}
+	return g.tameable.Animal.Entity.Position.DistanceSquared(g.owner.GetPosition()) > 2.0*2.0
+}
+
+func (g *AIFollowOwner) StartExecuting() {
+	g.delay = 0
+	g.tameable.Animal.Entity.Metadata.SetLong(DataOwnerEID, g.owner.GetID())
+}
+
+func (g *AIFollowOwner) ResetTask() {
+	g.owner = nil
+	g.tameable.Animal.Entity.MoveHelper.IsMoving = false
+}
+func (g *AIFollowOwner) UpdateTask() {
+	e := g.tameable.Animal.Entity
+	pos := g.owner.GetPosition()
+	e.LookHelper.SetLookPosition(pos.X, pos.Y+g.owner.GetEyeHeight(), pos.Z, 10.0, 40.0)
+
+	g.delay--
+	if g.delay > 0 {
+		return
+	}
+	g.delay = 10
+
+	if e.Position.DistanceSquared(pos) >= 12.0*12.0 {
+		e.SetPosition(NewVector3(pos.X, pos.Y, pos.Z))
+		e.Motion.X = 0
+		e.Motion.Y = 0
+		e.Motion.Z = 0
+		return
+	}
+	e.MoveHelper.SetMoveTo(pos.X, pos.Y, pos.Z, g.speed)
+}
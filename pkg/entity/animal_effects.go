@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+)
+
+// EffectTarget is an entity that status effects can be applied to.
+type EffectTarget interface {
+	IEntity
+	GetName() string
+	AddEffect(e *effect.Effect) bool
+	RemoveEffect(id int) bool
+	ClearEffects() int
+}
+
+var (
+	_ EffectTarget = (*Living)(nil)
+	_ EffectTarget = (*Animal)(nil)
+)
+
+// Animals are not Living, so their effects are kept by a Living that
+// shares the animal's Entity. Healing and damage from effects are routed
+// back through the animal so they go through its own damage handling.
+func (a *Animal) effectState() *Living {
+	if a.effects == nil {
+		a.effects = &Living{Entity: a.Entity, effects: make(map[int]*effect.Effect)}
+		a.effects.SetEffectHandler(animalEffectHandler{a})
+	}
+	return a.effects
+}
+
+func (a *Animal) AddEffect(e *effect.Effect) bool {
+	return a.effectState().AddEffect(e)
+}
+
+func (a *Animal) RemoveEffect(id int) bool {
+	return a.effectState().RemoveEffect(id)
+}
+
+func (a *Animal) ClearEffects() int {
+	return a.effectState().ClearEffects()
+}
+
+func (a *Animal) HasEffect(id int) bool {
+	return a.effectState().HasEffect(id)
+}
+
+func (a *Animal) GetEffect(id int) *effect.Effect {
+	return a.effectState().GetEffect(id)
+}
+
+func (a *Animal) GetEffects() []*effect.Effect {
+	return a.effectState().GetEffects()
+}
+
+func (a *Animal) GetAbsorption() float64 {
+	return a.effectState().GetAbsorption()
+}
+
+func (a *Animal) SetAbsorption(amount float64) {
+	a.effectState().SetAbsorption(amount)
+}
+
+type animalEffectHandler struct {
+	animal *Animal
+}
+
+func (h animalEffectHandler) Heal(amount float64) {
+	h.animal.effects.Heal(amount)
+}
+
+func (h animalEffectHandler) Attack(damage float64, cause int) bool {
+	victim, ok := h.animal.outer.(Damageable)
+	if !ok {
+		return false
+	}
+	return Damage(victim, NewSimpleDamageSource(cause, damage)) != nil
+}
+
+func (h animalEffectHandler) OnEffectAdded(e *effect.Effect, modify bool) {}
+
+func (h animalEffectHandler) OnEffectRemoved(e *effect.Effect) {}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+)
+
+func TestAnimalEffects(t *testing.T) {
+	cow := NewCow()
+	health := cow.GetHealth()
+	if !cow.AddEffect(effect.NewEffect(effect.InstantDamage, 1, 0)) {
+		t.Fatal("instant damage was refused")
+	}
+	if got := cow.GetHealth(); got != health-6 {
+		t.Errorf("health after instant damage = %d, want %d", got, health-6)
+	}
+
+	cow.NoDamageTicks = 0
+	cow.AddEffect(effect.NewEffect(effect.Resistance, 600, 1))
+	result := Damage(cow, NewSimpleDamageSource(DamageCauseEntityAttack, 5))
+	if result == nil || result.Damage != 3 {
+		t.Errorf("damage with Resistance II = %+v, want 3", result)
+	}
+
+	sheep := NewSheepWithColor(2)
+	sheep.AddEffect(effect.NewEffect(effect.Speed, 600, 2))
+	loaded := LoadPassiveMob(SavePassiveMob(sheep))
+	e := loaded.GetAnimal().GetEffect(effect.Speed)
+	if e == nil || e.Amplifier != 2 || e.Duration != 600 {
+		t.Fatalf("loaded speed effect = %+v", e)
+	}
+}
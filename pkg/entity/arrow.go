@@ -2,11 +2,16 @@ package entity
 
 import (
 	"math"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
 )
 const ArrowNetworkID = 80
 type Arrow struct {
 	*Projectile
 	PunchKnockback float64
+
+	collided bool
 }
 func NewArrow(shooterID int64, critical bool) *Arrow {
 	a := &Arrow{
@@ -24,6 +29,17 @@ func NewArrow(shooterID int64, critical bool) *Arrow {
 
 	return a
 }
+func NewArrowFromBow(shooterID int64, bow item.Item, force float64) *Arrow {
+	a := NewArrow(shooterID, item.IsBowCritical(force))
+	a.Projectile.BaseDamage += enchantment.ArrowDamageBonus(bow)
+	if punch := enchantment.PunchLevel(bow); punch > 0 {
+		a.SetPunchKnockback(float64(punch))
+	}
+	if seconds := enchantment.FlameSeconds(bow); seconds > 0 {
+		a.Entity.FireTicks = seconds * 20
+	}
+	return a
+}
 type ArrowTickResult struct {
 	ProjectileTickResult
 	ShowCriticalParticle bool
@@ -86,3 +102,57 @@ func (a *Arrow) SetPunchKnockback(value float64) {
 func (a *Arrow) GetPunchKnockback() float64 {
 	return a.PunchKnockback
 }
+func (a *Arrow) Tick(currentTick int64) bool {
+	if a.Closed {
+		return false
+	}
+	lvl := a.Entity.Level
+	if lvl == nil || a.BoundingBox == nil {
+		return a.Entity.Tick(currentTick)
+	}
+	a.LastPos.X, a.LastPos.Y, a.LastPos.Z = a.Position.X, a.Position.Y, a.Position.Z
+
+	searchBB := a.BoundingBox.AddCoord(a.Motion.X, a.Motion.Y, a.Motion.Z).Grow(1, 1, 1)
+	nearby := lvl.GetNearbyEntities(searchBB, a)
+	result := a.TickArrow(nearby, a.collided)
+	if result.HitEntityID != 0 {
+		for _, target := range nearby {
+			if target.GetID() == result.HitEntityID {
+				a.hitEntity(lvl, target)
+				break
+			}
+		}
+		a.Close()
+		return false
+	}
+	if result.ShouldClose {
+		a.Close()
+		return false
+	}
+	if !a.HadCollision {
+		mx, my, mz := a.Motion.X, a.Motion.Y, a.Motion.Z
+		a.Move(mx, my, mz)
+		a.collided = a.Motion.X != mx || a.Motion.Y != my || a.Motion.Z != mz
+	}
+	return true
+}
+func (a *Arrow) hitEntity(lvl ILevel, target IEntity) {
+	victim, ok := target.(Damageable)
+	if !ok {
+		return
+	}
+	var shooter IEntity
+	for _, e := range append(lvl.GetPlayers(), lvl.GetEntities()...) {
+		if e.GetID() == a.ShootingEntityID {
+			shooter = e
+			break
+		}
+	}
+	hit := a.CalcArrowHit()
+	src := NewChildEntityDamageSource(DamageCauseProjectile, hit.Damage, shooter, a)
+	src.Knockback = a.PunchKnockback * 0.5
+	if hit.TransferFire {
+		src.FireSeconds = hit.FireDuration / 20
+	}
+	Damage(victim, src)
+}
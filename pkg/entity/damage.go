@@ -1,5 +1,22 @@
 package entity
 
+import (
+	"math"
+
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+)
+
+const (
+	DefaultNoDamageTicks   = 10
+	DefaultKnockback       = 0.4
+	KnockbackVerticalLimit = 0.4
+	CriticalBonus          = 0.5
+	DeathAnimationTicks    = 20
+)
+
 type DamageSource interface {
 	Cause() int
 	GetDamage() float64
@@ -9,6 +26,11 @@ type EntityDamageSource struct {
 	DamageCause int
 	Damage      float64
 	Attacker    IEntity
+	Child       IEntity
+	Origin      *Vector3
+	Critical    bool
+	Knockback   float64
+	FireSeconds int
 }
 
 func NewEntityDamageSource(cause int, damage float64, attacker IEntity) *EntityDamageSource {
@@ -19,6 +41,15 @@ func NewEntityDamageSource(cause int, damage float64, attacker IEntity) *EntityD
 	}
 }
 
+func NewChildEntityDamageSource(cause int, damage float64, attacker, child IEntity) *EntityDamageSource {
+	return &EntityDamageSource{
+		DamageCause: cause,
+		Damage:      damage,
+		Attacker:    attacker,
+		Child:       child,
+	}
+}
+
 func (e *EntityDamageSource) Cause() int {
 	return e.DamageCause
 }
@@ -31,6 +62,10 @@ func (e *EntityDamageSource) GetAttacker() IEntity {
 	return e.Attacker
 }
 
+func (e *EntityDamageSource) GetChild() IEntity {
+	return e.Child
+}
+
 type SimpleDamageSource struct {
 	DamageCause int
 	Damage      float64
@@ -51,6 +86,402 @@ func (s *SimpleDamageSource) GetDamage() float64 {
 	return s.Damage
 }
 
+type Damageable interface {
+	IEntity
+	GetHealth() int
+	SetHealth(health int)
+}
+
+type Armored interface {
+	GetArmorContents() []item.Item
+	SetArmorItem(slot int, it item.Item) error
+}
+
+type DamageHandler interface {
+	OnDamaged(result *DamageResult)
+}
+
+type DamageResult struct {
+	Victim    Damageable
+	Source    DamageSource
+	Event     *event.EntityDamageEvent
+	Damage    float64
+	Knockback float64
+	Killed    bool
+}
+
+type damageGuard interface {
+	IsInvulnerableTo(cause int) bool
+}
+
+type effectHolder interface {
+	GetEffect(id int) *effect.Effect
+}
+
+type absorber interface {
+	GetAbsorption() float64
+	SetAbsorption(amount float64)
+}
+
+type creativeChecker interface {
+	IsCreative() bool
+}
+
+type damageRecorder interface {
+	recordDamage(source DamageSource, damage float64)
+	GetNoDamageTicks() int
+	GetLastDamage() float64
+}
+
+func Damage(victim Damageable, source DamageSource) *DamageResult {
+	cause := source.Cause()
+	if victim.GetHealth() <= 0 {
+		return nil
+	}
+	if g, ok := victim.(damageGuard); ok && g.IsInvulnerableTo(cause) {
+		return nil
+	}
+	// A hit during the invulnerability window only lands if it is stronger
+	// than the one that started it, and then only deals the difference.
+	recorder, _ := victim.(damageRecorder)
+	var covered float64
+	if recorder != nil && recorder.GetNoDamageTicks() > 0 {
+		covered = recorder.GetLastDamage()
+	}
+
+	called, evt := newDamageEvent(victim, source)
+	armorHit := applyDamageModifiers(evt, victim, source)
+	if covered > 0 && evt.GetFinalDamage() <= covered {
+		return nil
+	}
+	event.Call(called)
+	if evt.IsCancelled() {
+		return nil
+	}
+
+	result := &DamageResult{
+		Victim: victim,
+		Source: source,
+		Event:  evt,
+		Damage: evt.GetFinalDamage(),
+	}
+	if result.Damage <= covered {
+		return nil
+	}
+
+	damage := result.Damage - covered
+	if a, ok := victim.(absorber); ok {
+		if absorption := a.GetAbsorption(); absorption > 0 {
+			absorbed := math.Min(absorption, damage)
+			a.SetAbsorption(absorption - absorbed)
+			damage -= absorbed
+		}
+	}
+	if damage > 0 {
+		lost := int(math.Round(damage))
+		if lost < 1 {
+			lost = 1
+		}
+		victim.SetHealth(victim.GetHealth() - lost)
+	}
+	if recorder != nil {
+		recorder.recordDamage(source, result.Damage)
+	}
+	result.Killed = victim.GetHealth() <= 0
+
+	if armorHit {
+		if armored, ok := victim.(Armored); ok {
+			if c, ok := victim.(creativeChecker); !ok || !c.IsCreative() {
+				DamageArmor(armored)
+			}
+		}
+	}
+	if src, ok := source.(*EntityDamageSource); ok {
+		applyEntityDamageEffects(result, src)
+	}
+
+	if h, ok := victim.(DamageHandler); ok {
+		h.OnDamaged(result)
+	} else {
+		defaultOnDamaged(result)
+	}
+	return result
+}
+
+func newDamageEvent(victim IEntity, source DamageSource) (event.Cancellable, *event.EntityDamageEvent) {
+	cause, damage := source.Cause(), source.GetDamage()
+	if src, ok := source.(*EntityDamageSource); ok && src.Attacker != nil {
+		if src.Child != nil {
+			evt := event.NewEntityDamageByChildEntityEvent(victim.GetID(), src.Attacker.GetID(), src.Child.GetID(), EventCause(cause), damage)
+			return evt, evt.EntityDamageEvent
+		}
+		evt := event.NewEntityDamageByEntityEvent(victim.GetID(), src.Attacker.GetID(), EventCause(cause), damage)
+		return evt, evt.EntityDamageEvent
+	}
+	evt := event.NewEntityDamageEvent(victim.GetID(), EventCause(cause), damage)
+	return evt, evt
+}
+
+// EventCause converts a damage cause to the numbering used by the event
+// package, where Custom and Starvation are the other way round.
+func EventCause(cause int) int {
+	switch cause {
+	case DamageCauseStarvation:
+		return event.CauseStarvation
+	case DamageCauseCustom:
+		return event.CauseCustom
+	}
+	return cause
+}
+
+// scaleDamage records the change from damage to reduced as a multiplier
+// on modifier and returns the new running damage.
+func scaleDamage(evt *event.EntityDamageEvent, modifier int, damage, reduced float64) float64 {
+	if damage <= 0 {
+		return damage
+	}
+	evt.AddModifier(modifier, reduced/damage)
+	return reduced
+}
+
+func applyDamageModifiers(evt *event.EntityDamageEvent, victim IEntity, source DamageSource) bool {
+	cause := source.Cause()
+	damage := source.GetDamage()
+
+	if src, ok := source.(*EntityDamageSource); ok {
+		if src.Critical {
+			damage = scaleDamage(evt, event.ModifierCritical, damage, damage*(1+CriticalBonus))
+		}
+		if holder, ok := src.Attacker.(effectHolder); ok && cause == DamageCauseEntityAttack {
+			if e := holder.GetEffect(effect.Strength); e != nil {
+				damage = scaleDamage(evt, event.ModifierStrength, damage, damage+3*float64(e.Level()))
+			}
+			if e := holder.GetEffect(effect.Weakness); e != nil {
+				damage = scaleDamage(evt, event.ModifierWeakness, damage, math.Max(0, damage-4*float64(e.Level())))
+			}
+		}
+	}
+
+	armorHit := false
+	if armored, ok := victim.(Armored); ok {
+		armor := armored.GetArmorContents()
+		if ArmorApplies(cause) {
+			if points := ArmorPoints(armor); points > 0 {
+				damage = scaleDamage(evt, event.ModifierArmor, damage, NewDamageCalculator().CalculateDamage(damage, points, 0))
+				armorHit = true
+			}
+		}
+		if kind, ok := ProtectionKind(cause); ok {
+			if reduced := enchantment.ApplyProtection(damage, armor, kind); reduced < damage {
+				damage = scaleDamage(evt, event.ModifierProtection, damage, reduced)
+			}
+		}
+	}
+
+	if holder, ok := victim.(effectHolder); ok {
+		switch cause {
+		case DamageCauseFire, DamageCauseFireTick, DamageCauseLava:
+			if holder.GetEffect(effect.FireResistance) != nil {
+				evt.AddModifier(event.ModifierResistance, 0)
+				return armorHit
+			}
+		case DamageCauseVoid, DamageCauseSuicide:
+			return armorHit
+		}
+		if e := holder.GetEffect(effect.Resistance); e != nil {
+			evt.AddModifier(event.ModifierResistance, 1-math.Min(1, 0.2*float64(e.Level())))
+		}
+	}
+	return armorHit
+}
+
+func applyEntityDamageEffects(result *DamageResult, src *EntityDamageSource) {
+	victim := result.Victim
+	origin := src.Origin
+	if origin == nil {
+		if src.Attacker != nil {
+			origin = src.Attacker.GetPosition()
+		} else if src.Child != nil {
+			origin = src.Child.GetPosition()
+		}
+	}
+	if origin != nil {
+		result.Knockback = result.Event.GetKnockBack() + src.Knockback
+		pos := origin
+		if k, ok := victim.(interface {
+			KnockBack(attackerX, attackerZ float64, base, verticalLimit float64)
+		}); ok && result.Knockback > 0 {
+			k.KnockBack(pos.X, pos.Z, result.Knockback, KnockbackVerticalLimit)
+		}
+	}
+	if src.FireSeconds > 0 {
+		if b, ok := victim.(interface{ SetOnFire(seconds int) }); ok {
+			b.SetOnFire(src.FireSeconds)
+		}
+	}
+	if src.Attacker == nil || src.DamageCause != DamageCauseEntityAttack {
+		return
+	}
+	if armored, ok := victim.(Armored); ok {
+		if thorns := enchantment.ThornsDamage(armored.GetArmorContents()); thorns > 0 {
+			if attacker, ok := src.Attacker.(Damageable); ok {
+				Damage(attacker, NewEntityDamageSource(DamageCauseThorns, thorns, victim))
+			}
+		}
+	}
+}
+
+func defaultOnDamaged(result *DamageResult) {
+	victim := result.Victim
+	lvl := entityLevel(victim)
+	if lvl != nil {
+		lvl.BroadcastEntityEvent(victim.GetID(), 2)
+	}
+	if !result.Killed {
+		return
+	}
+	var drops []interface{}
+	if d, ok := victim.(interface{ GetDrops() []interface{} }); ok {
+		drops = d.GetDrops()
+	}
+	evt := event.NewEntityDeathEvent(victim.GetID(), drops)
+	evt.Cause = EventCause(result.Source.Cause())
+	if d, ok := victim.(ExperienceDropper); ok && killedByCollector(result.Source) {
+		evt.Experience = d.GetDropExperience()
+	}
+	event.Call(evt)
+	if lvl != nil {
+		lvl.BroadcastEntityEvent(victim.GetID(), 3)
+		pos := victim.GetPosition()
+		for _, drop := range evt.Drops {
+			it, ok := drop.(item.Item)
+			if !ok || it.IsAir() {
+				continue
+			}
+			ent := NewItemEntity(it)
+			ent.Entity.SetPosition(NewVector3(pos.X, pos.Y+0.5, pos.Z))
+			ent.Level = lvl
+			lvl.SpawnEntity(ent)
+		}
+		DropExperience(lvl, pos, evt.Experience)
+	}
+	if d, ok := victim.(interface{ setDead() }); ok {
+		d.setDead()
+	}
+}
+
+func killedByCollector(source DamageSource) bool {
+	src, ok := source.(*EntityDamageSource)
+	if !ok {
+		return false
+	}
+	_, ok = src.Attacker.(ExperienceCollector)
+	return ok
+}
+
+func entityLevel(e IEntity) ILevel {
+	if l, ok := e.(interface{ currentLevel() ILevel }); ok {
+		return l.currentLevel()
+	}
+	return nil
+}
+
+func ArmorApplies(cause int) bool {
+	switch cause {
+	case DamageCauseContact, DamageCauseEntityAttack, DamageCauseProjectile,
+		DamageCauseFire, DamageCauseLava, DamageCauseBlockExplosion,
+		DamageCauseEntityExplosion, DamageCauseLightning, DamageCauseAnvil:
+		return true
+	}
+	return false
+}
+
+func ProtectionKind(cause int) (int, bool) {
+	switch cause {
+	case DamageCauseVoid, DamageCauseSuicide, DamageCauseStarvation, DamageCauseCustom:
+		return 0, false
+	case DamageCauseFire, DamageCauseFireTick, DamageCauseLava:
+		return enchantment.DamageKindFire, true
+	case DamageCauseBlockExplosion, DamageCauseEntityExplosion:
+		return enchantment.DamageKindExplosion, true
+	case DamageCauseProjectile:
+		return enchantment.DamageKindProjectile, true
+	case DamageCauseFall:
+		return enchantment.DamageKindFall, true
+	}
+	return enchantment.DamageKindGeneric, true
+}
+
+func ArmorPoints(armor []item.Item) int {
+	points := 0
+	for _, piece := range armor {
+		points += ArmorProtection(piece.ID)
+	}
+	return points
+}
+
+func DamageArmor(a Armored) {
+	for slot, piece := range a.GetArmorContents() {
+		if item.GetArmorPieceInfo(piece.ID) == nil {
+			continue
+		}
+		res := item.UseOnArmor(piece.ID, piece.Meta, piece.NBTData, enchantment.UnbreakingLevel(piece))
+		if res.DamageIncrease == 0 {
+			continue
+		}
+		if res.IsBroken {
+			a.SetArmorItem(slot, item.Air())
+			continue
+		}
+		piece.Meta += res.DamageIncrease
+		a.SetArmorItem(slot, piece)
+	}
+}
+
+func UseWeapon(weapon item.Item) item.Item {
+	if !item.IsTool(weapon.ID) {
+		return weapon
+	}
+	if weapon.UseOn(item.UseTypeBreak, true, 0, 0, enchantment.UnbreakingLevel(weapon)) {
+		return item.Air()
+	}
+	return weapon
+}
+
+func MeleeDamage(weapon item.Item, target IEntity) float64 {
+	networkID := target.GetNetworkID()
+	return WeaponDamage(weapon.ID) + enchantment.DamageBonus(weapon, IsUndead(networkID), IsArthropod(networkID))
+}
+
+func DamageByExplosion(lvl ILevel, center *Vector3, size float64, source IEntity) {
+	radius := size * 2
+	bb := NewAxisAlignedBB(center.X-radius-1, center.Y-radius-1, center.Z-radius-1,
+		center.X+radius+1, center.Y+radius+1, center.Z+radius+1)
+	cause := DamageCauseBlockExplosion
+	if source != nil {
+		cause = DamageCauseEntityExplosion
+	}
+	for _, e := range append(lvl.GetNearbyEntities(bb, source), lvl.GetPlayers()...) {
+		victim, ok := e.(Damageable)
+		if !ok || !bb.IntersectsWith(e.GetBoundingBox()) {
+			continue
+		}
+		distance := e.GetPosition().Distance(center) / radius
+		if distance > 1 {
+			continue
+		}
+		impact := 1 - distance
+		src := NewEntityDamageSource(cause, math.Floor((impact*impact+impact)/2*8*radius+1), source)
+		src.Origin = center
+		src.Knockback = impact
+		Damage(victim, src)
+	}
+}
+
+func FallDamage(distance float64) float64 {
+	return math.Ceil(distance - 3)
+}
+
 type DamageCalculator struct{}
 
 func NewDamageCalculator() *DamageCalculator {
@@ -75,85 +506,24 @@ func (d *DamageCalculator) CalculateDamage(baseDamage float64, armorPoints int,
 }
 
 func WeaponDamage(itemID int) float64 {
-	damageTable := map[int]float64{
-
-		268: 4,
-		283: 4,
-		272: 5,
-		267: 6,
-		276: 7,
-
-		271: 3,
-		286: 3,
-		275: 3,
-		258: 5,
-		279: 6,
-
-		270: 2,
-		285: 2,
-		274: 3,
-		257: 4,
-		278: 5,
-
-		269: 1,
-		284: 1,
-		273: 2,
-		256: 3,
-		277: 4,
-	}
-
-	if damage, ok := damageTable[itemID]; ok {
-		return damage
+	if info := item.GetToolInfo(itemID); info != nil && info.BaseDamage > 0 {
+		return info.BaseDamage
 	}
 	return 1
 }
 
 func ArmorProtection(itemID int) int {
-	protectionTable := map[int]int{
-
-		298: 1,
-		299: 3,
-		300: 2,
-		301: 1,
-
-		302: 2,
-		303: 5,
-		304: 4,
-		305: 1,
-
-		306: 2,
-		307: 6,
-		308: 5,
-		309: 2,
-
-		314: 2,
-		315: 5,
-		316: 3,
-		317: 1,
-
-		310: 3,
-		311: 8,
-		312: 6,
-		313: 3,
-	}
-
-	if protection, ok := protectionTable[itemID]; ok {
-		return protection
+	if info := item.GetArmorPieceInfo(itemID); info != nil {
+		return info.Defense
 	}
 	return 0
 }
 
 const (
 	DamageCauseLightning    = 16
-	DamageCauseFreezing     = 17
-	DamageCauseCampfire     = 18
-	DamageCauseSonicBoom    = 19
-	DamageCauseFlyIntoWall  = 20
 	DamageCauseWitherEffect = 21
 	DamageCauseThorns       = 22
 	DamageCauseAnvil        = 23
-	DamageCauseStalactite   = 24
-	DamageCauseStalagmite   = 25
 )
 
 func DamageCauseName(cause int) string {
@@ -175,6 +545,9 @@ func DamageCauseName(cause int) string {
 		DamageCauseStarvation:      "starvation",
 		DamageCauseCustom:          "custom",
 		DamageCauseLightning:       "lightning",
+		DamageCauseWitherEffect:    "wither",
+		DamageCauseThorns:          "thorns",
+		DamageCauseAnvil:           "anvil",
 	}
 
 	if name, ok := names[cause]; ok {
@@ -182,3 +555,72 @@ func DamageCauseName(cause int) string {
 	}
 	return "unknown"
 }
+
+func EntityName(e IEntity) string {
+	if e == nil {
+		return ""
+	}
+	if n, ok := e.(interface{ GetName() string }); ok {
+		return n.GetName()
+	}
+	return ""
+}
+
+func DeathMessage(victim string, source DamageSource) string {
+	if source == nil {
+		return victim + " died"
+	}
+	killer := ""
+	if src, ok := source.(*EntityDamageSource); ok {
+		killer = EntityName(src.Attacker)
+	}
+	switch source.Cause() {
+	case DamageCauseEntityAttack:
+		if killer != "" {
+			return victim + " was slain by " + killer
+		}
+	case DamageCauseProjectile:
+		if killer != "" {
+			return victim + " was shot by " + killer
+		}
+		return victim + " was shot"
+	case DamageCauseSuffocation:
+		return victim + " suffocated in a wall"
+	case DamageCauseFall:
+		return victim + " hit the ground too hard"
+	case DamageCauseFire:
+		return victim + " went up in flames"
+	case DamageCauseFireTick:
+		return victim + " burned to death"
+	case DamageCauseLava:
+		return victim + " tried to swim in lava"
+	case DamageCauseDrowning:
+		return victim + " drowned"
+	case DamageCauseContact:
+		return victim + " was pricked to death"
+	case DamageCauseBlockExplosion:
+		return victim + " blew up"
+	case DamageCauseEntityExplosion:
+		if killer != "" {
+			return victim + " was blown up by " + killer
+		}
+		return victim + " blew up"
+	case DamageCauseVoid:
+		return victim + " fell out of the world"
+	case DamageCauseMagic:
+		return victim + " was killed by magic"
+	case DamageCauseStarvation:
+		return victim + " starved to death"
+	case DamageCauseLightning:
+		return victim + " was struck by lightning"
+	case DamageCauseWitherEffect:
+		return victim + " withered away"
+	case DamageCauseThorns:
+		if killer != "" {
+			return victim + " was killed trying to hurt " + killer
+		}
+	case DamageCauseAnvil:
+		return victim + " was squashed by a falling anvil"
+	}
+	return victim + " died"
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+type armoredCow struct {
+	*Animal
+	armor []item.Item
+}
+
+func (c *armoredCow) GetArmorContents() []item.Item { return c.armor }
+
+func (c *armoredCow) SetArmorItem(slot int, it item.Item) error {
+	c.armor[slot] = it
+	return nil
+}
+
+func TestDamageModifiers(t *testing.T) {
+	tests := []struct {
+		name       string
+		armor      bool
+		resistance bool
+		source     func() DamageSource
+		want       float64
+		armorMod   float64
+	}{
+		{"bare attack", false, false, func() DamageSource { return NewSimpleDamageSource(DamageCauseEntityAttack, 10) }, 10, 0},
+		{"armoured attack", true, false, func() DamageSource { return NewSimpleDamageSource(DamageCauseEntityAttack, 10) }, 6, 0.6},
+		{"armour ignores falls", true, false, func() DamageSource { return NewSimpleDamageSource(DamageCauseFall, 10) }, 10, 0},
+		{"critical through armour", true, false, func() DamageSource {
+			src := NewEntityDamageSource(DamageCauseEntityAttack, 10, nil)
+			src.Critical = true
+			return src
+		}, 9, 0.6},
+		{"resistance and armour", true, true, func() DamageSource { return NewSimpleDamageSource(DamageCauseEntityAttack, 10) }, 3.6, 0.6},
+	}
+	for _, tc := range tests {
+		cow := &armoredCow{Animal: NewCow(), armor: make([]item.Item, 4)}
+		cow.setOuter(cow)
+		cow.Entity.MaxHealth, cow.Entity.Health = 100, 100
+		if tc.armor {
+			cow.armor[0] = item.NewItem(item.IRON_HELMET, 0, 1)
+			cow.armor[1] = item.NewItem(item.DIAMOND_CHESTPLATE, 0, 1)
+		}
+		if tc.resistance {
+			cow.AddEffect(effect.NewEffect(effect.Resistance, 600, 1))
+		}
+
+		result := Damage(cow, tc.source())
+		if result == nil {
+			t.Errorf("%s: no damage dealt", tc.name)
+			continue
+		}
+		if math.Abs(result.Damage-tc.want) > 1e-9 {
+			t.Errorf("%s: damage = %v, want %v", tc.name, result.Damage, tc.want)
+		}
+		if got := result.Event.GetDamage(event.ModifierArmor); math.Abs(got-tc.armorMod) > 1e-9 {
+			t.Errorf("%s: armour modifier = %v, want %v", tc.name, got, tc.armorMod)
+		}
+	}
+}
+
+func TestEventCause(t *testing.T) {
+	tests := []struct{ cause, want int }{
+		{DamageCauseStarvation, event.CauseStarvation},
+		{DamageCauseCustom, event.CauseCustom},
+		{DamageCauseFall, event.CauseFall},
+		{DamageCauseMagic, event.CauseMagic},
+	}
+	for _, tc := range tests {
+		if got := EventCause(tc.cause); got != tc.want {
+			t.Errorf("EventCause(%d) = %d, want %d", tc.cause, got, tc.want)
+		}
+	}
+}
+
+func TestDamageDuringInvulnerability(t *testing.T) {
+	cow := NewCow()
+	cow.Entity.MaxHealth, cow.Entity.Health = 100, 100
+
+	steps := []struct {
+		name   string
+		cause  int
+		damage float64
+		health int
+	}{
+		{"poison", DamageCauseMagic, 1, 99},
+		{"stronger hit deals the excess", DamageCauseEntityAttack, 5, 95},
+		{"weaker hit is ignored", DamageCauseEntityAttack, 3, 95},
+		{"equal hit is ignored", DamageCauseEntityAttack, 5, 95},
+		{"stronger again", DamageCauseEntityAttack, 8, 92},
+	}
+	for _, step := range steps {
+		Damage(cow, NewSimpleDamageSource(step.cause, step.damage))
+		if got := cow.GetHealth(); got != step.health {
+			t.Errorf("%s: health = %d, want %d", step.name, got, step.health)
+		}
+	}
+	if got := cow.GetNoDamageTicks(); got != DefaultNoDamageTicks {
+		t.Errorf("no-damage ticks = %d, want %d", got, DefaultNoDamageTicks)
+	}
+}
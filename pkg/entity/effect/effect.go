@@ -38,3 +38,128 @@ type Effect struct {
 	Duration  int
 	Particles bool
 }
+
+const (
+	DefaultDuration  = 600
+	InfiniteDuration = 2147483647
+)
+
+type info struct {
+	name    string
+	color   [3]byte
+	bad     bool
+	instant bool
+}
+
+var effects = map[int]info{
+	Speed:          {"Speed", [3]byte{0x7c, 0xaf, 0xc6}, false, false},
+	Slowness:       {"Slowness", [3]byte{0x5a, 0x6c, 0x81}, true, false},
+	Haste:          {"Haste", [3]byte{0xd9, 0xc0, 0x43}, false, false},
+	MiningFatigue:  {"Mining Fatigue", [3]byte{0x4a, 0x42, 0x17}, true, false},
+	Strength:       {"Strength", [3]byte{0x93, 0x24, 0x23}, false, false},
+	InstantHealth:  {"Instant Health", [3]byte{0xf8, 0x24, 0x23}, false, true},
+	InstantDamage:  {"Instant Damage", [3]byte{0x43, 0x0a, 0x09}, true, true},
+	JumpBoost:      {"Jump Boost", [3]byte{0x22, 0xff, 0x4c}, false, false},
+	Nausea:         {"Nausea", [3]byte{0x55, 0x1d, 0x4a}, true, false},
+	Regeneration:   {"Regeneration", [3]byte{0xcd, 0x5c, 0xab}, false, false},
+	Resistance:     {"Resistance", [3]byte{0x99, 0x45, 0x3a}, false, false},
+	FireResistance: {"Fire Resistance", [3]byte{0xe4, 0x9a, 0x3a}, false, false},
+	WaterBreathing: {"Water Breathing", [3]byte{0x2e, 0x52, 0x99}, false, false},
+	Invisibility:   {"Invisibility", [3]byte{0x7f, 0x83, 0x92}, false, false},
+	Blindness:      {"Blindness", [3]byte{0x1f, 0x1f, 0x23}, true, false},
+	NightVision:    {"Night Vision", [3]byte{0x1f, 0x1f, 0xa1}, false, false},
+	Hunger:         {"Hunger", [3]byte{0x58, 0x76, 0x53}, true, false},
+	Weakness:       {"Weakness", [3]byte{0x48, 0x4d, 0x48}, true, false},
+	Poison:         {"Poison", [3]byte{0x4e, 0x93, 0x31}, true, false},
+	Wither:         {"Wither", [3]byte{0x35, 0x2a, 0x27}, true, false},
+	HealthBoost:    {"Health Boost", [3]byte{0xf8, 0x7d, 0x23}, false, false},
+	Absorption:     {"Absorption", [3]byte{0x25, 0x52, 0xa5}, false, false},
+	Saturation:     {"Saturation", [3]byte{0xf8, 0x24, 0x23}, false, true},
+}
+
+func NewEffect(id, duration, amplifier int) *Effect {
+	return &Effect{
+		ID:        id,
+		Amplifier: amplifier,
+		Duration:  duration,
+		Particles: true,
+	}
+}
+
+func IsValid(id int) bool {
+	_, ok := effects[id]
+	return ok
+}
+
+func Name(id int) string {
+	if i, ok := effects[id]; ok {
+		return i.name
+	}
+	return "Unknown"
+}
+
+func Color(id int) (r, g, b byte) {
+	c := effects[id].color
+	return c[0], c[1], c[2]
+}
+
+func IsBad(id int) bool {
+	return effects[id].bad
+}
+
+func IsInstant(id int) bool {
+	return effects[id].instant
+}
+
+func (e *Effect) Clone() *Effect {
+	c := *e
+	return &c
+}
+
+func (e *Effect) Level() int {
+	return e.Amplifier + 1
+}
+
+func (e *Effect) IsInstant() bool {
+	return IsInstant(e.ID)
+}
+
+func (e *Effect) CanTick() bool {
+	interval := 0
+	switch e.ID {
+	case Regeneration:
+		interval = 50 >> uint(e.Amplifier)
+	case Poison:
+		interval = 25 >> uint(e.Amplifier)
+	case Wither:
+		interval = 40 >> uint(e.Amplifier)
+	case Hunger:
+		return true
+	default:
+		return false
+	}
+	if interval <= 0 {
+		return true
+	}
+	return e.Duration%interval == 0
+}
+
+func Mix(list []*Effect) (r, g, b byte, ok bool) {
+	var tr, tg, tb, n int
+	for _, e := range list {
+		if !e.Particles {
+			continue
+		}
+		cr, cg, cb := Color(e.ID)
+		for i := 0; i < e.Level(); i++ {
+			tr += int(cr)
+			tg += int(cg)
+			tb += int(cb)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, 0, 0, false
+	}
+	return byte(tr / n), byte(tg / n), byte(tb / n), true
+}
@@ -0,0 +1,50 @@
+package effect
+
+type potionEffect struct {
+	id        int
+	duration  int
+	amplifier int
+}
+
+var potions = map[int][]potionEffect{
+	5:  {{NightVision, 3600, 0}},
+	6:  {{NightVision, 9600, 0}},
+	7:  {{Invisibility, 3600, 0}},
+	8:  {{Invisibility, 9600, 0}},
+	9:  {{JumpBoost, 3600, 0}},
+	10: {{JumpBoost, 9600, 0}},
+	11: {{JumpBoost, 1800, 1}},
+	12: {{FireResistance, 3600, 0}},
+	13: {{FireResistance, 9600, 0}},
+	14: {{Speed, 3600, 0}},
+	15: {{Speed, 9600, 0}},
+	16: {{Speed, 1800, 1}},
+	17: {{Slowness, 1800, 0}},
+	18: {{Slowness, 4800, 0}},
+	19: {{WaterBreathing, 3600, 0}},
+	20: {{WaterBreathing, 9600, 0}},
+	21: {{InstantHealth, 1, 0}},
+	22: {{InstantHealth, 1, 1}},
+	23: {{InstantDamage, 1, 0}},
+	24: {{InstantDamage, 1, 1}},
+	25: {{Poison, 900, 0}},
+	26: {{Poison, 2400, 0}},
+	27: {{Poison, 432, 1}},
+	28: {{Regeneration, 900, 0}},
+	29: {{Regeneration, 2400, 0}},
+	30: {{Regeneration, 440, 1}},
+	31: {{Strength, 3600, 0}},
+	32: {{Strength, 9600, 0}},
+	33: {{Strength, 1800, 1}},
+	34: {{Weakness, 1800, 0}},
+	35: {{Weakness, 4800, 0}},
+}
+
+func FromPotion(meta int) []*Effect {
+	list := potions[meta]
+	out := make([]*Effect, 0, len(list))
+	for _, p := range list {
+		out = append(out, NewEffect(p.id, p.duration, p.amplifier))
+	}
+	return out
+}
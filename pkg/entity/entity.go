@@ -2,7 +2,7 @@ package entity
 
 import (
 	"fmt"
-	_ "math"
+	"math"
 	"sync/atomic"
 
 	"github.com/scaxe/scaxe-go/pkg/block"
@@ -34,6 +34,11 @@ type ILevel interface {
 	AddEntity(e IEntity)
 	RemoveEntity(e IEntity)
 	FindGroundY(x, z, startY int32) int32
+	SpawnEntity(e IEntity)
+	BroadcastEntityEvent(entityID int64, event byte)
+	UpdateEntityMetadata(e IEntity)
+	UpdateBlock(x, y, z int32, id, meta byte)
+	GetPlayers() []IEntity
 }
 
 var entityIDCounter int64 = 1
@@ -90,6 +95,10 @@ type Entity struct {
 	MoveHelper *MoveHelper
 	LookHelper *LookHelper
 	JumpHelper *JumpHelper
+
+	lastDamageSource DamageSource
+	lastDamage       float64
+	deadTicks        int
 }
 
 func NewEntity() *Entity {
@@ -146,6 +155,10 @@ func (e *Entity) GetHealth() int {
 	return e.Health
 }
 
+func (e *Entity) GetMetadata() *MetadataStore {
+	return e.Metadata
+}
+
 func (e *Entity) GetBoundingBox() *AxisAlignedBB {
 	return e.BoundingBox
 }
@@ -198,6 +211,14 @@ func (e *Entity) Tick(currentTick int64) bool {
 	if e.Closed {
 		return false
 	}
+	if e.deadTicks > 0 {
+		e.deadTicks++
+		if e.deadTicks > DeathAnimationTicks {
+			e.Close()
+			return false
+		}
+		return true
+	}
 	e.TicksLived++
 
 	e.LastPos.X = e.Position.X
@@ -269,6 +290,109 @@ func (e *Entity) Close() {
 	e.Closed = true
 }
 
+func (e *Entity) currentLevel() ILevel {
+	return e.Level
+}
+
+func (e *Entity) IsInvulnerableTo(cause int) bool {
+	return e.Invulnerable && cause != DamageCauseVoid
+}
+
+func (e *Entity) GetNoDamageTicks() int {
+	return e.NoDamageTicks
+}
+
+func (e *Entity) GetLastDamageSource() DamageSource {
+	return e.lastDamageSource
+}
+
+// GetLastDamage is the damage of the hit that started or last raised the
+// current invulnerability window.
+func (e *Entity) GetLastDamage() float64 {
+	return e.lastDamage
+}
+
+func (e *Entity) recordDamage(source DamageSource, damage float64) {
+	e.lastDamageSource = source
+	e.lastDamage = damage
+	if e.NoDamageTicks <= 0 {
+		e.NoDamageTicks = DefaultNoDamageTicks
+	}
+}
+
+func (e *Entity) setDead() {
+	e.deadTicks = 1
+}
+
+func (e *Entity) IsDying() bool {
+	return e.deadTicks > 0
+}
+
+func (e *Entity) updateFallDistance() float64 {
+	if e.SlowFall || e.IsInWater() {
+		e.FallDistance = 0
+		return 0
+	}
+	if e.OnGround {
+		distance := e.FallDistance
+		e.FallDistance = 0
+		return distance
+	}
+	if dy := e.Position.Y - e.LastPos.Y; dy < 0 {
+		e.FallDistance -= dy
+	}
+	return 0
+}
+
+func (e *Entity) KnockBack(attackerX, attackerZ float64, base, verticalLimit float64) {
+
+	dx := e.Position.X - attackerX
+	dz := e.Position.Z - attackerZ
+	dist := math.Sqrt(dx*dx + dz*dz)
+
+	if dist <= 0 {
+		return
+	}
+
+	f := 1 / dist
+
+	e.Motion.X /= 2
+	e.Motion.Y /= 2
+	e.Motion.Z /= 2
+
+	e.Motion.X += dx * f * base
+	e.Motion.Y += base
+	e.Motion.Z += dz * f * base
+
+	if attr := e.Attributes.GetAttribute(AttributeKnockbackResist); attr != nil {
+		resistance := attr.GetValue()
+		if resistance > 0 {
+			e.Motion.X *= (1 - resistance)
+			e.Motion.Z *= (1 - resistance)
+
+		}
+	}
+
+	if e.Motion.Y > verticalLimit {
+		e.Motion.Y = verticalLimit
+	}
+}
+
+func (e *Entity) SetOnFire(seconds int) {
+	ticks := seconds * 20
+	if ticks > e.FireTicks {
+		e.FireTicks = ticks
+	}
+}
+
+func (e *Entity) ExtinguishFire() {
+	e.FireTicks = 0
+}
+
+func (e *Entity) IsOnFire() bool {
+	return e.FireTicks > 0
+}
+
 func (e *Entity) IsInWater() bool {
 	if e.Level == nil {
 		return false
@@ -514,6 +638,53 @@ func (e *Entity) SaveNBT() {
 	e.NamedTag.Set(nbt.NewByteTag("OnGround", val))
 }
 
+// LoadNBT restores what SaveNBT wrote.
+func (e *Entity) LoadNBT() {
+	if e.NamedTag == nil {
+		return
+	}
+	if pos := e.NamedTag.GetList("Pos"); pos != nil && pos.Len() == 3 {
+		e.SetPosition(NewVector3(listDouble(pos, 0), listDouble(pos, 1), listDouble(pos, 2)))
+	}
+	if mot := e.NamedTag.GetList("Motion"); mot != nil && mot.Len() == 3 {
+		e.Motion = NewVector3(listDouble(mot, 0), listDouble(mot, 1), listDouble(mot, 2))
+	}
+	if rot := e.NamedTag.GetList("Rotation"); rot != nil && rot.Len() == 2 {
+		if yaw, ok := rot.Get(0).(*nbt.FloatTag); ok {
+			e.Yaw = float64(yaw.Value().(float32))
+		}
+		if pitch, ok := rot.Get(1).(*nbt.FloatTag); ok {
+			e.Pitch = float64(pitch.Value().(float32))
+		}
+	}
+	if e.NamedTag.Has("Health") {
+		e.Health = nbtInt(e.NamedTag, "Health")
+	}
+	e.FireTicks = nbtInt(e.NamedTag, "Fire")
+	e.OnGround = e.NamedTag.GetByte("OnGround") == 1
+}
+
+func listDouble(list *nbt.ListTag, i int) float64 {
+	if tag, ok := list.Get(i).(*nbt.DoubleTag); ok {
+		return tag.Value().(float64)
+	}
+	return 0
+}
+
+// nbtInt reads an integer tag of any width, so fields that older saves
+// stored as a short still load after being widened.
+func nbtInt(tag *nbt.CompoundTag, name string) int {
+	switch t := tag.Get(name).(type) {
+	case *nbt.ByteTag:
+		return int(t.Value().(int8))
+	case *nbt.ShortTag:
+		return int(t.Value().(int16))
+	case *nbt.IntTag:
+		return int(t.Value().(int32))
+	}
+	return 0
+}
+
 func (e *Entity) SetSprinting(value bool) {
 	e.Metadata.SetFlag(DataFlags, DataFlagSprinting, value)
 
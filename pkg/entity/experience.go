@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	ExperienceOrbFollowRange = 8.0
+	ExperienceOrbMergeRange  = 1.0
+	MaxDeathExperience       = 100
+	DeathExperiencePerLevel  = 7
+	BreedingExperienceMin    = 1
+	BreedingExperienceMax    = 7
+)
+
+var orbSplitSizes = []int{2477, 1237, 617, 307, 149, 73, 37, 17, 7, 3, 1}
+
+type ExperienceCollector interface {
+	IEntity
+	CanCollectExperience() bool
+	CollectExperience(amount int)
+}
+
+type ExperienceDropper interface {
+	GetDropExperience() int
+}
+
+func ExperienceToNextLevel(level int) int {
+	switch {
+	case level >= 30:
+		return 9*level - 158
+	case level >= 15:
+		return 5*level - 38
+	default:
+		return 2*level + 7
+	}
+}
+
+func ExperienceForLevel(level int) int {
+	if level <= 0 {
+		return 0
+	}
+	l := float64(level)
+	switch {
+	case level <= 16:
+		return level*level + 6*level
+	case level <= 31:
+		return int(2.5*l*l - 40.5*l + 360)
+	default:
+		return int(4.5*l*l - 162.5*l + 2220)
+	}
+}
+
+func LevelFromExperience(total int) (int, float64) {
+	if total <= 0 {
+		return 0, 0
+	}
+	level := 0
+	for ExperienceForLevel(level+1) <= total {
+		level++
+	}
+	progress := float64(total-ExperienceForLevel(level)) / float64(ExperienceToNextLevel(level))
+	return level, progress
+}
+
+func DeathExperience(level int) int {
+	return int(math.Min(float64(level*DeathExperiencePerLevel), MaxDeathExperience))
+}
+
+func RandomExperience(min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + rand.Intn(max-min+1)
+}
+
+func SplitExperience(amount int) []int {
+	var orbs []int
+	for amount > 0 {
+		for _, size := range orbSplitSizes {
+			if amount >= size {
+				orbs = append(orbs, size)
+				amount -= size
+				break
+			}
+		}
+	}
+	return orbs
+}
+
+func DropExperience(lvl ILevel, pos *Vector3, amount int) {
+	if lvl == nil || amount <= 0 {
+		return
+	}
+	for _, size := range SplitExperience(amount) {
+		orb := NewExperienceOrb(size)
+		orb.Entity.SetPosition(NewVector3(pos.X, pos.Y+0.5, pos.Z))
+		orb.Motion = NewVector3(rand.Float64()*0.2-0.1, 0.2, rand.Float64()*0.2-0.1)
+		orb.Level = lvl
+		lvl.SpawnEntity(orb)
+	}
+}
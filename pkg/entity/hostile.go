@@ -31,6 +31,9 @@ func NewMonster(networkID int, name string, maxHealth int, width, height float64
 
 	return m
 }
+func (m *Monster) GetDropExperience() int {
+	return RandomExperience(m.DropExpMin, m.DropExpMax)
+}
 func (m *Monster) GetHurt() int {
 	return m.AttackDamage
 }
@@ -75,6 +78,7 @@ func (m *Monster) SaveMonsterNBT() {
 	if m.IsBabyFlag {
 		m.Mob.Living.Entity.NamedTag.Set(nbt.NewByteTag("IsBaby", 1))
 	}
+	m.Mob.Living.SaveEffectsNBT()
 }
 func (m *Monster) LoadMonsterFromNBT() {
 	if m.Mob.Living.Entity.NamedTag == nil {
@@ -83,6 +87,7 @@ func (m *Monster) LoadMonsterFromNBT() {
 	if m.Mob.Living.Entity.NamedTag.GetByte("IsBaby") == 1 {
 		m.SetBaby(true)
 	}
+	m.Mob.Living.LoadEffectsNBT()
 }
 func (m *Monster) GetName() string {
 	return m.MobName
@@ -139,3 +144,17 @@ func NewMonsterFromInfo(networkID int) *Monster {
 	m.DropExpMax = info.DropExpMax
 	return m
 }
+func IsUndead(networkID int) bool {
+	switch networkID {
+	case ZombieNetworkID, SkeletonNetworkID, PigZombieNetworkID:
+		return true
+	}
+	return false
+}
+func IsArthropod(networkID int) bool {
+	switch networkID {
+	case SpiderNetworkID, CaveSpiderNetworkID, SilverfishNetworkID:
+		return true
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "math"
+
 type Human struct {
 	*Living
 
@@ -21,8 +23,6 @@ type Human struct {
 	XPLevel    int
 	XPProgress float64
 
-	Absorption int
-
 	HeldItemSlot int
 }
 
@@ -42,7 +42,6 @@ func NewHuman() *Human {
 		TotalXP:       0,
 		XPLevel:       0,
 		XPProgress:    0,
-		Absorption:    0,
 		HeldItemSlot:  0,
 	}
 	h.initHumanAttributes()
@@ -169,11 +168,8 @@ func (h *Human) SetXPLevel(level int) {
 	if level < 0 {
 		level = 0
 	}
-	h.XPLevel = level
-
-	if attr := h.Attributes.GetAttribute(AttributeExperienceLevel); attr != nil {
-		attr.SetValue(float64(level))
-	}
+	h.TotalXP = ExperienceForLevel(level) + int(math.Round(h.XPProgress*float64(ExperienceToNextLevel(level))))
+	h.setXPState(level, h.XPProgress)
 }
 
 func (h *Human) AddXPLevel(levels int) {
@@ -191,8 +187,16 @@ func (h *Human) SetXPProgress(progress float64) {
 	if progress > 1 {
 		progress = 1
 	}
-	h.XPProgress = progress
+	h.TotalXP = ExperienceForLevel(h.XPLevel) + int(math.Round(progress*float64(ExperienceToNextLevel(h.XPLevel))))
+	h.setXPState(h.XPLevel, progress)
+}
 
+func (h *Human) setXPState(level int, progress float64) {
+	h.XPLevel = level
+	h.XPProgress = progress
+	if attr := h.Attributes.GetAttribute(AttributeExperienceLevel); attr != nil {
+		attr.SetValue(float64(level))
+	}
 	if attr := h.Attributes.GetAttribute(AttributeExperience); attr != nil {
 		attr.SetValue(progress)
 	}
@@ -207,20 +211,9 @@ func (h *Human) SetTotalXP(xp int) {
 		xp = 0
 	}
 	h.TotalXP = xp
+	h.setXPState(LevelFromExperience(xp))
 }
 
 func (h *Human) AddXP(xp int) {
-	h.TotalXP += xp
-
-}
-
-func (h *Human) GetAbsorption() int {
-	return h.Absorption
-}
-
-func (h *Human) SetAbsorption(absorption int) {
-	if absorption < 0 {
-		absorption = 0
-	}
-	h.Absorption = absorption
+	h.SetTotalXP(h.TotalXP + xp)
 }
@@ -2,6 +2,8 @@ package entity
 
 import (
 	"math"
+
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
 )
 
 type Living struct {
@@ -13,6 +15,10 @@ type Living struct {
 	JumpVelocity float64
 
 	HeadYaw float32
+
+	effects       map[int]*effect.Effect
+	effectHandler EffectHandler
+	absorption    float64
 }
 
 func NewLiving() *Living {
@@ -23,6 +29,7 @@ func NewLiving() *Living {
 		DeathTime:    0,
 		JumpVelocity: 0.42,
 		HeadYaw:      0,
+		effects:      make(map[int]*effect.Effect),
 	}
 	l.initLivingAttributes()
 	return l
@@ -41,27 +48,12 @@ func (l *Living) GetName() string {
 }
 
 func (l *Living) Attack(damage float64, cause int) bool {
-	if l.Invulnerable || l.NoDamageTicks > 0 {
-		return false
-	}
-
-	if attr := l.Attributes.GetAttribute(AttributeKnockbackResist); attr != nil {
-		resistance := attr.GetValue()
-		if resistance >= 1 {
-
-		}
-	}
-
-	finalDamage := int(math.Round(damage))
-	if finalDamage < 1 {
-		finalDamage = 1
-	}
-
-	l.SetHealth(l.GetHealth() - finalDamage)
-	l.LastDamage = damage
-	l.NoDamageTicks = 10
+	return Damage(l, NewSimpleDamageSource(cause, damage)) != nil
+}
 
-	return true
+func (l *Living) AttackByEntity(damage float64, attacker interface{}) bool {
+	source, _ := attacker.(IEntity)
+	return Damage(l, NewEntityDamageSource(DamageCauseEntityAttack, damage, source)) != nil
 }
 
 func (l *Living) Heal(amount float64) {
@@ -72,40 +64,6 @@ func (l *Living) Heal(amount float64) {
 	l.SetHealth(newHealth)
 }
 
-func (l *Living) KnockBack(attackerX, attackerZ float64, base, verticalLimit float64) {
-
-	dx := l.Position.X - attackerX
-	dz := l.Position.Z - attackerZ
-	dist := math.Sqrt(dx*dx + dz*dz)
-
-	if dist <= 0 {
-		return
-	}
-
-	f := 1 / dist
-
-	l.Motion.X /= 2
-	l.Motion.Y /= 2
-	l.Motion.Z /= 2
-
-	l.Motion.X += dx * f * base
-	l.Motion.Y += base
-	l.Motion.Z += dz * f * base
-
-	if attr := l.Attributes.GetAttribute(AttributeKnockbackResist); attr != nil {
-		resistance := attr.GetValue()
-		if resistance > 0 {
-			l.Motion.X *= (1 - resistance)
-			l.Motion.Z *= (1 - resistance)
-
-		}
-	}
-
-	if l.Motion.Y > verticalLimit {
-		l.Motion.Y = verticalLimit
-	}
-}
-
 func (l *Living) Kill() {
 	l.SetHealth(0)
 	l.DeathTime = 0
@@ -121,8 +79,7 @@ func (l *Living) Jump() {
 
 func (l *Living) Fall(fallDistance float64) {
 	if fallDistance > 3 {
-		damage := fallDistance - 3
-		l.Attack(damage, DamageCauseFall)
+		l.Attack(FallDamage(fallDistance), DamageCauseFall)
 	}
 }
 
@@ -181,6 +138,7 @@ func (l *Living) EntityBaseTick(tickDiff int) bool {
 			}
 		}
 
+		l.TickEffects(tickDiff)
 		hasUpdate = true
 	}
 
@@ -255,18 +213,3 @@ func (l *Living) ApplyEntityCollision(other IEntity) bool {
 	}
 	return false
 }
-
-func (l *Living) SetOnFire(seconds int) {
-	ticks := seconds * 20
-	if ticks > l.FireTicks {
-		l.FireTicks = ticks
-	}
-}
-
-func (l *Living) ExtinguishFire() {
-	l.FireTicks = 0
-}
-
-func (l *Living) IsOnFire() bool {
-	return l.FireTicks > 0
-}
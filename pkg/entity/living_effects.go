@@ -0,0 +1,318 @@
+package entity
+
+import (
+	"sort"
+
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/nbt"
+)
+
+type EffectHandler interface {
+	Heal(amount float64)
+	Attack(damage float64, cause int) bool
+	OnEffectAdded(e *effect.Effect, modify bool)
+	OnEffectRemoved(e *effect.Effect)
+}
+
+func (l *Living) SetEffectHandler(h EffectHandler) {
+	l.effectHandler = h
+}
+
+func (l *Living) HasEffect(id int) bool {
+	_, ok := l.effects[id]
+	return ok
+}
+
+func (l *Living) GetEffect(id int) *effect.Effect {
+	return l.effects[id]
+}
+
+func (l *Living) GetEffects() []*effect.Effect {
+	list := make([]*effect.Effect, 0, len(l.effects))
+	for _, e := range l.effects {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (l *Living) AddEffect(e *effect.Effect) bool {
+	if e == nil || !effect.IsValid(e.ID) || e.Amplifier < 0 || e.Amplifier > 255 {
+		return false
+	}
+	old := l.effects[e.ID]
+	if old != nil && (old.Amplifier > e.Amplifier || (old.Amplifier == e.Amplifier && old.Duration > e.Duration)) {
+		return false
+	}
+
+	evt := event.NewEntityEffectAddEvent(l.ID, e.ID, e.Amplifier, e.Duration, e.Particles, old != nil)
+	event.Call(evt)
+	if evt.IsCancelled() {
+		return false
+	}
+	e = e.Clone()
+	e.Amplifier = evt.Amplifier
+	e.Duration = evt.Duration
+	e.Particles = evt.Particles
+
+	if e.IsInstant() {
+		l.applyInstantEffect(e)
+		return true
+	}
+
+	if old != nil {
+		l.unapplyEffect(old)
+	}
+	l.effects[e.ID] = e
+	l.applyEffect(e)
+	l.updateEffectColor()
+	if l.effectHandler != nil {
+		l.effectHandler.OnEffectAdded(e, old != nil)
+	}
+	return true
+}
+
+func (l *Living) RemoveEffect(id int) bool {
+	return l.removeEffect(id, false)
+}
+
+func (l *Living) ClearEffects() int {
+	removed := 0
+	for _, e := range l.GetEffects() {
+		if l.removeEffect(e.ID, false) {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (l *Living) removeEffect(id int, expired bool) bool {
+	e, ok := l.effects[id]
+	if !ok {
+		return false
+	}
+	evt := event.NewEntityEffectRemoveEvent(l.ID, id, e.Amplifier, expired)
+	event.Call(evt)
+	if evt.IsCancelled() && !expired {
+		return false
+	}
+
+	delete(l.effects, id)
+	l.unapplyEffect(e)
+	l.updateEffectColor()
+	if l.effectHandler != nil {
+		l.effectHandler.OnEffectRemoved(e)
+	}
+	return true
+}
+
+func (l *Living) TickEffects(tickDiff int) bool {
+	if len(l.effects) == 0 {
+		return false
+	}
+	for _, e := range l.GetEffects() {
+		if e.CanTick() {
+			l.applyEffectTick(e)
+		}
+		e.Duration -= tickDiff
+		if e.Duration <= 0 {
+			l.removeEffect(e.ID, true)
+		}
+	}
+	return true
+}
+
+func (l *Living) applyEffectTick(e *effect.Effect) {
+	switch e.ID {
+	case effect.Regeneration:
+		if l.GetHealth() < l.GetMaxHealth() {
+			l.effectHeal(1)
+		}
+	case effect.Poison:
+		if l.GetHealth() > 1 {
+			l.effectAttack(1, DamageCauseMagic)
+		}
+	case effect.Wither:
+		l.effectAttack(1, DamageCauseWitherEffect)
+	case effect.Hunger:
+		if h, ok := l.effectHandler.(interface{ Exhaust(amount float64) }); ok {
+			h.Exhaust(0.025 * float64(e.Level()))
+		}
+	}
+}
+
+func (l *Living) applyInstantEffect(e *effect.Effect) {
+	shift := uint(e.Amplifier)
+	if shift > 8 {
+		shift = 8
+	}
+	switch e.ID {
+	case effect.InstantHealth:
+		l.effectHeal(float64(int(4) << shift))
+	case effect.InstantDamage:
+		l.effectAttack(float64(int(6)<<shift), DamageCauseMagic)
+	case effect.Saturation:
+		if h, ok := l.effectHandler.(interface {
+			AddFood(amount float64)
+			AddSaturation(amount float64)
+		}); ok {
+			h.AddFood(float64(e.Level()))
+			h.AddSaturation(float64(e.Level() * 2))
+		}
+	}
+}
+
+func (l *Living) applyEffect(e *effect.Effect) {
+	switch e.ID {
+	case effect.Speed, effect.Slowness:
+		l.updateEffectSpeed()
+	case effect.HealthBoost:
+		l.SetMaxHealth(l.GetMaxHealth() + 4*e.Level())
+	case effect.Absorption:
+		if amount := float64(4 * e.Level()); l.GetAbsorption() < amount {
+			l.SetAbsorption(amount)
+		}
+	}
+}
+
+func (l *Living) unapplyEffect(e *effect.Effect) {
+	switch e.ID {
+	case effect.Speed, effect.Slowness:
+		l.updateEffectSpeed()
+	case effect.HealthBoost:
+		l.SetMaxHealth(l.GetMaxHealth() - 4*e.Level())
+		if l.GetHealth() > l.GetMaxHealth() {
+			l.SetHealth(l.GetMaxHealth())
+		}
+	case effect.Absorption:
+		l.SetAbsorption(0)
+	}
+}
+
+func (l *Living) updateEffectSpeed() {
+	attr := l.Attributes.GetAttribute(AttributeMovementSpeed)
+	if attr == nil {
+		return
+	}
+	mult := 1.0
+	if e, ok := l.effects[effect.Speed]; ok {
+		mult *= 1 + 0.2*float64(e.Level())
+	}
+	if e, ok := l.effects[effect.Slowness]; ok {
+		mult *= 1 - 0.15*float64(e.Level())
+	}
+	if mult < 0 {
+		mult = 0
+	}
+	attr.SetValue(attr.DefaultValue * mult)
+}
+
+func (l *Living) updateEffectColor() {
+	r, g, b, ok := effect.Mix(l.GetEffects())
+	color := int32(0)
+	if ok {
+		color = int32(r)<<16 | int32(g)<<8 | int32(b)
+	}
+	if l.Metadata.GetInt(DataPotionColor) == color {
+		return
+	}
+	l.Metadata.SetInt(DataPotionColor, color)
+	l.Metadata.SetByte(DataPotionAmbient, 0)
+	if l.Level != nil {
+		l.Level.UpdateEntityMetadata(l.Entity)
+	}
+}
+
+func (l *Living) effectHeal(amount float64) {
+	if l.effectHandler != nil {
+		l.effectHandler.Heal(amount)
+		return
+	}
+	l.Heal(amount)
+}
+
+func (l *Living) effectAttack(damage float64, cause int) {
+	if l.effectHandler != nil {
+		l.effectHandler.Attack(damage, cause)
+		return
+	}
+	l.Attack(damage, cause)
+}
+
+func (l *Living) GetAbsorption() float64 {
+	return l.absorption
+}
+
+func (l *Living) SetAbsorption(amount float64) {
+	if amount < 0 {
+		amount = 0
+	}
+	l.absorption = amount
+	if attr := l.Attributes.GetAttribute(AttributeAbsorption); attr != nil {
+		attr.SetValue(amount)
+	}
+}
+
+func (l *Living) GetBreakTimeMultiplier() float64 {
+	mult := 1.0
+	if e, ok := l.effects[effect.Haste]; ok {
+		mult *= 1 - 0.2*float64(e.Level())
+	}
+	if e, ok := l.effects[effect.MiningFatigue]; ok {
+		mult *= 1 + 0.3*float64(e.Level())
+	}
+	if mult < 0 {
+		mult = 0
+	}
+	return mult
+}
+
+func (l *Living) SaveEffectsNBT() {
+	if l.NamedTag == nil {
+		l.NamedTag = nbt.NewCompoundTag("")
+	}
+	if len(l.effects) == 0 {
+		l.NamedTag.Remove("ActiveEffects")
+		return
+	}
+	list := nbt.NewListTag("ActiveEffects", nbt.TagCompound)
+	for _, e := range l.GetEffects() {
+		tag := nbt.NewCompoundTag("")
+		tag.Set(nbt.NewByteTag("Id", int8(e.ID)))
+		tag.Set(nbt.NewByteTag("Amplifier", int8(e.Amplifier)))
+		tag.Set(nbt.NewIntTag("Duration", int32(e.Duration)))
+		tag.Set(nbt.NewByteTag("Ambient", 0))
+		tag.Set(nbt.NewByteTag("ShowParticles", int8(boolByte(e.Particles))))
+		list.Add(tag)
+	}
+	l.NamedTag.Set(list)
+}
+
+func (l *Living) LoadEffectsNBT() {
+	if l.NamedTag == nil {
+		return
+	}
+	list := l.NamedTag.GetList("ActiveEffects")
+	if list == nil {
+		return
+	}
+	for i := 0; i < list.Len(); i++ {
+		tag, ok := list.Get(i).(*nbt.CompoundTag)
+		if !ok {
+			continue
+		}
+		e := &effect.Effect{
+			ID:        int(uint8(tag.GetByte("Id"))),
+			Amplifier: int(uint8(tag.GetByte("Amplifier"))),
+			Duration:  int(tag.GetInt("Duration")),
+			Particles: !tag.Has("ShowParticles") || tag.GetByte("ShowParticles") != 0,
+		}
+		if !effect.IsValid(e.ID) || e.IsInstant() || e.Duration <= 0 {
+			continue
+		}
+		l.effects[e.ID] = e
+		l.applyEffect(e)
+	}
+	l.updateEffectColor()
+}
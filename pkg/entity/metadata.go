@@ -70,6 +70,7 @@ const (
 	DataAnimalFlagSitting    = 1
 	DataAnimalFlagAngry      = 2
 	DataAnimalFlagInterested = 3
+	DataAnimalFlagTamed      = 4
 )
 
 const (
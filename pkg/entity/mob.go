@@ -43,10 +43,17 @@ func (m *Mob) Tick(currentTick int64) bool {
 	if !m.Entity.Tick(currentTick) {
 		return false
 	}
+	if m.IsDying() {
+		return true
+	}
+	if distance := m.updateFallDistance(); distance > 3 {
+		m.Fall(distance)
+	}
 
 	if m.AttackTime > 0 {
 		m.AttackTime--
 	}
+	m.TickEffects(1)
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
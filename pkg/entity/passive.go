@@ -1,21 +1,36 @@
 package entity
 
 import (
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
 	"github.com/scaxe/scaxe-go/pkg/nbt"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
 )
 
 const (
-	BabyGrowAge = -24000
+	BabyGrowAge      = -24000
+	BreedingCooldown = 6000
+	InLoveDuration   = 600
 )
+type Breedable interface {
+	IEntity
+	GetAnimal() *Animal
+}
 type Animal struct {
 	*Entity
-	IsBabyFlag bool
-	InLove bool
-	AnimalAge int
-	DropExpMin int
-	DropExpMax int
-	FeedFoodID int
-	MobName string
+	IsBabyFlag   bool
+	InLove       bool
+	LoveTicks    int
+	AnimalAge    int
+	DropExpMin   int
+	DropExpMax   int
+	FeedFoodID   int
+	ExtraFoodIDs []int
+	MobName      string
+
+	outer   Breedable
+	effects *Living
 }
 func NewAnimal(networkID int, name string, maxHealth int, width, height float64, movementSpeed, panicSpeed float64) *Animal {
 	a := &Animal{
@@ -23,6 +38,7 @@ func NewAnimal(networkID int, name string, maxHealth int, width, height float64,
 		MobName:   name,
 		AnimalAge: 0,
 	}
+	a.outer = a
 
 	a.Entity.NetworkID = networkID
 	a.Entity.Width = width
@@ -39,96 +55,182 @@ func NewAnimal(networkID int, name string, maxHealth int, width, height float64,
 
 	a.Entity.Tasks.AddTask(0, NewAISwimming(a.Entity))
 	a.Entity.Tasks.AddTask(1, NewAIPanic(a.Entity, a.Entity.MoveHelper, panicSpeed))
+	a.Entity.Tasks.AddTask(2, NewAIMate(a, 1.0))
+	a.Entity.Tasks.AddTask(3, NewAITempt(a, 1.2))
+	a.Entity.Tasks.AddTask(4, NewAIFollowParent(a, 1.1))
 	a.Entity.Tasks.AddTask(5, NewAIWanderWithChance(a.Entity, a.Entity.MoveHelper, 1.0, 120))
 	a.Entity.Tasks.AddTask(6, NewAIWatchClosest(a.Entity, a.Entity.LookHelper, 6.0))
 	a.Entity.Tasks.AddTask(7, NewAILookIdle(a.Entity, a.Entity.LookHelper))
 
 	return a
 }
+func (a *Animal) GetAnimal() *Animal {
+	return a
+}
+func (a *Animal) setOuter(outer Breedable) {
+	a.outer = outer
+}
 func (a *Animal) SetBaby(baby bool) {
 	a.IsBabyFlag = baby
 	if baby {
 		a.AnimalAge = BabyGrowAge
+	} else if a.AnimalAge < 0 {
+		a.AnimalAge = 0
 	}
+	a.Entity.Metadata.SetFlag(DataAgeableFlags, DataAnimalFlagIsBaby, baby)
 }
 func (a *Animal) IsBaby() bool {
 	return a.IsBabyFlag
 }
 func (a *Animal) SetInLove(inLove bool) {
 	a.InLove = inLove
+	if inLove {
+		a.LoveTicks = InLoveDuration
+	} else {
+		a.LoveTicks = 0
+	}
+	a.Entity.Metadata.SetByte(DataInLove, boolByte(inLove))
 }
 func (a *Animal) IsInLove() bool {
 	return a.InLove
 }
-type AnimalTickResult struct {
-	HasUpdate bool
-	GrewUp    bool
+func (a *Animal) CanBreed() bool {
+	return !a.IsBabyFlag && a.AnimalAge == 0 && !a.InLove
 }
-func (a *Animal) TickAnimal() AnimalTickResult {
-	result := AnimalTickResult{}
-
-	a.Entity.TicksLived++
-	a.AnimalAge++
-
-	if a.IsBabyFlag && a.AnimalAge >= 0 {
+func (a *Animal) Feed(itemID int) bool {
+	if !a.CanBeFedWith(itemID) {
+		return false
+	}
+	if a.IsBabyFlag {
+		a.AgeUp(-a.AnimalAge / 10)
+		return true
+	}
+	if !a.CanBreed() {
+		return false
+	}
+	a.SetInLove(true)
+	if a.Entity.Level != nil {
+		a.Entity.Level.BroadcastEntityEvent(a.Entity.ID, protocol.EntityEventTameSuccess)
+		a.Entity.Level.UpdateEntityMetadata(a.outer)
+	}
+	return true
+}
+func (a *Animal) AgeUp(ticks int) {
+	if !a.IsBabyFlag {
+		return
+	}
+	a.AnimalAge += ticks
+	if a.AnimalAge >= 0 {
 		a.SetBaby(false)
-		result.GrewUp = true
-		result.HasUpdate = true
+		if a.Entity.Level != nil {
+			a.Entity.Level.UpdateEntityMetadata(a.outer)
+		}
 	}
-
-	a.Entity.UpdateAI()
-
-	if !a.Entity.OnGround && !a.Entity.IsInWater() {
-		a.Entity.Motion.Y -= a.Entity.Gravity
+}
+func (a *Animal) Tick(currentTick int64) bool {
+	if !a.Entity.Tick(currentTick) {
+		return false
 	}
-	if a.Entity.OnGround {
-		a.Entity.Motion.X *= 0.5
-		a.Entity.Motion.Z *= 0.5
+	if a.IsDying() {
+		return true
 	}
-
-	if a.Entity.Motion.X != 0 || a.Entity.Motion.Y != 0 || a.Entity.Motion.Z != 0 {
-		a.Entity.Move(a.Entity.Motion.X, a.Entity.Motion.Y, a.Entity.Motion.Z)
-		result.HasUpdate = true
+	if distance := a.updateFallDistance(); distance > 3 {
+		if victim, ok := a.outer.(Damageable); ok {
+			Damage(victim, NewSimpleDamageSource(DamageCauseFall, FallDamage(distance)))
+		}
 	}
-
-	if a.Entity.NoDamageTicks > 0 {
-		a.Entity.NoDamageTicks--
+	a.tickAnimal()
+	if a.effects != nil {
+		a.effects.TickEffects(1)
+	}
+	return true
+}
+func (a *Animal) tickAnimal() {
+	if a.AnimalAge < 0 {
+		a.AgeUp(1)
+	} else if a.AnimalAge > 0 {
+		a.AnimalAge--
 	}
 
-	return result
+	if a.InLove {
+		a.LoveTicks--
+		if a.LoveTicks <= 0 {
+			a.SetInLove(false)
+			if a.Entity.Level != nil {
+				a.Entity.Level.UpdateEntityMetadata(a.outer)
+			}
+		}
+	}
 }
 func (a *Animal) SaveAnimalNBT() {
 	a.Entity.SaveNBT()
-	baby := int8(0)
-	if a.IsBabyFlag {
-		baby = 1
-	}
-	a.Entity.NamedTag.Set(nbt.NewByteTag("IsBaby", baby))
-	a.Entity.NamedTag.Set(nbt.NewShortTag("Age", int16(a.AnimalAge)))
+	a.Entity.NamedTag.Set(nbt.NewByteTag("IsBaby", int8(boolByte(a.IsBabyFlag))))
+	a.Entity.NamedTag.Set(nbt.NewIntTag("Age", int32(a.AnimalAge)))
+	a.Entity.NamedTag.Set(nbt.NewIntTag("InLove", int32(a.LoveTicks)))
+	a.effectState().SaveEffectsNBT()
 }
 func (a *Animal) LoadAnimalFromNBT() {
 	if a.Entity.NamedTag == nil {
 		return
 	}
-	if a.Entity.NamedTag.GetByte("IsBaby") == 1 {
+	age := nbtInt(a.Entity.NamedTag, "Age")
+	if a.Entity.NamedTag.GetByte("IsBaby") == 1 || age < 0 {
 		a.SetBaby(true)
+		if age < 0 {
+			a.AnimalAge = age
+		}
+	} else {
+		a.AnimalAge = age
+	}
+	if love := nbtInt(a.Entity.NamedTag, "InLove"); love > 0 {
+		a.SetInLove(true)
+		a.LoveTicks = love
+	}
+	a.effectState().LoadEffectsNBT()
+}
+func (a *Animal) DropItem(it item.Item) {
+	if a.Entity.Level == nil {
+		return
 	}
-	age := a.Entity.NamedTag.GetShort("Age")
-	if age != 0 {
-		a.AnimalAge = int(age)
+	drop := NewItemEntity(it)
+	drop.Entity.SetPosition(NewVector3(a.Entity.Position.X, a.Entity.Position.Y+a.Entity.Height/2, a.Entity.Position.Z))
+	drop.Motion = NewVector3((rand.Float64()-0.5)*0.2, 0.2, (rand.Float64()-0.5)*0.2)
+	drop.Level = a.Entity.Level
+	a.Entity.Level.SpawnEntity(drop)
+}
+func boolByte(v bool) byte {
+	if v {
+		return 1
 	}
+	return 0
 }
 func (a *Animal) GetName() string {
 	return a.MobName
 }
+func (a *Animal) GetDropExperience() int {
+	if a.IsBaby() {
+		return 0
+	}
+	return RandomExperience(a.DropExpMin, a.DropExpMax)
+}
 func (a *Animal) GetFeedFoodID() int {
 	return a.FeedFoodID
 }
 func (a *Animal) CanBeFedWith(itemID int) bool {
-	return a.FeedFoodID > 0 && itemID == a.FeedFoodID
+	if a.FeedFoodID > 0 && itemID == a.FeedFoodID {
+		return true
+	}
+	for _, id := range a.ExtraFoodIDs {
+		if id == itemID {
+			return true
+		}
+	}
+	return false
 }
+
 const CowNetworkID = 11
 const ItemWheat = 296
+
 func NewCow() *Animal {
 	cow := NewAnimal(CowNetworkID, "Cow", 8, 0.9, 1.3, 0.20, 2.0)
 	cow.Entity.EyeHeight = 1.2
@@ -137,6 +239,9 @@ func NewCow() *Animal {
 	cow.DropExpMax = 3
 	return cow
 }
+func (a *Animal) CanBeMilked() bool {
+	return a.Entity.NetworkID == CowNetworkID && !a.IsBabyFlag
+}
 func CowDrops(isOnFire bool, rand01 int, count int) (int, int, int) {
 	const (
 		RawBeef    = 363
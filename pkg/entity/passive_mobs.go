@@ -3,6 +3,7 @@ package entity
 import (
 	"math/rand"
 
+	"github.com/scaxe/scaxe-go/pkg/item"
 	"github.com/scaxe/scaxe-go/pkg/nbt"
 )
 
@@ -18,7 +19,7 @@ const (
 	ItemCookedChicken  = 366
 	ItemFeather        = 288
 	ItemCarrot         = 391
-	BlockWool = 35
+	BlockWool          = 35
 )
 func NewPig() *Animal {
 	pig := NewAnimal(PigNetworkID, "Pig", 10, 0.9, 0.9, 0.25, 1.25)
@@ -42,6 +43,7 @@ func NewChicken() *Chicken {
 	c := &Chicken{
 		Animal: NewAnimal(ChickenNetworkID, "Chicken", 4, 0.4, 0.7, 0.25, 1.4),
 	}
+	c.Animal.setOuter(c)
 	c.Animal.Entity.EyeHeight = 0.7
 	c.Animal.Entity.Gravity = 0.04
 	c.Animal.Entity.SlowFall = true
@@ -52,23 +54,34 @@ func NewChicken() *Chicken {
 	return c
 }
 func (c *Chicken) ResetEggTimer() {
-	c.EggTimer = 3000 + rand.Intn(3001)
+	c.EggTimer = 6000 + rand.Intn(6000)
 }
-type ChickenTickResult struct {
-	AnimalTickResult
-	ShouldLayEgg bool
-}
-func (c *Chicken) TickChicken() ChickenTickResult {
-	base := c.Animal.TickAnimal()
-	result := ChickenTickResult{AnimalTickResult: base}
+func (c *Chicken) Tick(currentTick int64) bool {
+	if !c.Animal.Tick(currentTick) {
+		return false
+	}
+	if c.IsBaby() || c.IsDying() {
+		return true
+	}
 
 	c.EggTimer--
 	if c.EggTimer <= 0 {
-		result.ShouldLayEgg = true
+		c.DropItem(item.NewItem(item.EGG, 0, 1))
 		c.ResetEggTimer()
 	}
-
-	return result
+	return true
+}
+func (c *Chicken) SaveChickenNBT() {
+	c.Animal.SaveAnimalNBT()
+	c.Animal.Entity.NamedTag.Set(nbt.NewIntTag("EggLayTime", int32(c.EggTimer)))
+}
+func (c *Chicken) LoadChickenFromNBT() {
+	c.Animal.LoadAnimalFromNBT()
+	if c.Animal.Entity.NamedTag != nil {
+		if t := c.Animal.Entity.NamedTag.GetInt("EggLayTime"); t > 0 {
+			c.EggTimer = int(t)
+		}
+	}
 }
 func (c *Chicken) IsImmuneToFallDamage() bool {
 	return true
@@ -84,7 +97,7 @@ func ChickenDrops(isOnFire bool, rand01 int, count int) (int, int, int) {
 }
 type Sheep struct {
 	*Animal
-	Color int
+	Color   int
 	Sheared bool
 }
 var SheepColorWeights = []struct {
@@ -125,41 +138,79 @@ func GetRandomSheepColor() int {
 	return 0
 }
 func NewSheep() *Sheep {
-	s := &Sheep{
-		Animal:  NewAnimal(SheepNetworkID, "Sheep", 8, 0.9, 1.3, 0.23, 1.25),
-		Color:   GetRandomSheepColor(),
-		Sheared: false,
-	}
-	s.Animal.Entity.EyeHeight = 0.95 * 1.3
-	s.Animal.FeedFoodID = ItemWheat
-	s.Animal.DropExpMin = 1
-	s.Animal.DropExpMax = 3
-	return s
+	return NewSheepWithColor(GetRandomSheepColor())
 }
 func NewSheepWithColor(color int) *Sheep {
 	s := &Sheep{
-		Animal:  NewAnimal(SheepNetworkID, "Sheep", 8, 0.9, 1.3, 0.23, 1.25),
-		Color:   color,
-		Sheared: false,
+		Animal: NewAnimal(SheepNetworkID, "Sheep", 8, 0.9, 1.3, 0.23, 1.25),
 	}
+	s.Animal.setOuter(s)
 	s.Animal.Entity.EyeHeight = 0.95 * 1.3
 	s.Animal.FeedFoodID = ItemWheat
+	s.Animal.DropExpMin = 1
+	s.Animal.DropExpMax = 3
+	s.Animal.Entity.Tasks.AddTask(5, NewAIEatGrass(s))
+	s.SetColor(color)
 	return s
 }
 func (s *Sheep) SetColor(color int) {
-	s.Color = color
+	s.Color = color & 0x0f
+	s.updateColorInfo()
 }
 func (s *Sheep) GetColor() int {
 	return s.Color
 }
 func (s *Sheep) SetSheared(sheared bool) {
 	s.Sheared = sheared
+	s.updateColorInfo()
 }
 func (s *Sheep) IsSheared() bool {
 	return s.Sheared
 }
+func (s *Sheep) updateColorInfo() {
+	info := byte(s.Color)
+	if s.Sheared {
+		info |= 0x10
+	}
+	s.Animal.Entity.Metadata.SetByte(DataColorInfo, info)
+}
 func (s *Sheep) RegrowWool() {
-	s.Sheared = false
+	s.SetSheared(false)
+}
+func (s *Sheep) CanBeSheared() bool {
+	return !s.Sheared && !s.IsBaby()
+}
+func (s *Sheep) Shear() bool {
+	if !s.CanBeSheared() {
+		return false
+	}
+	s.SetSheared(true)
+	wool := item.NewItem(BlockWool, s.Color, 1+rand.Intn(3))
+	s.DropItem(wool)
+	if s.Animal.Entity.Level != nil {
+		s.Animal.Entity.Level.UpdateEntityMetadata(s)
+	}
+	return true
+}
+func (s *Sheep) OnEatGrass() {
+	if s.IsBaby() {
+		s.AgeUp(1200)
+	}
+	s.RegrowWool()
+	if s.Animal.Entity.Level != nil {
+		s.Animal.Entity.Level.UpdateEntityMetadata(s)
+	}
+}
+func (s *Sheep) InheritFrom(a, b Breedable) {
+	parents := make([]*Sheep, 0, 2)
+	for _, p := range []Breedable{a, b} {
+		if ps, ok := p.(*Sheep); ok {
+			parents = append(parents, ps)
+		}
+	}
+	if len(parents) > 0 {
+		s.SetColor(parents[rand.Intn(len(parents))].Color)
+	}
 }
 func (s *Sheep) SheepDrops() (int, int, int) {
 	if s.Sheared {
@@ -168,34 +219,93 @@ func (s *Sheep) SheepDrops() (int, int, int) {
 	return BlockWool, s.Color, 1
 }
 func (s *Sheep) ShearDrops(count int) (int, int, int) {
-	s.Sheared = true
+	s.SetSheared(true)
 	return BlockWool, s.Color, count
 }
 func (s *Sheep) SaveSheepNBT() {
 	s.Animal.SaveAnimalNBT()
 	s.Animal.Entity.NamedTag.Set(nbt.NewByteTag("Color", int8(s.Color)))
-	sheared := int8(0)
-	if s.Sheared {
-		sheared = 1
-	}
-	s.Animal.Entity.NamedTag.Set(nbt.NewByteTag("Sheared", sheared))
+	s.Animal.Entity.NamedTag.Set(nbt.NewByteTag("Sheared", int8(boolByte(s.Sheared))))
 }
 func (s *Sheep) LoadSheepFromNBT() {
 	s.Animal.LoadAnimalFromNBT()
 	if s.Animal.Entity.NamedTag != nil {
-		s.Color = int(s.Animal.Entity.NamedTag.GetByte("Color"))
-		s.Sheared = s.Animal.Entity.NamedTag.GetByte("Sheared") == 1
+		s.SetColor(int(s.Animal.Entity.NamedTag.GetByte("Color")))
+		s.SetSheared(s.Animal.Entity.NamedTag.GetByte("Sheared") == 1)
 	}
 }
-var MobFactory = map[int]func() *Animal{
-	CowNetworkID:     NewCow,
-	PigNetworkID:     NewPig,
-	SheepNetworkID:   func() *Animal { return NewSheep().Animal },
-	ChickenNetworkID: func() *Animal { return NewChicken().Animal },
+var MobFactory = map[int]func() Breedable{
+	CowNetworkID:     func() Breedable { return NewCow() },
+	PigNetworkID:     func() Breedable { return NewPig() },
+	SheepNetworkID:   func() Breedable { return NewSheep() },
+	ChickenNetworkID: func() Breedable { return NewChicken() },
+	WolfNetworkID:    func() Breedable { return NewWolf() },
+	OcelotNetworkID:  func() Breedable { return NewOcelot() },
 }
-func CreatePassiveMob(networkID int) *Animal {
+func CreatePassiveMob(networkID int) Breedable {
 	if factory, ok := MobFactory[networkID]; ok {
 		return factory()
 	}
 	return nil
 }
+
+// passiveSaveIDs maps the "id" PocketMine writes for each mob to its
+// network ID.
+var passiveSaveIDs = map[string]int{
+	"Cow":     CowNetworkID,
+	"Pig":     PigNetworkID,
+	"Sheep":   SheepNetworkID,
+	"Chicken": ChickenNetworkID,
+	"Wolf":    WolfNetworkID,
+	"Ocelot":  OcelotNetworkID,
+}
+
+func IsPassiveMobNBT(tag *nbt.CompoundTag) bool {
+	_, ok := passiveSaveIDs[tag.GetString("id")]
+	return ok
+}
+
+// SavePassiveMob writes the mob's state into its NamedTag and returns it.
+func SavePassiveMob(mob Breedable) *nbt.CompoundTag {
+	switch m := mob.(type) {
+	case *Sheep:
+		m.SaveSheepNBT()
+	case *Chicken:
+		m.SaveChickenNBT()
+	case *Wolf:
+		m.SaveWolfNBT()
+	case *Ocelot:
+		m.SaveOcelotNBT()
+	default:
+		mob.GetAnimal().SaveAnimalNBT()
+	}
+	a := mob.GetAnimal()
+	a.Entity.NamedTag.Set(nbt.NewStringTag("id", a.MobName))
+	return a.Entity.NamedTag
+}
+
+// LoadPassiveMob rebuilds a mob saved by SavePassiveMob, or returns nil
+// if the tag is not a passive mob.
+func LoadPassiveMob(tag *nbt.CompoundTag) Breedable {
+	networkID, ok := passiveSaveIDs[tag.GetString("id")]
+	if !ok {
+		return nil
+	}
+	mob := CreatePassiveMob(networkID)
+	a := mob.GetAnimal()
+	a.Entity.NamedTag = tag
+	a.Entity.LoadNBT()
+	switch m := mob.(type) {
+	case *Sheep:
+		m.LoadSheepFromNBT()
+	case *Chicken:
+		m.LoadChickenFromNBT()
+	case *Wolf:
+		m.LoadWolfFromNBT()
+	case *Ocelot:
+		m.LoadOcelotFromNBT()
+	default:
+		a.LoadAnimalFromNBT()
+	}
+	return mob
+}
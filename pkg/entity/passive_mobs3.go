@@ -0,0 +1,230 @@
+package entity
+
+import (
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/nbt"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+const (
+	WolfNetworkID   = 14
+	OcelotNetworkID = 22
+)
+
+const (
+	ItemBone        = 352
+	ItemRawFish     = 349
+	ItemRawBeef     = 363
+	ItemCookedBeef  = 364
+	ItemRottenFlesh = 367
+)
+
+type namedEntity interface {
+	GetName() string
+}
+type TameableAnimal struct {
+	*Animal
+	OwnerName  string
+	Sitting    bool
+	TameItemID int
+	TameChance int
+}
+
+func newTameableAnimal(a *Animal, tameItemID, tameChance int) *TameableAnimal {
+	t := &TameableAnimal{
+		Animal:     a,
+		TameItemID: tameItemID,
+		TameChance: tameChance,
+	}
+	a.Entity.Tasks.AddTask(1, NewAISit(t))
+	a.Entity.Tasks.AddTask(4, NewAIFollowOwner(t, 1.0))
+	return t
+}
+func (t *TameableAnimal) IsTamed() bool {
+	return t.OwnerName != ""
+}
+func (t *TameableAnimal) GetOwnerName() string {
+	return t.OwnerName
+}
+func (t *TameableAnimal) GetTameItemID() int {
+	return t.TameItemID
+}
+func (t *TameableAnimal) IsOwner(e IEntity) bool {
+	n, ok := e.(namedEntity)
+	return ok && t.IsTamed() && n.GetName() == t.OwnerName
+}
+func (t *TameableAnimal) SetOwner(owner IEntity) {
+	if n, ok := owner.(namedEntity); ok {
+		t.OwnerName = n.GetName()
+	}
+	t.Animal.Entity.Metadata.SetFlag(DataAnimalFlags, DataAnimalFlagTamed, true)
+	t.Animal.Entity.Metadata.SetLong(DataOwnerEID, owner.GetID())
+}
+func (t *TameableAnimal) SetSitting(sitting bool) {
+	t.Sitting = sitting
+	t.Animal.Entity.Metadata.SetFlag(DataAnimalFlags, DataAnimalFlagSitting, sitting)
+}
+func (t *TameableAnimal) IsSitting() bool {
+	return t.Sitting
+}
+func (t *TameableAnimal) ToggleSitting() {
+	t.SetSitting(!t.Sitting)
+	if t.Animal.Entity.Level != nil {
+		t.Animal.Entity.Level.UpdateEntityMetadata(t.Animal.outer)
+	}
+}
+func (t *TameableAnimal) TryTame(owner IEntity) bool {
+	if t.IsTamed() {
+		return false
+	}
+	lvl := t.Animal.Entity.Level
+	if rand.Intn(t.TameChance) != 0 {
+		if lvl != nil {
+			lvl.BroadcastEntityEvent(t.Animal.Entity.ID, protocol.EntityEventTameFail)
+		}
+		return false
+	}
+
+	t.SetOwner(owner)
+	t.SetSitting(true)
+	t.Animal.Entity.MoveHelper.IsMoving = false
+	if lvl != nil {
+		lvl.BroadcastEntityEvent(t.Animal.Entity.ID, protocol.EntityEventTameSuccess)
+		lvl.UpdateEntityMetadata(t.Animal.outer)
+	}
+	return true
+}
+func (t *TameableAnimal) InheritFrom(a, b Breedable) {
+	for _, p := range []Breedable{a, b} {
+		if owner, ok := p.(interface{ GetOwnerName() string }); ok && owner.GetOwnerName() != "" {
+			t.OwnerName = owner.GetOwnerName()
+			t.Animal.Entity.Metadata.SetFlag(DataAnimalFlags, DataAnimalFlagTamed, true)
+			return
+		}
+	}
+}
+func (t *TameableAnimal) findOwner() IEntity {
+	if !t.IsTamed() || t.Animal.Entity.Level == nil {
+		return nil
+	}
+	for _, pl := range t.Animal.Entity.Level.GetPlayers() {
+		if t.IsOwner(pl) {
+			return pl
+		}
+	}
+	return nil
+}
+func (t *TameableAnimal) SaveTameableNBT() {
+	t.Animal.SaveAnimalNBT()
+	t.Animal.Entity.NamedTag.Set(nbt.NewStringTag("Owner", t.OwnerName))
+	t.Animal.Entity.NamedTag.Set(nbt.NewByteTag("Sitting", int8(boolByte(t.Sitting))))
+}
+func (t *TameableAnimal) LoadTameableFromNBT() {
+	t.Animal.LoadAnimalFromNBT()
+	if t.Animal.Entity.NamedTag == nil {
+		return
+	}
+	t.OwnerName = t.Animal.Entity.NamedTag.GetString("Owner")
+	t.Animal.Entity.Metadata.SetFlag(DataAnimalFlags, DataAnimalFlagTamed, t.IsTamed())
+	t.SetSitting(t.Animal.Entity.NamedTag.GetByte("Sitting") == 1)
+}
+
+type Wolf struct {
+	*TameableAnimal
+	CollarColor int
+}
+
+func NewWolf() *Wolf {
+	a := NewAnimal(WolfNetworkID, "Wolf", 8, 0.6, 0.85, 0.3, 1.4)
+	a.Entity.EyeHeight = 0.8 * 0.85
+	a.DropExpMin = 1
+	a.DropExpMax = 3
+	w := &Wolf{
+		TameableAnimal: newTameableAnimal(a, ItemBone, 3),
+		CollarColor:    14,
+	}
+	a.setOuter(w)
+	return w
+}
+func (w *Wolf) onTamed() {
+	w.Animal.Entity.MaxHealth = 20
+	w.Animal.Entity.Health = 20
+	w.Animal.FeedFoodID = ItemRawBeef
+	w.Animal.ExtraFoodIDs = []int{ItemCookedBeef, ItemRawPorkchop, ItemCookedPorkchop, ItemRawChicken, ItemCookedChicken, ItemRottenFlesh}
+	w.Animal.Entity.Metadata.SetByte(DataColorInfo, byte(w.CollarColor))
+}
+func (w *Wolf) TryTame(owner IEntity) bool {
+	if !w.TameableAnimal.TryTame(owner) {
+		return false
+	}
+	w.onTamed()
+	return true
+}
+func (w *Wolf) InheritFrom(a, b Breedable) {
+	w.TameableAnimal.InheritFrom(a, b)
+	if w.IsTamed() {
+		w.onTamed()
+	}
+}
+func (w *Wolf) SaveWolfNBT() {
+	w.SaveTameableNBT()
+	w.Animal.Entity.NamedTag.Set(nbt.NewByteTag("CollarColor", int8(w.CollarColor)))
+}
+func (w *Wolf) LoadWolfFromNBT() {
+	w.LoadTameableFromNBT()
+	if w.Animal.Entity.NamedTag == nil {
+		return
+	}
+	if w.Animal.Entity.NamedTag.Has("CollarColor") {
+		w.CollarColor = int(w.Animal.Entity.NamedTag.GetByte("CollarColor"))
+	}
+	if w.IsTamed() {
+		w.onTamed()
+	}
+}
+
+type Ocelot struct {
+	*TameableAnimal
+	CatType int
+}
+
+func NewOcelot() *Ocelot {
+	a := NewAnimal(OcelotNetworkID, "Ocelot", 10, 0.6, 0.7, 0.3, 1.33)
+	a.Entity.EyeHeight = 0.6
+	a.FeedFoodID = ItemRawFish
+	a.DropExpMin = 1
+	a.DropExpMax = 3
+	o := &Ocelot{
+		TameableAnimal: newTameableAnimal(a, ItemRawFish, 3),
+	}
+	a.setOuter(o)
+	return o
+}
+func (o *Ocelot) SetCatType(catType int) {
+	o.CatType = catType
+	o.Animal.Entity.Metadata.SetByte(DataCatType, byte(catType))
+}
+func (o *Ocelot) TryTame(owner IEntity) bool {
+	if !o.TameableAnimal.TryTame(owner) {
+		return false
+	}
+	o.SetCatType(1 + rand.Intn(3))
+	return true
+}
+func (o *Ocelot) InheritFrom(a, b Breedable) {
+	o.TameableAnimal.InheritFrom(a, b)
+	if parent, ok := a.(*Ocelot); ok {
+		o.SetCatType(parent.CatType)
+	}
+}
+func (o *Ocelot) SaveOcelotNBT() {
+	o.SaveTameableNBT()
+	o.Animal.Entity.NamedTag.Set(nbt.NewIntTag("CatType", int32(o.CatType)))
+}
+func (o *Ocelot) LoadOcelotFromNBT() {
+	o.LoadTameableFromNBT()
+	if o.Animal.Entity.NamedTag != nil {
+		o.SetCatType(int(o.Animal.Entity.NamedTag.GetInt("CatType")))
+	}
+}
@@ -87,3 +87,18 @@ func (t *PrimedTNT) SetExplosionForce(force float64) {
 func (t *PrimedTNT) SetBlockBreaking(breaking bool) {
 	t.BlockBreaking = breaking
 }
+func (t *PrimedTNT) Tick(currentTick int64) bool {
+	if t.Closed {
+		return false
+	}
+	result := t.TickTNT()
+	if !result.ShouldExplode {
+		t.Entity.TicksLived--
+		return t.Entity.Tick(currentTick)
+	}
+	if t.Entity.Level != nil {
+		DamageByExplosion(t.Entity.Level, NewVector3(result.ExplodeX, result.ExplodeY, result.ExplodeZ), result.Force, t)
+	}
+	t.Close()
+	return false
+}
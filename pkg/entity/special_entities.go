@@ -1,6 +1,9 @@
 package entity
 
-import "math/rand"
+import (
+	"math"
+	"math/rand"
+)
 
 const FishingHookNetworkID = 77
 type FishingHook struct {
@@ -38,7 +41,7 @@ func (fh *FishingHook) IsHooked() bool {
 
 const ExperienceOrbNetworkID = 69
 type ExperienceOrb struct {
-	Entity
+	*Entity
 	Experience int
 	PickupDelay int
 	Age int
@@ -50,6 +53,7 @@ const (
 )
 func NewExperienceOrb(experience int) *ExperienceOrb {
 	orb := &ExperienceOrb{
+		Entity:      NewEntity(),
 		Experience:  experience,
 		PickupDelay: 10,
 	}
@@ -77,6 +81,66 @@ func (o *ExperienceOrb) CanPickup() bool {
 func (o *ExperienceOrb) GetExperience() int {
 	return o.Experience
 }
+func (o *ExperienceOrb) Tick(currentTick int64) bool {
+	if o.Closed {
+		return false
+	}
+	if o.TickExperienceOrb() {
+		o.Close()
+		return false
+	}
+	if o.Level != nil && o.BoundingBox != nil {
+		o.mergeNearby()
+		if target := o.findCollector(); target != nil {
+			pos := target.GetPosition()
+			dx := pos.X - o.Position.X
+			dy := pos.Y + 0.9 - o.Position.Y
+			dz := pos.Z - o.Position.Z
+			dist := math.Sqrt(dx*dx + dy*dy + dz*dz)
+			if dist <= ExperienceOrbPickupDist && o.CanPickup() {
+				target.CollectExperience(o.Experience)
+				o.Close()
+				return false
+			}
+			if dist > 0 {
+				factor := 1 - dist/ExperienceOrbFollowRange
+				factor *= factor * 0.1
+				o.Motion.X += dx / dist * factor
+				o.Motion.Y += dy / dist * factor
+				o.Motion.Z += dz / dist * factor
+			}
+		}
+	}
+	return o.Entity.Tick(currentTick)
+}
+func (o *ExperienceOrb) mergeNearby() {
+	bb := o.BoundingBox.Grow(ExperienceOrbMergeRange, ExperienceOrbMergeRange, ExperienceOrbMergeRange)
+	for _, e := range o.Level.GetNearbyEntities(bb, o) {
+		other, ok := e.(*ExperienceOrb)
+		if !ok || other.Closed || other.GetID() < o.GetID() {
+			continue
+		}
+		o.Experience += other.Experience
+		if other.Age < o.Age {
+			o.Age = other.Age
+		}
+		other.Close()
+	}
+}
+func (o *ExperienceOrb) findCollector() ExperienceCollector {
+	var nearest ExperienceCollector
+	best := ExperienceOrbFollowRange
+	for _, e := range o.Level.GetPlayers() {
+		c, ok := e.(ExperienceCollector)
+		if !ok || !c.CanCollectExperience() {
+			continue
+		}
+		if dist := e.GetPosition().Distance(o.Position); dist < best {
+			nearest, best = c, dist
+		}
+	}
+	return nearest
+}
 
 const BigFireballNetworkID = 85
 type BigFireball struct {
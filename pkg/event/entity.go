@@ -44,6 +44,9 @@ const (
 	CauseCustom          = 14
 	CauseStarvation      = 15
 	CauseLightning       = 16
+	CauseWitherEffect    = 21
+	CauseThorns          = 22
+	CauseAnvil           = 23
 )
 
 type EntityDamageEvent struct {
@@ -86,6 +89,11 @@ func (e *EntityDamageEvent) SetDamage(damage float64, modifierType int) {
 	e.modifiers[modifierType] = damage
 }
 
+func (e *EntityDamageEvent) AddModifier(modifierType int, damage float64) {
+	e.modifiers[modifierType] = damage
+	e.originals[modifierType] = damage
+}
+
 func (e *EntityDamageEvent) GetOriginalDamage(modifierType int) float64 {
 	if d, ok := e.originals[modifierType]; ok {
 		return d
@@ -101,6 +109,11 @@ func (e *EntityDamageEvent) GetFinalDamage() float64 {
 	return damage
 }
 
+func (e *EntityDamageEvent) IsApplicable(modifierType int) bool {
+	_, ok := e.modifiers[modifierType]
+	return ok
+}
+
 func (e *EntityDamageEvent) GetKnockBack() float64 {
 	return e.knockBack
 }
@@ -133,7 +146,9 @@ func (e *EntityDamageByEntityEvent) GetDamagerID() int64 {
 
 type EntityDeathEvent struct {
 	*EntityEvent
-	Drops []interface{}
+	Cause      int
+	Drops      []interface{}
+	Experience int
 }
 
 var entityDeathHandlers = NewHandlerList()
@@ -141,6 +156,7 @@ var entityDeathHandlers = NewHandlerList()
 func NewEntityDeathEvent(entityID int64, drops []interface{}) *EntityDeathEvent {
 	return &EntityDeathEvent{
 		EntityEvent: NewEntityEvent("EntityDeathEvent", entityID),
+		Cause:       CauseCustom,
 		Drops:       drops,
 	}
 }
@@ -493,6 +509,50 @@ func NewEntityDrinkPotionEvent(entityID int64, potionID int) *EntityDrinkPotionE
 
 func (e *EntityDrinkPotionEvent) GetHandlers() *HandlerList { return entityDrinkPotionHandlers }
 
+type EntityEffectAddEvent struct {
+	*EntityEvent
+	EffectID  int
+	Amplifier int
+	Duration  int
+	Particles bool
+	Modify    bool
+}
+
+var entityEffectAddHandlers = NewHandlerList()
+
+func NewEntityEffectAddEvent(entityID int64, effectID, amplifier, duration int, particles, modify bool) *EntityEffectAddEvent {
+	return &EntityEffectAddEvent{
+		EntityEvent: NewEntityEvent("EntityEffectAddEvent", entityID),
+		EffectID:    effectID,
+		Amplifier:   amplifier,
+		Duration:    duration,
+		Particles:   particles,
+		Modify:      modify,
+	}
+}
+
+func (e *EntityEffectAddEvent) GetHandlers() *HandlerList { return entityEffectAddHandlers }
+
+type EntityEffectRemoveEvent struct {
+	*EntityEvent
+	EffectID  int
+	Amplifier int
+	Expired   bool
+}
+
+var entityEffectRemoveHandlers = NewHandlerList()
+
+func NewEntityEffectRemoveEvent(entityID int64, effectID, amplifier int, expired bool) *EntityEffectRemoveEvent {
+	return &EntityEffectRemoveEvent{
+		EntityEvent: NewEntityEvent("EntityEffectRemoveEvent", entityID),
+		EffectID:    effectID,
+		Amplifier:   amplifier,
+		Expired:     expired,
+	}
+}
+
+func (e *EntityEffectRemoveEvent) GetHandlers() *HandlerList { return entityEffectRemoveHandlers }
+
 type EntityInventoryChangeEvent struct {
 	*EntityEvent
 	Slot      int
@@ -0,0 +1,35 @@
+package event
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDamageModifierMath(t *testing.T) {
+	evt := NewEntityDamageEvent(1, CauseEntityAttack, 10)
+	evt.AddModifier(ModifierArmor, 0.6)
+	evt.AddModifier(ModifierResistance, 0.5)
+
+	steps := []struct {
+		name string
+		set  func()
+		want float64
+	}{
+		{"armour and resistance", func() {}, 3},
+		{"base raised", func() { evt.SetDamage(20, ModifierBase) }, 6},
+		{"armour removed", func() { evt.SetDamage(1, ModifierArmor) }, 10},
+		{"fully resisted", func() { evt.SetDamage(0, ModifierResistance) }, 0},
+	}
+	for _, step := range steps {
+		step.set()
+		if got := evt.GetFinalDamage(); math.Abs(got-step.want) > 1e-9 {
+			t.Errorf("%s: final damage = %v, want %v", step.name, got, step.want)
+		}
+	}
+	if got := evt.GetOriginalDamage(ModifierArmor); got != 0.6 {
+		t.Errorf("original armour modifier = %v, want 0.6", got)
+	}
+	if got := evt.GetOriginalDamage(ModifierBase); got != 10 {
+		t.Errorf("original base damage = %v, want 10", got)
+	}
+}
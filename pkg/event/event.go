@@ -1,5 +1,7 @@
 package event
 
+import "github.com/scaxe/scaxe-go/pkg/timings"
+
 const (
 	PriorityMonitor = 0
 	PriorityHighest = 1
@@ -74,6 +76,12 @@ func (r *RegisteredListener) Call(event Event) {
 			return
 		}
 	}
+	if r.pluginName != "" && timings.IsEnabled() {
+		plugin := timings.PluginEventsTimer(r.pluginName)
+		defer timings.Start(timings.PluginHandlers)()
+		defer timings.StartChild(timings.PluginHandlers, plugin)()
+		defer timings.StartChild(plugin, timings.HandlerTimer(r.pluginName, event.Name()))()
+	}
 	r.handler(event)
 }
 
@@ -136,6 +136,7 @@ func (e *PlayerMoveEvent) GetHandlers() *HandlerList {
 
 type PlayerDeathEvent struct {
 	*PlayerEvent
+	Cause          int
 	DeathMessage   string
 	KeepInventory  bool
 	KeepExperience bool
@@ -146,6 +147,7 @@ var playerDeathHandlers = NewHandlerList()
 func NewPlayerDeathEvent(playerName string, playerID int64, deathMessage string) *PlayerDeathEvent {
 	return &PlayerDeathEvent{
 		PlayerEvent:    NewPlayerEvent("PlayerDeathEvent", playerName, playerID),
+		Cause:          CauseCustom,
 		DeathMessage:   deathMessage,
 		KeepInventory:  false,
 		KeepExperience: false,
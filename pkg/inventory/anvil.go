@@ -0,0 +1,56 @@
+package inventory
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+)
+
+const (
+	AnvilSlotTarget    = 0
+	AnvilSlotSacrifice = 1
+	AnvilSlotResult    = 2
+)
+
+type AnvilInventory struct {
+	*TemporaryInventory
+}
+
+func NewAnvilInventory(holder InventoryHolder) *AnvilInventory {
+	return &AnvilInventory{
+		TemporaryInventory: NewTemporaryInventory(holder, GetInventoryType(TypeAnvil), AnvilSlotResult),
+	}
+}
+
+func (a *AnvilInventory) GetTarget() item.Item {
+	return a.GetItem(AnvilSlotTarget)
+}
+
+func (a *AnvilInventory) GetSacrifice() item.Item {
+	return a.GetItem(AnvilSlotSacrifice)
+}
+
+func (a *AnvilInventory) Result(name string) enchantment.AnvilResult {
+	return enchantment.Anvil(a.GetTarget(), a.GetSacrifice(), name)
+}
+
+func (a *AnvilInventory) Matches(res enchantment.AnvilResult, taken item.Item) bool {
+	if !res.IsValid() {
+		return false
+	}
+	out := res.Output
+	return out.ID == taken.ID && out.Meta == taken.Meta &&
+		out.GetCustomName() == taken.GetCustomName() &&
+		enchantment.SameEnchantments(out, taken)
+}
+
+func (a *AnvilInventory) Consume(res enchantment.AnvilResult) {
+	a.ClearSlot(AnvilSlotTarget, true)
+	sacrifice := a.GetSacrifice()
+	if res.SacrificeUsed >= sacrifice.Count {
+		a.ClearSlot(AnvilSlotSacrifice, true)
+	} else if res.SacrificeUsed > 0 {
+		sacrifice.Count -= res.SacrificeUsed
+		a.SetItem(AnvilSlotSacrifice, sacrifice)
+	}
+	a.ClearSlot(AnvilSlotResult, true)
+}
@@ -12,6 +12,13 @@ func NewContainerInventory(holder InventoryHolder, invType *InventoryType, overr
 		BaseInventory: NewBaseInventory(holder, invType, overrideSize, overrideTitle),
 	}
 }
+func (c *ContainerInventory) Open(who Viewer) bool {
+	c.OnOpen(who)
+	return true
+}
+func (c *ContainerInventory) Close(who Viewer) {
+	c.OnClose(who)
+}
 func (c *ContainerInventory) OnOpen(who Viewer) {
 	c.BaseInventory.OnOpen(who)
 
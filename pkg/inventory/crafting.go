@@ -19,6 +19,9 @@ func NewTemporaryInventory(holder InventoryHolder, invType *InventoryType, resul
 func (t *TemporaryInventory) GetResultSlotIndex() int {
 	return t.resultSlotIndex
 }
+func (t *TemporaryInventory) Close(who Viewer) {
+	t.OnClose(who)
+}
 func (t *TemporaryInventory) OnClose(who Viewer) {
 	if dropper, ok := who.(ItemDropper); ok {
 		for slot, it := range t.GetContents() {
@@ -58,3 +61,6 @@ func (f *FakeBlockMenu) GetInventory() Inventory { return f.inv }
 func (f *FakeBlockMenu) GetX() int               { return f.x }
 func (f *FakeBlockMenu) GetY() int               { return f.y }
 func (f *FakeBlockMenu) GetZ() int               { return f.z }
+func (f *FakeBlockMenu) SetInventory(inv Inventory) {
+	f.inv = inv
+}
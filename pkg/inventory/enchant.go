@@ -0,0 +1,91 @@
+package inventory
+
+import (
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+const EnchantSlotInput = 0
+
+type EnchantInventory struct {
+	*TemporaryInventory
+	bookshelves int
+	seed        int64
+	options     []enchantment.Option
+}
+
+func NewEnchantInventory(holder InventoryHolder, bookshelves int) *EnchantInventory {
+	e := &EnchantInventory{
+		TemporaryInventory: NewTemporaryInventory(holder, GetInventoryType(TypeEnchantTable), -1),
+		bookshelves:        bookshelves,
+		seed:               rand.Int63(),
+	}
+	e.OnSlotChangeFunc = func(slot int, _ item.Item) {
+		if slot == EnchantSlotInput {
+			e.refreshOptions()
+		}
+	}
+	return e
+}
+
+func (e *EnchantInventory) GetBookshelves() int {
+	return e.bookshelves
+}
+
+func (e *EnchantInventory) GetInput() item.Item {
+	return e.GetItem(EnchantSlotInput)
+}
+
+func (e *EnchantInventory) GetOptions() []enchantment.Option {
+	return e.options
+}
+
+func (e *EnchantInventory) MatchOption(result item.Item) (enchantment.Option, int, bool) {
+	input := e.GetInput()
+	for i, opt := range e.options {
+		expected := opt.Apply(input)
+		if expected.ID == result.ID && expected.Meta == result.Meta && enchantment.SameEnchantments(expected, result) {
+			return opt, i, true
+		}
+	}
+	return enchantment.Option{}, 0, false
+}
+
+func (e *EnchantInventory) Reseed() {
+	e.seed = rand.Int63()
+	e.refreshOptions()
+}
+
+func (e *EnchantInventory) Open(who Viewer) bool {
+	e.ContainerInventory.OnOpen(who)
+	e.sendOptions(who)
+	return true
+}
+
+func (e *EnchantInventory) refreshOptions() {
+	e.options = enchantment.Options(e.GetInput(), e.bookshelves, e.seed)
+	e.sendOptions(e.getViewerSlice()...)
+}
+
+func (e *EnchantInventory) sendOptions(targets ...Viewer) {
+	if len(e.options) == 0 {
+		return
+	}
+	list := protocol.EnchantmentList{}
+	for _, opt := range e.options {
+		entry := protocol.EnchantmentEntry{Cost: int32(opt.Cost), RandomName: opt.RandomName}
+		for _, in := range opt.Enchantments {
+			entry.Enchantments = append(entry.Enchantments, protocol.EnchantData{ID: int32(in.ID), Level: int32(in.Level)})
+		}
+		list.Entries = append(list.Entries, entry)
+	}
+	pk := protocol.NewCraftingDataPacket()
+	pk.CleanRecipes = false
+	pk.AddEnchantList(list)
+	for _, viewer := range targets {
+		viewer.SendDataPacket(pk)
+	}
+}
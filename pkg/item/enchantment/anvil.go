@@ -0,0 +1,175 @@
+package enchantment
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+const (
+	MaxAnvilCost = 40
+
+	planksID      = 5
+	cobblestoneID = 4
+)
+
+type AnvilResult struct {
+	Output        item.Item
+	Cost          int
+	SacrificeUsed int
+}
+
+func (r AnvilResult) IsValid() bool {
+	return !r.Output.IsAir() && r.Cost > 0
+}
+
+func repairMaterial(itemID int) int {
+	if info := item.GetArmorPieceInfo(itemID); info != nil {
+		switch info.Tier {
+		case item.ArmorTierLeather:
+			return item.LEATHER
+		case item.ArmorTierChain, item.ArmorTierIron:
+			return item.IRON_INGOT
+		case item.ArmorTierGold:
+			return item.GOLD_INGOT
+		case item.ArmorTierDiamond:
+			return item.DIAMOND
+		}
+		return 0
+	}
+	switch item.GetToolTier(itemID) {
+	case item.TierWooden:
+		return planksID
+	case item.TierStone:
+		return cobblestoneID
+	case item.TierIron:
+		return item.IRON_INGOT
+	case item.TierGolden:
+		return item.GOLD_INGOT
+	case item.TierDiamond:
+		return item.DIAMOND
+	}
+	return 0
+}
+
+func maxDurability(itemID int) int {
+	if info := item.GetArmorPieceInfo(itemID); info != nil {
+		return info.Durability
+	}
+	return item.GetMaxDurability(itemID)
+}
+
+func rarityMultiplier(e *Enchantment, fromBook bool) int {
+	m := 1
+	switch e.Rarity {
+	case RarityUncommon:
+		m = 2
+	case RarityRare:
+		m = 4
+	case RarityMythic:
+		m = 8
+	}
+	if fromBook && m > 1 {
+		m /= 2
+	}
+	return m
+}
+
+func Anvil(target, sacrifice item.Item, name string) AnvilResult {
+	if target.IsAir() {
+		return AnvilResult{Output: item.Air()}
+	}
+	out := target.Clone()
+	out.Count = 1
+	cost := 0
+	used := 0
+	prior := RepairCost(target)
+
+	if !sacrifice.IsAir() {
+		prior += RepairCost(sacrifice)
+		maxDur := maxDurability(target.ID)
+		fromBook := sacrifice.ID == item.ENCHANTED_BOOK
+
+		switch {
+		case maxDur > 0 && sacrifice.ID == repairMaterial(target.ID):
+			if out.Meta <= 0 {
+				return AnvilResult{Output: item.Air()}
+			}
+			step := maxDur / 4
+			for used < sacrifice.Count && out.Meta > 0 {
+				out.Meta -= step
+				if out.Meta < 0 {
+					out.Meta = 0
+				}
+				used++
+				cost++
+			}
+		case sacrifice.ID == target.ID || fromBook:
+			if !fromBook && maxDur > 0 && target.Meta > 0 {
+				remaining := (maxDur - target.Meta) + (maxDur - sacrifice.Meta) + maxDur*12/100
+				damage := maxDur - remaining
+				if damage < 0 {
+					damage = 0
+				}
+				if damage < out.Meta {
+					out.Meta = damage
+					cost += 2
+				}
+			}
+			applied := false
+			for _, in := range List(sacrifice) {
+				e := Get(in.ID)
+				if e == nil {
+					continue
+				}
+				if target.ID != item.ENCHANTED_BOOK && !CanApply(in.ID, target.ID) {
+					continue
+				}
+				if !CanAdd(out, in.ID, in.Level) {
+					cost++
+					continue
+				}
+				level := in.Level
+				if current := Level(out, in.ID); current == level {
+					level++
+				} else if current > level {
+					level = current
+				}
+				if level > e.MaxLevel {
+					level = e.MaxLevel
+				}
+				Set(&out, in.ID, level)
+				cost += level * rarityMultiplier(e, fromBook)
+				applied = true
+			}
+			if fromBook && !applied {
+				return AnvilResult{Output: item.Air()}
+			}
+			used = 1
+		default:
+			return AnvilResult{Output: item.Air()}
+		}
+	}
+
+	if name != target.GetCustomName() {
+		out.SetCustomName(name)
+		cost++
+	}
+	if cost == 0 {
+		return AnvilResult{Output: item.Air()}
+	}
+
+	SetRepairCost(&out, prior*2+1)
+	return AnvilResult{Output: out, Cost: cost + prior, SacrificeUsed: used}
+}
+
+func SameEnchantments(a, b item.Item) bool {
+	la, lb := List(a), List(b)
+	if len(la) != len(lb) {
+		return false
+	}
+	for _, in := range la {
+		if Level(b, in.ID) != in.Level {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package enchantment
+
+import (
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+const (
+	DamageKindGeneric = iota
+	DamageKindFire
+	DamageKindExplosion
+	DamageKindProjectile
+	DamageKindFall
+)
+
+const (
+	maxProtectionFactor = 20
+	fireAspectSeconds   = 4
+	flameSeconds        = 5
+)
+
+func DamageBonus(weapon item.Item, undead, arthropod bool) float64 {
+	bonus := 0.0
+	for _, in := range List(weapon) {
+		switch in.ID {
+		case Sharpness:
+			bonus += 1.25 * float64(in.Level)
+		case Smite:
+			if undead {
+				bonus += 2.5 * float64(in.Level)
+			}
+		case BaneOfArthropods:
+			if arthropod {
+				bonus += 2.5 * float64(in.Level)
+			}
+		}
+	}
+	return bonus
+}
+
+func KnockbackBonus(weapon item.Item) float64 {
+	return float64(Level(weapon, Knockback)) * 0.5
+}
+
+func FireAspectSeconds(weapon item.Item) int {
+	return Level(weapon, FireAspect) * fireAspectSeconds
+}
+
+func ProtectionFactor(armor []item.Item, kind int) int {
+	epf := 0
+	for _, piece := range armor {
+		for _, in := range List(piece) {
+			switch {
+			case in.ID == Protection:
+				epf += in.Level
+			case in.ID == FireProtection && kind == DamageKindFire:
+				epf += in.Level * 2
+			case in.ID == BlastProtection && kind == DamageKindExplosion:
+				epf += in.Level * 2
+			case in.ID == ProjectileProtection && kind == DamageKindProjectile:
+				epf += in.Level * 2
+			case in.ID == FeatherFalling && kind == DamageKindFall:
+				epf += in.Level * 3
+			}
+		}
+	}
+	if epf > maxProtectionFactor {
+		epf = maxProtectionFactor
+	}
+	return epf
+}
+
+func ApplyProtection(damage float64, armor []item.Item, kind int) float64 {
+	epf := ProtectionFactor(armor, kind)
+	if epf <= 0 {
+		return damage
+	}
+	return damage * (1 - float64(epf)*0.04)
+}
+
+func ThornsDamage(armor []item.Item) float64 {
+	damage := 0.0
+	for _, piece := range armor {
+		lvl := Level(piece, Thorns)
+		if lvl > 0 && rand.Float64() < 0.15*float64(lvl) {
+			damage += float64(1 + rand.Intn(4))
+		}
+	}
+	return damage
+}
+
+func EfficiencyLevel(tool item.Item) int {
+	return Level(tool, Efficiency)
+}
+
+func UnbreakingLevel(it item.Item) int {
+	return Level(it, Unbreaking)
+}
+
+func HasSilkTouch(tool item.Item) bool {
+	return Has(tool, SilkTouch)
+}
+
+func FortuneLevel(tool item.Item) int {
+	return Level(tool, Fortune)
+}
+
+func LootingLevel(weapon item.Item) int {
+	return Level(weapon, Looting)
+}
+
+func FortuneMultiplier(level int) int {
+	if level <= 0 {
+		return 1
+	}
+	bonus := rand.Intn(level+2) - 1
+	if bonus < 0 {
+		bonus = 0
+	}
+	return bonus + 1
+}
+
+func ArrowDamageBonus(bow item.Item) float64 {
+	lvl := Level(bow, Power)
+	if lvl <= 0 {
+		return 0
+	}
+	return float64(lvl)*0.5 + 0.5
+}
+
+func PunchLevel(bow item.Item) int {
+	return Level(bow, Punch)
+}
+
+func FlameSeconds(bow item.Item) int {
+	if Has(bow, Flame) {
+		return flameSeconds
+	}
+	return 0
+}
+
+func HasInfinity(bow item.Item) bool {
+	return Has(bow, Infinity)
+}
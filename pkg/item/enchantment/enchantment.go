@@ -0,0 +1,268 @@
+package enchantment
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+const (
+	Protection           = 0
+	FireProtection       = 1
+	FeatherFalling       = 2
+	BlastProtection      = 3
+	ProjectileProtection = 4
+	Thorns               = 5
+	Respiration          = 6
+	DepthStrider         = 7
+	AquaAffinity         = 8
+	Sharpness            = 9
+	Smite                = 10
+	BaneOfArthropods     = 11
+	Knockback            = 12
+	FireAspect           = 13
+	Looting              = 14
+	Efficiency           = 15
+	SilkTouch            = 16
+	Unbreaking           = 17
+	Fortune              = 18
+	Power                = 19
+	Punch                = 20
+	Flame                = 21
+	Infinity             = 22
+	LuckOfTheSea         = 23
+	Lure                 = 24
+)
+
+const (
+	RarityCommon   = 10
+	RarityUncommon = 5
+	RarityRare     = 2
+	RarityMythic   = 1
+)
+
+const (
+	SlotNone       = 0
+	SlotHead       = 1 << 0
+	SlotTorso      = 1 << 1
+	SlotLegs       = 1 << 2
+	SlotFeet       = 1 << 3
+	SlotSword      = 1 << 4
+	SlotAxe        = 1 << 5
+	SlotDigger     = 1 << 6
+	SlotBow        = 1 << 7
+	SlotFishingRod = 1 << 8
+	SlotShears     = 1 << 9
+	SlotTool       = 1 << 10
+
+	SlotArmor     = SlotHead | SlotTorso | SlotLegs | SlotFeet
+	SlotBreakable = SlotArmor | SlotSword | SlotAxe | SlotDigger | SlotBow | SlotFishingRod | SlotShears | SlotTool
+	SlotAll       = SlotBreakable
+)
+
+const (
+	groupNone = iota
+	groupProtection
+	groupDamage
+	groupMining
+)
+
+type Enchantment struct {
+	ID       int
+	Name     string
+	MaxLevel int
+	Rarity   int
+	Slots    int
+
+	minBase int
+	minStep int
+	maxSpan int
+	group   int
+}
+
+var enchantments = map[int]*Enchantment{
+	Protection:           {ID: Protection, Name: "protection", MaxLevel: 4, Rarity: RarityCommon, Slots: SlotArmor, minBase: 1, minStep: 11, maxSpan: 20, group: groupProtection},
+	FireProtection:       {ID: FireProtection, Name: "fire_protection", MaxLevel: 4, Rarity: RarityUncommon, Slots: SlotArmor, minBase: 10, minStep: 8, maxSpan: 12, group: groupProtection},
+	FeatherFalling:       {ID: FeatherFalling, Name: "feather_falling", MaxLevel: 4, Rarity: RarityUncommon, Slots: SlotFeet, minBase: 5, minStep: 6, maxSpan: 10},
+	BlastProtection:      {ID: BlastProtection, Name: "blast_protection", MaxLevel: 4, Rarity: RarityRare, Slots: SlotArmor, minBase: 5, minStep: 8, maxSpan: 12, group: groupProtection},
+	ProjectileProtection: {ID: ProjectileProtection, Name: "projectile_protection", MaxLevel: 4, Rarity: RarityUncommon, Slots: SlotArmor, minBase: 3, minStep: 6, maxSpan: 15, group: groupProtection},
+	Thorns:               {ID: Thorns, Name: "thorns", MaxLevel: 3, Rarity: RarityMythic, Slots: SlotTorso, minBase: 10, minStep: 20, maxSpan: 50},
+	Respiration:          {ID: Respiration, Name: "respiration", MaxLevel: 3, Rarity: RarityRare, Slots: SlotHead, minBase: 10, minStep: 10, maxSpan: 30},
+	DepthStrider:         {ID: DepthStrider, Name: "depth_strider", MaxLevel: 3, Rarity: RarityRare, Slots: SlotFeet, minBase: 10, minStep: 10, maxSpan: 15},
+	AquaAffinity:         {ID: AquaAffinity, Name: "aqua_affinity", MaxLevel: 1, Rarity: RarityRare, Slots: SlotHead, minBase: 1, minStep: 0, maxSpan: 40},
+	Sharpness:            {ID: Sharpness, Name: "sharpness", MaxLevel: 5, Rarity: RarityCommon, Slots: SlotSword | SlotAxe, minBase: 1, minStep: 11, maxSpan: 20, group: groupDamage},
+	Smite:                {ID: Smite, Name: "smite", MaxLevel: 5, Rarity: RarityUncommon, Slots: SlotSword | SlotAxe, minBase: 5, minStep: 8, maxSpan: 20, group: groupDamage},
+	BaneOfArthropods:     {ID: BaneOfArthropods, Name: "bane_of_arthropods", MaxLevel: 5, Rarity: RarityUncommon, Slots: SlotSword | SlotAxe, minBase: 5, minStep: 8, maxSpan: 20, group: groupDamage},
+	Knockback:            {ID: Knockback, Name: "knockback", MaxLevel: 2, Rarity: RarityUncommon, Slots: SlotSword, minBase: 5, minStep: 20, maxSpan: 50},
+	FireAspect:           {ID: FireAspect, Name: "fire_aspect", MaxLevel: 2, Rarity: RarityRare, Slots: SlotSword, minBase: 10, minStep: 20, maxSpan: 50},
+	Looting:              {ID: Looting, Name: "looting", MaxLevel: 3, Rarity: RarityRare, Slots: SlotSword, minBase: 15, minStep: 9, maxSpan: 50},
+	Efficiency:           {ID: Efficiency, Name: "efficiency", MaxLevel: 5, Rarity: RarityCommon, Slots: SlotDigger | SlotAxe | SlotShears, minBase: 1, minStep: 10, maxSpan: 50},
+	SilkTouch:            {ID: SilkTouch, Name: "silk_touch", MaxLevel: 1, Rarity: RarityMythic, Slots: SlotDigger | SlotAxe | SlotShears, minBase: 15, minStep: 0, maxSpan: 50, group: groupMining},
+	Unbreaking:           {ID: Unbreaking, Name: "unbreaking", MaxLevel: 3, Rarity: RarityUncommon, Slots: SlotBreakable, minBase: 5, minStep: 8, maxSpan: 50},
+	Fortune:              {ID: Fortune, Name: "fortune", MaxLevel: 3, Rarity: RarityRare, Slots: SlotDigger | SlotAxe, minBase: 15, minStep: 9, maxSpan: 50, group: groupMining},
+	Power:                {ID: Power, Name: "power", MaxLevel: 5, Rarity: RarityCommon, Slots: SlotBow, minBase: 1, minStep: 10, maxSpan: 15},
+	Punch:                {ID: Punch, Name: "punch", MaxLevel: 2, Rarity: RarityRare, Slots: SlotBow, minBase: 12, minStep: 20, maxSpan: 25},
+	Flame:                {ID: Flame, Name: "flame", MaxLevel: 1, Rarity: RarityRare, Slots: SlotBow, minBase: 20, minStep: 0, maxSpan: 30},
+	Infinity:             {ID: Infinity, Name: "infinity", MaxLevel: 1, Rarity: RarityMythic, Slots: SlotBow, minBase: 20, minStep: 0, maxSpan: 30},
+	LuckOfTheSea:         {ID: LuckOfTheSea, Name: "luck_of_the_sea", MaxLevel: 3, Rarity: RarityRare, Slots: SlotFishingRod, minBase: 15, minStep: 9, maxSpan: 50},
+	Lure:                 {ID: Lure, Name: "lure", MaxLevel: 3, Rarity: RarityRare, Slots: SlotFishingRod, minBase: 15, minStep: 9, maxSpan: 50},
+}
+
+var aliases = map[string]int{
+	"fire_prot":        FireProtection,
+	"blast_prot":       BlastProtection,
+	"projectile_prot":  ProjectileProtection,
+	"bane":             BaneOfArthropods,
+	"silk":             SilkTouch,
+	"luck":             LuckOfTheSea,
+	"luck_of_sea":      LuckOfTheSea,
+	"protection_fire":  FireProtection,
+	"protection_fall":  FeatherFalling,
+	"fall_protection":  FeatherFalling,
+	"water_breathing":  Respiration,
+	"water_worker":     AquaAffinity,
+	"water_walker":     DepthStrider,
+	"damage_all":       Sharpness,
+	"damage_undead":    Smite,
+	"damage_arthropod": BaneOfArthropods,
+	"dig_speed":        Efficiency,
+	"durability":       Unbreaking,
+	"loot_bonus":       Looting,
+	"bow_power":        Power,
+	"bow_knockback":    Punch,
+	"bow_fire":         Flame,
+	"bow_infinity":     Infinity,
+}
+
+func Get(id int) *Enchantment {
+	return enchantments[id]
+}
+
+func ByName(name string) *Enchantment {
+	key := strings.ToLower(strings.ReplaceAll(name, " ", "_"))
+	for _, e := range enchantments {
+		if e.Name == key {
+			return e
+		}
+	}
+	if id, ok := aliases[key]; ok {
+		return enchantments[id]
+	}
+	return nil
+}
+
+func All() []*Enchantment {
+	list := make([]*Enchantment, 0, len(enchantments))
+	for _, e := range enchantments {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (e *Enchantment) MinEnchantability(level int) int {
+	return e.minBase + (level-1)*e.minStep
+}
+
+func (e *Enchantment) MaxEnchantability(level int) int {
+	return e.MinEnchantability(level) + e.maxSpan
+}
+
+func (e *Enchantment) IsCompatibleWith(other *Enchantment) bool {
+	if e.ID == other.ID {
+		return false
+	}
+	if e.group != groupNone && e.group == other.group {
+		return false
+	}
+	return true
+}
+
+func IsCompatible(a, b int) bool {
+	ea, eb := Get(a), Get(b)
+	if ea == nil || eb == nil {
+		return false
+	}
+	return ea.IsCompatibleWith(eb)
+}
+
+func SlotsFor(itemID int) int {
+	switch {
+	case itemID == item.BOOK, itemID == item.ENCHANTED_BOOK:
+		return SlotAll
+	case itemID == item.BOW:
+		return SlotBow
+	case itemID == item.FISHING_ROD:
+		return SlotFishingRod
+	case itemID == item.SHEARS:
+		return SlotShears
+	case itemID == item.FLINT_AND_STEEL:
+		return SlotTool
+	}
+	if info := item.GetArmorPieceInfo(itemID); info != nil {
+		switch info.Slot {
+		case item.ArmorSlotHelmet:
+			return SlotHead
+		case item.ArmorSlotChestplate:
+			return SlotTorso
+		case item.ArmorSlotLeggings:
+			return SlotLegs
+		case item.ArmorSlotBoots:
+			return SlotFeet
+		}
+	}
+	switch item.GetToolType(itemID) {
+	case item.ToolTypeSword:
+		return SlotSword
+	case item.ToolTypeAxe:
+		return SlotAxe
+	case item.ToolTypePickaxe, item.ToolTypeShovel:
+		return SlotDigger
+	case item.ToolTypeHoe:
+		return SlotTool
+	}
+	return SlotNone
+}
+
+func CanApply(id int, itemID int) bool {
+	e := Get(id)
+	if e == nil {
+		return false
+	}
+	return e.Slots&SlotsFor(itemID) != 0
+}
+
+func Enchantability(itemID int) int {
+	switch itemID {
+	case item.BOOK, item.BOW, item.FISHING_ROD:
+		return 1
+	}
+	if info := item.GetArmorPieceInfo(itemID); info != nil {
+		switch info.Tier {
+		case item.ArmorTierLeather:
+			return 15
+		case item.ArmorTierGold:
+			return 25
+		case item.ArmorTierChain:
+			return 12
+		case item.ArmorTierIron:
+			return 9
+		case item.ArmorTierDiamond:
+			return 10
+		}
+	}
+	switch item.GetToolTier(itemID) {
+	case item.TierWooden:
+		return 15
+	case item.TierGolden:
+		return 22
+	case item.TierStone:
+		return 5
+	case item.TierIron:
+		return 14
+	case item.TierDiamond:
+		return 10
+	}
+	return 0
+}
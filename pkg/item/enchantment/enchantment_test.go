@@ -0,0 +1,115 @@
+package enchantment
+
+import (
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+func book(id, level int) item.Item {
+	b := item.NewItem(item.ENCHANTED_BOOK, 0, 1)
+	Set(&b, id, level)
+	return b
+}
+
+func TestAnvilCost(t *testing.T) {
+	worked := item.NewItem(item.IRON_SWORD, 0, 1)
+	SetRepairCost(&worked, 3)
+
+	tests := []struct {
+		name      string
+		target    item.Item
+		sacrifice item.Item
+		rename    string
+		cost      int
+		meta      int
+		used      int
+	}{
+		{"rename", item.NewItem(item.IRON_SWORD, 0, 1), item.Air(), "Blade", 1, 0, 0},
+		{"rename with prior work", worked, item.Air(), "Blade", 4, 0, 0},
+		{"repair one ingot", item.NewItem(item.IRON_SWORD, 100, 1), item.NewItem(item.IRON_INGOT, 0, 1), "", 1, 38, 1},
+		{"repair stops when whole", item.NewItem(item.IRON_SWORD, 100, 1), item.NewItem(item.IRON_INGOT, 0, 5), "", 2, 0, 2},
+		{"combine books", book(Sharpness, 1), book(Sharpness, 1), "", 2, 0, 1},
+		{"uncommon book on sword", item.NewItem(item.IRON_SWORD, 0, 1), book(Unbreaking, 2), "", 2, 0, 1},
+		{"nothing to do", item.NewItem(item.IRON_SWORD, 0, 1), item.Air(), "", 0, 0, 0},
+		{"wrong material", item.NewItem(item.IRON_SWORD, 100, 1), item.NewItem(item.DIAMOND, 0, 1), "", 0, 0, 0},
+	}
+	for _, tc := range tests {
+		res := Anvil(tc.target, tc.sacrifice, tc.rename)
+		if tc.cost == 0 {
+			if res.IsValid() {
+				t.Errorf("%s: got %+v, want no result", tc.name, res)
+			}
+			continue
+		}
+		if !res.IsValid() || res.Cost != tc.cost || res.Output.Meta != tc.meta || res.SacrificeUsed != tc.used {
+			t.Errorf("%s: cost=%d meta=%d used=%d, want cost=%d meta=%d used=%d",
+				tc.name, res.Cost, res.Output.Meta, res.SacrificeUsed, tc.cost, tc.meta, tc.used)
+		}
+		if want := RepairCost(tc.target)*2 + 1; RepairCost(res.Output) != want {
+			t.Errorf("%s: repair cost = %d, want %d", tc.name, RepairCost(res.Output), want)
+		}
+	}
+
+	if res := Anvil(book(Sharpness, 1), book(Sharpness, 1), ""); Level(res.Output, Sharpness) != 2 {
+		t.Errorf("combined sharpness level = %d, want 2", Level(res.Output, Sharpness))
+	}
+}
+
+func TestEnchantLevelForPower(t *testing.T) {
+	tests := []struct {
+		power int
+		want  int
+	}{
+		{0, 0},
+		{1, 1},
+		{11, 1},
+		{12, 2},
+		{30, 3},
+		{44, 4},
+		{45, 5},
+	}
+	for _, tc := range tests {
+		got := 0
+		for _, in := range available(item.DIAMOND_SWORD, tc.power) {
+			if in.ID == Sharpness {
+				got = in.Level
+			}
+		}
+		if got != tc.want {
+			t.Errorf("sharpness level at power %d = %d, want %d", tc.power, got, tc.want)
+		}
+	}
+}
+
+func TestTableCosts(t *testing.T) {
+	sword := item.NewItem(item.DIAMOND_SWORD, 0, 1)
+	for seed := int64(0); seed < 200; seed++ {
+		options := Options(sword, MaxBookshelves, seed)
+		if len(options) != TableOptions {
+			t.Fatalf("seed %d: %d options", seed, len(options))
+		}
+		if options[2].Cost != 30 {
+			t.Errorf("seed %d: top cost = %d with %d bookshelves, want 30", seed, options[2].Cost, MaxBookshelves)
+		}
+		for i, opt := range options {
+			if opt.Cost < 1 || (i > 0 && opt.Cost < options[i-1].Cost) {
+				t.Errorf("seed %d: costs %d, %d, %d", seed, options[0].Cost, options[1].Cost, options[2].Cost)
+				break
+			}
+		}
+
+		bare := Options(sword, 0, seed)
+		for _, opt := range bare {
+			if opt.Cost > 8 {
+				t.Errorf("seed %d: cost %d without bookshelves", seed, opt.Cost)
+			}
+		}
+	}
+
+	enchanted := book(Sharpness, 1)
+	enchanted.ID = item.DIAMOND_SWORD
+	if Options(enchanted, MaxBookshelves, 1) != nil {
+		t.Error("offered options for an enchanted item")
+	}
+}
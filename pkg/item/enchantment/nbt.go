@@ -0,0 +1,139 @@
+package enchantment
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/nbt"
+)
+
+const TagName = "ench"
+
+type Instance struct {
+	ID    int
+	Level int
+}
+
+func (in Instance) Enchantment() *Enchantment {
+	return Get(in.ID)
+}
+
+func List(it item.Item) []Instance {
+	if !it.HasNBT() {
+		return nil
+	}
+	list := it.NBTData.GetList(TagName)
+	if list == nil {
+		return nil
+	}
+	out := make([]Instance, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		entry, ok := list.Get(i).(*nbt.CompoundTag)
+		if !ok {
+			continue
+		}
+		out = append(out, Instance{ID: int(entry.GetShort("id")), Level: int(entry.GetShort("lvl"))})
+	}
+	return out
+}
+
+func HasEnchantments(it item.Item) bool {
+	return len(List(it)) > 0
+}
+
+func Level(it item.Item, id int) int {
+	for _, in := range List(it) {
+		if in.ID == id {
+			return in.Level
+		}
+	}
+	return 0
+}
+
+func Has(it item.Item, id int) bool {
+	return Level(it, id) > 0
+}
+
+func Set(it *item.Item, id, level int) {
+	current := List(*it)
+	replaced := false
+	for i := range current {
+		if current[i].ID == id {
+			current[i].Level = level
+			replaced = true
+		}
+	}
+	if !replaced {
+		current = append(current, Instance{ID: id, Level: level})
+	}
+	write(it, current)
+}
+
+func Remove(it *item.Item, id int) {
+	current := List(*it)
+	kept := current[:0]
+	for _, in := range current {
+		if in.ID != id {
+			kept = append(kept, in)
+		}
+	}
+	write(it, kept)
+}
+
+func Clear(it *item.Item) {
+	write(it, nil)
+}
+
+func write(it *item.Item, list []Instance) {
+	if len(list) == 0 {
+		if it.HasNBT() {
+			it.NBTData.Remove(TagName)
+			if it.NBTData.Count() == 0 {
+				it.ClearNBT()
+			}
+		}
+		return
+	}
+	tag := nbt.NewListTag(TagName, nbt.TagCompound)
+	for _, in := range list {
+		entry := nbt.NewCompoundTag("")
+		entry.Set(nbt.NewShortTag("id", int16(in.ID)))
+		entry.Set(nbt.NewShortTag("lvl", int16(in.Level)))
+		tag.Add(entry)
+	}
+	it.GetNBT().Set(tag)
+}
+
+func CanAdd(it item.Item, id, level int) bool {
+	e := Get(id)
+	if e == nil || level < 1 {
+		return false
+	}
+	if it.ID != item.ENCHANTED_BOOK && !CanApply(id, it.ID) {
+		return false
+	}
+	for _, in := range List(it) {
+		if in.ID == id {
+			continue
+		}
+		if other := Get(in.ID); other != nil && !e.IsCompatibleWith(other) {
+			return false
+		}
+	}
+	return true
+}
+
+func RepairCost(it item.Item) int {
+	if !it.HasNBT() {
+		return 0
+	}
+	return int(it.NBTData.GetInt("RepairCost"))
+}
+
+func SetRepairCost(it *item.Item, cost int) {
+	if cost <= 0 {
+		if it.HasNBT() {
+			it.NBTData.Remove("RepairCost")
+		}
+		return
+	}
+	it.GetNBT().Set(nbt.NewIntTag("RepairCost", int32(cost)))
+}
@@ -0,0 +1,170 @@
+package enchantment
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+const (
+	MaxBookshelves = 15
+	TableOptions   = 3
+)
+
+type Option struct {
+	Cost         int
+	Enchantments []Instance
+	RandomName   string
+}
+
+var nameWords = []string{
+	"the", "elder", "scrolls", "klaatu", "berata", "niktu", "xyzzy", "bless", "curse",
+	"light", "darkness", "fire", "air", "earth", "water", "hot", "dry", "cold", "wet",
+	"ignite", "snuff", "embiggen", "twist", "shorten", "stretch", "fiddle", "destroy",
+	"imbue", "galvanize", "enchant", "free", "limited", "range", "of", "towards",
+	"inside", "sphere", "cube", "self", "other", "ball", "mental", "physical", "grow",
+	"shrink", "demon", "elemental", "spirit", "animal", "creature", "beast", "humanoid",
+	"undead", "fresh", "stale",
+}
+
+func CanEnchant(it item.Item) bool {
+	if it.IsAir() || it.Count != 1 || HasEnchantments(it) {
+		return false
+	}
+	return Enchantability(it.ID) > 0 && SlotsFor(it.ID) != SlotNone
+}
+
+func Options(it item.Item, bookshelves int, seed int64) []Option {
+	if !CanEnchant(it) {
+		return nil
+	}
+	if bookshelves > MaxBookshelves {
+		bookshelves = MaxBookshelves
+	}
+	r := rand.New(rand.NewSource(seed))
+
+	base := r.Intn(8) + 1 + bookshelves/2 + r.Intn(bookshelves+1)
+	costs := [TableOptions]int{
+		maxInt(base/3, 1),
+		base*2/3 + 1,
+		maxInt(base, bookshelves*2),
+	}
+
+	options := make([]Option, 0, TableOptions)
+	for _, cost := range costs {
+		list := selectEnchantments(r, it.ID, cost)
+		if len(list) == 0 {
+			continue
+		}
+		options = append(options, Option{Cost: cost, Enchantments: list, RandomName: randomName(r)})
+	}
+	return options
+}
+
+func (o Option) Apply(it item.Item) item.Item {
+	result := it.Clone()
+	if result.ID == item.BOOK {
+		result.ID = item.ENCHANTED_BOOK
+	}
+	for _, in := range o.Enchantments {
+		Set(&result, in.ID, in.Level)
+	}
+	return result
+}
+
+func selectEnchantments(r *rand.Rand, itemID int, cost int) []Instance {
+	ability := Enchantability(itemID)
+	if ability <= 0 {
+		return nil
+	}
+	level := cost + 1 + r.Intn(ability/4+1) + r.Intn(ability/4+1)
+	bonus := 1 + (r.Float64()+r.Float64()-1)*0.15
+	level = int(math.Round(float64(level) * bonus))
+	if level < 1 {
+		level = 1
+	}
+
+	candidates := available(itemID, level)
+	picked := pickWeighted(r, candidates)
+	if picked == nil {
+		return nil
+	}
+	result := []Instance{*picked}
+
+	for r.Intn(50) <= level {
+		filtered := candidates[:0:0]
+		for _, c := range candidates {
+			ok := true
+			for _, chosen := range result {
+				if !IsCompatible(c.ID, chosen.ID) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				filtered = append(filtered, c)
+			}
+		}
+		candidates = filtered
+		next := pickWeighted(r, candidates)
+		if next == nil {
+			break
+		}
+		result = append(result, *next)
+		level /= 2
+	}
+	return result
+}
+
+func available(itemID int, power int) []Instance {
+	slots := SlotsFor(itemID)
+	var list []Instance
+	for _, e := range All() {
+		if e.Slots&slots == 0 {
+			continue
+		}
+		for lvl := e.MaxLevel; lvl >= 1; lvl-- {
+			if power >= e.MinEnchantability(lvl) && power <= e.MaxEnchantability(lvl) {
+				list = append(list, Instance{ID: e.ID, Level: lvl})
+				break
+			}
+		}
+	}
+	return list
+}
+
+func pickWeighted(r *rand.Rand, list []Instance) *Instance {
+	total := 0
+	for _, in := range list {
+		total += Get(in.ID).Rarity
+	}
+	if total <= 0 {
+		return nil
+	}
+	n := r.Intn(total)
+	for i := range list {
+		n -= Get(list[i].ID).Rarity
+		if n < 0 {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func randomName(r *rand.Rand) string {
+	count := r.Intn(2) + 3
+	words := make([]string, count)
+	for i := range words {
+		words[i] = nameWords[r.Intn(len(nameWords))]
+	}
+	return strings.Join(words, " ")
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
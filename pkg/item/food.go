@@ -11,6 +11,13 @@ type FoodInfo struct {
 	EffectDuration int
 	EffectLevel    int
 	AlwaysEdible   bool
+	Extra          []FoodEffect
+}
+type FoodEffect struct {
+	ID       int
+	Duration int
+	Level    int
+	Chance   float32
 }
 var foods = map[int]FoodInfo{
 	APPLE:        {ID: APPLE, Name: "Apple", FoodRestore: 4, Saturation: 2.4},
@@ -33,7 +40,8 @@ var foods = map[int]FoodInfo{
 	COOKED_CHICKEN:  {ID: COOKED_CHICKEN, Name: "Cooked Chicken", FoodRestore: 6, Saturation: 7.2},
 	COOKED_RABBIT:   {ID: COOKED_RABBIT, Name: "Cooked Rabbit", FoodRestore: 5, Saturation: 6.0},
 	COOKED_FISH:     {ID: COOKED_FISH, Name: "Cooked Fish", FoodRestore: 5, Saturation: 6.0},
-	GOLDEN_APPLE:  {ID: GOLDEN_APPLE, Name: "Golden Apple", FoodRestore: 4, Saturation: 9.6, AlwaysEdible: true},
+	GOLDEN_APPLE: {ID: GOLDEN_APPLE, Name: "Golden Apple", FoodRestore: 4, Saturation: 9.6, AlwaysEdible: true,
+		Extra: []FoodEffect{{ID: 10, Duration: 100, Level: 1, Chance: 1}, {ID: 22, Duration: 2400, Level: 0, Chance: 1}}},
 	GOLDEN_CARROT: {ID: GOLDEN_CARROT, Name: "Golden Carrot", FoodRestore: 6, Saturation: 14.4},
 	ROTTEN_FLESH: {ID: ROTTEN_FLESH, Name: "Rotten Flesh", FoodRestore: 4, Saturation: 0.8,
 		EffectChance: 0.8, EffectID: 17, EffectDuration: 600, EffectLevel: 0},
@@ -49,11 +57,14 @@ var foods = map[int]FoodInfo{
 	COOKED_SALMON: {ID: COOKED_SALMON, Name: "Cooked Salmon", FoodRestore: 6, Saturation: 9.6},
 	CLOWN_FISH:    {ID: CLOWN_FISH, Name: "Clownfish", FoodRestore: 1, Saturation: 0.2},
 	PUFFER_FISH: {ID: PUFFER_FISH, Name: "Pufferfish", FoodRestore: 1, Saturation: 0.2,
-		EffectChance: 1.0, EffectID: 19, EffectDuration: 1200, EffectLevel: 3},
+		EffectChance: 1.0, EffectID: 19, EffectDuration: 1200, EffectLevel: 3,
+		Extra: []FoodEffect{{ID: 17, Duration: 300, Level: 2, Chance: 1}, {ID: 9, Duration: 300, Level: 1, Chance: 1}}},
 	SPIDER_EYE: {ID: SPIDER_EYE, Name: "Spider Eye", FoodRestore: 2, Saturation: 3.2,
 		EffectChance: 1.0, EffectID: 19, EffectDuration: 100, EffectLevel: 0},
 	ENCHANTED_GOLDEN_APPLE: {ID: ENCHANTED_GOLDEN_APPLE, Name: "Enchanted Golden Apple",
-		FoodRestore: 4, Saturation: 9.6, AlwaysEdible: true},
+		FoodRestore: 4, Saturation: 9.6, AlwaysEdible: true,
+		Extra: []FoodEffect{{ID: 10, Duration: 600, Level: 4, Chance: 1}, {ID: 22, Duration: 2400, Level: 0, Chance: 1},
+			{ID: 11, Duration: 6000, Level: 0, Chance: 1}, {ID: 12, Duration: 6000, Level: 0, Chance: 1}}},
 }
 func GetFoodInfo(id int) *FoodInfo {
 	info, ok := foods[id]
@@ -74,6 +85,7 @@ type EatResult struct {
 	EffectID       int
 	EffectDuration int
 	EffectLevel    int
+	Effects        []FoodEffect
 }
 func CanEat(itemID int, currentFood int, maxFood int) bool {
 	info := GetFoodInfo(itemID)
@@ -104,7 +116,11 @@ func Eat(item Item) *EatResult {
 		result.EffectID = info.EffectID
 		result.EffectDuration = info.EffectDuration
 		result.EffectLevel = info.EffectLevel
+		result.Effects = append(result.Effects, FoodEffect{
+			ID: info.EffectID, Duration: info.EffectDuration, Level: info.EffectLevel, Chance: info.EffectChance,
+		})
 	}
+	result.Effects = append(result.Effects, info.Extra...)
 
 	return result
 }
@@ -125,12 +125,34 @@ func (i Item) GetCompoundTagBytes() []byte {
 	buf := new(bytes.Buffer)
 	w := nbt.NewWriter(buf, nbt.LittleEndian)
 
+	if err := w.WriteByte(nbt.TagCompound); err != nil {
+		return nil
+	}
+	if err := w.WriteString(""); err != nil {
+		return nil
+	}
 	if err := i.NBTData.Write(w); err != nil {
 		return nil
 	}
 	return buf.Bytes()
 }
 
+func ParseCompoundTag(data []byte) *nbt.CompoundTag {
+	if len(data) == 0 {
+		return nil
+	}
+	tag, err := nbt.NewReader(bytes.NewReader(data), nbt.LittleEndian).ReadTag()
+	if err != nil {
+		return nil
+	}
+	compound, ok := tag.(*nbt.CompoundTag)
+	if !ok || compound.Count() == 0 {
+		return nil
+	}
+	compound.SetName("")
+	return compound
+}
+
 func (i *Item) GetNBT() *nbt.CompoundTag {
 	if i.NBTData == nil {
 		i.NBTData = nbt.NewCompoundTag("")
@@ -188,3 +188,10 @@ const (
 	COOKED_SALMON          = 463
 	ENCHANTED_GOLDEN_APPLE = 466
 )
+
+// Block IDs that furnaces produce as items.
+const (
+	STONE         = 1
+	GLASS         = 20
+	HARDENED_CLAY = 172
+)
@@ -129,3 +129,29 @@ func BucketTypeToBlock(meta int) int {
 		return 0
 	}
 }
+
+var smeltingExperience = map[int]float64{
+	IRON_INGOT:      0.7,
+	GOLD_INGOT:      1.0,
+	DIAMOND:         1.0,
+	EMERALD:         1.0,
+	REDSTONE:        0.7,
+	DYE:             0.2,
+	COAL:            0.1,
+	NETHER_QUARTZ:   0.2,
+	BRICK:           0.3,
+	NETHER_BRICK:    0.1,
+	COOKED_PORKCHOP: 0.35,
+	COOKED_BEEF:     0.35,
+	COOKED_CHICKEN:  0.35,
+	COOKED_FISH:     0.35,
+	COOKED_SALMON:   0.35,
+	COOKED_RABBIT:   0.35,
+	BAKED_POTATO:    0.35,
+	STONE:           0.1,
+	GLASS:           0.1,
+	HARDENED_CLAY:   0.35,
+}
+func SmeltingExperience(resultID int) float64 {
+	return smeltingExperience[resultID]
+}
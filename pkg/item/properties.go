@@ -1,5 +1,7 @@
 package item
 
+import "strings"
+
 type ItemProperty struct {
 	ID            int
 	Name          string
@@ -25,6 +27,16 @@ func GetItemName(id int) string {
 	return "Unknown Item"
 }
 
+func ByName(name string) (int, bool) {
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimPrefix(name, "minecraft:")))
+	for id, prop := range itemProperties {
+		if strings.NewReplacer(" ", "_", "'", "").Replace(strings.ToLower(prop.Name)) == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
 func GetMaxStackSizeFor(id int) int {
 	if prop, ok := itemProperties[id]; ok && prop.MaxStackSize > 0 {
 		return prop.MaxStackSize
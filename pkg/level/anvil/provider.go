@@ -14,9 +14,13 @@ type AnvilProvider struct {
 	path    string
 	loaders map[uint64]*RegionLoader
 	mu      sync.Mutex
+	writeMu sync.RWMutex
 }
 
-var _ level.Provider = (*AnvilProvider)(nil)
+var (
+	_ level.Provider     = (*AnvilProvider)(nil)
+	_ level.WriteBlocker = (*AnvilProvider)(nil)
+)
 
 func NewAnvilProvider(path string) (*AnvilProvider, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -78,6 +82,9 @@ func (p *AnvilProvider) LoadChunk(x, z int32) (*world.Chunk, error) {
 }
 
 func (p *AnvilProvider) SaveChunk(chunk *world.Chunk) error {
+	p.writeMu.RLock()
+	defer p.writeMu.RUnlock()
+
 	rx := chunk.X >> 5
 	rz := chunk.Z >> 5
 
@@ -89,6 +96,14 @@ func (p *AnvilProvider) SaveChunk(chunk *world.Chunk) error {
 	return loader.WriteChunk(chunk)
 }
 
+// BlockWrites runs fn with chunk saves held off, so the region files do not
+// change underneath it.
+func (p *AnvilProvider) BlockWrites(fn func() error) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	return fn()
+}
+
 func (p *AnvilProvider) Close() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -0,0 +1,38 @@
+package anvil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/world"
+)
+
+func TestBlockWritesHoldsOffSaves(t *testing.T) {
+	p, err := NewAnvilProvider(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	saved := make(chan error, 1)
+	err = p.BlockWrites(func() error {
+		go func() { saved <- p.SaveChunk(world.NewChunk(0, 0)) }()
+		select {
+		case <-saved:
+			t.Error("chunk saved while writes were blocked")
+		case <-time.After(50 * time.Millisecond):
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-saved:
+		if err != nil {
+			t.Fatalf("SaveChunk: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("chunk was not saved after writes were unblocked")
+	}
+}
@@ -0,0 +1,85 @@
+package level
+
+import (
+	"math"
+
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/nbt"
+	"github.com/scaxe/scaxe-go/pkg/world"
+)
+
+// loadEntitiesFromChunk spawns the passive mobs saved in chunk. Tags for
+// entities this server does not run stay in chunk.Entities untouched.
+func (l *Level) loadEntitiesFromChunk(chunk *world.Chunk) {
+	loaded := 0
+	for _, tag := range chunk.Entities {
+		mob := entity.LoadPassiveMob(tag)
+		if mob == nil {
+			continue
+		}
+		mob.GetAnimal().Entity.Level = l
+		l.SpawnEntity(mob)
+		loaded++
+	}
+	if loaded > 0 {
+		logger.DebugLevel("Loaded entities from chunk", "cx", chunk.X, "cz", chunk.Z, "count", loaded)
+	}
+}
+
+// saveEntities writes every live passive mob into the Entities list of the
+// chunk it stands in, replacing the tags saved last time.
+func (l *Level) saveEntities() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	saved := make(map[int64][]*nbt.CompoundTag)
+	for _, e := range l.Entities {
+		if mob, ok := e.(entity.Breedable); ok {
+			hash := entityChunkHash(e)
+			saved[hash] = append(saved[hash], entity.SavePassiveMob(mob))
+		}
+	}
+	for hash, chunk := range l.Chunks {
+		replaceMobTags(chunk, saved[hash])
+	}
+}
+
+// unloadChunkEntities saves the passive mobs standing in chunk into it and
+// despawns them, so loading the chunk again does not spawn them twice. It
+// is called with l.mu held.
+func (l *Level) unloadChunkEntities(hash int64, chunk *world.Chunk) {
+	var mobs []*nbt.CompoundTag
+	for id, e := range l.Entities {
+		mob, ok := e.(entity.Breedable)
+		if !ok || entityChunkHash(e) != hash {
+			continue
+		}
+		mobs = append(mobs, entity.SavePassiveMob(mob))
+		delete(l.Entities, id)
+		l.pendingUpdates.Despawns = append(l.pendingUpdates.Despawns, id)
+		e.Close()
+	}
+	replaceMobTags(chunk, mobs)
+}
+
+func entityChunkHash(e entity.IEntity) int64 {
+	pos := e.GetPosition()
+	return world.ChunkHash(int32(math.Floor(pos.X))>>4, int32(math.Floor(pos.Z))>>4)
+}
+
+// replaceMobTags swaps the passive mob tags in chunk for mobs, keeping the
+// tags of entities this server does not run.
+func replaceMobTags(chunk *world.Chunk, mobs []*nbt.CompoundTag) {
+	entities := make([]*nbt.CompoundTag, 0, len(chunk.Entities)+len(mobs))
+	for _, tag := range chunk.Entities {
+		if !entity.IsPassiveMobNBT(tag) {
+			entities = append(entities, tag)
+		}
+	}
+	if len(entities) == len(chunk.Entities) && len(mobs) == 0 {
+		return
+	}
+	chunk.Entities = append(entities, mobs...)
+	chunk.SetChanged(true)
+}
@@ -0,0 +1,95 @@
+package level
+
+import (
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/nbt"
+	"github.com/scaxe/scaxe-go/pkg/world"
+)
+
+func TestPassiveMobsSurviveSave(t *testing.T) {
+	l := &Level{Chunks: make(map[int64]*world.Chunk), Entities: make(map[int64]entity.IEntity)}
+	chunk := world.NewChunk(1, 0)
+	unknown := nbt.NewCompoundTag("")
+	unknown.Set(nbt.NewStringTag("id", "Villager"))
+	chunk.Entities = []*nbt.CompoundTag{unknown}
+	l.Chunks[world.ChunkHash(1, 0)] = chunk
+
+	sheep := entity.NewSheepWithColor(4)
+	sheep.SetBaby(true)
+	sheep.Animal.Entity.SetPosition(entity.NewVector3(20, 64, 5))
+	wolf := entity.NewWolf()
+	wolf.OwnerName = "Alice"
+	wolf.Animal.Entity.SetPosition(entity.NewVector3(30, 64, 5))
+	l.AddEntity(sheep)
+	l.AddEntity(wolf)
+
+	l.saveEntities()
+	l.saveEntities()
+	if len(chunk.Entities) != 3 {
+		t.Fatalf("chunk holds %d entity tags, want 3", len(chunk.Entities))
+	}
+
+	loaded := &Level{Chunks: make(map[int64]*world.Chunk), Entities: make(map[int64]entity.IEntity)}
+	loaded.loadEntitiesFromChunk(chunk)
+	var gotSheep *entity.Sheep
+	var gotWolf *entity.Wolf
+	for _, e := range loaded.GetEntities() {
+		switch m := e.(type) {
+		case *entity.Sheep:
+			gotSheep = m
+		case *entity.Wolf:
+			gotWolf = m
+		}
+	}
+	if gotSheep == nil || gotWolf == nil {
+		t.Fatalf("loaded %d entities, want a sheep and a wolf", len(loaded.GetEntities()))
+	}
+	if !gotSheep.IsBaby() || gotSheep.GetColor() != 4 || gotSheep.Animal.Entity.Metadata.GetByte(entity.DataColorInfo) != 4 {
+		t.Errorf("sheep baby=%v color=%d", gotSheep.IsBaby(), gotSheep.GetColor())
+	}
+	if pos := gotSheep.GetPosition(); pos.X != 20 || pos.Z != 5 {
+		t.Errorf("sheep at %v", pos)
+	}
+	if !gotWolf.IsTamed() || gotWolf.OwnerName != "Alice" {
+		t.Errorf("wolf owner = %q", gotWolf.OwnerName)
+	}
+}
+
+func TestShortAgeStillLoads(t *testing.T) {
+	tag := nbt.NewCompoundTag("")
+	tag.Set(nbt.NewStringTag("id", "Cow"))
+	tag.Set(nbt.NewShortTag("Age", -1200))
+
+	cow := entity.LoadPassiveMob(tag)
+	if cow == nil || !cow.GetAnimal().IsBaby() || cow.GetAnimal().AnimalAge != -1200 {
+		t.Fatalf("loaded %+v", cow)
+	}
+}
+
+func TestUnloadChunkDespawnsMobs(t *testing.T) {
+	l := &Level{Chunks: make(map[int64]*world.Chunk), Entities: make(map[int64]entity.IEntity)}
+	chunk := world.NewChunk(1, 0)
+	l.Chunks[world.ChunkHash(1, 0)] = chunk
+
+	sheep := entity.NewSheepWithColor(4)
+	sheep.Animal.Entity.ID = 7
+	sheep.Animal.Entity.SetPosition(entity.NewVector3(20, 64, 5))
+	l.AddEntity(sheep)
+
+	for i := 0; i < 2; i++ {
+		l.UnloadChunk(1, 0, false, false)
+		if len(l.GetEntities()) != 0 || len(chunk.Entities) != 1 {
+			t.Fatalf("after unload: %d live entities, %d tags", len(l.GetEntities()), len(chunk.Entities))
+		}
+		l.Chunks[world.ChunkHash(1, 0)] = chunk
+		l.loadEntitiesFromChunk(chunk)
+		if len(l.GetEntities()) != 1 {
+			t.Fatalf("reload %d spawned %d entities, want 1", i, len(l.GetEntities()))
+		}
+	}
+	if despawns := l.DrainEntityUpdates().Despawns; len(despawns) != 2 || despawns[0] != 7 {
+		t.Errorf("despawns = %v", despawns)
+	}
+}
@@ -0,0 +1,69 @@
+package level
+
+import "github.com/scaxe/scaxe-go/pkg/entity"
+
+type PendingEntityEvent struct {
+	EntityID int64
+	Event    byte
+}
+
+type EntityUpdates struct {
+	Spawns   []entity.IEntity
+	Despawns []int64
+	Events   []PendingEntityEvent
+	Metadata []entity.IEntity
+}
+
+type PlayerProvider func() []entity.IEntity
+
+func (l *Level) SetPlayerProvider(provider PlayerProvider) {
+	l.mu.Lock()
+	l.playerProvider = provider
+	l.mu.Unlock()
+}
+
+func (l *Level) GetPlayers() []entity.IEntity {
+	l.mu.RLock()
+	provider := l.playerProvider
+	l.mu.RUnlock()
+	if provider == nil {
+		return nil
+	}
+	return provider()
+}
+
+func (l *Level) SpawnEntity(e entity.IEntity) {
+	l.mu.Lock()
+	l.Entities[e.GetID()] = e
+	l.pendingUpdates.Spawns = append(l.pendingUpdates.Spawns, e)
+	l.mu.Unlock()
+}
+
+func (l *Level) BroadcastEntityEvent(entityID int64, event byte) {
+	l.mu.Lock()
+	l.pendingUpdates.Events = append(l.pendingUpdates.Events, PendingEntityEvent{EntityID: entityID, Event: event})
+	l.mu.Unlock()
+}
+
+func (l *Level) UpdateEntityMetadata(e entity.IEntity) {
+	l.mu.Lock()
+	l.pendingUpdates.Metadata = append(l.pendingUpdates.Metadata, e)
+	l.mu.Unlock()
+}
+
+func (l *Level) UpdateBlock(x, y, z int32, id, meta byte) {
+	if !l.SetBlock(x, y, z, id, meta, true) {
+		return
+	}
+	l.PendingBlockUpdates = append(l.PendingBlockUpdates, PendingBlockUpdate{
+		X: x, Y: y, Z: z, ID: id, Meta: meta,
+	})
+}
+
+func (l *Level) DrainEntityUpdates() EntityUpdates {
+	l.mu.Lock()
+	updates := l.pendingUpdates
+	l.pendingUpdates = EntityUpdates{}
+	l.mu.Unlock()
+	return updates
+}
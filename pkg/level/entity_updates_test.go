@@ -0,0 +1,24 @@
+package level
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/entity"
+)
+
+func TestRemoveEntityQueuesDespawn(t *testing.T) {
+	l := &Level{Entities: make(map[int64]entity.IEntity)}
+	cow := entity.NewCow()
+	cow.Entity.ID = 42
+	l.AddEntity(cow)
+
+	l.RemoveEntity(cow)
+	l.RemoveEntity(cow)
+	if l.GetEntityByID(42) != nil {
+		t.Fatal("entity still in level after RemoveEntity")
+	}
+	if got := l.DrainEntityUpdates().Despawns; !reflect.DeepEqual(got, []int64{42}) {
+		t.Fatalf("Despawns = %v, want [42]", got)
+	}
+}
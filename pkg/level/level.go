@@ -9,6 +9,7 @@ import (
 	"github.com/scaxe/scaxe-go/pkg/level/generator"
 	"github.com/scaxe/scaxe-go/pkg/logger"
 	"github.com/scaxe/scaxe-go/pkg/tile"
+	"github.com/scaxe/scaxe-go/pkg/timings"
 	"github.com/scaxe/scaxe-go/pkg/world"
 )
 
@@ -66,6 +67,12 @@ type Level struct {
 	Tiles     *tile.TileManager
 
 	PendingBlockUpdates []PendingBlockUpdate
+
+	pendingUpdates EntityUpdates
+	playerProvider PlayerProvider
+
+	genMu sync.Mutex
+	ready map[int64]struct{}
 }
 
 type PendingBlockUpdate struct {
@@ -100,13 +107,13 @@ func NewLevel(name string, path string, provider Provider, generatorName string)
 
 	l.Generator = generator.GetGenerator(generatorName, nil)
 	if l.Generator != nil {
-		l.Generator.Init(l, l.Seed)
+		l.Generator.Init(l.generationView(), l.Seed)
 		logger.Info("Level generator initialized", "name", l.Generator.GetName())
 	} else {
 		logger.Warn("Unknown generator, falling back to gorigional", "name", generatorName)
 		l.Generator = generator.GetGenerator("gorigional", nil)
 		if l.Generator != nil {
-			l.Generator.Init(l, l.Seed)
+			l.Generator.Init(l.generationView(), l.Seed)
 		} else {
 			logger.Error("Failed to load any generator, world generation will not work")
 		}
@@ -116,7 +123,16 @@ func NewLevel(name string, path string, provider Provider, generatorName string)
 	return l
 }
 
+// GetChunk returns the chunk at x, z. With generate set, the chunk is
+// created if needed and only returned once it is fully populated.
 func (l *Level) GetChunk(x, z int32, generate bool) *world.Chunk {
+	if generate && l.Generator != nil {
+		return l.prepareChunk(x, z)
+	}
+	return l.loadChunk(x, z)
+}
+
+func (l *Level) loadChunk(x, z int32) *world.Chunk {
 	hash := world.ChunkHash(x, z)
 	l.mu.RLock()
 	chunk, exists := l.Chunks[hash]
@@ -138,24 +154,13 @@ func (l *Level) GetChunk(x, z int32, generate bool) *world.Chunk {
 			l.Chunks[hash] = c
 			l.mu.Unlock()
 			l.loadTilesFromChunk(c)
+			l.loadEntitiesFromChunk(c)
 
 			return c
 		}
 	}
 
-	if !generate {
-		return nil
-	}
-
-	if l.Generator != nil {
-		l.Generator.GenerateChunk(x, z)
-		l.Generator.PopulateChunk(x, z)
-	}
-
-	l.mu.RLock()
-	chunk = l.Chunks[hash]
-	l.mu.RUnlock()
-	return chunk
+	return nil
 }
 
 func (l *Level) GetSeed() int64 {
@@ -186,6 +191,7 @@ func (l *Level) UnloadChunk(x, z int32, safe bool, save bool) bool {
 	if !exists {
 		return true
 	}
+	l.unloadChunkEntities(hash, chunk)
 
 	if save && chunk.HasChanged() {
 		if l.Provider != nil {
@@ -199,12 +205,13 @@ func (l *Level) UnloadChunk(x, z int32, safe bool, save bool) bool {
 	}
 
 	delete(l.Chunks, hash)
+	delete(l.ready, hash)
 	return true
 }
 
 func (l *Level) RequestChunk(x, z int32, loader ChunkLoader) {
 
-	chunk := l.GetChunk(x, z, false)
+	chunk := l.readyChunk(x, z)
 	if chunk != nil {
 		loader.OnChunkLoaded(chunk)
 		return
@@ -220,7 +227,7 @@ func (l *Level) RequestChunk(x, z int32, loader ChunkLoader) {
 
 func (l *Level) AsyncLoadChunk(x, z int32, callback func(*world.Chunk)) {
 
-	if chunk := l.GetChunk(x, z, false); chunk != nil {
+	if chunk := l.readyChunk(x, z); chunk != nil {
 		if callback != nil {
 			callback(chunk)
 		}
@@ -228,28 +235,7 @@ func (l *Level) AsyncLoadChunk(x, z int32, callback func(*world.Chunk)) {
 	}
 
 	go func() {
-		var chunk *world.Chunk
-		var err error
-
-		if l.Provider != nil {
-			chunk, err = l.Provider.LoadChunk(x, z)
-			if err != nil {
-				logger.Error("AsyncLoadChunk failed", "x", x, "z", z, "error", err)
-			}
-		}
-
-		if chunk == nil {
-
-			if l.Generator != nil {
-				l.Generator.GenerateChunk(x, z)
-				l.Generator.PopulateChunk(x, z)
-			}
-
-			chunk = l.GetChunk(x, z, false)
-		} else {
-
-			l.SetChunk(x, z, chunk)
-		}
+		chunk := l.GetChunk(x, z, true)
 
 		if callback != nil {
 			callback(chunk)
@@ -382,7 +368,10 @@ func (l *Level) AddEntity(e entity.IEntity) {
 
 func (l *Level) RemoveEntity(e entity.IEntity) {
 	l.mu.Lock()
-	delete(l.Entities, e.GetID())
+	if _, ok := l.Entities[e.GetID()]; ok {
+		delete(l.Entities, e.GetID())
+		l.pendingUpdates.Despawns = append(l.pendingUpdates.Despawns, e.GetID())
+	}
 	l.mu.Unlock()
 }
 
@@ -555,20 +544,42 @@ func (l *Level) Tick() {
 	if len(entities) > 0 && l.tickState.currentTick%100 == 1 {
 		logger.Info("Level entity tick", "count", len(entities), "tick", l.tickState.currentTick)
 	}
+	stop := timings.Start(timings.EntityTick)
 	for _, e := range entities {
+		stopEntity := timings.StartChild(timings.EntityTick, entityTimer(e))
 		if !e.Tick(l.Time) {
 			l.RemoveEntity(e)
 		}
+		stopEntity()
 	}
+	stop()
+
+	stop = timings.Start(timings.ScheduledUpdates)
 	l.processScheduledUpdates()
+	stop()
 
+	stop = timings.Start(timings.PressurePlates)
 	l.tickPressurePlates()
+	stop()
 
+	stop = timings.Start(timings.ChunkTick)
 	l.tickChunks()
+	stop()
 
+	stop = timings.Start(timings.Weather)
 	l.TickWeather()
+	stop()
 
+	stop = timings.Start(timings.TileTick)
 	l.Tiles.TickUpdates()
+	stop()
+}
+
+func entityTimer(e entity.IEntity) string {
+	if !timings.IsEnabled() {
+		return ""
+	}
+	return timings.EntityTimer(e)
 }
 
 func (l *Level) tickPressurePlates() {
@@ -617,6 +628,8 @@ func isPressurePlate(bid byte) bool {
 }
 
 func (l *Level) Save() {
+	l.saveEntities()
+
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
@@ -636,7 +649,18 @@ func (l *Level) Save() {
 	}
 }
 
+// Snapshot hands the level directory to fn while the provider holds off
+// chunk writes. It does not save; call Save on the main thread first.
+func (l *Level) Snapshot(fn func(path string) error) error {
+	if b, ok := l.Provider.(WriteBlocker); ok {
+		return b.BlockWrites(func() error { return fn(l.Path) })
+	}
+	return fn(l.Path)
+}
+
 func (l *Level) Close() {
+	l.saveEntities()
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -684,6 +708,8 @@ func (l *Level) GetGenerator() generator.Generator {
 
 func (l *Level) GetSpawnLocation() *world.Vector3 {
 	if l.Generator != nil {
+		l.genMu.Lock()
+		defer l.genMu.Unlock()
 		return l.Generator.GetSpawn()
 	}
 
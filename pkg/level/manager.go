@@ -84,7 +84,7 @@ func (m *LevelManager) GenerateLevel(name string, generatorName string, seed int
 	gen := generator.GetGenerator(generatorName, nil)
 	if gen != nil {
 		lvl.Generator = gen
-		gen.Init(lvl, seed)
+		gen.Init(lvl.generationView(), seed)
 	}
 
 	m.levels[name] = lvl
@@ -0,0 +1,166 @@
+package level
+
+import (
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/level/generator"
+	"github.com/scaxe/scaxe-go/pkg/metrics"
+	"github.com/scaxe/scaxe-go/pkg/world"
+)
+
+var populationOrder = [4][2]int32{{-1, -1}, {0, -1}, {-1, 0}, {0, 0}}
+
+// readyChunk returns the chunk at x, z if it is loaded and fully populated.
+func (l *Level) readyChunk(x, z int32) *world.Chunk {
+	hash := world.ChunkHash(x, z)
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	chunk := l.Chunks[hash]
+	if l.Generator == nil {
+		return chunk
+	}
+	if _, ok := l.ready[hash]; !ok {
+		return nil
+	}
+	return chunk
+}
+
+// Chunks are built in two passes. Terrain comes first; population then
+// decorates the 16x16 area offset by +8 blocks, so PopulateChunk(x, z)
+// writes into chunks x+1 and z+1 as well and may only run once those have
+// terrain. A chunk is handed out as ready when the four populations that
+// write into it, its own and those of its -X, -Z and -X-Z neighbours, have
+// all run, which keeps trees and lakes from being cut at chunk borders.
+//
+// Generators are not safe for concurrent use, so every call into one
+// happens with genMu held. Chunk.Populated is saved as TerrainPopulated,
+// so a chunk loaded from disk is never populated twice.
+func (l *Level) prepareChunk(x, z int32) *world.Chunk {
+	if chunk := l.readyChunk(x, z); chunk != nil {
+		return chunk
+	}
+
+	l.genMu.Lock()
+	defer l.genMu.Unlock()
+
+	start := time.Now()
+	complete, worked := true, false
+	for _, offset := range populationOrder {
+		done, ran := l.populate(x+offset[0], z+offset[1])
+		complete = complete && done
+		worked = worked || ran
+	}
+
+	chunk := l.loadChunk(x, z)
+	if chunk == nil {
+		return nil
+	}
+	if worked {
+		// Neighbours may have decorated this chunk after its own pass.
+		chunk.InitBasicLighting()
+		metrics.ObserveChunkGeneration(time.Since(start))
+	}
+	if complete {
+		hash := world.ChunkHash(x, z)
+		l.mu.Lock()
+		if l.ready == nil {
+			l.ready = make(map[int64]struct{})
+		}
+		l.ready[hash] = struct{}{}
+		l.mu.Unlock()
+	}
+	return chunk
+}
+
+// populate runs the population pass for x, z unless it already has, first
+// generating terrain for the chunks it writes into. done reports whether
+// the chunk is populated afterwards. Called with genMu held.
+func (l *Level) populate(x, z int32) (done, ran bool) {
+	chunk := l.generateTerrain(x, z)
+	if chunk == nil {
+		return false, false
+	}
+	if chunk.Populated {
+		return true, false
+	}
+	for _, offset := range [3][2]int32{{1, 0}, {0, 1}, {1, 1}} {
+		if l.generateTerrain(x+offset[0], z+offset[1]) == nil {
+			return false, false
+		}
+	}
+
+	l.Generator.PopulateChunk(x, z)
+	chunk.Populated = true
+	chunk.SetChanged(true)
+	return true, true
+}
+
+// generateTerrain loads the chunk at x, z or generates its terrain without
+// populating it. Called with genMu held.
+func (l *Level) generateTerrain(x, z int32) *world.Chunk {
+	if chunk := l.loadChunk(x, z); chunk != nil {
+		return chunk
+	}
+	if l.Generator == nil {
+		return nil
+	}
+
+	l.Generator.GenerateChunk(x, z)
+	hash := world.ChunkHash(x, z)
+	l.mu.RLock()
+	chunk := l.Chunks[hash]
+	l.mu.RUnlock()
+	if chunk != nil {
+		chunk.Populated = false
+	}
+	return chunk
+}
+
+func (l *Level) generationView() generator.ChunkManager {
+	return generationView{l}
+}
+
+// generationView is the ChunkManager generators are initialised with.
+// Asking it for a chunk only ever generates terrain, so a generator that
+// reaches into a neighbour never starts another population pass. It is
+// only used with genMu held.
+type generationView struct {
+	level *Level
+}
+
+func (v generationView) GetChunk(x, z int32, create bool) *world.Chunk {
+	if create {
+		return v.level.generateTerrain(x, z)
+	}
+	return v.level.loadChunk(x, z)
+}
+
+func (v generationView) SetChunk(x, z int32, chunk *world.Chunk) {
+	v.level.SetChunk(x, z, chunk)
+}
+
+func (v generationView) GetSeed() int64 {
+	return v.level.GetSeed()
+}
+
+func (v generationView) GetBlockId(x, y, z int32) byte {
+	return v.level.GetBlockId(x, y, z)
+}
+
+func (v generationView) GetHeight(x, z int32) int32 {
+	return v.level.GetHeight(x, z)
+}
+
+// SetBlock writes straight into the chunk. Lighting is left to the
+// relight done once the chunk is ready, so update is ignored.
+func (v generationView) SetBlock(x, y, z int32, id, meta byte, update bool) bool {
+	if y < YMin || y >= YMax {
+		return false
+	}
+	chunk := v.GetChunk(x>>4, z>>4, true)
+	if chunk == nil {
+		return false
+	}
+	chunk.SetBlock(int(x&0x0F), int(y), int(z&0x0F), id, meta)
+	return true
+}
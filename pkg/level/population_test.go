@@ -0,0 +1,157 @@
+package level
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/block"
+	"github.com/scaxe/scaxe-go/pkg/level/generator"
+	"github.com/scaxe/scaxe-go/pkg/world"
+)
+
+// populationGen places a log in the far corner of every population area,
+// which lands in the +X+Z neighbour, and records what it was asked to do.
+type populationGen struct {
+	t        *testing.T
+	cm       generator.ChunkManager
+	active   int32
+	mu       sync.Mutex
+	terrain  map[int64]int
+	populate map[int64]int
+}
+
+func newPopulationGen(t *testing.T) *populationGen {
+	return &populationGen{t: t, terrain: make(map[int64]int), populate: make(map[int64]int)}
+}
+
+func (g *populationGen) enter() func() {
+	if atomic.AddInt32(&g.active, 1) != 1 {
+		g.t.Error("generator called concurrently")
+	}
+	return func() { atomic.AddInt32(&g.active, -1) }
+}
+
+func (g *populationGen) GetName() string                               { return "population" }
+func (g *populationGen) Init(level generator.ChunkManager, seed int64) { g.cm = level }
+func (g *populationGen) GetSpawn() *world.Vector3                      { return world.NewVector3(0, 64, 0) }
+func (g *populationGen) GetSettings() map[string]interface{}           { return nil }
+
+func (g *populationGen) GenerateChunk(cx, cz int32) {
+	defer g.enter()()
+	g.mu.Lock()
+	g.terrain[world.ChunkHash(cx, cz)]++
+	g.mu.Unlock()
+	g.cm.SetChunk(cx, cz, world.NewChunk(cx, cz))
+}
+
+func (g *populationGen) PopulateChunk(cx, cz int32) {
+	defer g.enter()()
+	for _, n := range [][2]int32{{cx, cz}, {cx + 1, cz}, {cx, cz + 1}, {cx + 1, cz + 1}} {
+		if g.cm.GetChunk(n[0], n[1], false) == nil {
+			g.t.Errorf("populating %d,%d before %d,%d has terrain", cx, cz, n[0], n[1])
+		}
+	}
+	g.mu.Lock()
+	g.populate[world.ChunkHash(cx, cz)]++
+	g.mu.Unlock()
+	g.cm.SetBlock(cx*16+8+15, 70, cz*16+8+15, block.LOG, 0, false)
+}
+
+func newPopulationLevel(t *testing.T) (*Level, *populationGen) {
+	gen := newPopulationGen(t)
+	l := &Level{Chunks: make(map[int64]*world.Chunk), Generator: gen}
+	gen.Init(l.generationView(), 0)
+	return l, gen
+}
+
+func TestPopulateAfterNeighbours(t *testing.T) {
+	l, gen := newPopulationLevel(t)
+
+	chunk := l.GetChunk(0, 0, true)
+	if chunk == nil || !chunk.Populated {
+		t.Fatalf("GetChunk(0, 0, true) = %v, want a populated chunk", chunk)
+	}
+	if id := chunk.GetBlockId(7, 70, 7); id != block.LOG {
+		t.Errorf("decoration from -1,-1 missing, block = %d", id)
+	}
+
+	tests := []struct {
+		x, z      int32
+		populated int
+	}{
+		{-1, -1, 1}, {0, -1, 1}, {-1, 0, 1}, {0, 0, 1},
+		{1, 0, 0}, {0, 1, 0}, {1, 1, 0},
+	}
+	for _, tc := range tests {
+		hash := world.ChunkHash(tc.x, tc.z)
+		if gen.terrain[hash] != 1 {
+			t.Errorf("terrain for %d,%d generated %d times", tc.x, tc.z, gen.terrain[hash])
+		}
+		if gen.populate[hash] != tc.populated {
+			t.Errorf("%d,%d populated %d times, want %d", tc.x, tc.z, gen.populate[hash], tc.populated)
+		}
+		if c := l.GetChunk(tc.x, tc.z, false); c.Populated != (tc.populated == 1) {
+			t.Errorf("%d,%d Populated = %v", tc.x, tc.z, c.Populated)
+		}
+	}
+
+	if l.readyChunk(1, 1) != nil {
+		t.Error("1,1 handed out before its neighbours were populated")
+	}
+	l.GetChunk(1, 1, true)
+	if gen.populate[world.ChunkHash(0, 0)] != 1 {
+		t.Error("0,0 populated again")
+	}
+}
+
+func TestPopulateSkipsSavedChunks(t *testing.T) {
+	l, gen := newPopulationLevel(t)
+	for x := int32(-1); x <= 0; x++ {
+		for z := int32(-1); z <= 0; z++ {
+			l.SetChunk(x, z, world.NewChunk(x, z))
+		}
+	}
+
+	if l.GetChunk(0, 0, true) == nil {
+		t.Fatal("GetChunk(0, 0, true) = nil")
+	}
+	if len(gen.terrain) != 0 || len(gen.populate) != 0 {
+		t.Errorf("generated %v, populated %v; want nothing", gen.terrain, gen.populate)
+	}
+}
+
+func TestPopulateConcurrently(t *testing.T) {
+	l, gen := newPopulationLevel(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for x := int32(0); x < 4; x++ {
+			for z := int32(0); z < 4; z++ {
+				wg.Add(1)
+				if (x+z+int32(i))%2 == 0 {
+					go func(x, z int32) {
+						defer wg.Done()
+						l.GetChunk(x, z, true)
+					}(x, z)
+				} else {
+					l.AsyncLoadChunk(x, z, func(*world.Chunk) { wg.Done() })
+				}
+			}
+		}
+	}
+	wg.Wait()
+
+	for x := int32(0); x < 4; x++ {
+		for z := int32(0); z < 4; z++ {
+			if l.readyChunk(x, z) == nil {
+				t.Errorf("%d,%d not ready", x, z)
+			}
+		}
+	}
+	for hash, n := range gen.terrain {
+		if n != 1 || gen.populate[hash] > 1 {
+			t.Errorf("chunk %d: terrain %d, populated %d", hash, n, gen.populate[hash])
+		}
+	}
+}
@@ -13,3 +13,9 @@ type Provider interface {
 
 	Close() error
 }
+
+// WriteBlocker is implemented by providers that can hold off chunk writes
+// while their files are read directly, e.g. by a backup.
+type WriteBlocker interface {
+	BlockWrites(fn func() error) error
+}
@@ -49,6 +49,9 @@ var (
 	debugLevel  bool = false
 	debugEntity bool = false
 	debugPlayer bool = false
+
+	subscribers    = make(map[int]func(string))
+	nextSubscriber int
 )
 
 func Init(out io.Writer, debug bool) {
@@ -124,6 +127,14 @@ func SetColor(enabled bool) {
 	colorEnabled = enabled
 }
 
+// SetOutput redirects console output without opening a log file, for tools
+// that embed server packages.
+func SetOutput(out io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = out
+}
+
 func Close() {
 	mu.Lock()
 	defer mu.Unlock()
@@ -186,6 +197,27 @@ func write(text string) {
 	if fileOutput != nil {
 		fmt.Fprint(fileOutput, stripANSI(text))
 	}
+	if len(subscribers) > 0 {
+		line := stripANSI(text)
+		for _, fn := range subscribers {
+			fn(line)
+		}
+	}
+}
+
+// Subscribe calls fn with every line written, without colour codes. fn runs
+// with the logger locked, so it must not block or log.
+func Subscribe(fn func(line string)) (cancel func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	id := nextSubscriber
+	nextSubscriber++
+	subscribers[id] = fn
+	return func() {
+		mu.Lock()
+		delete(subscribers, id)
+		mu.Unlock()
+	}
 }
 
 func Server(msg string, args ...any) {
@@ -7,12 +7,17 @@ import (
 
 type luaCommand struct {
 	command.BaseCommand
-	callback   *lua.LFunction
-	state      *lua.LState
-	pluginName string
+	callback *lua.LFunction
+	state    *lua.LState
+	plugin   *Plugin
 }
 
 func (c *luaCommand) Execute(sender command.CommandSender, args []string) bool {
+	if !c.plugin.Enabled || c.plugin.State != c.state {
+		sender.SendMessage("§cPlugin " + c.plugin.Meta.Name + " is disabled")
+		return true
+	}
+
 	senderTable := c.state.NewTable()
 	senderTable.RawSetString("name", lua.LString(sender.GetName()))
 	if sender.IsOp() {
@@ -26,11 +31,7 @@ func (c *luaCommand) Execute(sender command.CommandSender, args []string) bool {
 		argsTable.RawSetInt(i+1, lua.LString(arg))
 	}
 
-	if err := c.state.CallByParam(lua.P{
-		Fn:      c.callback,
-		NRet:    0,
-		Protect: true,
-	}, senderTable, argsTable); err != nil {
+	if err := c.plugin.call(c.callback, senderTable, argsTable); err != nil {
 		sender.SendMessage("§cCommand error: " + err.Error())
 		return false
 	}
@@ -67,9 +68,9 @@ func registerCommandAPI(L *lua.LState, p *Plugin, server ServerAPI) {
 				Usage:       usage,
 				Permission:  perm,
 			},
-			callback:   fn,
-			state:      L,
-			pluginName: p.Meta.Name,
+			callback: fn,
+			state:    L,
+			plugin:   p,
 		}
 
 		server.RegisterCommand(cmd)
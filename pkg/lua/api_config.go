@@ -0,0 +1,259 @@
+package lua
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func (p *Plugin) configPath() string {
+	return filepath.Join(p.DataDir(), "config.yml")
+}
+
+func (p *Plugin) loadConfig() error {
+	p.config = make(map[string]interface{})
+
+	path := p.configPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		defaults, err := os.ReadFile(filepath.Join(p.Dir, "config.yml"))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read default config.yml: %w", err)
+		}
+		if err := os.WriteFile(path, defaults, 0644); err != nil {
+			return fmt.Errorf("failed to write config.yml: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config.yml: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &p.config); err != nil {
+		return fmt.Errorf("failed to parse config.yml: %w", err)
+	}
+	if p.config == nil {
+		p.config = make(map[string]interface{})
+	}
+	return nil
+}
+
+func (p *Plugin) saveConfig() error {
+	return writeYAML(p.configPath(), p.config)
+}
+
+func writeYAML(path string, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func lookupPath(root map[string]interface{}, key string) (interface{}, bool) {
+	var cur interface{} = root
+	for _, part := range strings.Split(key, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func setPath(root map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	cur := root
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := cur[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			cur[part] = next
+		}
+		cur = next
+	}
+	last := parts[len(parts)-1]
+	if value == nil {
+		delete(cur, last)
+		return
+	}
+	cur[last] = value
+}
+
+func registerConfigAPI(L *lua.LState, p *Plugin) {
+	mod := L.NewTable()
+
+	mod.RawSetString("get", L.NewFunction(func(L *lua.LState) int {
+		v, ok := lookupPath(p.config, L.CheckString(1))
+		if !ok {
+			L.Push(L.Get(2))
+			return 1
+		}
+		L.Push(toLuaValue(L, v))
+		return 1
+	}))
+
+	mod.RawSetString("set", L.NewFunction(func(L *lua.LState) int {
+		setPath(p.config, L.CheckString(1), fromLuaValue(L.Get(2)))
+		return 0
+	}))
+
+	mod.RawSetString("getAll", L.NewFunction(func(L *lua.LState) int {
+		L.Push(toLuaValue(L, p.config))
+		return 1
+	}))
+
+	mod.RawSetString("save", L.NewFunction(func(L *lua.LState) int {
+		if err := p.saveConfig(); err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		L.Push(lua.LTrue)
+		return 1
+	}))
+
+	mod.RawSetString("reload", L.NewFunction(func(L *lua.LState) int {
+		if err := p.loadConfig(); err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		L.Push(lua.LTrue)
+		return 1
+	}))
+
+	L.SetGlobal("config", mod)
+
+	data := L.NewTable()
+
+	data.RawSetString("save", L.NewFunction(func(L *lua.LState) int {
+		path, err := p.resolveDataPath(L.CheckString(1) + ".yml")
+		if err == nil {
+			err = writeYAML(path, fromLuaValue(L.CheckAny(2)))
+		}
+		if err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		L.Push(lua.LTrue)
+		return 1
+	}))
+
+	data.RawSetString("load", L.NewFunction(func(L *lua.LState) int {
+		path, err := p.resolveDataPath(L.CheckString(1) + ".yml")
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		raw, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			L.Push(lua.LNil)
+			return 1
+		}
+		var v interface{}
+		if err == nil {
+			err = yaml.Unmarshal(raw, &v)
+		}
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		L.Push(toLuaValue(L, v))
+		return 1
+	}))
+
+	data.RawSetString("delete", L.NewFunction(func(L *lua.LState) int {
+		path, err := p.resolveDataPath(L.CheckString(1) + ".yml")
+		L.Push(lua.LBool(err == nil && os.Remove(path) == nil))
+		return 1
+	}))
+
+	L.SetGlobal("data", data)
+}
+
+func toLuaValue(L *lua.LState, v interface{}) lua.LValue {
+	switch val := v.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(val)
+	case int:
+		return lua.LNumber(val)
+	case int64:
+		return lua.LNumber(val)
+	case uint64:
+		return lua.LNumber(val)
+	case float64:
+		return lua.LNumber(val)
+	case string:
+		return lua.LString(val)
+	case []interface{}:
+		tbl := L.NewTable()
+		for _, item := range val {
+			tbl.Append(toLuaValue(L, item))
+		}
+		return tbl
+	case map[string]interface{}:
+		tbl := L.NewTable()
+		for k, item := range val {
+			tbl.RawSetString(k, toLuaValue(L, item))
+		}
+		return tbl
+	default:
+		return lua.LString(fmt.Sprint(val))
+	}
+}
+
+func fromLuaValue(v lua.LValue) interface{} {
+	switch val := v.(type) {
+	case lua.LBool:
+		return bool(val)
+	case lua.LNumber:
+		f := float64(val)
+		if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return int64(f)
+		}
+		return f
+	case lua.LString:
+		return string(val)
+	case *lua.LTable:
+		if n := val.MaxN(); n > 0 && n == countKeys(val) {
+			list := make([]interface{}, 0, n)
+			for i := 1; i <= n; i++ {
+				list = append(list, fromLuaValue(val.RawGetInt(i)))
+			}
+			return list
+		}
+		m := make(map[string]interface{})
+		val.ForEach(func(k, item lua.LValue) {
+			m[k.String()] = fromLuaValue(item)
+		})
+		return m
+	default:
+		return nil
+	}
+}
+
+func countKeys(tbl *lua.LTable) int {
+	n := 0
+	tbl.ForEach(func(_, _ lua.LValue) { n++ })
+	return n
+}
@@ -0,0 +1,68 @@
+package lua
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+func registerEntityAPI(L *lua.LState, server ServerAPI) {
+	mod := L.NewTable()
+
+	mod.RawSetString("spawn", L.NewFunction(func(L *lua.LState) int {
+		networkID := L.CheckInt(1)
+		x := float64(L.CheckNumber(2))
+		y := float64(L.CheckNumber(3))
+		z := float64(L.CheckNumber(4))
+		lvl := resolveLevel(L, server, 5)
+		if lvl == nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString("world not loaded"))
+			return 2
+		}
+		id, err := lvl.SpawnEntity(networkID, x, y, z)
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		L.Push(lua.LNumber(id))
+		return 1
+	}))
+
+	mod.RawSetString("remove", L.NewFunction(func(L *lua.LState) int {
+		id := int64(L.CheckNumber(1))
+		lvl := resolveLevel(L, server, 2)
+		L.Push(lua.LBool(lvl != nil && lvl.RemoveEntity(id)))
+		return 1
+	}))
+
+	mod.RawSetString("getAll", L.NewFunction(func(L *lua.LState) int {
+		lvl := resolveLevel(L, server, 1)
+		tbl := L.NewTable()
+		if lvl != nil {
+			for _, info := range lvl.GetEntities() {
+				tbl.Append(entityInfoToTable(L, info))
+			}
+		}
+		L.Push(tbl)
+		return 1
+	}))
+
+	L.SetGlobal("entity", mod)
+}
+
+func entityInfoToTable(L *lua.LState, info EntityInfo) *lua.LTable {
+	tbl := L.NewTable()
+	tbl.RawSetString("id", lua.LNumber(info.ID))
+	tbl.RawSetString("type", lua.LNumber(info.NetworkID))
+	tbl.RawSetString("x", lua.LNumber(info.X))
+	tbl.RawSetString("y", lua.LNumber(info.Y))
+	tbl.RawSetString("z", lua.LNumber(info.Z))
+	return tbl
+}
+
+func resolveLevel(L *lua.LState, server ServerAPI, idx int) LevelAPI {
+	if name, ok := L.Get(idx).(lua.LString); ok {
+		return server.GetLevelByName(string(name))
+	}
+	return server.GetLevel()
+}
@@ -0,0 +1,101 @@
+package lua
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+func registerInventoryAPI(L *lua.LState, server ServerAPI) {
+	mod := L.NewTable()
+
+	mod.RawSetString("getContents", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		if p == nil {
+			L.Push(lua.LNil)
+			return 1
+		}
+		tbl := L.NewTable()
+		for _, stack := range p.GetInventory() {
+			tbl.Append(itemStackToTable(L, stack))
+		}
+		L.Push(tbl)
+		return 1
+	}))
+
+	mod.RawSetString("getItemInHand", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		if p == nil {
+			L.Push(lua.LNil)
+			return 1
+		}
+		L.Push(itemStackToTable(L, p.GetItemInHand()))
+		return 1
+	}))
+
+	mod.RawSetString("setSlot", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		stack := ItemStack{
+			Slot:  L.CheckInt(2),
+			ID:    L.CheckInt(3),
+			Meta:  L.OptInt(5, 0),
+			Count: L.OptInt(4, 1),
+		}
+		L.Push(lua.LBool(p != nil && p.SetSlot(stack.Slot, stack)))
+		return 1
+	}))
+
+	mod.RawSetString("give", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		stack := ItemStack{ID: L.CheckInt(2), Count: L.OptInt(3, 1), Meta: L.OptInt(4, 0)}
+		if p == nil {
+			L.Push(lua.LNumber(stack.Count))
+			return 1
+		}
+		L.Push(lua.LNumber(p.AddItem(stack)))
+		return 1
+	}))
+
+	mod.RawSetString("take", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		stack := ItemStack{ID: L.CheckInt(2), Count: L.OptInt(3, 1), Meta: L.OptInt(4, -1)}
+		if p == nil {
+			L.Push(lua.LNumber(0))
+			return 1
+		}
+		L.Push(lua.LNumber(p.RemoveItem(stack)))
+		return 1
+	}))
+
+	mod.RawSetString("count", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		id := L.CheckInt(2)
+		meta := L.OptInt(3, -1)
+		total := 0
+		if p != nil {
+			for _, stack := range p.GetInventory() {
+				if stack.ID == id && (meta < 0 || stack.Meta == meta) {
+					total += stack.Count
+				}
+			}
+		}
+		L.Push(lua.LNumber(total))
+		return 1
+	}))
+
+	mod.RawSetString("clear", L.NewFunction(func(L *lua.LState) int {
+		if p := server.GetPlayer(L.CheckString(1)); p != nil {
+			p.ClearInventory()
+		}
+		return 0
+	}))
+
+	L.SetGlobal("inventory", mod)
+}
+
+func itemStackToTable(L *lua.LState, stack ItemStack) *lua.LTable {
+	tbl := L.NewTable()
+	tbl.RawSetString("slot", lua.LNumber(stack.Slot))
+	tbl.RawSetString("id", lua.LNumber(stack.ID))
+	tbl.RawSetString("meta", lua.LNumber(stack.Meta))
+	tbl.RawSetString("count", lua.LNumber(stack.Count))
+	return tbl
+}
@@ -81,5 +81,31 @@ func registerLevelAPI(L *lua.LState, server ServerAPI) {
 		return 1
 	}))
 
+	mod.RawSetString("addParticle", L.NewFunction(func(L *lua.LState) int {
+		particle := L.CheckInt(1)
+		x := L.CheckNumber(2)
+		y := L.CheckNumber(3)
+		z := L.CheckNumber(4)
+		data := L.OptInt(5, 0)
+		lvl := resolveLevel(L, server, 6)
+		if lvl != nil {
+			lvl.AddParticle(particle, float64(x), float64(y), float64(z), data)
+		}
+		return 0
+	}))
+
+	mod.RawSetString("playSound", L.NewFunction(func(L *lua.LState) int {
+		sound := L.CheckInt(1)
+		x := L.CheckNumber(2)
+		y := L.CheckNumber(3)
+		z := L.CheckNumber(4)
+		pitch := L.OptNumber(5, 1)
+		lvl := resolveLevel(L, server, 6)
+		if lvl != nil {
+			lvl.PlaySound(sound, float64(x), float64(y), float64(z), float64(pitch))
+		}
+		return 0
+	}))
+
 	L.SetGlobal("level", mod)
 }
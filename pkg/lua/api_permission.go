@@ -0,0 +1,120 @@
+package lua
+
+import (
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func registerPermissionAPI(L *lua.LState, server ServerAPI) {
+	mod := L.NewTable()
+
+	mod.RawSetString("has", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		L.Push(lua.LBool(p != nil && p.HasPermission(L.CheckString(2))))
+		return 1
+	}))
+
+	mod.RawSetString("grant", L.NewFunction(func(L *lua.LState) int {
+		if p := server.GetPlayer(L.CheckString(1)); p != nil {
+			p.SetPermission(L.CheckString(2), L.OptBool(3, true))
+		}
+		return 0
+	}))
+
+	mod.RawSetString("revoke", L.NewFunction(func(L *lua.LState) int {
+		if p := server.GetPlayer(L.CheckString(1)); p != nil {
+			p.UnsetPermission(L.CheckString(2))
+		}
+		return 0
+	}))
+
+	mod.RawSetString("register", L.NewFunction(func(L *lua.LState) int {
+		server.RegisterPermission(L.CheckString(1), L.OptString(2, ""), L.OptString(3, "op"))
+		return 0
+	}))
+
+	mod.RawSetString("getGroups", L.NewFunction(func(L *lua.LState) int {
+		tbl := L.NewTable()
+		if store := server.GetPermissionStore(); store != nil {
+			for _, name := range store.GetGroups() {
+				tbl.Append(lua.LString(name))
+			}
+		}
+		L.Push(tbl)
+		return 1
+	}))
+
+	mod.RawSetString("getPlayerGroups", L.NewFunction(func(L *lua.LState) int {
+		tbl := L.NewTable()
+		if store := server.GetPermissionStore(); store != nil {
+			for _, name := range store.GetPlayerGroups(L.CheckString(1)) {
+				tbl.Append(lua.LString(name))
+			}
+		}
+		L.Push(tbl)
+		return 1
+	}))
+
+	mod.RawSetString("setGroup", L.NewFunction(func(L *lua.LState) int {
+		return pushStoreResult(L, server, func(store PermissionStoreAPI) error {
+			return store.SetPlayerGroup(L.CheckString(1), L.CheckString(2))
+		})
+	}))
+
+	mod.RawSetString("addGroup", L.NewFunction(func(L *lua.LState) int {
+		return pushStoreResult(L, server, func(store PermissionStoreAPI) error {
+			return store.AddPlayerGroup(L.CheckString(1), L.CheckString(2), expiresIn(L.OptInt(3, 0)))
+		})
+	}))
+
+	mod.RawSetString("removeGroup", L.NewFunction(func(L *lua.LState) int {
+		return pushStoreResult(L, server, func(store PermissionStoreAPI) error {
+			return store.RemovePlayerGroup(L.CheckString(1), L.CheckString(2))
+		})
+	}))
+
+	mod.RawSetString("setNode", L.NewFunction(func(L *lua.LState) int {
+		return pushStoreResult(L, server, func(store PermissionStoreAPI) error {
+			return store.SetPlayerPermission(L.CheckString(1), L.OptString(4, ""), L.CheckString(2),
+				L.OptBool(3, true), expiresIn(L.OptInt(5, 0)))
+		})
+	}))
+
+	mod.RawSetString("unsetNode", L.NewFunction(func(L *lua.LState) int {
+		return pushStoreResult(L, server, func(store PermissionStoreAPI) error {
+			return store.UnsetPlayerPermission(L.CheckString(1), L.OptString(3, ""), L.CheckString(2))
+		})
+	}))
+
+	mod.RawSetString("reload", L.NewFunction(func(L *lua.LState) int {
+		return pushStoreResult(L, server, func(store PermissionStoreAPI) error {
+			return store.Reload()
+		})
+	}))
+
+	L.SetGlobal("permission", mod)
+}
+
+func pushStoreResult(L *lua.LState, server ServerAPI, fn func(store PermissionStoreAPI) error) int {
+	store := server.GetPermissionStore()
+	if store == nil {
+		L.Push(lua.LFalse)
+		L.Push(lua.LString("permission store not available"))
+		return 2
+	}
+	if err := fn(store); err != nil {
+		L.Push(lua.LFalse)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	L.Push(lua.LTrue)
+	return 1
+}
+
+func expiresIn(seconds int) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
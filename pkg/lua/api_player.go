@@ -130,6 +130,29 @@ func registerPlayerAPI(L *lua.LState, server ServerAPI) {
 		return 0
 	}))
 
+	mod.RawSetString("addEffect", L.NewFunction(func(L *lua.LState) int {
+		name := L.CheckString(1)
+		id := L.CheckInt(2)
+		seconds := L.OptInt(3, 30)
+		amplifier := L.OptInt(4, 0)
+		particles := L.OptBool(5, true)
+		p := server.GetPlayer(name)
+		if p != nil {
+			p.AddEffect(id, seconds*20, amplifier, particles)
+		}
+		return 0
+	}))
+
+	mod.RawSetString("removeEffect", L.NewFunction(func(L *lua.LState) int {
+		name := L.CheckString(1)
+		id := L.CheckInt(2)
+		p := server.GetPlayer(name)
+		if p != nil {
+			p.RemoveEffect(id)
+		}
+		return 0
+	}))
+
 	L.SetGlobal("player", mod)
 }
 
@@ -148,5 +171,6 @@ func playerToTable(L *lua.LState, p PlayerAPI) *lua.LTable {
 		tbl.RawSetString("op", lua.LFalse)
 	}
 	tbl.RawSetString("entityId", lua.LNumber(p.GetEntityID()))
+	tbl.RawSetString("world", lua.LString(p.GetLevelName()))
 	return tbl
 }
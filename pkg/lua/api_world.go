@@ -0,0 +1,61 @@
+package lua
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+func registerWorldAPI(L *lua.LState, server ServerAPI) {
+	mod := L.NewTable()
+
+	mod.RawSetString("getNames", L.NewFunction(func(L *lua.LState) int {
+		tbl := L.NewTable()
+		for _, name := range server.GetLevelNames() {
+			tbl.Append(lua.LString(name))
+		}
+		L.Push(tbl)
+		return 1
+	}))
+
+	mod.RawSetString("getDefault", L.NewFunction(func(L *lua.LState) int {
+		lvl := server.GetLevel()
+		if lvl == nil {
+			L.Push(lua.LNil)
+			return 1
+		}
+		L.Push(lua.LString(lvl.GetName()))
+		return 1
+	}))
+
+	mod.RawSetString("isLoaded", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LBool(server.GetLevelByName(L.CheckString(1)) != nil))
+		return 1
+	}))
+
+	mod.RawSetString("load", L.NewFunction(func(L *lua.LState) int {
+		if err := server.LoadLevel(L.CheckString(1)); err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		L.Push(lua.LTrue)
+		return 1
+	}))
+
+	mod.RawSetString("getPlayerWorld", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		if p == nil {
+			L.Push(lua.LNil)
+			return 1
+		}
+		L.Push(lua.LString(p.GetLevelName()))
+		return 1
+	}))
+
+	mod.RawSetString("teleport", L.NewFunction(func(L *lua.LState) int {
+		p := server.GetPlayer(L.CheckString(1))
+		L.Push(lua.LBool(p != nil && p.SwitchLevel(L.CheckString(2))))
+		return 1
+	}))
+
+	L.SetGlobal("world", mod)
+}
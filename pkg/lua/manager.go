@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/timings"
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -14,7 +16,9 @@ type PluginManager struct {
 	mu        sync.RWMutex
 	server    ServerAPI
 	plugins   map[string]*Plugin
+	order     []string
 	pluginDir string
+	limits    Limits
 }
 
 func NewPluginManager(server ServerAPI, dir string) *PluginManager {
@@ -22,9 +26,16 @@ func NewPluginManager(server ServerAPI, dir string) *PluginManager {
 		server:    server,
 		plugins:   make(map[string]*Plugin),
 		pluginDir: dir,
+		limits:    DefaultLimits(),
 	}
 }
 
+func (pm *PluginManager) SetLimits(limits Limits) {
+	pm.mu.Lock()
+	pm.limits = limits
+	pm.mu.Unlock()
+}
+
 func (pm *PluginManager) LoadAll() error {
 	if err := os.MkdirAll(pm.pluginDir, 0755); err != nil {
 		return fmt.Errorf("failed to create plugins directory: %w", err)
@@ -35,7 +46,8 @@ func (pm *PluginManager) LoadAll() error {
 		return fmt.Errorf("failed to read plugins directory: %w", err)
 	}
 
-	loaded := 0
+	metas := make(map[string]*PluginMeta)
+	dirs := make(map[string]string)
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -46,8 +58,23 @@ func (pm *PluginManager) LoadAll() error {
 			continue
 		}
 
-		if err := pm.LoadPlugin(entry.Name()); err != nil {
+		meta, err := loadPluginMeta(pluginDir)
+		if err != nil {
 			logger.Error("Failed to load plugin", "name", entry.Name(), "error", err)
+			continue
+		}
+		if other, dup := dirs[meta.Name]; dup {
+			logger.Error("Duplicate plugin name", "name", meta.Name, "dir", entry.Name(), "other", other)
+			continue
+		}
+		metas[meta.Name] = meta
+		dirs[meta.Name] = entry.Name()
+	}
+
+	loaded := 0
+	for _, name := range resolveLoadOrder(metas) {
+		if err := pm.LoadPlugin(dirs[name]); err != nil {
+			logger.Error("Failed to load plugin", "name", name, "error", err)
 		} else {
 			loaded++
 		}
@@ -73,11 +100,20 @@ func (pm *PluginManager) LoadPlugin(dirName string) error {
 		return err
 	}
 
-	if _, exists := pm.plugins[meta.Name]; exists {
-		return fmt.Errorf("plugin %s is already loaded", meta.Name)
+	if existing, exists := pm.plugins[meta.Name]; exists {
+		if existing.Enabled {
+			return fmt.Errorf("plugin %s is already loaded", meta.Name)
+		}
+		delete(pm.plugins, meta.Name)
+	}
+
+	for _, dep := range meta.Depend {
+		if p, ok := pm.plugins[dep]; !ok || !p.Enabled {
+			return fmt.Errorf("plugin %s requires %s, which is not enabled", meta.Name, dep)
+		}
 	}
 
-	plugin := newPlugin(meta, pluginDir)
+	plugin := newPlugin(meta, pluginDir, pm.limits)
 	plugin.createState(pm.server)
 
 	if err := plugin.enable(); err != nil {
@@ -86,6 +122,8 @@ func (pm *PluginManager) LoadPlugin(dirName string) error {
 	}
 
 	pm.plugins[meta.Name] = plugin
+	pm.removeFromOrder(meta.Name)
+	pm.order = append(pm.order, meta.Name)
 	logger.Server("Plugin enabled", "name", meta.Name, "version", meta.Version, "author", meta.Author)
 	return nil
 }
@@ -94,15 +132,52 @@ func (pm *PluginManager) UnloadPlugin(name string) error {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
+	if _, exists := pm.plugins[name]; !exists {
+		return fmt.Errorf("plugin %s not found", name)
+	}
+	pm.unload(name)
+	return nil
+}
+
+func (pm *PluginManager) unload(name string) {
 	plugin, exists := pm.plugins[name]
 	if !exists {
-		return fmt.Errorf("plugin %s not found", name)
+		return
+	}
+	delete(pm.plugins, name)
+
+	for _, other := range pm.ordered() {
+		for _, dep := range other.Meta.Depend {
+			if dep == name {
+				logger.Warn("Disabling dependent plugin", "name", other.Meta.Name, "dependency", name)
+				pm.unload(other.Meta.Name)
+				break
+			}
+		}
 	}
 
 	plugin.disable()
-	delete(pm.plugins, name)
+	pm.removeFromOrder(name)
 	logger.Server("Plugin disabled", "name", name)
-	return nil
+}
+
+func (pm *PluginManager) removeFromOrder(name string) {
+	for i, n := range pm.order {
+		if n == name {
+			pm.order = append(pm.order[:i], pm.order[i+1:]...)
+			return
+		}
+	}
+}
+
+func (pm *PluginManager) ordered() []*Plugin {
+	result := make([]*Plugin, 0, len(pm.plugins))
+	for _, name := range pm.order {
+		if p, ok := pm.plugins[name]; ok {
+			result = append(result, p)
+		}
+	}
+	return result
 }
 
 func (pm *PluginManager) ReloadPlugin(name string) error {
@@ -114,12 +189,36 @@ func (pm *PluginManager) ReloadPlugin(name string) error {
 	}
 
 	dir := plugin.Dir
-	plugin.disable()
-	delete(pm.plugins, name)
+	dependents := pm.dependents(name)
+	pm.unload(name)
 	pm.mu.Unlock()
 
-	dirName := filepath.Base(dir)
-	return pm.LoadPlugin(dirName)
+	if err := pm.LoadPlugin(filepath.Base(dir)); err != nil {
+		return err
+	}
+	for _, dep := range dependents {
+		if err := pm.LoadPlugin(filepath.Base(dep.Dir)); err != nil {
+			logger.Error("Failed to re-enable dependent plugin", "name", dep.Meta.Name, "error", err)
+		}
+	}
+	return nil
+}
+
+// dependents lists the plugins that unloading name takes down with it, in
+// load order, so they can be brought back after name is reloaded.
+func (pm *PluginManager) dependents(name string) []*Plugin {
+	affected := map[string]bool{name: true}
+	var result []*Plugin
+	for _, p := range pm.ordered() {
+		for _, dep := range p.Meta.Depend {
+			if affected[dep] && !affected[p.Meta.Name] {
+				affected[p.Meta.Name] = true
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result
 }
 
 func (pm *PluginManager) GetPlugin(name string) *Plugin {
@@ -131,38 +230,87 @@ func (pm *PluginManager) GetPlugin(name string) *Plugin {
 func (pm *PluginManager) GetPlugins() []*Plugin {
 	pm.mu.RLock()
 	defer pm.mu.RUnlock()
-	result := make([]*Plugin, 0, len(pm.plugins))
-	for _, p := range pm.plugins {
-		result = append(result, p)
-	}
-	return result
+	return pm.ordered()
 }
 
 func (pm *PluginManager) DisableAll() {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
-	for name, plugin := range pm.plugins {
-		plugin.disable()
-		logger.Server("Plugin disabled", "name", name)
+	plugins := pm.ordered()
+	for i := len(plugins) - 1; i >= 0; i-- {
+		plugins[i].disable()
+		logger.Server("Plugin disabled", "name", plugins[i].Meta.Name)
 	}
 	pm.plugins = make(map[string]*Plugin)
+	pm.order = nil
 }
 
 func (pm *PluginManager) GetPluginNames() []string {
 	pm.mu.RLock()
 	defer pm.mu.RUnlock()
 	names := make([]string, 0, len(pm.plugins))
-	for name := range pm.plugins {
-		names = append(names, name)
+	for _, p := range pm.ordered() {
+		names = append(names, p.Meta.Name)
 	}
 	return names
 }
 
 func (pm *PluginManager) EnablePlugin(name string) error {
+	pm.mu.RLock()
+	plugin, exists := pm.plugins[name]
+	pm.mu.RUnlock()
+	if exists {
+		if plugin.Enabled {
+			return fmt.Errorf("plugin %s is already enabled", name)
+		}
+		return pm.LoadPlugin(filepath.Base(plugin.Dir))
+	}
 	return pm.LoadPlugin(name)
 }
 
+func (pm *PluginManager) IsPluginEnabled(name string) bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	plugin, exists := pm.plugins[name]
+	return exists && plugin.Enabled
+}
+
+func (pm *PluginManager) GetPluginStatus(name string) (string, error) {
+	pm.mu.RLock()
+	plugin, exists := pm.plugins[name]
+	pm.mu.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("plugin %s not found", name)
+	}
+
+	stats := plugin.Stats()
+	state := "enabled"
+	if !plugin.Enabled {
+		state = "disabled"
+		if stats.DisabledReason != "" {
+			state = "disabled (" + stats.DisabledReason + ")"
+		}
+	}
+	status := fmt.Sprintf("%s v%s: %s, calls=%d, faults=%d, timeouts=%d, memory=%s, peak=%s",
+		plugin.Meta.Name, plugin.Meta.Version, state, stats.Calls, stats.Faults, stats.Timeouts,
+		formatBytes(stats.MemoryBytes), formatBytes(stats.PeakMemoryBytes))
+	if stats.LastError != "" {
+		status += ", last error: " + stats.LastError
+	}
+	return status, nil
+}
+
+func formatBytes(n uint64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
 func (pm *PluginManager) DisablePlugin(name string) error {
 	return pm.UnloadPlugin(name)
 }
@@ -171,8 +319,10 @@ func (pm *PluginManager) Tick(currentTick int64) {
 	pm.mu.RLock()
 	defer pm.mu.RUnlock()
 
-	for _, plugin := range pm.plugins {
+	for _, plugin := range pm.ordered() {
+		stop := timings.StartChild(timings.PluginTick, timings.PluginTickTimer(plugin.Meta.Name))
 		plugin.tick(currentTick)
+		stop()
 	}
 }
 
@@ -181,15 +331,21 @@ func (pm *PluginManager) CallEvent(eventName string, data map[string]interface{}
 	defer pm.mu.RUnlock()
 
 	cancelled := false
-	for _, plugin := range pm.plugins {
+	for _, plugin := range pm.ordered() {
 		if !plugin.Enabled || plugin.State == nil {
 			continue
 		}
 
+		stopAll := timings.Start(timings.PluginHandlers)
+		stop := timings.StartChild(timings.PluginHandlers, timings.PluginEventsTimer(plugin.Meta.Name))
+		stopHandler := timings.StartChild(timings.PluginEventsTimer(plugin.Meta.Name), timings.HandlerTimer(plugin.Meta.Name, eventName))
 		eventTable := mapToLuaTable(plugin.State, data)
 		if plugin.callEvent(eventName, eventTable) {
 			cancelled = true
 		}
+		stopHandler()
+		stop()
+		stopAll()
 	}
 	return cancelled
 }
@@ -220,3 +376,62 @@ func mapToLuaTable(L *lua.LState, data map[string]interface{}) *lua.LTable {
 	}
 	return tbl
 }
+
+func resolveLoadOrder(metas map[string]*PluginMeta) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+		failed
+	)
+	state := make(map[string]int, len(metas))
+	order := make([]string, 0, len(metas))
+
+	names := make([]string, 0, len(metas))
+	for name := range metas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case done:
+			return true
+		case failed:
+			return false
+		case visiting:
+			logger.Error("Circular plugin dependency", "name", name)
+			return false
+		}
+		state[name] = visiting
+		meta := metas[name]
+
+		for _, dep := range meta.Depend {
+			if _, ok := metas[dep]; !ok {
+				logger.Error("Missing plugin dependency", "name", name, "dependency", dep)
+				state[name] = failed
+				return false
+			}
+			if !visit(dep) {
+				logger.Error("Plugin dependency failed to resolve", "name", name, "dependency", dep)
+				state[name] = failed
+				return false
+			}
+		}
+		for _, dep := range meta.SoftDepend {
+			if _, ok := metas[dep]; ok && state[dep] != visiting {
+				visit(dep)
+			}
+		}
+
+		state[name] = done
+		order = append(order, name)
+		return true
+	}
+
+	for _, name := range names {
+		visit(name)
+	}
+	return order
+}
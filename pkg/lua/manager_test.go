@@ -0,0 +1,59 @@
+package lua
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writePlugin(t *testing.T, root, name string, depend ...string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	meta := "name: " + name + "\nversion: 1.0.0\n"
+	if len(depend) > 0 {
+		meta += "depend:\n"
+		for _, dep := range depend {
+			meta += "  - " + dep + "\n"
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plugin.yml"), []byte(meta), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.lua"), []byte("x = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadPluginReenablesDependents(t *testing.T) {
+	root := t.TempDir()
+	writePlugin(t, root, "economy")
+	writePlugin(t, root, "shop", "economy")
+	writePlugin(t, root, "auction", "shop")
+	writePlugin(t, root, "chat")
+
+	pm := NewPluginManager(nil, root)
+	if err := pm.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.ReloadPlugin("economy"); err != nil {
+		t.Fatalf("ReloadPlugin: %v", err)
+	}
+
+	for _, name := range []string{"economy", "shop", "auction", "chat"} {
+		if !pm.IsPluginEnabled(name) {
+			t.Errorf("%s is disabled after reload", name)
+		}
+	}
+	var order []string
+	for _, p := range pm.ordered() {
+		order = append(order, p.Meta.Name)
+	}
+	want := []string{"chat", "economy", "shop", "auction"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
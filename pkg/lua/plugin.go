@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -11,11 +12,13 @@ import (
 )
 
 type PluginMeta struct {
-	Name        string `yaml:"name"`
-	Version     string `yaml:"version"`
-	Author      string `yaml:"author"`
-	Description string `yaml:"description"`
-	Main        string `yaml:"main"`
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Author      string   `yaml:"author"`
+	Description string   `yaml:"description"`
+	Main        string   `yaml:"main"`
+	Depend      []string `yaml:"depend"`
+	SoftDepend  []string `yaml:"softdepend"`
 }
 
 type Plugin struct {
@@ -27,6 +30,13 @@ type Plugin struct {
 	eventHandlers  map[string][]*lua.LFunction
 	schedulerTasks []*schedulerTask
 	nextTaskID     int
+	config         map[string]interface{}
+
+	limits            Limits
+	stats             PluginStats
+	memoryCheckedAt   time.Time
+	consecutiveFaults int
+	callDepth         int
 }
 
 type schedulerTask struct {
@@ -61,24 +71,34 @@ func loadPluginMeta(dir string) (*PluginMeta, error) {
 	return &meta, nil
 }
 
-func newPlugin(meta *PluginMeta, dir string) *Plugin {
+func newPlugin(meta *PluginMeta, dir string, limits Limits) *Plugin {
 	return &Plugin{
 		Meta:           *meta,
 		Dir:            dir,
 		Enabled:        false,
 		eventHandlers:  make(map[string][]*lua.LFunction),
 		schedulerTasks: make([]*schedulerTask, 0),
+		limits:         limits,
 	}
 }
 
 func (p *Plugin) createState(server ServerAPI) *lua.LState {
 	L := lua.NewState(lua.Options{
-		SkipOpenLibs: false,
+		SkipOpenLibs:    true,
+		CallStackSize:   256,
+		RegistrySize:    1024 * 16,
+		RegistryMaxSize: 1024 * 256,
 	})
+	p.openSandbox(L)
 
 	registerServerAPI(L, server)
 	registerPlayerAPI(L, server)
 	registerLevelAPI(L, server)
+	registerInventoryAPI(L, server)
+	registerEntityAPI(L, server)
+	registerWorldAPI(L, server)
+	registerPermissionAPI(L, server)
+	registerConfigAPI(L, p)
 	registerEventAPI(L, p)
 	registerCommandAPI(L, p, server)
 	registerSchedulerAPI(L, p, server)
@@ -93,41 +113,46 @@ func (p *Plugin) enable() error {
 		return fmt.Errorf("lua state not initialized for plugin %s", p.Meta.Name)
 	}
 
+	if err := os.MkdirAll(p.DataDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create data folder: %w", err)
+	}
+	if err := p.loadConfig(); err != nil {
+		return err
+	}
+
 	mainFile := filepath.Join(p.Dir, p.Meta.Main)
-	if err := p.State.DoFile(mainFile); err != nil {
+	if err := p.guard(p.limits.LoadTimeout, func() error {
+		return p.State.DoFile(mainFile)
+	}); err != nil {
 		return fmt.Errorf("failed to execute %s: %w", p.Meta.Main, err)
 	}
 
+	p.Enabled = true
 	onEnable := p.State.GetGlobal("onEnable")
 	if fn, ok := onEnable.(*lua.LFunction); ok {
-		if err := p.State.CallByParam(lua.P{
-			Fn:      fn,
-			NRet:    0,
-			Protect: true,
+		if err := p.guard(p.limits.LoadTimeout, func() error {
+			return p.State.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true})
 		}); err != nil {
+			p.Enabled = false
 			return fmt.Errorf("onEnable error: %w", err)
 		}
 	}
 
-	p.Enabled = true
 	return nil
 }
 
 func (p *Plugin) disable() {
+	wasEnabled := p.Enabled
+	p.Enabled = false
 	if p.State != nil {
 		onDisable := p.State.GetGlobal("onDisable")
-		if fn, ok := onDisable.(*lua.LFunction); ok {
-			_ = p.State.CallByParam(lua.P{
-				Fn:      fn,
-				NRet:    0,
-				Protect: true,
-			})
+		if fn, ok := onDisable.(*lua.LFunction); ok && wasEnabled {
+			_ = p.call(fn)
 		}
 		p.State.Close()
 		p.State = nil
 	}
 
-	p.Enabled = false
 	p.eventHandlers = make(map[string][]*lua.LFunction)
 	p.schedulerTasks = nil
 }
@@ -142,12 +167,9 @@ func (p *Plugin) tick(currentTick int64) {
 			continue
 		}
 		if currentTick >= task.nextRun {
-			if err := p.State.CallByParam(lua.P{
-				Fn:      task.callback,
-				NRet:    0,
-				Protect: true,
-			}); err != nil {
-				fmt.Printf("[Plugin:%s] scheduler error: %v\n", p.Meta.Name, err)
+			_ = p.call(task.callback)
+			if !p.Enabled {
+				return
 			}
 			if task.repeat {
 				task.nextRun = currentTick + task.interval
@@ -170,12 +192,10 @@ func (p *Plugin) callEvent(eventName string, eventTable *lua.LTable) bool {
 
 	cancelled := false
 	for _, fn := range handlers {
-		if err := p.State.CallByParam(lua.P{
-			Fn:      fn,
-			NRet:    0,
-			Protect: true,
-		}, eventTable); err != nil {
-			fmt.Printf("[Plugin:%s] event handler error for %s: %v\n", p.Meta.Name, eventName, err)
+		if err := p.call(fn, eventTable); err != nil {
+			if !p.Enabled {
+				break
+			}
 			continue
 		}
 
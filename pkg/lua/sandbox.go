@@ -0,0 +1,394 @@
+package lua
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Limits struct {
+	CallbackTimeout time.Duration
+	LoadTimeout     time.Duration
+	MemoryLimit     uint64
+	MaxFaults       int
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		CallbackTimeout: 50 * time.Millisecond,
+		LoadTimeout:     2 * time.Second,
+		MemoryLimit:     16 << 20,
+		MaxFaults:       5,
+	}
+}
+
+type PluginStats struct {
+	Calls           int64
+	Faults          int64
+	Timeouts        int64
+	MemoryBytes     uint64
+	PeakMemoryBytes uint64
+	LastError       string
+	DisabledReason  string
+}
+
+var sandboxLibs = []struct {
+	name string
+	fn   lua.LGFunction
+}{
+	{lua.LoadLibName, lua.OpenPackage},
+	{lua.BaseLibName, lua.OpenBase},
+	{lua.TabLibName, lua.OpenTable},
+	{lua.StringLibName, lua.OpenString},
+	{lua.MathLibName, lua.OpenMath},
+	{lua.CoroutineLibName, lua.OpenCoroutine},
+	{lua.IoLibName, lua.OpenIo},
+	{lua.OsLibName, lua.OpenOs},
+}
+
+var safeOsFuncs = []string{"clock", "date", "difftime", "time"}
+
+func (p *Plugin) DataDir() string {
+	return filepath.Join(p.Dir, "data")
+}
+
+func (p *Plugin) openSandbox(L *lua.LState) {
+	for _, lib := range sandboxLibs {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+
+	L.SetGlobal("dofile", lua.LNil)
+	L.SetGlobal("loadfile", lua.LNil)
+
+	pkg := L.GetGlobal("package").(*lua.LTable)
+	pkg.RawSetString("path", lua.LString(filepath.Join(p.Dir, "?.lua")))
+	pkg.RawSetString("cpath", lua.LString(""))
+	loaded := pkg.RawGetString("loaded").(*lua.LTable)
+	// The loaders table is shared with require, so the file loader is
+	// swapped in place for one confined to the plugin folder.
+	loaders := pkg.RawGetString("loaders").(*lua.LTable)
+	loaders.RawSetInt(2, L.NewFunction(p.loadModule))
+	pkgProxy := readOnlyProxy(L, pkg, "path", "cpath", "loaders")
+	L.SetGlobal("package", pkgProxy)
+	loaded.RawSetString("package", pkgProxy)
+
+	p.wrapCoroutines(L)
+
+	fullOs := L.GetGlobal("os").(*lua.LTable)
+	osMod := L.NewTable()
+	for _, name := range safeOsFuncs {
+		osMod.RawSetString(name, fullOs.RawGetString(name))
+	}
+	L.SetGlobal("os", osMod)
+	loaded.RawSetString("os", osMod)
+
+	fullIo := L.GetGlobal("io").(*lua.LTable)
+	ioMod := L.NewTable()
+	ioMod.RawSetString("type", fullIo.RawGetString("type"))
+	ioMod.RawSetString("open", p.wrapDataPath(L, fullIo.RawGetString("open")))
+	ioMod.RawSetString("lines", p.wrapDataPath(L, fullIo.RawGetString("lines")))
+	L.SetGlobal("io", ioMod)
+	loaded.RawSetString("io", ioMod)
+}
+
+func (p *Plugin) wrapDataPath(L *lua.LState, orig lua.LValue) *lua.LFunction {
+	fn := orig.(*lua.LFunction)
+	return L.NewFunction(func(L *lua.LState) int {
+		path, err := p.resolveDataPath(L.CheckString(1))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		if mode := L.OptString(2, "r"); strings.ContainsAny(mode, "wa") {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				L.Push(lua.LNil)
+				L.Push(lua.LString(err.Error()))
+				return 2
+			}
+		}
+
+		return callThrough(L, fn, append([]lua.LValue{lua.LString(path)}, stackArgs(L, 2)...)...)
+	})
+}
+
+func (p *Plugin) resolveDataPath(name string) (string, error) {
+	full, ok := resolveWithin(p.DataDir(), name)
+	if !ok {
+		return "", fmt.Errorf("access denied: %s is outside the plugin data folder", name)
+	}
+	return full, nil
+}
+
+func resolveWithin(dir, name string) (string, bool) {
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", false
+	}
+	full := filepath.Join(dir, name)
+	rel, err := filepath.Rel(dir, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return full, true
+}
+
+// loadModule is the require loader for Lua files. It ignores package.path
+// and only finds modules inside the plugin folder.
+func (p *Plugin) loadModule(L *lua.LState) int {
+	name := L.CheckString(1)
+	path, ok := resolveWithin(p.Dir, strings.ReplaceAll(name, ".", string(filepath.Separator))+".lua")
+	if !ok {
+		L.Push(lua.LString("\n\tmodule " + name + " is outside the plugin folder"))
+		return 1
+	}
+	if _, err := os.Stat(path); err != nil {
+		L.Push(lua.LString("\n\tno file '" + path + "'"))
+		return 1
+	}
+	fn, err := L.LoadFile(path)
+	if err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+	L.Push(fn)
+	return 1
+}
+
+// readOnlyProxy stands in for t, refusing assignments to the given keys.
+func readOnlyProxy(L *lua.LState, t *lua.LTable, keys ...string) *lua.LTable {
+	locked := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		locked[key] = true
+	}
+	proxy := L.NewTable()
+	mt := L.NewTable()
+	mt.RawSetString("__index", t)
+	mt.RawSetString("__newindex", L.NewFunction(func(L *lua.LState) int {
+		key := L.Get(2)
+		if locked[key.String()] {
+			L.RaiseError("%s is read-only", key.String())
+		}
+		t.RawSet(key, L.Get(3))
+		return 0
+	}))
+	mt.RawSetString("__metatable", lua.LFalse)
+	L.SetMetatable(proxy, mt)
+	return proxy
+}
+
+// wrapCoroutines makes a coroutine run under the time limit of the call
+// that resumes it. gopher-lua gives each thread the context of the call
+// that created it, which is cancelled once that callback returns.
+func (p *Plugin) wrapCoroutines(L *lua.LState) {
+	co := L.GetGlobal("coroutine").(*lua.LTable)
+	create := co.RawGetString("create").(*lua.LFunction)
+	rawResume := co.RawGetString("resume").(*lua.LFunction)
+
+	resume := L.NewFunction(func(L *lua.LState) int {
+		th := L.CheckThread(1)
+		if ctx := L.Context(); ctx != nil {
+			th.SetContext(ctx)
+		} else {
+			th.RemoveContext()
+		}
+		return callThrough(L, rawResume, stackArgs(L, 1)...)
+	})
+	co.RawSetString("resume", resume)
+
+	co.RawSetString("wrap", L.NewFunction(func(L *lua.LState) int {
+		L.CheckFunction(1)
+		L.CallByParam(lua.P{Fn: create, NRet: 1}, L.Get(1))
+		th := L.Get(-1)
+		L.Pop(1)
+		L.Push(L.NewFunction(func(L *lua.LState) int {
+			base := L.GetTop()
+			n := callThrough(L, resume, append([]lua.LValue{th}, stackArgs(L, 1)...)...)
+			if L.Get(base+1) == lua.LFalse {
+				L.RaiseError("%s", L.Get(base+2).String())
+			}
+			L.Remove(base + 1)
+			return n - 1
+		}))
+		return 1
+	}))
+}
+
+func stackArgs(L *lua.LState, from int) []lua.LValue {
+	var args []lua.LValue
+	for i := from; i <= L.GetTop(); i++ {
+		args = append(args, L.Get(i))
+	}
+	return args
+}
+
+func callThrough(L *lua.LState, fn *lua.LFunction, args ...lua.LValue) int {
+	base := L.GetTop()
+	L.CallByParam(lua.P{Fn: fn, NRet: lua.MultRet}, args...)
+	return L.GetTop() - base
+}
+
+// memoryCheckInterval spaces out walks of a plugin's Lua heap. Between
+// checks a runaway callback is still bounded by its time limit.
+const memoryCheckInterval = time.Second
+
+// stateSize estimates the bytes held by values reachable from the plugin's
+// Lua state. The walk stops counting once it passes limit, so its cost is
+// bounded by the quota rather than by what a script managed to build.
+func (p *Plugin) stateSize(limit uint64) uint64 {
+	w := sizeWalker{seen: make(map[interface{}]struct{}), limit: limit}
+	w.value(p.State.G.Global)
+	w.value(p.State.G.Registry)
+	for _, handlers := range p.eventHandlers {
+		for _, fn := range handlers {
+			w.value(fn)
+		}
+	}
+	return w.size
+}
+
+type sizeWalker struct {
+	seen  map[interface{}]struct{}
+	size  uint64
+	limit uint64
+}
+
+func (w *sizeWalker) visit(v interface{}) bool {
+	if _, ok := w.seen[v]; ok {
+		return false
+	}
+	w.seen[v] = struct{}{}
+	return true
+}
+
+func (w *sizeWalker) value(v lua.LValue) {
+	if w.limit > 0 && w.size > w.limit {
+		return
+	}
+	switch v := v.(type) {
+	case lua.LString:
+		w.size += 16 + uint64(len(v))
+	case *lua.LTable:
+		if v == nil || !w.visit(v) {
+			return
+		}
+		w.size += 64
+		w.value(v.Metatable)
+		v.ForEach(func(key, val lua.LValue) {
+			w.size += 40
+			w.value(key)
+			w.value(val)
+		})
+	case *lua.LFunction:
+		if v == nil || !w.visit(v) {
+			return
+		}
+		w.size += 64
+		if v.Env != nil {
+			w.value(v.Env)
+		}
+		for _, uv := range v.Upvalues {
+			if uv != nil {
+				w.size += 16
+				w.value(uv.Value())
+			}
+		}
+	case *lua.LUserData:
+		if v == nil || !w.visit(v) {
+			return
+		}
+		w.size += 48
+		w.value(v.Metatable)
+		if v.Env != nil {
+			w.value(v.Env)
+		}
+	}
+}
+
+func (p *Plugin) call(fn *lua.LFunction, args ...lua.LValue) error {
+	return p.guard(p.limits.CallbackTimeout, func() error {
+		return p.State.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    0,
+			Protect: true,
+		}, args...)
+	})
+}
+
+func (p *Plugin) guard(timeout time.Duration, fn func() error) error {
+	if p.State == nil {
+		return fmt.Errorf("plugin %s is not enabled", p.Meta.Name)
+	}
+	if p.callDepth > 0 {
+		return fn()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	p.State.SetContext(ctx)
+	p.callDepth++
+
+	err := fn()
+
+	p.callDepth--
+	if p.State != nil {
+		p.State.RemoveContext()
+	}
+
+	p.stats.Calls++
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		p.stats.Timeouts++
+		err = fmt.Errorf("callback exceeded time limit of %s", timeout)
+	} else if err == nil && p.State != nil && p.limits.MemoryLimit > 0 && time.Since(p.memoryCheckedAt) >= memoryCheckInterval {
+		p.memoryCheckedAt = time.Now()
+		size := p.stateSize(p.limits.MemoryLimit)
+		p.stats.MemoryBytes = size
+		if size > p.stats.PeakMemoryBytes {
+			p.stats.PeakMemoryBytes = size
+		}
+		if size > p.limits.MemoryLimit {
+			err = fmt.Errorf("plugin state holds over %d bytes, limit is %d", size, p.limits.MemoryLimit)
+			// Memory is not given back by a later good call, so waiting
+			// for MaxFaults would only let the state keep growing.
+			p.recordFault(err)
+			if p.Enabled {
+				p.stats.DisabledReason = err.Error()
+				logger.Warn("Disabling Lua plugin over its memory limit", "plugin", p.Meta.Name, "bytes", size)
+				p.disable()
+			}
+			return err
+		}
+	}
+
+	if err != nil {
+		p.recordFault(err)
+		return err
+	}
+	p.consecutiveFaults = 0
+	return nil
+}
+
+func (p *Plugin) recordFault(err error) {
+	p.stats.Faults++
+	p.consecutiveFaults++
+	p.stats.LastError = err.Error()
+	logger.Error("Lua plugin fault", "plugin", p.Meta.Name, "faults", p.consecutiveFaults, "error", err)
+
+	if p.limits.MaxFaults > 0 && p.consecutiveFaults >= p.limits.MaxFaults && p.Enabled {
+		p.stats.DisabledReason = fmt.Sprintf("%d consecutive faults, last: %s", p.consecutiveFaults, err.Error())
+		logger.Warn("Disabling faulty Lua plugin", "plugin", p.Meta.Name, "reason", p.stats.DisabledReason)
+		p.disable()
+	}
+}
+
+func (p *Plugin) Stats() PluginStats {
+	return p.stats
+}
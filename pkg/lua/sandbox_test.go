@@ -0,0 +1,125 @@
+package lua
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func loadScript(t *testing.T, limits Limits, src string, files map[string]string) *Plugin {
+	t.Helper()
+	root := t.TempDir()
+	writePlugin(t, root, "sandboxed")
+	dir := filepath.Join(root, "sandboxed")
+	files["main.lua"] = src
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pm := NewPluginManager(nil, root)
+	pm.SetLimits(limits)
+	if err := pm.LoadPlugin("sandboxed"); err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+	return pm.GetPlugin("sandboxed")
+}
+
+func callGlobal(p *Plugin, name string) error {
+	return p.call(p.State.GetGlobal(name).(*lua.LFunction))
+}
+
+func TestSandboxDeniesEscapes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"os.execute", `assert(os.execute == nil)`},
+		{"io.popen", `assert(io.popen == nil)`},
+		{"dofile", `assert(dofile == nil and loadfile == nil)`},
+		{"io.open parent", `local f, err = io.open("../main.lua"); assert(f == nil and err:find("access denied"))`},
+		{"io.open absolute", `local f = io.open("/etc/passwd"); assert(f == nil)`},
+		{"package.path", `assert(not pcall(function() package.path = "/etc/?" end))`},
+		{"package.loaded.package", `assert(not pcall(function() package.loaded.package.path = "/etc/?" end))`},
+		{"require parent", `assert(not pcall(require, "..secret"))`},
+		{"require module", `assert(require("lib.util").answer == 42)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := loadScript(t, DefaultLimits(), "function check() "+tt.src+" end", map[string]string{
+				"lib/util.lua": "return { answer = 42 }",
+			})
+			if err := callGlobal(p, "check"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSandboxTimeout(t *testing.T) {
+	limits := DefaultLimits()
+	limits.CallbackTimeout = 20 * time.Millisecond
+	p := loadScript(t, limits, "function spin() while true do end end", map[string]string{})
+
+	start := time.Now()
+	err := callGlobal(p, "spin")
+	if err == nil || !strings.Contains(err.Error(), "time limit") {
+		t.Fatalf("err = %v, want time limit error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("busy loop ran for %s", elapsed)
+	}
+	if got := p.Stats().Timeouts; got != 1 {
+		t.Errorf("Timeouts = %d, want 1", got)
+	}
+}
+
+func TestSandboxMemoryLimit(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MemoryLimit = 64 << 10
+	p := loadScript(t, limits, `
+		hoard = {}
+		function grow() for i = 1, 20000 do hoard[i] = "entry" .. i end end
+	`, map[string]string{})
+
+	p.memoryCheckedAt = time.Time{}
+	if err := callGlobal(p, "grow"); err == nil {
+		t.Fatal("expected memory limit error")
+	}
+	if p.Enabled {
+		t.Error("plugin still enabled over its memory limit")
+	}
+	if p.Stats().DisabledReason == "" {
+		t.Error("DisabledReason not recorded")
+	}
+}
+
+func TestSandboxCoroutineOutlivesCallback(t *testing.T) {
+	p := loadScript(t, DefaultLimits(), `
+		steps = 0
+		co = coroutine.create(function() while true do steps = steps + 1; coroutine.yield() end end)
+		gen = coroutine.wrap(function() while true do steps = steps + 10; coroutine.yield() end end)
+		function step()
+			local ok, err = coroutine.resume(co)
+			assert(ok, err)
+			gen()
+		end
+	`, map[string]string{})
+
+	for i := 0; i < 3; i++ {
+		if err := callGlobal(p, "step"); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if got := lua.LVAsNumber(p.State.GetGlobal("steps")); got != 33 {
+		t.Errorf("steps = %v, want 33", got)
+	}
+}
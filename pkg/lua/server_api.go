@@ -1,6 +1,10 @@
 package lua
 
-import "github.com/scaxe/scaxe-go/pkg/command"
+import (
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+)
 
 type ServerAPI interface {
 	BroadcastMessage(message string)
@@ -12,6 +16,11 @@ type ServerAPI interface {
 	GetOnlinePlayers() []PlayerAPI
 	KickPlayer(username string, reason string)
 	GetLevel() LevelAPI
+	GetLevelByName(name string) LevelAPI
+	GetLevelNames() []string
+	LoadLevel(name string) error
+	RegisterPermission(name, description, defaultValue string)
+	GetPermissionStore() PermissionStoreAPI
 	RegisterCommand(cmd command.Command)
 	UnregisterCommand(name string)
 	Stop()
@@ -30,6 +39,30 @@ type PlayerAPI interface {
 	GetHealth() int
 	SetHealth(health int)
 	GetEntityID() int64
+	GetLevelName() string
+	SwitchLevel(name string) bool
+	GetInventory() []ItemStack
+	GetItemInHand() ItemStack
+	SetSlot(slot int, stack ItemStack) bool
+	AddItem(stack ItemStack) int
+	RemoveItem(stack ItemStack) int
+	ClearInventory()
+	HasPermission(name string) bool
+	SetPermission(name string, value bool)
+	UnsetPermission(name string)
+	AddEffect(id, duration, amplifier int, particles bool)
+	RemoveEffect(id int)
+}
+
+type PermissionStoreAPI interface {
+	GetGroups() []string
+	GetPlayerGroups(player string) []string
+	SetPlayerGroup(player, group string) error
+	AddPlayerGroup(player, group string, expires time.Time) error
+	RemovePlayerGroup(player, group string) error
+	SetPlayerPermission(player, world, node string, value bool, expires time.Time) error
+	UnsetPlayerPermission(player, world, node string) error
+	Reload() error
 }
 
 type LevelAPI interface {
@@ -39,4 +72,23 @@ type LevelAPI interface {
 	SetTime(time int64)
 	GetSeed() int64
 	GetSpawnLocation() (x, y, z float64)
+	GetName() string
+	SpawnEntity(networkID int, x, y, z float64) (int64, error)
+	RemoveEntity(id int64) bool
+	GetEntities() []EntityInfo
+	AddParticle(particle int, x, y, z float64, data int)
+	PlaySound(sound int, x, y, z float64, pitch float64)
+}
+
+type ItemStack struct {
+	Slot  int
+	ID    int
+	Meta  int
+	Count int
+}
+
+type EntityInfo struct {
+	ID        int64
+	NetworkID int
+	X, Y, Z   float64
 }
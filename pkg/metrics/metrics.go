@@ -0,0 +1,250 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+type Collector func(w *Writer)
+
+var chunkBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+var (
+	mu         sync.Mutex
+	collectors []Collector
+	packetName = func(id byte) string { return fmt.Sprintf("0x%02x", id) }
+
+	packetsIn  [256]atomic.Uint64
+	bytesIn    [256]atomic.Uint64
+	packetsOut [256]atomic.Uint64
+	bytesOut   [256]atomic.Uint64
+
+	chunkGen = newHistogram(chunkBuckets)
+)
+
+func Register(c Collector) {
+	mu.Lock()
+	collectors = append(collectors, c)
+	mu.Unlock()
+}
+
+func SetPacketNamer(fn func(id byte) string) {
+	mu.Lock()
+	packetName = fn
+	mu.Unlock()
+}
+
+func PacketIn(id byte, size int) {
+	packetsIn[id].Add(1)
+	bytesIn[id].Add(uint64(size))
+}
+
+func PacketOut(id byte, size int) {
+	packetsOut[id].Add(1)
+	bytesOut[id].Add(uint64(size))
+}
+
+func ObserveChunkGeneration(d time.Duration) {
+	chunkGen.observe(d.Seconds())
+}
+
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+	h.mu.Unlock()
+}
+
+type Writer struct {
+	w     io.Writer
+	typed map[string]bool
+}
+
+func (w *Writer) header(name, kind, help string) {
+	if w.typed[name] {
+		return
+	}
+	w.typed[name] = true
+	fmt.Fprintf(w.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (w *Writer) sample(name string, value float64, labels []string) {
+	fmt.Fprintf(w.w, "%s%s %s\n", name, formatLabels(labels), formatValue(value))
+}
+
+func (w *Writer) Gauge(name, help string, value float64, labels ...string) {
+	w.header(name, "gauge", help)
+	w.sample(name, value, labels)
+}
+
+func (w *Writer) Counter(name, help string, value float64, labels ...string) {
+	w.header(name, "counter", help)
+	w.sample(name, value, labels)
+}
+
+func (w *Writer) histogram(name, help string, h *histogram) {
+	h.mu.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	count, sum := h.count, h.sum
+	h.mu.Unlock()
+
+	w.header(name, "histogram", help)
+	for i, b := range h.buckets {
+		w.sample(name+"_bucket", float64(counts[i]), []string{"le", formatValue(b)})
+	}
+	w.sample(name+"_bucket", float64(count), []string{"le", "+Inf"})
+	w.sample(name+"_sum", sum, nil)
+	w.sample(name+"_count", float64(count), nil)
+}
+
+func formatLabels(labels []string) string {
+	if len(labels) < 2 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(labels[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func Write(out io.Writer) {
+	w := &Writer{w: out, typed: make(map[string]bool)}
+
+	mu.Lock()
+	list := append([]Collector(nil), collectors...)
+	name := packetName
+	mu.Unlock()
+
+	for _, c := range list {
+		c(w)
+	}
+
+	writePackets(w, name)
+	w.histogram("scaxe_chunk_generation_seconds", "Time spent generating and populating a chunk.", chunkGen)
+	writeRuntime(w)
+}
+
+func writePackets(w *Writer, name func(byte) string) {
+	ids := make([]int, 0)
+	for id := 0; id < 256; id++ {
+		if packetsIn[id].Load() > 0 || packetsOut[id].Load() > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if n := packetsIn[id].Load(); n > 0 {
+			w.Counter("scaxe_packets_received_total", "Game packets received, by packet type.", float64(n), "packet", name(byte(id)))
+		}
+	}
+	for _, id := range ids {
+		if n := packetsOut[id].Load(); n > 0 {
+			w.Counter("scaxe_packets_sent_total", "Game packets sent, by packet type.", float64(n), "packet", name(byte(id)))
+		}
+	}
+	for _, id := range ids {
+		if n := bytesIn[id].Load(); n > 0 {
+			w.Counter("scaxe_packet_bytes_received_total", "Game packet bytes received, by packet type.", float64(n), "packet", name(byte(id)))
+		}
+	}
+	for _, id := range ids {
+		if n := bytesOut[id].Load(); n > 0 {
+			w.Counter("scaxe_packet_bytes_sent_total", "Game packet bytes sent, by packet type.", float64(n), "packet", name(byte(id)))
+		}
+	}
+}
+
+func writeRuntime(w *Writer) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	w.Gauge("go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()))
+	w.Gauge("go_memstats_alloc_bytes", "Bytes of allocated heap objects.", float64(m.Alloc))
+	w.Counter("go_memstats_alloc_bytes_total", "Cumulative bytes allocated for heap objects.", float64(m.TotalAlloc))
+	w.Gauge("go_memstats_sys_bytes", "Bytes of memory obtained from the OS.", float64(m.Sys))
+	w.Gauge("go_memstats_heap_inuse_bytes", "Bytes in in-use heap spans.", float64(m.HeapInuse))
+	w.Gauge("go_memstats_heap_objects", "Number of allocated heap objects.", float64(m.HeapObjects))
+	w.Counter("go_memstats_mallocs_total", "Cumulative count of heap objects allocated.", float64(m.Mallocs))
+	w.Counter("go_gc_cycles_total", "Number of completed GC cycles.", float64(m.NumGC))
+	w.Counter("go_gc_pause_seconds_total", "Cumulative GC stop-the-world pause time.", float64(m.PauseTotalNs)/1e9)
+	w.Gauge("go_memstats_next_gc_bytes", "Heap size target for the next GC cycle.", float64(m.NextGC))
+}
+
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w)
+	})
+}
+
+func Serve(addr string) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logger.Error("metrics server stopped", "error", err)
+		}
+	}()
+	logger.Info("Metrics endpoint listening", "address", ln.Addr().String())
+	return srv, nil
+}
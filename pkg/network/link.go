@@ -0,0 +1,342 @@
+package network
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const (
+	linkFramePacket   byte = 0
+	linkFrameHello    byte = 1
+	linkFrameTransfer byte = 2
+
+	linkNonceSize        = 32
+	maxLinkFrame         = 16 << 20
+	linkHandshakeTimeout = 5 * time.Second
+)
+
+var (
+	ErrLinkAuth      = errors.New("network: proxy link authentication failed")
+	ErrLinkNoSecret  = errors.New("network: proxy link needs a shared secret")
+	ErrLinkUntrusted = errors.New("network: proxy link from untrusted address")
+)
+
+// LinkHello is what the proxy tells a backend about the player behind a
+// link connection.
+type LinkHello struct {
+	Address  string   `json:"address"`
+	EntityID int64    `json:"entity_id"`
+	Username string   `json:"username"`
+	UUID     string   `json:"uuid"`
+	Server   string   `json:"server"`
+	Servers  []string `json:"servers"`
+}
+
+// LinkTransport accepts player sessions relayed by a proxy over TCP, one
+// connection per player. Every connection must come from a trusted address
+// and prove it knows the shared secret by signing a fresh nonce together
+// with its hello.
+type LinkTransport struct {
+	address string
+	Secret  string
+	Trusted []string
+
+	serverID int64
+	handler  Handler
+	listener net.Listener
+
+	mu    sync.RWMutex
+	conns map[*LinkConn]struct{}
+}
+
+type LinkConn struct {
+	conn  net.Conn
+	hello LinkHello
+
+	writeMu sync.Mutex
+}
+
+func NewLinkTransport(address string) *LinkTransport {
+	var id [8]byte
+	rand.Read(id[:])
+	return &LinkTransport{
+		address:  address,
+		Trusted:  []string{"127.0.0.1"},
+		serverID: int64(binary.BigEndian.Uint64(id[:]) >> 1),
+		conns:    make(map[*LinkConn]struct{}),
+	}
+}
+
+func (t *LinkTransport) Start() error {
+	if t.Secret == "" {
+		return ErrLinkNoSecret
+	}
+	ln, err := net.Listen("tcp", t.address)
+	if err != nil {
+		return err
+	}
+	t.listener = ln
+	logger.Server("Accepting proxy links", "address", ln.Addr().String(), "trusted", t.Trusted)
+	go t.acceptLoop(ln)
+	return nil
+}
+
+func (t *LinkTransport) Stop() {
+	if t.listener != nil {
+		t.listener.Close()
+	}
+	for _, c := range t.Conns() {
+		c.Close()
+	}
+}
+
+func (t *LinkTransport) Addr() net.Addr {
+	if t.listener == nil {
+		return nil
+	}
+	return t.listener.Addr()
+}
+
+func (t *LinkTransport) ServerID() int64 { return t.serverID }
+
+// SetPongData is a no-op: clients ping the proxy, not the backend.
+func (t *LinkTransport) SetPongData(data []byte) {}
+
+func (t *LinkTransport) SetHandler(h Handler) { t.handler = h }
+
+func (t *LinkTransport) Conns() []Conn {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	conns := make([]Conn, 0, len(t.conns))
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func (t *LinkTransport) acceptLoop(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go t.serve(conn)
+	}
+}
+
+func (t *LinkTransport) trusted(addr net.Addr) bool {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	for _, trusted := range t.Trusted {
+		if allowed := net.ParseIP(trusted); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *LinkTransport) serve(conn net.Conn) {
+	if !t.trusted(conn.RemoteAddr()) {
+		logger.Warn("Refused proxy link", "address", conn.RemoteAddr().String(), "error", ErrLinkUntrusted)
+		conn.Close()
+		return
+	}
+
+	hello, err := acceptLink(conn, t.Secret)
+	if err != nil {
+		logger.Warn("Refused proxy link", "address", conn.RemoteAddr().String(), "error", err)
+		conn.Close()
+		return
+	}
+
+	c := &LinkConn{conn: conn, hello: hello}
+	t.mu.Lock()
+	t.conns[c] = struct{}{}
+	t.mu.Unlock()
+
+	if t.handler.OnConnect != nil {
+		t.handler.OnConnect(c)
+	}
+	for {
+		kind, payload, err := readLinkFrame(conn)
+		if err != nil {
+			break
+		}
+		if kind == linkFramePacket && t.handler.OnPacket != nil {
+			t.handler.OnPacket(c, payload)
+		}
+	}
+	conn.Close()
+
+	t.mu.Lock()
+	delete(t.conns, c)
+	t.mu.Unlock()
+	if t.handler.OnDisconnect != nil {
+		t.handler.OnDisconnect(c)
+	}
+}
+
+// Address is the player's own address as seen by the proxy.
+func (c *LinkConn) Address() string { return c.hello.Address }
+
+func (c *LinkConn) Hello() LinkHello { return c.hello }
+
+func (c *LinkConn) SendPacket(data []byte) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	writeLinkFrame(c.conn, linkFramePacket, data)
+}
+
+// Transfer asks the proxy to move this player to another backend.
+func (c *LinkConn) Transfer(server string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeLinkFrame(c.conn, linkFrameTransfer, []byte(server))
+}
+
+// Close only shuts the socket; OnDisconnect runs from the read loop.
+func (c *LinkConn) Close() {
+	c.conn.Close()
+}
+
+// LinkClient is the proxy's end of a link connection.
+type LinkClient struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+}
+
+func DialLink(address, secret string, hello LinkHello, timeout time.Duration) (*LinkClient, error) {
+	if secret == "" {
+		return nil, ErrLinkNoSecret
+	}
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(linkHandshakeTimeout))
+	nonce := make([]byte, linkNonceSize)
+	if _, err := io.ReadFull(conn, nonce); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("network: proxy link handshake: %w", err)
+	}
+	body, err := json.Marshal(hello)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := writeLinkFrame(conn, linkFrameHello, append(linkMAC(secret, nonce, body), body...)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return &LinkClient{conn: conn}, nil
+}
+
+func (c *LinkClient) SendPacket(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeLinkFrame(c.conn, linkFramePacket, data)
+}
+
+// Serve reads from the backend until the connection closes.
+func (c *LinkClient) Serve(onPacket func([]byte), onTransfer func(string)) error {
+	for {
+		kind, payload, err := readLinkFrame(c.conn)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		switch kind {
+		case linkFramePacket:
+			onPacket(payload)
+		case linkFrameTransfer:
+			onTransfer(string(payload))
+		}
+	}
+}
+
+func (c *LinkClient) Close() {
+	c.conn.Close()
+}
+
+func acceptLink(conn net.Conn, secret string) (LinkHello, error) {
+	var hello LinkHello
+
+	conn.SetDeadline(time.Now().Add(linkHandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	nonce := make([]byte, linkNonceSize)
+	rand.Read(nonce)
+	if _, err := conn.Write(nonce); err != nil {
+		return hello, err
+	}
+
+	kind, payload, err := readLinkFrame(conn)
+	if err != nil {
+		return hello, err
+	}
+	if kind != linkFrameHello || len(payload) < sha256.Size {
+		return hello, ErrLinkAuth
+	}
+	mac, body := payload[:sha256.Size], payload[sha256.Size:]
+	if !hmac.Equal(mac, linkMAC(secret, nonce, body)) {
+		return hello, ErrLinkAuth
+	}
+	if err := json.Unmarshal(body, &hello); err != nil {
+		return hello, err
+	}
+	if hello.Address == "" {
+		return hello, errors.New("network: proxy link hello has no address")
+	}
+	return hello, nil
+}
+
+func linkMAC(secret string, nonce, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(nonce)
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+func writeLinkFrame(w io.Writer, kind byte, payload []byte) error {
+	frame := make([]byte, 5+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(1+len(payload)))
+	frame[4] = kind
+	copy(frame[5:], payload)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readLinkFrame(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(header[:4])
+	if n == 0 || n > maxLinkFrame {
+		return 0, nil, fmt.Errorf("network: proxy link frame of %d bytes", n)
+	}
+	payload := make([]byte, n-1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[4], payload, nil
+}
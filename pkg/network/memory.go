@@ -0,0 +1,263 @@
+package network
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrTransportClosed = errors.New("network: transport is not running")
+
+const memoryFlushTimeout = time.Second
+
+// MemoryTransport connects clients to the server through in-process queues.
+// Delivery is reliable and ordered, which makes it suited to tests that
+// should not depend on UDP timing.
+type MemoryTransport struct {
+	serverID int64
+	handler  Handler
+
+	mu       sync.RWMutex
+	conns    map[string]*memoryConn
+	pongData []byte
+	nextID   int
+	running  bool
+}
+
+type memoryAddr string
+
+func (a memoryAddr) Network() string { return TransportMemory }
+func (a memoryAddr) String() string  { return string(a) }
+
+type memoryConn struct {
+	transport *MemoryTransport
+	address   memoryAddr
+
+	toServer packetQueue
+	toClient packetQueue
+	packets  chan []byte
+
+	closeMu sync.Mutex
+	closed  chan struct{}
+	// done closes once the client has been handed every packet queued
+	// before the connection closed, such as a final Disconnect.
+	done chan struct{}
+}
+
+type MemoryClient struct {
+	conn *memoryConn
+}
+
+type packetQueue struct {
+	mu     sync.Mutex
+	items  [][]byte
+	signal chan struct{}
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{
+		serverID: rand.Int63(),
+		conns:    make(map[string]*memoryConn),
+	}
+}
+
+func (t *MemoryTransport) Start() error {
+	t.mu.Lock()
+	t.running = true
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *MemoryTransport) Stop() {
+	t.mu.Lock()
+	t.running = false
+	conns := make([]*memoryConn, 0, len(t.conns))
+	for _, c := range t.conns {
+		conns = append(conns, c)
+	}
+	t.mu.Unlock()
+
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+func (t *MemoryTransport) Addr() net.Addr {
+	return memoryAddr("memory:server")
+}
+
+func (t *MemoryTransport) ServerID() int64 {
+	return t.serverID
+}
+
+func (t *MemoryTransport) SetPongData(data []byte) {
+	t.mu.Lock()
+	t.pongData = data
+	t.mu.Unlock()
+}
+
+func (t *MemoryTransport) Pong() []byte {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.pongData
+}
+
+func (t *MemoryTransport) SetHandler(h Handler) {
+	t.handler = h
+}
+
+func (t *MemoryTransport) Conns() []Conn {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	conns := make([]Conn, 0, len(t.conns))
+	for _, c := range t.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func (t *MemoryTransport) Dial() (*MemoryClient, error) {
+	t.mu.Lock()
+	if !t.running {
+		t.mu.Unlock()
+		return nil, ErrTransportClosed
+	}
+	t.nextID++
+	c := &memoryConn{
+		transport: t,
+		address:   memoryAddr("memory:" + strconv.Itoa(t.nextID)),
+		toServer:  packetQueue{signal: make(chan struct{}, 1)},
+		toClient:  packetQueue{signal: make(chan struct{}, 1)},
+		packets:   make(chan []byte),
+		closed:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	t.conns[string(c.address)] = c
+	t.mu.Unlock()
+
+	if t.handler.OnConnect != nil {
+		t.handler.OnConnect(c)
+	}
+	go c.serverLoop()
+	go c.clientLoop()
+	return &MemoryClient{conn: c}, nil
+}
+
+func (c *memoryConn) serverLoop() {
+	for c.toServer.wait(c.closed) {
+		for _, data := range c.toServer.drain() {
+			if c.transport.handler.OnPacket != nil {
+				c.transport.handler.OnPacket(c, data)
+			}
+		}
+	}
+}
+
+func (c *memoryConn) clientLoop() {
+	defer close(c.done)
+	for {
+		open := c.toClient.wait(c.closed)
+		for _, data := range c.toClient.drain() {
+			if !c.deliver(data) {
+				return
+			}
+		}
+		if !open {
+			return
+		}
+	}
+}
+
+// deliver hands data to the client. Once the connection is closed it only
+// waits briefly, in case the client has stopped reading.
+func (c *memoryConn) deliver(data []byte) bool {
+	select {
+	case c.packets <- data:
+		return true
+	case <-c.closed:
+	}
+	select {
+	case c.packets <- data:
+		return true
+	case <-time.After(memoryFlushTimeout):
+		return false
+	}
+}
+
+func (c *memoryConn) Address() string {
+	return string(c.address)
+}
+
+func (c *memoryConn) SendPacket(data []byte) {
+	c.toClient.push(append([]byte(nil), data...))
+}
+
+func (c *memoryConn) Close() {
+	c.closeMu.Lock()
+	select {
+	case <-c.closed:
+		c.closeMu.Unlock()
+		return
+	default:
+		close(c.closed)
+	}
+	c.closeMu.Unlock()
+
+	t := c.transport
+	t.mu.Lock()
+	delete(t.conns, string(c.address))
+	t.mu.Unlock()
+	if t.handler.OnDisconnect != nil {
+		t.handler.OnDisconnect(c)
+	}
+}
+
+func (c *MemoryClient) SendPacket(data []byte) {
+	c.conn.toServer.push(append([]byte(nil), data...))
+}
+
+func (c *MemoryClient) Packets() <-chan []byte {
+	return c.conn.packets
+}
+
+func (c *MemoryClient) Done() <-chan struct{} {
+	return c.conn.done
+}
+
+func (c *MemoryClient) LocalAddr() net.Addr {
+	return c.conn.address
+}
+
+func (c *MemoryClient) Close() {
+	c.conn.Close()
+}
+
+func (q *packetQueue) push(data []byte) {
+	q.mu.Lock()
+	q.items = append(q.items, data)
+	q.mu.Unlock()
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (q *packetQueue) drain() [][]byte {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items := q.items
+	q.items = nil
+	return items
+}
+
+func (q *packetQueue) wait(closed <-chan struct{}) bool {
+	select {
+	case <-q.signal:
+		return true
+	case <-closed:
+		return false
+	}
+}
@@ -0,0 +1,43 @@
+package network
+
+import (
+	"net"
+
+	"github.com/scaxe/scaxe-go/pkg/raknet"
+)
+
+type RakNetTransport struct {
+	*raknet.Server
+}
+
+func NewRakNetTransport(address string) *RakNetTransport {
+	return &RakNetTransport{Server: raknet.NewServer(address)}
+}
+
+func (t *RakNetTransport) SetHandler(h Handler) {
+	t.OnConnect = nil
+	t.OnDisconnect = nil
+	t.OnPacket = nil
+	if h.OnConnect != nil {
+		t.OnConnect = func(s *raknet.Session) { h.OnConnect(s) }
+	}
+	if h.OnDisconnect != nil {
+		t.OnDisconnect = func(s *raknet.Session) { h.OnDisconnect(s) }
+	}
+	if h.OnPacket != nil {
+		t.OnPacket = func(s *raknet.Session, data []byte) { h.OnPacket(s, data) }
+	}
+}
+
+func (t *RakNetTransport) SetRawHandler(fn func(addr *net.UDPAddr, data []byte) bool) {
+	t.OnRawPacket = fn
+}
+
+func (t *RakNetTransport) Conns() []Conn {
+	sessions := t.Sessions()
+	conns := make([]Conn, len(sessions))
+	for i, s := range sessions {
+		conns[i] = s
+	}
+	return conns
+}
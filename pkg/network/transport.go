@@ -0,0 +1,58 @@
+package network
+
+import (
+	"fmt"
+	"net"
+)
+
+// The built-in RakNet stack is the only one that ships. go-raknet was
+// dropped because it only speaks RakNet protocol 11, and MCPE 0.14 and
+// 0.15 clients use 7 and 8.
+const (
+	TransportRakNet = "raknet"
+	TransportMemory = "memory"
+	TransportProxy  = "proxy"
+)
+
+type Conn interface {
+	Address() string
+	SendPacket(data []byte)
+	Close()
+}
+
+type Handler struct {
+	OnConnect    func(Conn)
+	OnDisconnect func(Conn)
+	OnPacket     func(Conn, []byte)
+}
+
+type Transport interface {
+	Start() error
+	Stop()
+	Addr() net.Addr
+	ServerID() int64
+	SetPongData(data []byte)
+	SetHandler(h Handler)
+	Conns() []Conn
+}
+
+// RawPacketTransport is implemented by transports that share their socket
+// with other UDP protocols such as GameSpy4 query.
+type RawPacketTransport interface {
+	SetRawHandler(fn func(addr *net.UDPAddr, data []byte) bool)
+	SendTo(addr *net.UDPAddr, data []byte) error
+}
+
+func New(kind, address string) (Transport, error) {
+	switch kind {
+	case "", TransportRakNet:
+		return NewRakNetTransport(address), nil
+	case "go-raknet":
+		return nil, fmt.Errorf("the go-raknet transport has been removed as it cannot talk to MCPE 0.14/0.15 clients; use %q", TransportRakNet)
+	case TransportMemory:
+		return NewMemoryTransport(), nil
+	case TransportProxy:
+		return NewLinkTransport(address), nil
+	}
+	return nil, fmt.Errorf("unknown network transport %q", kind)
+}
@@ -0,0 +1,114 @@
+package permission
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+type BanEntry struct {
+	Name    string    `json:"name"`
+	Reason  string    `json:"reason"`
+	Source  string    `json:"source"`
+	Created time.Time `json:"created"`
+}
+
+type BanList struct {
+	mu       sync.RWMutex
+	bans     map[string]BanEntry
+	filePath string
+}
+
+func NewBanList(path string) *BanList {
+	return &BanList{
+		bans:     make(map[string]BanEntry),
+		filePath: path,
+	}
+}
+
+func (l *BanList) Load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := os.ReadFile(l.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			l.bans = make(map[string]BanEntry)
+			return l.saveInternal()
+		}
+		return err
+	}
+
+	var entries []BanEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		logger.Error("Failed to parse ban list", "path", l.filePath, "error", err)
+		return err
+	}
+
+	l.bans = make(map[string]BanEntry)
+	for _, entry := range entries {
+		l.bans[strings.ToLower(entry.Name)] = entry
+	}
+
+	logger.Info("Loaded bans", "count", len(l.bans))
+	return nil
+}
+
+func (l *BanList) saveInternal() error {
+	entries := l.entries()
+	data, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.filePath, data, 0644)
+}
+
+func (l *BanList) entries() []BanEntry {
+	entries := make([]BanEntry, 0, len(l.bans))
+	for _, entry := range l.bans {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+func (l *BanList) Add(name, reason, source string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bans[strings.ToLower(name)] = BanEntry{Name: name, Reason: reason, Source: source, Created: time.Now().UTC()}
+	l.saveInternal()
+}
+
+func (l *BanList) Remove(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.bans[strings.ToLower(name)]; !ok {
+		return false
+	}
+	delete(l.bans, strings.ToLower(name))
+	l.saveInternal()
+	return true
+}
+
+func (l *BanList) Get(name string) (BanEntry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	entry, ok := l.bans[strings.ToLower(name)]
+	return entry, ok
+}
+
+func (l *BanList) IsBanned(name string) bool {
+	_, ok := l.Get(name)
+	return ok
+}
+
+func (l *BanList) Entries() []BanEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.entries()
+}
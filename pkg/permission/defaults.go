@@ -91,4 +91,10 @@ func RegisterDefaultPermissions() {
 	m.AddPermission(NewPermission("pocketmine.command.loadplugin", "", DefaultOp))
 
 	m.AddPermission(NewPermission("scaxe.command.luaplugin", "Allows the user to manage Lua plugins", DefaultOp))
+	m.AddPermission(NewPermission("scaxe.command.restore", "Allows the user to restore worlds from backups", DefaultOp))
+	m.AddPermission(NewPermission("scaxe.command.perm", "Allows the user to manage groups and player permissions", DefaultOp))
+	m.AddPermission(NewPermission("scaxe.command.capture", "Allows the user to record player packet captures", DefaultOp))
+	m.AddPermission(NewPermission("scaxe.command.netstats", "Allows the user to view RakNet statistics and block addresses", DefaultOp))
+	m.AddPermission(NewPermission("scaxe.command.server", "Allows the user to switch to another server behind the proxy", DefaultTrue))
+	m.AddPermission(NewPermission("scaxe.command.server.other", "Allows the user to move other players to another server", DefaultOp))
 }
@@ -27,6 +27,7 @@ func NewPermission(name, description, defaultValue string) *Permission {
 
 type PermissionManager struct {
 	permissions map[string]*Permission
+	store       *Store
 }
 
 var GlobalManager = NewPermissionManager()
@@ -45,6 +46,23 @@ func (m *PermissionManager) GetPermission(name string) *Permission {
 	return m.permissions[strings.ToLower(name)]
 }
 
+func (m *PermissionManager) SetStore(store *Store) {
+	m.store = store
+}
+
+func (m *PermissionManager) GetStore() *Store {
+	return m.store
+}
+
+func (m *PermissionManager) HasPermissionFor(player, world, name string, isOp bool) bool {
+	if m.store != nil {
+		if value, set := m.store.Resolve(player, world, name); set {
+			return value
+		}
+	}
+	return m.HasPermission(name, isOp)
+}
+
 func (m *PermissionManager) HasPermission(name string, isOp bool) bool {
 	perm := m.GetPermission(name)
 
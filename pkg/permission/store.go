@@ -0,0 +1,600 @@
+package permission
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const DefaultGroupName = "default"
+
+var (
+	ErrUnknownGroup = errors.New("unknown group")
+	ErrGroupExists  = errors.New("group already exists")
+	ErrGroupCycle   = errors.New("group inheritance cycle")
+)
+
+type Group struct {
+	Inherits    []string            `yaml:"inherits,omitempty"`
+	Permissions []string            `yaml:"permissions,omitempty"`
+	Worlds      map[string][]string `yaml:"worlds,omitempty"`
+}
+
+type TimedGrant struct {
+	Permission string    `yaml:"permission,omitempty"`
+	Group      string    `yaml:"group,omitempty"`
+	World      string    `yaml:"world,omitempty"`
+	Expires    time.Time `yaml:"expires"`
+}
+
+type PlayerEntry struct {
+	Groups      []string            `yaml:"groups,omitempty"`
+	Permissions []string            `yaml:"permissions,omitempty"`
+	Worlds      map[string][]string `yaml:"worlds,omitempty"`
+	Timed       []TimedGrant        `yaml:"timed,omitempty"`
+}
+
+type storeData struct {
+	DefaultGroup string                  `yaml:"default-group"`
+	Groups       map[string]*Group       `yaml:"groups"`
+	Players      map[string]*PlayerEntry `yaml:"players"`
+}
+
+type Store struct {
+	mu       sync.RWMutex
+	data     storeData
+	filePath string
+
+	// cache holds resolved permissions per player and world. It is only
+	// written under cacheMu while mu is read-locked, and dropped whenever
+	// the data changes.
+	cacheMu sync.Mutex
+	cache   map[cacheKey]cachedPerms
+}
+
+type cacheKey struct {
+	player, world string
+}
+
+type cachedPerms struct {
+	perms   map[string]bool
+	expires time.Time
+}
+
+func NewStore(path string) *Store {
+	return &Store{
+		data:     newStoreData(),
+		filePath: path,
+	}
+}
+
+func newStoreData() storeData {
+	return storeData{
+		DefaultGroup: DefaultGroupName,
+		Groups:       map[string]*Group{DefaultGroupName: {}},
+		Players:      make(map[string]*PlayerEntry),
+	}
+}
+
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.data = newStoreData()
+			return s.changed()
+		}
+		return err
+	}
+
+	data := newStoreData()
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		logger.Error("Failed to parse permissions file", "file", s.filePath, "error", err)
+		return err
+	}
+	data.normalize()
+	s.data = data
+	s.cache = nil
+	if pruned := s.pruneInternal(time.Now()); pruned > 0 {
+		s.saveInternal()
+	}
+
+	logger.Info("Loaded permissions", "groups", len(s.data.Groups), "players", len(s.data.Players))
+	return nil
+}
+
+func (s *Store) Reload() error {
+	return s.Load()
+}
+
+func (s *Store) Save() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.saveInternal()
+}
+
+// changed drops cached results after the data was modified and saves it.
+func (s *Store) changed() error {
+	s.cache = nil
+	return s.saveInternal()
+}
+
+func (s *Store) saveInternal() error {
+	raw, err := yaml.Marshal(&s.data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filePath, raw, 0644)
+}
+
+func (d *storeData) normalize() {
+	if d.DefaultGroup == "" {
+		d.DefaultGroup = DefaultGroupName
+	}
+	d.DefaultGroup = strings.ToLower(d.DefaultGroup)
+	groups := make(map[string]*Group, len(d.Groups))
+	for name, g := range d.Groups {
+		if g == nil {
+			g = &Group{}
+		}
+		groups[strings.ToLower(name)] = g
+	}
+	if groups[d.DefaultGroup] == nil {
+		groups[d.DefaultGroup] = &Group{}
+	}
+	d.Groups = groups
+	players := make(map[string]*PlayerEntry, len(d.Players))
+	for name, e := range d.Players {
+		if e != nil {
+			players[strings.ToLower(name)] = e
+		}
+	}
+	d.Players = players
+}
+
+func (s *Store) Resolve(player, world, node string) (bool, bool) {
+	return s.resolve(player, world, node, time.Now())
+}
+
+func (s *Store) resolve(player, world, node string, now time.Time) (bool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return matchNode(s.cached(strings.ToLower(player), world, now), strings.ToLower(node))
+}
+
+// cached returns the effective permissions of player in world, computing
+// them at most once until the data changes or a timed grant expires.
+func (s *Store) cached(player, world string, now time.Time) map[string]bool {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	key := cacheKey{player, world}
+	if c, ok := s.cache[key]; ok && (c.expires.IsZero() || now.Before(c.expires)) {
+		return c.perms
+	}
+	c := cachedPerms{perms: s.effective(player, world, now)}
+	if entry := s.data.Players[player]; entry != nil {
+		for _, t := range entry.Timed {
+			if now.Before(t.Expires) && (c.expires.IsZero() || t.Expires.Before(c.expires)) {
+				c.expires = t.Expires
+			}
+		}
+	}
+	if s.cache == nil {
+		s.cache = make(map[cacheKey]cachedPerms)
+	}
+	s.cache[key] = c
+	return c.perms
+}
+
+func (s *Store) EffectivePermissions(player, world string) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.effective(strings.ToLower(player), world, time.Now())
+}
+
+func (s *Store) effective(player, world string, now time.Time) map[string]bool {
+	perms := make(map[string]bool)
+	entry := s.data.Players[player]
+	visited := make(map[string]bool)
+	for _, group := range s.playerGroups(entry, now) {
+		s.applyGroup(perms, group, world, visited)
+	}
+	if entry == nil {
+		return perms
+	}
+	applyNodes(perms, entry.Permissions)
+	applyNodes(perms, entry.Worlds[world])
+	for _, t := range entry.Timed {
+		if t.Permission != "" && (t.World == "" || t.World == world) && now.Before(t.Expires) {
+			applyNode(perms, t.Permission)
+		}
+	}
+	return perms
+}
+
+func (s *Store) playerGroups(entry *PlayerEntry, now time.Time) []string {
+	var groups []string
+	if entry != nil {
+		groups = append(groups, entry.Groups...)
+	}
+	if len(groups) == 0 {
+		groups = append(groups, s.data.DefaultGroup)
+	}
+	if entry != nil {
+		for _, t := range entry.Timed {
+			if t.Group != "" && now.Before(t.Expires) {
+				groups = append(groups, t.Group)
+			}
+		}
+	}
+	return groups
+}
+
+func (s *Store) applyGroup(perms map[string]bool, name, world string, visited map[string]bool) {
+	name = strings.ToLower(name)
+	if visited[name] {
+		return
+	}
+	visited[name] = true
+	g := s.data.Groups[name]
+	if g == nil {
+		return
+	}
+	for _, parent := range g.Inherits {
+		s.applyGroup(perms, parent, world, visited)
+	}
+	applyNodes(perms, g.Permissions)
+	applyNodes(perms, g.Worlds[world])
+}
+
+func applyNodes(perms map[string]bool, nodes []string) {
+	for _, node := range nodes {
+		applyNode(perms, node)
+	}
+}
+
+func applyNode(perms map[string]bool, node string) {
+	node = strings.ToLower(strings.TrimSpace(node))
+	if strings.HasPrefix(node, "-") {
+		perms[node[1:]] = false
+	} else if node != "" {
+		perms[node] = true
+	}
+}
+
+func matchNode(perms map[string]bool, node string) (bool, bool) {
+	if v, ok := perms[node]; ok {
+		return v, true
+	}
+	for i := strings.LastIndex(node, "."); i >= 0; i = strings.LastIndex(node[:i], ".") {
+		if v, ok := perms[node[:i]+".*"]; ok {
+			return v, true
+		}
+	}
+	v, ok := perms["*"]
+	return v, ok
+}
+
+func (s *Store) GetGroups() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.data.Groups))
+	for name := range s.data.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Store) GetGroup(name string) *Group {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.data.Groups[strings.ToLower(name)]
+	if g == nil {
+		return nil
+	}
+	cp := *g
+	return &cp
+}
+
+func (s *Store) GetDefaultGroup() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data.DefaultGroup
+}
+
+func (s *Store) CreateGroup(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name = strings.ToLower(name)
+	if s.data.Groups[name] != nil {
+		return ErrGroupExists
+	}
+	s.data.Groups[name] = &Group{}
+	return s.changed()
+}
+
+func (s *Store) DeleteGroup(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name = strings.ToLower(name)
+	if s.data.Groups[name] == nil {
+		return ErrUnknownGroup
+	}
+	delete(s.data.Groups, name)
+	for _, g := range s.data.Groups {
+		g.Inherits = removeString(g.Inherits, name)
+	}
+	for _, e := range s.data.Players {
+		e.Groups = removeString(e.Groups, name)
+	}
+	return s.changed()
+}
+
+func (s *Store) AddGroupParent(group, parent string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, parent = strings.ToLower(group), strings.ToLower(parent)
+	g := s.data.Groups[group]
+	if g == nil || s.data.Groups[parent] == nil {
+		return ErrUnknownGroup
+	}
+	if group == parent || s.inheritsFrom(parent, group, make(map[string]bool)) {
+		return ErrGroupCycle
+	}
+	g.Inherits = addString(g.Inherits, parent)
+	return s.changed()
+}
+
+func (s *Store) RemoveGroupParent(group, parent string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.data.Groups[strings.ToLower(group)]
+	if g == nil {
+		return ErrUnknownGroup
+	}
+	g.Inherits = removeString(g.Inherits, strings.ToLower(parent))
+	return s.changed()
+}
+
+func (s *Store) inheritsFrom(group, target string, visited map[string]bool) bool {
+	if visited[group] {
+		return false
+	}
+	visited[group] = true
+	g := s.data.Groups[group]
+	if g == nil {
+		return false
+	}
+	for _, parent := range g.Inherits {
+		if parent == target || s.inheritsFrom(parent, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) SetGroupPermission(group, world, node string, value bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.data.Groups[strings.ToLower(group)]
+	if g == nil {
+		return ErrUnknownGroup
+	}
+	if world == "" {
+		g.Permissions = setNode(g.Permissions, node, value)
+	} else {
+		if g.Worlds == nil {
+			g.Worlds = make(map[string][]string)
+		}
+		g.Worlds[world] = setNode(g.Worlds[world], node, value)
+	}
+	return s.changed()
+}
+
+func (s *Store) UnsetGroupPermission(group, world, node string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.data.Groups[strings.ToLower(group)]
+	if g == nil {
+		return ErrUnknownGroup
+	}
+	if world == "" {
+		g.Permissions = unsetNode(g.Permissions, node)
+	} else if g.Worlds != nil {
+		g.Worlds[world] = unsetNode(g.Worlds[world], node)
+	}
+	return s.changed()
+}
+
+func (s *Store) GetPlayerGroups(player string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.playerGroups(s.data.Players[strings.ToLower(player)], time.Now())
+}
+
+func (s *Store) GetPlayerEntry(player string) *PlayerEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e := s.data.Players[strings.ToLower(player)]
+	if e == nil {
+		return nil
+	}
+	cp := *e
+	return &cp
+}
+
+func (s *Store) playerEntry(player string) *PlayerEntry {
+	player = strings.ToLower(player)
+	e := s.data.Players[player]
+	if e == nil {
+		e = &PlayerEntry{}
+		s.data.Players[player] = e
+	}
+	return e
+}
+
+func (s *Store) AddPlayerGroup(player, group string, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group = strings.ToLower(group)
+	if s.data.Groups[group] == nil {
+		return ErrUnknownGroup
+	}
+	e := s.playerEntry(player)
+	if expires.IsZero() {
+		e.Groups = addString(e.Groups, group)
+	} else {
+		e.Timed = append(e.Timed, TimedGrant{Group: group, Expires: expires})
+	}
+	return s.changed()
+}
+
+func (s *Store) SetPlayerGroup(player, group string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group = strings.ToLower(group)
+	if s.data.Groups[group] == nil {
+		return ErrUnknownGroup
+	}
+	s.playerEntry(player).Groups = []string{group}
+	return s.changed()
+}
+
+func (s *Store) RemovePlayerGroup(player, group string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.data.Players[strings.ToLower(player)]
+	if e == nil {
+		return nil
+	}
+	group = strings.ToLower(group)
+	e.Groups = removeString(e.Groups, group)
+	kept := e.Timed[:0]
+	for _, t := range e.Timed {
+		if t.Group != group {
+			kept = append(kept, t)
+		}
+	}
+	e.Timed = kept
+	return s.changed()
+}
+
+func (s *Store) SetPlayerPermission(player, world, node string, value bool, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.playerEntry(player)
+	switch {
+	case !expires.IsZero():
+		if !value {
+			node = "-" + node
+		}
+		e.Timed = append(e.Timed, TimedGrant{Permission: node, World: world, Expires: expires})
+	case world == "":
+		e.Permissions = setNode(e.Permissions, node, value)
+	default:
+		if e.Worlds == nil {
+			e.Worlds = make(map[string][]string)
+		}
+		e.Worlds[world] = setNode(e.Worlds[world], node, value)
+	}
+	return s.changed()
+}
+
+func (s *Store) UnsetPlayerPermission(player, world, node string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.data.Players[strings.ToLower(player)]
+	if e == nil {
+		return nil
+	}
+	if world == "" {
+		e.Permissions = unsetNode(e.Permissions, node)
+	} else if e.Worlds != nil {
+		e.Worlds[world] = unsetNode(e.Worlds[world], node)
+	}
+	node = strings.ToLower(node)
+	kept := e.Timed[:0]
+	for _, t := range e.Timed {
+		if t.World != world || strings.TrimPrefix(strings.ToLower(t.Permission), "-") != node {
+			kept = append(kept, t)
+		}
+	}
+	e.Timed = kept
+	return s.changed()
+}
+
+func (s *Store) PruneExpired() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pruned := s.pruneInternal(time.Now())
+	if pruned > 0 {
+		s.changed()
+	}
+	return pruned
+}
+
+func (s *Store) pruneInternal(now time.Time) int {
+	pruned := 0
+	for _, e := range s.data.Players {
+		kept := e.Timed[:0]
+		for _, t := range e.Timed {
+			if now.Before(t.Expires) {
+				kept = append(kept, t)
+			} else {
+				pruned++
+			}
+		}
+		e.Timed = kept
+	}
+	return pruned
+}
+
+func setNode(nodes []string, node string, value bool) []string {
+	nodes = unsetNode(nodes, node)
+	node = strings.ToLower(node)
+	if !value {
+		node = "-" + node
+	}
+	return append(nodes, node)
+}
+
+func unsetNode(nodes []string, node string) []string {
+	node = strings.ToLower(strings.TrimPrefix(node, "-"))
+	kept := nodes[:0]
+	for _, n := range nodes {
+		if strings.TrimPrefix(strings.ToLower(n), "-") != node {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+func addString(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+func removeString(list []string, s string) []string {
+	kept := list[:0]
+	for _, v := range list {
+		if v != s {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
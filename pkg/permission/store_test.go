@@ -0,0 +1,198 @@
+package permission
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, yml string) *Store {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "permissions.yml")
+	if yml != "" {
+		if err := os.WriteFile(path, []byte(yml), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewStore(path)
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestMatchNodePrecedence(t *testing.T) {
+	perms := map[string]bool{
+		"a.b.c": false,
+		"a.b.*": true,
+		"a.*":   false,
+		"*":     true,
+	}
+	tests := []struct {
+		node      string
+		value, ok bool
+	}{
+		{"a.b.c", false, true},
+		{"a.b.d", true, true},
+		{"a.b.c.d", true, true},
+		{"a.x", false, true},
+		{"a", true, true},
+		{"b.c", true, true},
+	}
+	for _, tt := range tests {
+		value, ok := matchNode(perms, tt.node)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("matchNode(%q) = %v, %v, want %v, %v", tt.node, value, ok, tt.value, tt.ok)
+		}
+	}
+	if _, ok := matchNode(map[string]bool{"a.*": true}, "b"); ok {
+		t.Error("unrelated node matched")
+	}
+}
+
+func TestResolveOverrides(t *testing.T) {
+	s := newTestStore(t, `
+default-group: default
+groups:
+  default:
+    permissions: [chat.use, home.set, warp.*]
+  vip:
+    inherits: [default]
+    permissions: [-chat.use, fly.use]
+    worlds:
+      nether: [-fly.use]
+  admin:
+    inherits: [vip]
+    permissions: [chat.use]
+players:
+  steve:
+    groups: [vip]
+    permissions: [-home.set, chat.use]
+    worlds:
+      nether: [fly.use]
+  alex:
+    groups: [admin]
+    permissions: [-warp.*]
+`)
+	tests := []struct {
+		player, world, node string
+		value, ok           bool
+	}{
+		{"nobody", "world", "home.set", true, true},
+		{"nobody", "world", "fly.use", false, false},
+		{"steve", "world", "home.set", false, true},
+		{"steve", "world", "chat.use", true, true},
+		{"steve", "world", "fly.use", true, true},
+		{"steve", "nether", "fly.use", true, true},
+		{"alex", "world", "chat.use", true, true},
+		{"alex", "nether", "fly.use", false, true},
+		{"alex", "world", "warp.spawn", false, true},
+		{"Alex", "world", "WARP.SPAWN", false, true},
+	}
+	for _, tt := range tests {
+		value, ok := s.Resolve(tt.player, tt.world, tt.node)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("Resolve(%s, %s, %s) = %v, %v, want %v, %v", tt.player, tt.world, tt.node, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestInheritanceCycle(t *testing.T) {
+	s := newTestStore(t, `
+groups:
+  a:
+    inherits: [b]
+    permissions: [from.a]
+  b:
+    inherits: [a]
+    permissions: [from.b]
+players:
+  steve:
+    groups: [a]
+`)
+	for _, node := range []string{"from.a", "from.b"} {
+		if value, _ := s.Resolve("steve", "world", node); !value {
+			t.Errorf("%s not granted through the cycle", node)
+		}
+	}
+
+	if err := s.CreateGroup("c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddGroupParent("c", "a"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct{ group, parent string }{{"a", "c"}, {"c", "c"}} {
+		if err := s.AddGroupParent(tt.group, tt.parent); !errors.Is(err, ErrGroupCycle) {
+			t.Errorf("AddGroupParent(%s, %s) = %v, want ErrGroupCycle", tt.group, tt.parent, err)
+		}
+	}
+}
+
+func TestTimedGrantsExpire(t *testing.T) {
+	s := newTestStore(t, `
+groups:
+  default: {}
+  vip:
+    permissions: [fly.use]
+`)
+	now := time.Now()
+	if err := s.SetPlayerPermission("steve", "", "kit.daily", true, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddPlayerGroup("steve", "vip", now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		at        time.Duration
+		node      string
+		value, ok bool
+	}{
+		{0, "kit.daily", true, true},
+		{0, "fly.use", true, true},
+		{90 * time.Minute, "kit.daily", false, false},
+		{90 * time.Minute, "fly.use", true, true},
+		{3 * time.Hour, "fly.use", false, false},
+	}
+	for _, tt := range tests {
+		value, ok := s.resolve("steve", "world", tt.node, now.Add(tt.at))
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("%s after %s = %v, %v, want %v, %v", tt.node, tt.at, value, ok, tt.value, tt.ok)
+		}
+	}
+
+	if err := s.SetPlayerPermission("alex", "", "kit.daily", true, now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.PruneExpired(); n != 1 {
+		t.Errorf("PruneExpired = %d, want 1", n)
+	}
+	if e := s.GetPlayerEntry("steve"); len(e.Timed) != 2 {
+		t.Errorf("steve has %d timed grants after pruning, want 2", len(e.Timed))
+	}
+}
+
+func TestResolveCacheInvalidation(t *testing.T) {
+	s := newTestStore(t, "")
+	if _, ok := s.Resolve("steve", "world", "fly.use"); ok {
+		t.Fatal("fly.use set on an empty store")
+	}
+	if err := s.SetPlayerPermission("steve", "", "fly.use", true, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := s.Resolve("steve", "world", "fly.use"); !value {
+		t.Error("change not seen after SetPlayerPermission")
+	}
+	if err := os.WriteFile(s.filePath, []byte("players:\n  steve:\n    permissions: [-fly.use]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := s.Resolve("steve", "world", "fly.use"); value || !ok {
+		t.Errorf("after reload = %v, %v, want false, true", value, ok)
+	}
+}
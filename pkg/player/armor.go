@@ -0,0 +1,65 @@
+package player
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+func (p *Player) GetArmorContents() []item.Item {
+	return p.Inventory.GetArmorContents()
+}
+
+func (p *Player) SetArmorItem(slot int, it item.Item) error {
+	return p.Inventory.SetArmorItem(slot, it)
+}
+
+func (p *Player) HandleArmorSlot(slot int, it item.Item) {
+	if slot < 0 || slot > 3 || p.survival.dead {
+		return
+	}
+	if !it.IsAir() {
+		info := item.GetArmorPieceInfo(it.ID)
+		source, ok := p.armorSource(slot, it)
+		if info == nil || info.Slot != slot || (!ok && !p.IsCreative()) {
+			p.Inventory.SendArmorContents(p)
+			return
+		}
+		if ok {
+			it = source
+		}
+	}
+	p.SetArmorItem(slot, it)
+}
+
+// armorSource finds the piece the client claims to put on: the one already
+// worn in slot or one carried in the inventory. The server's copy is
+// returned so enchantments never come from the client.
+func (p *Player) armorSource(slot int, it item.Item) (item.Item, bool) {
+	if current := p.Inventory.GetArmorItem(slot); current.ID == it.ID && current.Meta == it.Meta {
+		return current, true
+	}
+	for i := 0; i < p.Inventory.GetSize(); i++ {
+		carried := p.Inventory.GetItem(i)
+		if carried.ID == it.ID && carried.Meta == it.Meta {
+			carried = carried.Clone()
+			carried.Count = 1
+			return carried, true
+		}
+	}
+	return item.Item{}, false
+}
+
+func (p *Player) HandleArmorEquipment(slots [4]protocol.ArmorItem) {
+	current := p.GetArmorContents()
+	for slot, sent := range slots {
+		old := current[slot]
+		if int(sent.ID) == old.ID && int(sent.Meta) == old.Meta {
+			continue
+		}
+		it := item.Air()
+		if sent.ID > 0 {
+			it = item.NewItem(int(sent.ID), int(sent.Meta), 1)
+		}
+		p.HandleArmorSlot(slot, it)
+	}
+}
@@ -0,0 +1,44 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+func TestHandleArmorSlot(t *testing.T) {
+	p := NewPlayer(nil, "", 0)
+	chest := item.NewItem(item.DIAMOND_CHESTPLATE, 0, 1)
+
+	p.HandleArmorSlot(1, chest)
+	if got := p.Inventory.GetArmorItem(1); !got.IsAir() {
+		t.Fatalf("wore a chestplate the player does not have: %+v", got)
+	}
+
+	carried := chest.Clone()
+	enchantment.Set(&carried, enchantment.Protection, 4)
+	p.Inventory.SetItem(5, carried)
+
+	p.HandleArmorSlot(0, chest)
+	if got := p.Inventory.GetArmorItem(0); !got.IsAir() {
+		t.Fatalf("wore a chestplate as a helmet: %+v", got)
+	}
+
+	p.HandleArmorEquipment([4]protocol.ArmorItem{{}, {ID: int16(item.DIAMOND_CHESTPLATE)}, {}, {}})
+	if got := p.Inventory.GetArmorItem(1); got.ID != item.DIAMOND_CHESTPLATE || enchantment.Level(got, enchantment.Protection) != 4 {
+		t.Fatalf("armour slot = %+v, want the carried enchanted chestplate", got)
+	}
+
+	p.HandleArmorSlot(1, item.Air())
+	if got := p.Inventory.GetArmorItem(1); !got.IsAir() {
+		t.Fatalf("could not take off the chestplate: %+v", got)
+	}
+
+	p.Gamemode = 1
+	p.HandleArmorSlot(0, item.NewItem(item.IRON_HELMET, 0, 1))
+	if got := p.Inventory.GetArmorItem(0); got.ID != item.IRON_HELMET {
+		t.Fatalf("creative player could not wear a helmet: %+v", got)
+	}
+}
@@ -2,10 +2,14 @@ package player
 
 import (
 	"math"
+	"time"
 
 	"github.com/scaxe/scaxe-go/pkg/block"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
 	"github.com/scaxe/scaxe-go/pkg/level"
 	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
 )
 func (p *Player) HandleUseItem(x, y, z int32, face int, fx, fy, fz float32) {
 	if !p.Spawned || !p.Connected {
@@ -186,6 +190,89 @@ func (p *Player) HandlePlayerAction(action int32, x, y, z int32, face int) {
 		logger.DebugPlayer("Respawn", "player", p.Username)
 	}
 }
+func (p *Player) GetBreakTime(blockID uint8) float64 {
+	held := p.Inventory.GetItemInHand()
+	toolType := item.GetBlockToolType(held.ID)
+	toolTier := item.GetToolTier(held.ID)
+
+	behavior := block.Registry.GetBehavior(blockID)
+	breakTime := -1.0
+	if behavior != nil {
+		breakTime = behavior.GetBreakTime(toolType, toolTier)
+	}
+	if breakTime < 0 {
+		hardness := block.Registry.GetHardness(blockID)
+		if behavior != nil {
+			hardness = behavior.GetHardness()
+		}
+		if hardness < 0 {
+			return -1
+		}
+		info := block.BlockBreakInfo{Hardness: hardness}
+		efficiency := 1.0
+		if behavior != nil {
+			if behavior.GetToolTier() > 0 {
+				info.RequiredToolType = behavior.GetToolType()
+				info.RequiredToolTier = behavior.GetToolTier()
+			}
+			efficiency = item.GetMiningEfficiencyFor(held.ID, behavior.GetToolType(), int(blockID), enchantment.EfficiencyLevel(held))
+		}
+		breakTime = info.GetBreakTime(toolType, toolTier, efficiency)
+	}
+	return breakTime * p.GetBreakTimeMultiplier()
+}
+// Breaks may finish this early, as a fraction of the break time less a few
+// ticks, since START_BREAK and the break itself reach the server with
+// different network delays.
+const (
+	breakTimeTolerance = 0.8
+	breakTickMargin    = 3
+)
+
+// FinishBreak ends the break begun by the last start-break action and
+// reports whether it took as long as blockID needs with the held tool and
+// the player's Haste and Mining Fatigue, within the jitter allowance.
+func (p *Player) FinishBreak(blockID uint8) bool {
+	started := p.breakStarted
+	p.breakStarted = time.Time{}
+	breakTime := p.GetBreakTime(blockID)
+	if breakTime < 0 {
+		return false
+	}
+	ticks := math.Ceil(breakTime*20)*breakTimeTolerance - breakTickMargin
+	if ticks <= 0 {
+		return true
+	}
+	if started.IsZero() {
+		return false
+	}
+	return time.Since(started) >= time.Duration(ticks*float64(time.Second/20))
+}
+func (p *Player) BroadcastBreakProgress(x, y, z int32, start bool) {
+	pk := protocol.NewLevelEventPacket()
+	pk.X = float32(x)
+	pk.Y = float32(y)
+	pk.Z = float32(z)
+	if start {
+		lvl, ok := p.Human.Level.(*level.Level)
+		if !ok || lvl == nil {
+			return
+		}
+		breakTime := p.GetBreakTime(lvl.GetBlock(x, y, z).ID)
+		if breakTime <= 0 {
+			return
+		}
+		pk.EventID = uint16(protocol.EventBlockStartBreak)
+		pk.Data = int32(65535 / math.Ceil(breakTime*20))
+	} else {
+		pk.EventID = uint16(protocol.EventBlockStopBreak)
+	}
+	for _, viewer := range p.getViewers() {
+		if viewer != p {
+			viewer.SendPacket(pk)
+		}
+	}
+}
 func (p *Player) canInteract(x, y, z float64, maxDistance float64) bool {
 	eyeX := p.Position.X
 	eyeY := p.Position.Y + EyeHeight
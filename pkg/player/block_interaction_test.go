@@ -0,0 +1,60 @@
+package player
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/block"
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+)
+
+func TestBreakTimeWithEffects(t *testing.T) {
+	block.Registry.Init()
+	tests := []struct {
+		name    string
+		effects []*effect.Effect
+		mult    float64
+	}{
+		{"none", nil, 1},
+		{"haste II", []*effect.Effect{effect.NewEffect(effect.Haste, 600, 1)}, 0.6},
+		{"mining fatigue I", []*effect.Effect{effect.NewEffect(effect.MiningFatigue, 600, 0)}, 1.3},
+		{"both", []*effect.Effect{effect.NewEffect(effect.Haste, 600, 0), effect.NewEffect(effect.MiningFatigue, 600, 0)}, 0.8 * 1.3},
+	}
+	base := NewPlayer(nil, "", 0).GetBreakTime(block.STONE)
+	if base <= 0 {
+		t.Fatalf("stone break time = %v", base)
+	}
+	for _, tc := range tests {
+		p := NewPlayer(nil, "", 0)
+		for _, e := range tc.effects {
+			p.AddEffect(e)
+		}
+		want := base * tc.mult
+		if got := p.GetBreakTime(block.STONE); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: break time = %v, want %v", tc.name, got, want)
+		}
+
+		// Obsidian takes long enough that the jitter allowance stays well
+		// under half of it.
+		slow := p.GetBreakTime(block.OBSIDIAN)
+		p.HandleAction(ActionStartBreak)
+		p.breakStarted = time.Now().Add(-time.Duration(slow * 0.5 * float64(time.Second)))
+		if p.FinishBreak(block.OBSIDIAN) {
+			t.Errorf("%s: break accepted after half its time", tc.name)
+		}
+		p.HandleAction(ActionStartBreak)
+		p.breakStarted = time.Now().Add(-time.Duration(slow*float64(time.Second)) + 250*time.Millisecond)
+		if !p.FinishBreak(block.OBSIDIAN) {
+			t.Errorf("%s: break rejected after 5 ticks of jitter", tc.name)
+		}
+		p.HandleAction(ActionStartBreak)
+		p.breakStarted = time.Now().Add(-time.Duration(want * float64(time.Second)))
+		if !p.FinishBreak(block.STONE) {
+			t.Errorf("%s: break rejected after its full time", tc.name)
+		}
+		if p.FinishBreak(block.STONE) {
+			t.Errorf("%s: second break accepted without a start", tc.name)
+		}
+	}
+}
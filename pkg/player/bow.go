@@ -0,0 +1,95 @@
+package player
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+func (p *Player) StartUsingItem() {
+	held := p.Inventory.GetItemInHand()
+	if !item.IsBow(held.ID) {
+		p.combat.usingBow = false
+		return
+	}
+	if !p.IsCreative() && !p.Inventory.Contains(item.NewItem(item.ARROW, 0, 1)) {
+		return
+	}
+	p.combat.usingBow = true
+	p.combat.bowTicks = 0
+}
+
+func (p *Player) ReleaseItem() {
+	if !p.combat.usingBow {
+		return
+	}
+	p.combat.usingBow = false
+
+	bow := p.Inventory.GetItemInHand()
+	if !item.IsBow(bow.ID) {
+		return
+	}
+	force, state := item.CalcBowForce(p.combat.bowTicks)
+	if state == item.BowChargeNone {
+		return
+	}
+	lvl, ok := p.Human.Level.(*level.Level)
+	if !ok || lvl == nil {
+		return
+	}
+	infinite := p.IsCreative() || enchantment.HasInfinity(bow)
+	arrowItem := item.NewItem(item.ARROW, 0, 1)
+	if !infinite && !p.Inventory.Contains(arrowItem) {
+		return
+	}
+
+	arrow := entity.NewArrowFromBow(p.GetID(), bow, force)
+	yaw := p.Yaw * math.Pi / 180
+	pitch := p.Pitch * math.Pi / 180
+	arrow.Entity.SetPosition(entity.NewVector3(p.Position.X, p.Position.Y+p.GetEyeHeight(), p.Position.Z))
+	arrow.Entity.Yaw = p.Yaw
+	arrow.Entity.Pitch = p.Pitch
+	arrow.Entity.Level = lvl
+	arrow.Entity.SetMotion(entity.NewVector3(
+		-math.Sin(yaw)*math.Cos(pitch)*force,
+		-math.Sin(pitch)*force,
+		math.Cos(yaw)*math.Cos(pitch)*force,
+	))
+
+	ev := event.NewEntityShootBowEvent(p.GetID(), force, arrow.GetID())
+	event.Call(ev)
+	if ev.IsCancelled() {
+		p.Inventory.SendContents(p)
+		return
+	}
+
+	if !infinite {
+		p.Inventory.RemoveItem(arrowItem)
+	}
+	if !p.IsCreative() {
+		p.damageBow(bow)
+	}
+	lvl.SpawnEntity(arrow)
+
+	logger.DebugPlayer("Shot bow", "player", p.Username, "force", force, "arrow", arrow.GetID())
+}
+
+func (p *Player) damageBow(bow item.Item) {
+	if item.IsUnbreakable(bow.NBTData) {
+		return
+	}
+	if rand.Intn(enchantment.UnbreakingLevel(bow)+1) != 0 {
+		return
+	}
+	bow.Meta++
+	if bow.Meta >= item.GetMaxDurability(bow.ID) {
+		bow = item.Air()
+	}
+	p.Inventory.SetItemInHand(bow)
+}
@@ -0,0 +1,184 @@
+package player
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/block"
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+const (
+	MaxAirTicks     = 300
+	VoidDamageY     = -16
+	VoidDamage      = 10
+	DrowningDamage  = 2
+	LavaDamage      = 4
+	LavaFireSeconds = 15
+	FireDamage      = 1
+	FireSeconds     = 8
+)
+
+func (p *Player) Attack(damage float64, cause int) bool {
+	return entity.Damage(p, entity.NewSimpleDamageSource(cause, damage)) != nil
+}
+
+func (p *Player) AttackByEntity(damage float64, attacker interface{}) bool {
+	source, _ := attacker.(entity.IEntity)
+	return entity.Damage(p, entity.NewEntityDamageSource(entity.DamageCauseEntityAttack, damage, source)) != nil
+}
+
+func (p *Player) IsInvulnerableTo(cause int) bool {
+	if p.survival.dead || !p.Spawned {
+		return true
+	}
+	if cause == entity.DamageCauseVoid {
+		return false
+	}
+	return p.Invulnerable || p.IsCreative()
+}
+
+func (p *Player) OnDamaged(result *entity.DamageResult) {
+	healthPk := protocol.NewSetHealthPacket()
+	healthPk.Health = int32(p.GetHealth())
+	p.SendPacket(healthPk)
+	p.sendHurtAnimation()
+	if result.Killed {
+		p.onDeath()
+	}
+}
+
+func (p *Player) KnockBack(attackerX, attackerZ float64, base, verticalLimit float64) {
+	p.Human.KnockBack(attackerX, attackerZ, base, verticalLimit)
+	pk := protocol.NewSetEntityMotionPacket()
+	pk.EntityID = 0
+	pk.SpeedX = float32(p.Motion.X)
+	pk.SpeedY = float32(p.Motion.Y)
+	pk.SpeedZ = float32(p.Motion.Z)
+	p.SendPacket(pk)
+}
+
+func (p *Player) updateFallDistance(dy float64) {
+	ms := p.movement
+	if ms.Swimming || ms.Climbing || ms.AllowFlight {
+		p.FallDistance = 0
+		return
+	}
+	if ms.OnGround {
+		if p.FallDistance > 3 {
+			p.Attack(entity.FallDamage(p.FallDistance), entity.DamageCauseFall)
+		}
+		p.FallDistance = 0
+		return
+	}
+	if dy < 0 {
+		p.FallDistance -= dy
+	}
+}
+
+func (p *Player) tickHazards() {
+	if p.survival.dead {
+		return
+	}
+	if p.NoDamageTicks > 0 {
+		p.NoDamageTicks--
+	}
+	if p.Position.Y < VoidDamageY {
+		p.Attack(VoidDamage, entity.DamageCauseVoid)
+		return
+	}
+	lvl, ok := p.Human.Level.(*level.Level)
+	if !ok || lvl == nil {
+		return
+	}
+	x := int32(math.Floor(p.Position.X))
+	z := int32(math.Floor(p.Position.Z))
+	feet := lvl.GetBlock(x, int32(math.Floor(p.Position.Y)), z)
+	head := lvl.GetBlock(x, int32(math.Floor(p.Position.Y+EyeHeight)), z)
+
+	switch {
+	case isLava(feet.ID) || isLava(head.ID):
+		p.SetOnFire(LavaFireSeconds)
+		p.Attack(LavaDamage, entity.DamageCauseLava)
+	case feet.ID == block.FIRE || head.ID == block.FIRE:
+		p.SetOnFire(FireSeconds)
+		p.Attack(FireDamage, entity.DamageCauseFire)
+	}
+	if prop := block.GetProperty(head.ID); prop.Solid && !prop.Transparent {
+		p.Attack(1, entity.DamageCauseSuffocation)
+	}
+	p.tickFire(isWater(feet.ID) || isWater(head.ID))
+	p.tickAir(isWater(head.ID))
+}
+
+func (p *Player) tickFire(inWater bool) {
+	burning := p.FireTicks > 0
+	if burning {
+		if inWater {
+			p.ExtinguishFire()
+		} else {
+			if p.FireTicks%20 == 0 {
+				p.Attack(1, entity.DamageCauseFireTick)
+			}
+			p.FireTicks--
+		}
+	}
+	if onFire := p.FireTicks > 0; onFire != p.Human.Metadata.GetFlag(entity.DataFlags, entity.DataFlagOnFire) {
+		p.Human.Metadata.SetFlag(entity.DataFlags, entity.DataFlagOnFire, onFire)
+		p.Human.Level.UpdateEntityMetadata(p)
+	}
+}
+
+func (p *Player) tickAir(headInWater bool) {
+	air := int(p.Human.Metadata.GetShort(entity.DataAir))
+	if headInWater && !p.HasEffect(effect.WaterBreathing) {
+		respiration := enchantment.Level(p.Inventory.GetHelmet(), enchantment.Respiration)
+		if respiration == 0 || rand.Intn(respiration+1) == 0 {
+			air--
+		}
+		if air <= -20 {
+			air = 0
+			p.Attack(DrowningDamage, entity.DamageCauseDrowning)
+		}
+	} else {
+		air = MaxAirTicks
+	}
+	if int16(air) != p.Human.Metadata.GetShort(entity.DataAir) {
+		p.Human.Metadata.SetShort(entity.DataAir, int16(air))
+		p.Human.Level.UpdateEntityMetadata(p)
+	}
+}
+
+func (p *Player) deathEvent() *event.PlayerDeathEvent {
+	source := p.GetLastDamageSource()
+	evt := event.NewPlayerDeathEvent(p.Username, p.GetID(), entity.DeathMessage(p.Username, source))
+	if source != nil {
+		evt.Cause = entity.EventCause(source.Cause())
+	}
+	event.Call(evt)
+	return evt
+}
+
+func (p *Player) broadcastDeathMessage(message string) {
+	logger.Info("Player died", "player", p.Username, "message", message)
+	if message == "" {
+		return
+	}
+	for _, viewer := range p.getViewers() {
+		viewer.SendMessage(message)
+	}
+}
+
+func isLava(id uint8) bool {
+	return id == block.LAVA || id == block.STILL_LAVA
+}
+
+func isWater(id uint8) bool {
+	return id == block.WATER || id == block.STILL_WATER
+}
@@ -0,0 +1,137 @@
+package player
+
+import (
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+const milkBucketMeta = 1
+
+var effectAttributes = []int{
+	entity.AttributeHealth,
+	entity.AttributeMovementSpeed,
+	entity.AttributeAbsorption,
+}
+
+func (p *Player) OnEffectAdded(e *effect.Effect, modify bool) {
+	eventID := protocol.MobEffectEventAdd
+	if modify {
+		eventID = protocol.MobEffectEventModify
+	}
+	p.sendMobEffect(eventID, e)
+	p.syncEffectAttributes()
+}
+
+func (p *Player) OnEffectRemoved(e *effect.Effect) {
+	p.sendMobEffect(protocol.MobEffectEventRemove, e)
+	p.syncEffectAttributes()
+}
+
+func (p *Player) sendMobEffect(eventID byte, e *effect.Effect) {
+	pk := protocol.NewMobEffectPacket()
+	pk.EntityID = 0
+	pk.EventID = eventID
+	pk.EffectID = byte(e.ID)
+	pk.Amplifier = byte(e.Amplifier)
+	pk.Particles = e.Particles
+	pk.Duration = int32(e.Duration)
+	p.SendPacket(pk)
+}
+
+func (p *Player) syncEffectAttributes() {
+	if health := p.Attributes.GetAttribute(entity.AttributeHealth); health != nil {
+		health.SetMaxValue(float64(p.GetMaxHealth()))
+		health.SetValue(float64(p.GetHealth()))
+	}
+
+	p.sendAttributes(effectAttributes...)
+
+	healthPk := protocol.NewSetHealthPacket()
+	healthPk.Health = int32(p.GetHealth())
+	p.SendPacket(healthPk)
+}
+
+func (p *Player) tickEffects() {
+	if p.survival.dead {
+		return
+	}
+	p.TickEffects(1)
+}
+
+func (p *Player) HandleEntityEvent(eventID byte) {
+	if !p.Spawned || p.survival.dead {
+		return
+	}
+	switch eventID {
+	case protocol.EntityEventUseItem:
+		p.consumeItemInHand()
+	}
+}
+
+func (p *Player) consumeItemInHand() {
+	held := p.Inventory.GetItemInHand()
+	switch {
+	case held.ID == item.POTION:
+	case held.ID == item.BUCKET && held.Meta == milkBucketMeta:
+	case item.IsFoodItem(held.ID):
+		if !item.CanEat(held.ID, int(p.GetFood()), item.MaxFood) {
+			return
+		}
+	default:
+		return
+	}
+
+	evt := event.NewPlayerItemConsumeEvent(p.Username, p.GetID(), held.ID, held.Meta)
+	event.Call(evt)
+	if evt.IsCancelled() {
+		p.sendInventoryContents()
+		return
+	}
+
+	residue := item.NewItem(0, 0, 0)
+	switch held.ID {
+	case item.POTION:
+		for _, e := range effect.FromPotion(held.Meta) {
+			p.AddEffect(e)
+		}
+		residue = item.NewItem(item.GLASS_BOTTLE, 0, 1)
+	case item.BUCKET:
+		p.ClearEffects()
+		residue = item.NewItem(item.BUCKET, 0, 1)
+	default:
+		result := item.Eat(held)
+		p.AddFood(float64(result.FoodRestore))
+		p.AddSaturation(float64(result.Saturation))
+		p.syncFoodAttributes()
+		for _, fe := range result.Effects {
+			if rand.Float32() < fe.Chance {
+				p.AddEffect(effect.NewEffect(fe.ID, fe.Duration, fe.Level))
+			}
+		}
+		if result.ResidueItem != nil {
+			residue = *result.ResidueItem
+		}
+	}
+	p.broadcastEntityEvent(protocol.EntityEventUseItem)
+
+	logger.DebugPlayer("Item consumed",
+		"player", p.Username,
+		"id", held.ID, "meta", held.Meta)
+
+	if p.IsCreative() {
+		return
+	}
+	held.Count--
+	if held.Count <= 0 {
+		held = residue
+	} else if residue.ID != 0 {
+		p.Inventory.AddItem(residue)
+	}
+	p.Inventory.SetItemInHand(held)
+}
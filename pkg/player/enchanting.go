@@ -0,0 +1,121 @@
+package player
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/entity/attribute"
+	"github.com/scaxe/scaxe-go/pkg/inventory"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+type ItemDropFunc func(x, y, z float32, it item.Item)
+
+func (p *Player) SetItemDropFunc(fn ItemDropFunc) {
+	p.dropItem = fn
+}
+
+func (p *Player) DropItem(it item.Item) {
+	for _, left := range p.Inventory.AddItem(it) {
+		if p.dropItem == nil {
+			logger.Warn("Discarded item that did not fit in inventory", "player", p.Username, "item", left.ID, "count", left.Count)
+			continue
+		}
+		p.dropItem(float32(p.Position.X), float32(p.Position.Y)+1.3, float32(p.Position.Z), left)
+	}
+}
+
+func (p *Player) sendAttributes(ids ...int) {
+	pk := protocol.NewUpdateAttributesPacket()
+	pk.EntityID = 0
+	for _, id := range ids {
+		attr := p.Attributes.GetAttribute(id)
+		if attr == nil || !attr.IsDirty() {
+			continue
+		}
+		pk.Entries = append(pk.Entries, &attribute.Attribute{
+			Name:         attr.Name,
+			MinValue:     float32(attr.MinValue),
+			MaxValue:     float32(attr.MaxValue),
+			DefaultValue: float32(attr.DefaultValue),
+			CurrentValue: float32(attr.CurrentValue),
+		})
+		attr.MarkClean()
+	}
+	if len(pk.Entries) > 0 {
+		p.SendPacket(pk)
+	}
+}
+
+func (p *Player) HandleContainerSlot(windowID byte, slot int, it item.Item) {
+	inv := p.GetWindowByID(windowID)
+	if inv == nil || slot < 0 || slot >= inv.GetSize() {
+		return
+	}
+	switch win := inv.(type) {
+	case *inventory.EnchantInventory:
+		p.handleEnchantSlot(win, slot, it)
+	case *inventory.AnvilInventory:
+		p.handleAnvilSlot(win, slot, it)
+	case *inventory.FurnaceInventory:
+		p.handleFurnaceSlot(win, slot, it)
+	default:
+		inv.SetItem(slot, it)
+	}
+}
+
+func (p *Player) handleEnchantSlot(inv *inventory.EnchantInventory, slot int, it item.Item) {
+	input := inv.GetInput()
+	if slot != inventory.EnchantSlotInput || input.IsAir() || it.IsAir() ||
+		!enchantment.HasEnchantments(it) || enchantment.HasEnchantments(input) {
+		inv.SetItem(slot, it)
+		return
+	}
+
+	opt, index, ok := inv.MatchOption(it)
+	if !ok {
+		logger.DebugPlayer("Rejected enchant", "player", p.Username, "item", it.ID)
+		inv.SendContents(p)
+		return
+	}
+	if !p.IsCreative() {
+		if p.GetXPLevel() < opt.Cost {
+			inv.SendContents(p)
+			return
+		}
+		p.SetXPLevel(p.GetXPLevel() - opt.Cost)
+	}
+	inv.SetItem(inventory.EnchantSlotInput, opt.Apply(input))
+	inv.Reseed()
+
+	logger.Player("Enchanted item", "player", p.Username, "item", input.ID, "option", index, "cost", opt.Cost)
+}
+
+func (p *Player) handleAnvilSlot(inv *inventory.AnvilInventory, slot int, it item.Item) {
+	if slot != inventory.AnvilSlotResult {
+		inv.SetItem(slot, it)
+		return
+	}
+	if it.IsAir() {
+		return
+	}
+
+	res := inv.Result(it.GetCustomName())
+	if !inv.Matches(res, it) {
+		logger.DebugPlayer("Rejected anvil result", "player", p.Username, "item", it.ID)
+		inv.SendContents(p)
+		p.Inventory.SendContents(p)
+		return
+	}
+	if !p.IsCreative() {
+		if res.Cost >= enchantment.MaxAnvilCost || p.GetXPLevel() < res.Cost {
+			inv.SendContents(p)
+			p.Inventory.SendContents(p)
+			return
+		}
+		p.SetXPLevel(p.GetXPLevel() - res.Cost)
+	}
+	inv.Consume(res)
+
+	logger.Player("Used anvil", "player", p.Username, "item", it.ID, "cost", res.Cost)
+}
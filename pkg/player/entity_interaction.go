@@ -1,26 +1,30 @@
 package player
 
 import (
-	"math"
-
 	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
 	"github.com/scaxe/scaxe-go/pkg/level"
 	"github.com/scaxe/scaxe-go/pkg/logger"
 	"github.com/scaxe/scaxe-go/pkg/protocol"
 )
 
 const (
-	InteractActionLeftClick    byte = 1
-	InteractActionRightClick   byte = 2
-	InteractActionLeaveVehicle byte = 3
+	InteractActionRightClick   = protocol.ActionRightClick
+	InteractActionLeftClick    = protocol.ActionLeftClick
+	InteractActionLeaveVehicle = protocol.ActionLeaveVehicle
 	AttackCooldownTicks = 10
-	DefaultKnockback = 0.4
+	SprintKnockback = 0.5
+	ExhaustionPerAttack = 0.3
 )
 type CombatState struct {
 	CPS            int
 	AttackCooldown int
 	MaxCPS         int
 	LastAttackTick int64
+
+	usingBow bool
+	bowTicks int
 }
 func newCombatState() *CombatState {
 	return &CombatState{
@@ -73,42 +77,36 @@ func (p *Player) handleEntityAttack(target entity.IEntity) {
 	if !p.canInteract(targetPos.X, targetPos.Y, targetPos.Z, maxDist) {
 		return
 	}
+	victim, ok := target.(entity.Damageable)
+	if !ok {
+		return
+	}
 	heldItem := p.Inventory.GetItemInHand()
-	damageBase := 1.0
-	_ = heldItem
-	knockback := DefaultKnockback
-	isCritical := false
-	if !p.IsOnGround() && p.movement.SpeedY < 0 && !p.IsSwimming() {
-		damageBase *= 1.5
-		isCritical = true
-	}
-	if ent, ok := target.(*entity.Entity); ok {
-		ent.Health -= int(damageBase)
-		if ent.Health < 0 {
-			ent.Health = 0
-		}
+	source := entity.NewEntityDamageSource(entity.DamageCauseEntityAttack, entity.MeleeDamage(heldItem, target), p)
+	source.Knockback = enchantment.KnockbackBonus(heldItem)
+	if p.IsSprinting() {
+		source.Knockback += SprintKnockback
+	}
+	source.FireSeconds = enchantment.FireAspectSeconds(heldItem)
+	isCritical := !p.IsOnGround() && p.movement.SpeedY < 0 && !p.IsSwimming() && !p.IsClimbing()
+	source.Critical = isCritical
+	p.combat.AttackCooldown = AttackCooldownTicks
+	result := entity.Damage(victim, source)
+	if result == nil {
+		return
 	}
-	dx := targetPos.X - p.Position.X
-	dz := targetPos.Z - p.Position.Z
-	dist := math.Sqrt(dx*dx + dz*dz)
-	if dist > 0 {
-		kbX := dx / dist * knockback
-		kbZ := dz / dist * knockback
-		if ent, ok := target.(*entity.Entity); ok {
-			ent.SetMotion(entity.NewVector3(kbX, knockback*0.4, kbZ))
-		}
+	if p.IsSurvival() {
+		p.Inventory.SetItemInHand(entity.UseWeapon(heldItem))
 	}
+	p.Exhaust(ExhaustionPerAttack)
 	if isCritical {
 		p.broadcastCriticalHit(target)
 	}
-	p.combat.AttackCooldown = AttackCooldownTicks
-	if p.IsSurvival() {
-	}
 
 	logger.DebugPlayer("Entity attacked",
 		"player", p.Username,
 		"target", target.GetID(),
-		"damage", damageBase,
+		"damage", result.Damage,
 		"critical", isCritical)
 }
 func (p *Player) handleEntityRightClick(target entity.IEntity) {
@@ -120,6 +118,9 @@ func (p *Player) handleEntityRightClick(target entity.IEntity) {
 		"player", p.Username,
 		"target", target.GetID())
 }
+func (p *Player) GetItemInHand() item.Item {
+	return p.Inventory.GetItemInHand()
+}
 func (p *Player) handleLeaveVehicle(target entity.IEntity) {
 	p.Human.Metadata.SetFlag(entity.DataFlags, entity.DataFlagRiding, false)
 
@@ -162,6 +163,9 @@ func (p *Player) tickCombat() {
 	if p.combat.AttackCooldown > 0 {
 		p.combat.AttackCooldown--
 	}
+	if p.combat.usingBow {
+		p.combat.bowTicks++
+	}
 }
 func (p *Player) ResetCPS() {
 	p.combat.CPS = 0
@@ -0,0 +1,82 @@
+package player
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/inventory"
+	"github.com/scaxe/scaxe-go/pkg/item"
+)
+
+func (p *Player) CanCollectExperience() bool {
+	return p.Spawned && !p.survival.dead && !p.IsSpectator()
+}
+
+func (p *Player) CollectExperience(amount int) {
+	p.AddXP(amount)
+}
+
+func (p *Player) AddXP(amount int) {
+	p.SetTotalXP(p.GetTotalXP() + amount)
+}
+
+func (p *Player) SetTotalXP(total int) {
+	if total < 0 {
+		total = 0
+	}
+	level, progress := entity.LevelFromExperience(total)
+	p.setExperience(level, progress)
+}
+
+func (p *Player) SetXPLevel(level int) {
+	if level < 0 {
+		level = 0
+	}
+	p.setExperience(level, p.GetXPProgress())
+}
+
+func (p *Player) AddXPLevel(levels int) {
+	p.SetXPLevel(p.GetXPLevel() + levels)
+}
+
+func (p *Player) setExperience(level int, progress float64) bool {
+	evt := event.NewPlayerExperienceChangeEvent(p.Username, p.GetID(), p.GetXPLevel(), p.GetXPProgress(), level, progress)
+	event.Call(evt)
+	if evt.IsCancelled() {
+		return false
+	}
+	p.Human.SetXPLevel(evt.NewLevel)
+	p.Human.SetXPProgress(evt.NewExp)
+	p.sendAttributes(entity.AttributeExperienceLevel, entity.AttributeExperience)
+	return true
+}
+
+func (p *Player) dropExperience() {
+	if p.Gamemode == 0 {
+		entity.DropExperience(p.Human.Level, p.Position, entity.DeathExperience(p.GetXPLevel()))
+	}
+	p.setExperience(0, 0)
+}
+
+func (p *Player) handleFurnaceSlot(inv *inventory.FurnaceInventory, slot int, it item.Item) {
+	before := inv.GetItem(slot)
+	inv.SetItem(slot, it)
+	if slot != inventory.FurnaceSlotResult || before.IsAir() {
+		return
+	}
+	taken := before.Count
+	if it.ID == before.ID {
+		taken -= it.Count
+	}
+	if taken <= 0 {
+		return
+	}
+	xp := item.SmeltingExperience(before.ID) * float64(taken)
+	amount := int(math.Floor(xp))
+	if rand.Float64() < xp-float64(amount) {
+		amount++
+	}
+	entity.DropExperience(p.Human.Level, p.Position, amount)
+}
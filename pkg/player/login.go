@@ -0,0 +1,45 @@
+package player
+
+import "strings"
+
+// Disconnect screens the client translates itself.
+const (
+	DisconnectNoReason      = "disconnectionScreen.noReason"
+	DisconnectServerFull    = "disconnectionScreen.serverFull"
+	DisconnectInvalidName   = "disconnectionScreen.invalidName"
+	DisconnectInvalidSkin   = "disconnectionScreen.invalidSkin"
+	DisconnectOtherLocation = "disconnectionScreen.loggedinOtherLocation"
+)
+
+const (
+	MaxUsernameLength = 16
+
+	SkinSizeClassic = 64 * 32 * 4
+	SkinSizeSlim    = 64 * 64 * 4
+)
+
+// IsValidUsername applies the vanilla client's rules: 1-16 letters, digits,
+// underscores or inner spaces, and none of the names the console uses.
+func IsValidUsername(name string) bool {
+	if len(name) == 0 || len(name) > MaxUsernameLength {
+		return false
+	}
+	if name[0] == ' ' || name[len(name)-1] == ' ' {
+		return false
+	}
+	switch strings.ToLower(name) {
+	case "console", "rcon", "server":
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '_' && c != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+func IsValidSkin(data []byte) bool {
+	return len(data) == SkinSizeClassic || len(data) == SkinSizeSlim
+}
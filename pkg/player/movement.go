@@ -87,6 +87,7 @@ func (p *Player) handleMovement(newX, newY, newZ float64) {
 		ms.Moving = true
 		p.checkGroundState(dx, dy, dz)
 		p.checkBlockCollision()
+		p.updateFallDistance(dy)
 
 	} else if distSq <= 0.0001 {
 		ms.SpeedX = 0
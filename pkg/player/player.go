@@ -1,7 +1,9 @@
 package player
 
 import (
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/scaxe/scaxe-go/pkg/block"
 	"github.com/scaxe/scaxe-go/pkg/entity"
@@ -10,9 +12,10 @@ import (
 	"github.com/scaxe/scaxe-go/pkg/item"
 	"github.com/scaxe/scaxe-go/pkg/level"
 	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/metrics"
+	"github.com/scaxe/scaxe-go/pkg/network"
 	"github.com/scaxe/scaxe-go/pkg/permission"
 	"github.com/scaxe/scaxe-go/pkg/protocol"
-	"github.com/scaxe/scaxe-go/pkg/raknet"
 	"github.com/scaxe/scaxe-go/pkg/world"
 )
 
@@ -24,11 +27,12 @@ type Player struct {
 	*entity.Human
 	mu sync.RWMutex
 
-	Session   *raknet.Session
+	Session   network.Conn
 	ClientID  uint64
 	IPAddress string
 	Port      int
 	Protocol  int32
+	Codec     *protocol.Codec
 
 	DisplayName string
 
@@ -52,15 +56,19 @@ type Player struct {
 	LastMoveTime int64
 	Ping         int
 	Difficulty   int
+	breakStarted time.Time
 
 	Inventory *inventory.PlayerInventory
 	windows   *InventoryWindows
 	movement  *MovementState
 	combat    *CombatState
 	survival  *SurvivalState
+	dropItem  ItemDropFunc
+
+	attachments map[string]bool
 }
 
-func NewPlayer(session *raknet.Session, ip string, port int) *Player {
+func NewPlayer(session network.Conn, ip string, port int) *Player {
 	p := &Player{
 		Human:          entity.NewHuman(),
 		Session:        session,
@@ -127,6 +135,7 @@ func NewPlayer(session *raknet.Session, ip string, port int) *Player {
 	p.Inventory.AddItem(item.NewItem(item.IRON_PICKAXE, 0, 1))
 	p.Inventory.AddItem(item.NewItem(item.DIAMOND_SWORD, 0, 1))
 	p.Inventory.AddItem(item.NewItem(block.PLANKS, 0, 64))
+	p.Human.Living.SetEffectHandler(p)
 
 	return p
 }
@@ -155,7 +164,39 @@ func (p *Player) IsOp() bool {
 }
 
 func (p *Player) HasPermission(name string) bool {
-	return permission.GlobalManager.HasPermission(name, p.IsOp())
+	p.mu.RLock()
+	value, set := p.attachments[strings.ToLower(name)]
+	p.mu.RUnlock()
+	if set {
+		return value
+	}
+	return permission.GlobalManager.HasPermissionFor(p.Username, p.GetLevelName(), name, p.IsOp())
+}
+
+func (p *Player) GetLevel() interface{} {
+	return p.Human.Level
+}
+
+func (p *Player) GetLevelName() string {
+	if lvl, ok := p.Human.Level.(*level.Level); ok && lvl != nil {
+		return lvl.Name
+	}
+	return ""
+}
+
+func (p *Player) SetPermission(name string, value bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.attachments == nil {
+		p.attachments = make(map[string]bool)
+	}
+	p.attachments[strings.ToLower(name)] = value
+}
+
+func (p *Player) UnsetPermission(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.attachments, strings.ToLower(name))
 }
 
 func (p *Player) sendInventoryContents() {
@@ -249,26 +290,53 @@ func (p *Player) IsSpawned() bool {
 }
 
 func (p *Player) Close() {
-	quitEvt := event.NewPlayerQuitEvent(p.Username, p.GetID(), p.Username+" left the game", "disconnect")
-	event.Call(quitEvt)
-
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	if !p.Connected {
+		p.mu.Unlock()
+		return
+	}
 	p.Connected = false
 	p.Spawned = false
-	if p.Session != nil {
-		p.Session.Close()
+	session := p.Session
+	p.mu.Unlock()
+
+	quitEvt := event.NewPlayerQuitEvent(p.Username, p.GetID(), p.Username+" left the game", "disconnect")
+	event.Call(quitEvt)
+
+	if session != nil {
+		session.Close()
 	}
 }
 
 func (p *Player) SendPacket(pk protocol.DataPacket) {
 	if p.Session != nil && p.Connected {
-		buf := protocol.NewBinaryStream()
-		pk.Encode(buf)
-		p.Session.SendPacket(buf.Bytes())
+		if data := p.EncodePacket(pk); data != nil {
+			p.Session.SendPacket(data)
+		}
 	}
 }
 
+// PacketCodec is the codec negotiated at login, or the default before that.
+func (p *Player) PacketCodec() *protocol.Codec {
+	if p.Codec != nil {
+		return p.Codec
+	}
+	return protocol.DefaultCodec
+}
+
+// EncodePacket encodes pk for the player's protocol version. Metrics are
+// recorded here, under the canonical ID, since the wire ID differs between
+// versions.
+func (p *Player) EncodePacket(pk protocol.DataPacket) []byte {
+	data, err := p.PacketCodec().Encode(pk)
+	if err != nil {
+		logger.Error("Failed to encode packet", "packet", pk.Name(), "player", p.Username, "error", err)
+		return nil
+	}
+	metrics.PacketOut(pk.ID(), len(data))
+	return data
+}
+
 func (p *Player) BroadcastArmorChange() {
 
 	levelInterface := p.Human.Level
@@ -306,6 +374,8 @@ func (p *Player) Tick(currentTick int64) bool {
 		p.processMovement()
 		p.tickCombat()
 		p.tickSurvival()
+		p.tickHazards()
+		p.tickEffects()
 	}
 
 	p.checkNearEntities()
@@ -411,13 +481,6 @@ func (p *Player) checkNearEntities() {
 		itemEnt.Close()
 		level.RemoveEntity(itemEnt)
 
-		removePk := protocol.NewRemoveEntityPacket()
-		removePk.EntityID = itemEnt.GetID()
-		for _, viewer := range p.getViewers() {
-			viewer.SendPacket(removePk)
-		}
-		p.SendPacket(removePk)
-
 		p.sendInventoryContents()
 
 		logger.DebugPlayer("Picked up item", "player", p.Username, "item", itemEnt.Item.ID)
@@ -655,6 +718,10 @@ func (p *Player) HandleMove(x, y, z float64, yaw, bodyYaw, pitch float32, onGrou
 
 func (p *Player) HandleAction(action int32) {
 	switch action {
+	case ActionStartBreak:
+		p.breakStarted = time.Now()
+	case ActionAbortBreak:
+		p.breakStarted = time.Time{}
 	case ActionJump:
 		p.ExhaustFromJump()
 
@@ -668,6 +735,8 @@ func (p *Player) HandleAction(action int32) {
 		p.SetSneaking(false)
 	case ActionRespawn:
 		p.handleRespawn()
+	case ActionReleaseItem:
+		p.ReleaseItem()
 	}
 }
 
@@ -106,3 +106,30 @@ func TestChunkRadius(t *testing.T) {
 		t.Errorf("Expected radius 8, got %d", p.GetChunkRadius())
 	}
 }
+
+func TestIsValidUsername(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"Steve", true},
+		{"Alex_2016", true},
+		{"Mr Steve", true},
+		{"A", true},
+		{"SixteenCharsLong", true},
+		{"", false},
+		{"SeventeenCharsLon", false},
+		{" Steve", false},
+		{"Steve ", false},
+		{"Ste.ve", false},
+		{"Stéve", false},
+		{"CONSOLE", false},
+		{"rcon", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidUsername(tt.name); got != tt.valid {
+			t.Errorf("IsValidUsername(%q) = %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
@@ -80,7 +80,6 @@ func (p *Player) tickSurvival() {
 			}
 			if shouldDamage {
 				p.Attack(1, entity.DamageCauseStarvation)
-				p.sendHurtAnimation()
 			}
 		}
 	}
@@ -94,11 +93,17 @@ func (p *Player) onDeath() {
 	p.survival.dead = true
 	p.survival.deathTime = 0
 
-	logger.Info("Player died", "player", p.Username)
+	evt := p.deathEvent()
+	p.broadcastDeathMessage(evt.GetDeathMessage())
+	p.ClearEffects()
+	p.ExtinguishFire()
 	p.broadcastEntityEvent(EntityEventDeathAnimation)
-	if p.Gamemode == 0 {
+	if p.Gamemode == 0 && !evt.KeepInventory {
 		p.dropAllItems()
 	}
+	if !evt.KeepExperience {
+		p.dropExperience()
+	}
 	respawnPk := protocol.NewRespawnPacket()
 	if lvl, ok := p.Human.Level.(*level.Level); ok {
 		spawn := lvl.GetSafeSpawn()
@@ -119,6 +124,9 @@ func (p *Player) dropAllItems() {
 			continue
 		}
 		p.Inventory.ClearSlot(slot, false)
+		if p.dropItem != nil {
+			p.dropItem(float32(p.Position.X), float32(p.Position.Y)+1.3, float32(p.Position.Z), it)
+		}
 	}
 }
 func (p *Player) handleRespawn() {
@@ -133,6 +141,9 @@ func (p *Player) handleRespawn() {
 	p.SetSaturation(20)
 	p.SetExhaustion(0)
 	p.Human.FoodTickTimer = 0
+	p.FallDistance = 0
+	p.NoDamageTicks = 0
+	p.Human.Metadata.SetShort(entity.DataAir, MaxAirTicks)
 	lvl, ok := p.Human.Level.(*level.Level)
 	if !ok || lvl == nil {
 		return
@@ -162,23 +173,6 @@ func (p *Player) Heal(amount float64) {
 		p.SendPacket(healthPk)
 	}
 }
-func (p *Player) Attack(damage float64, cause int) bool {
-	if p.survival.dead {
-		return false
-	}
-
-	result := p.Human.Living.Attack(damage, cause)
-	if result {
-		healthPk := protocol.NewSetHealthPacket()
-		healthPk.Health = int32(p.GetHealth())
-		p.SendPacket(healthPk)
-
-		if p.GetHealth() <= 0 {
-			p.onDeath()
-		}
-	}
-	return result
-}
 func (p *Player) sendHurtAnimation() {
 	p.broadcastEntityEvent(EntityEventHurtAnimation)
 }
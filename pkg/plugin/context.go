@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"path/filepath"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/scheduler"
+)
+
+type Context struct {
+	Server    Server
+	Events    *event.EventManager
+	Commands  *command.CommandMap
+	Scheduler *scheduler.Scheduler
+
+	name     string
+	dataDir  string
+	commands []string
+	tasks    []*scheduler.TaskHandler
+}
+
+func (c *Context) PluginName() string {
+	return c.name
+}
+
+// DataDir returns the plugin's data folder, which exists by the time
+// OnEnable is called.
+func (c *Context) DataDir() string {
+	return c.dataDir
+}
+
+func (c *Context) DataPath(name string) string {
+	return filepath.Join(c.DataDir(), name)
+}
+
+func (c *Context) RegisterEvent(eventName string, handler event.Handler, priority int) {
+	c.Events.RegisterHandler(eventName, handler, priority, c.name)
+}
+
+func (c *Context) RegisterEventEx(eventName string, handler event.Handler, priority int, ignoreCancelled bool) {
+	c.Events.RegisterHandlerEx(eventName, handler, priority, ignoreCancelled, c.name)
+}
+
+func (c *Context) RegisterCommand(cmd command.Command) {
+	c.Commands.Register(cmd)
+	c.commands = append(c.commands, cmd.GetName())
+}
+
+func (c *Context) RunLater(delay int, fn func(currentTick int64)) *scheduler.TaskHandler {
+	h := c.Scheduler.ScheduleDelayedTask(scheduler.NewClosureTask(c.name, fn), delay)
+	c.tasks = append(c.tasks, h)
+	return h
+}
+
+func (c *Context) RunRepeating(period int, fn func(currentTick int64)) *scheduler.TaskHandler {
+	h := c.Scheduler.ScheduleRepeatingTask(scheduler.NewClosureTask(c.name, fn), period)
+	c.tasks = append(c.tasks, h)
+	return h
+}
+
+func (c *Context) ScheduleTask(task scheduler.Task, delay, period int) *scheduler.TaskHandler {
+	h := c.Scheduler.ScheduleDelayedRepeatingTask(task, delay, period)
+	c.tasks = append(c.tasks, h)
+	return h
+}
+
+func (c *Context) release() {
+	c.Events.UnregisterPlugin(c.name)
+	for _, name := range c.commands {
+		c.Commands.Unregister(name)
+	}
+	for _, h := range c.tasks {
+		if !h.IsCancelled() {
+			c.Scheduler.CancelTask(h.GetTaskID())
+		}
+	}
+	c.commands = nil
+	c.tasks = nil
+}
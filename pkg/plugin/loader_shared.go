@@ -0,0 +1,84 @@
+//go:build linux && cgo
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	goplugin "plugin"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+func loadSharedPlugins(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	loaded := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".so") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		names, err := openShared(path)
+		if err != nil {
+			logger.Error("Failed to open native plugin", "file", entry.Name(), "error", err)
+			continue
+		}
+		for _, name := range names {
+			loaded[name] = path
+		}
+	}
+	return loaded, nil
+}
+
+func openShared(path string) ([]string, error) {
+	before := make(map[string]bool)
+	for _, name := range RegisteredPlugins() {
+		before[name] = true
+	}
+
+	so, err := goplugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if sym, err := so.Lookup("NewPlugin"); err == nil {
+		var factory Factory
+		switch fn := sym.(type) {
+		case func() Plugin:
+			factory = fn
+		case *func() Plugin:
+			factory = *fn
+		default:
+			return nil, fmt.Errorf("NewPlugin has unexpected type %T", sym)
+		}
+		var depend []string
+		if sym, err := so.Lookup("Depend"); err == nil {
+			d, ok := sym.(*[]string)
+			if !ok {
+				return nil, fmt.Errorf("Depend has unexpected type %T", sym)
+			}
+			depend = *d
+		}
+		Register(factory().Name(), factory, depend...)
+	}
+
+	var names []string
+	for _, name := range RegisteredPlugins() {
+		if !before[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no plugins registered; export NewPlugin or call plugin.Register in init")
+	}
+	return names, nil
+}
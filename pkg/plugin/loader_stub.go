@@ -0,0 +1,7 @@
+//go:build !linux || !cgo
+
+package plugin
+
+func loadSharedPlugins(dir string) (map[string]string, error) {
+	return nil, nil
+}
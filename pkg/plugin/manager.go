@@ -0,0 +1,308 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/scheduler"
+)
+
+type loadedPlugin struct {
+	plugin  Plugin
+	ctx     *Context
+	enabled bool
+	source  string
+	lastErr error
+}
+
+type Manager struct {
+	mu        sync.Mutex
+	server    Server
+	commands  *command.CommandMap
+	pluginDir string
+	plugins   map[string]*loadedPlugin
+	order     []string
+}
+
+func NewManager(server Server, commands *command.CommandMap, dir string) *Manager {
+	return &Manager{
+		server:    server,
+		commands:  commands,
+		pluginDir: dir,
+		plugins:   make(map[string]*loadedPlugin),
+	}
+}
+
+func (m *Manager) LoadAll() error {
+	sources := make(map[string]string)
+	for _, name := range RegisteredPlugins() {
+		sources[name] = "builtin"
+	}
+
+	shared, err := loadSharedPlugins(m.pluginDir)
+	if err != nil {
+		logger.Warn("Failed to scan native plugins", "dir", m.pluginDir, "error", err)
+	}
+	for name, path := range shared {
+		sources[name] = filepath.Base(path)
+	}
+
+	loaded := 0
+	for _, name := range m.resolveOrder(sources) {
+		if err := m.enable(name, sources[name]); err != nil {
+			logger.Error("Failed to enable native plugin", "name", name, "error", err)
+			continue
+		}
+		loaded++
+	}
+	if loaded > 0 {
+		logger.Server("Native plugins loaded", "count", loaded)
+	}
+	return nil
+}
+
+func (m *Manager) resolveOrder(sources map[string]string) []string {
+	order := make([]string, 0, len(sources))
+	state := make(map[string]int)
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case 1:
+			logger.Error("Circular native plugin dependency", "name", name)
+			return false
+		case 2:
+			return true
+		case 3:
+			return false
+		}
+		state[name] = 1
+		for _, dep := range getDepend(name) {
+			if _, ok := sources[dep]; !ok || !visit(dep) {
+				logger.Error("Missing native plugin dependency", "name", name, "dependency", dep)
+				state[name] = 3
+				return false
+			}
+		}
+		state[name] = 2
+		order = append(order, name)
+		return true
+	}
+	for _, name := range RegisteredPlugins() {
+		if _, ok := sources[name]; ok {
+			visit(name)
+		}
+	}
+	return order
+}
+
+func (m *Manager) enable(name, source string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if lp, ok := m.plugins[name]; ok && lp.enabled {
+		return fmt.Errorf("plugin %s is already enabled", name)
+	}
+	factory := getFactory(name)
+	if factory == nil {
+		return fmt.Errorf("plugin %s is not registered", name)
+	}
+
+	for _, dep := range getDepend(name) {
+		if lp, ok := m.plugins[dep]; !ok || !lp.enabled {
+			return fmt.Errorf("plugin %s requires %s, which is not enabled", name, dep)
+		}
+	}
+
+	dataDir := filepath.Join(m.pluginDir, name)
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data folder: %w", err)
+	}
+
+	p := factory()
+	ctx := &Context{
+		Server:    m.server,
+		Events:    event.GetGlobalManager(),
+		Commands:  m.commands,
+		Scheduler: scheduler.GetGlobalScheduler(),
+		name:      name,
+		dataDir:   dataDir,
+	}
+	lp := &loadedPlugin{plugin: p, ctx: ctx, source: source}
+	if prev, ok := m.plugins[name]; ok && source == "" {
+		lp.source = prev.source
+	}
+	m.plugins[name] = lp
+	if !containsName(m.order, name) {
+		m.order = append(m.order, name)
+	}
+
+	if err := m.callEnable(p, ctx); err != nil {
+		ctx.release()
+		lp.lastErr = err
+		return err
+	}
+	lp.enabled = true
+	logger.Server("Native plugin enabled", "name", name, "version", p.Version(), "source", lp.source)
+	return nil
+}
+
+func (m *Manager) callEnable(p Plugin, ctx *Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in OnEnable: %v", r)
+		}
+	}()
+	return p.OnEnable(ctx)
+}
+
+func (m *Manager) disable(name string) {
+	lp, ok := m.plugins[name]
+	if !ok || !lp.enabled {
+		return
+	}
+	for _, other := range m.order {
+		olp := m.plugins[other]
+		if olp == nil || !olp.enabled {
+			continue
+		}
+		if containsName(getDepend(other), name) {
+			logger.Warn("Disabling dependent native plugin", "name", other, "dependency", name)
+			m.disable(other)
+		}
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("Native plugin panicked in OnDisable", "name", name, "error", r)
+			}
+		}()
+		lp.plugin.OnDisable()
+	}()
+	lp.ctx.release()
+	lp.enabled = false
+	logger.Server("Native plugin disabled", "name", name)
+}
+
+func (m *Manager) EnablePlugin(name string) error {
+	return m.enable(name, "")
+}
+
+func (m *Manager) DisablePlugin(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lp, ok := m.plugins[name]
+	if !ok {
+		return fmt.Errorf("plugin %s not found", name)
+	}
+	if !lp.enabled {
+		return fmt.Errorf("plugin %s is already disabled", name)
+	}
+	m.disable(name)
+	return nil
+}
+
+func (m *Manager) ReloadPlugin(name string) error {
+	m.mu.Lock()
+	if _, ok := m.plugins[name]; !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("plugin %s not found", name)
+	}
+	dependents := m.enabledDependents(name)
+	m.disable(name)
+	m.mu.Unlock()
+
+	if err := m.enable(name, ""); err != nil {
+		return err
+	}
+	for _, dep := range dependents {
+		if err := m.enable(dep, ""); err != nil {
+			logger.Error("Failed to re-enable dependent native plugin", "name", dep, "error", err)
+		}
+	}
+	return nil
+}
+
+// enabledDependents lists the enabled plugins that disabling name takes
+// down with it, in the order they were enabled.
+func (m *Manager) enabledDependents(name string) []string {
+	affected := map[string]bool{name: true}
+	var result []string
+	for _, other := range m.order {
+		lp := m.plugins[other]
+		if lp == nil || !lp.enabled || affected[other] {
+			continue
+		}
+		for _, dep := range getDepend(other) {
+			if affected[dep] {
+				affected[other] = true
+				result = append(result, other)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (m *Manager) DisableAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.order) - 1; i >= 0; i-- {
+		m.disable(m.order[i])
+	}
+}
+
+func (m *Manager) GetPluginNames() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.order...)
+}
+
+func (m *Manager) IsPluginEnabled(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lp, ok := m.plugins[name]
+	return ok && lp.enabled
+}
+
+func (m *Manager) GetPlugin(name string) Plugin {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lp, ok := m.plugins[name]; ok {
+		return lp.plugin
+	}
+	return nil
+}
+
+func (m *Manager) GetPluginStatus(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lp, ok := m.plugins[name]
+	if !ok {
+		return "", fmt.Errorf("plugin %s not found", name)
+	}
+	state := "disabled"
+	if lp.enabled {
+		state = "enabled"
+	}
+	status := fmt.Sprintf("%s v%s (%s): %s, commands=%d, tasks=%d",
+		name, lp.plugin.Version(), lp.source, state, len(lp.ctx.commands), len(lp.ctx.tasks))
+	if lp.lastErr != nil {
+		status += ", last error: " + lp.lastErr.Error()
+	}
+	return status, nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scaxe/scaxe-go/pkg/command"
+)
+
+type recordingPlugin struct {
+	name    string
+	enabled *[]string
+}
+
+func (p *recordingPlugin) Name() string    { return p.name }
+func (p *recordingPlugin) Version() string { return "1.0.0" }
+
+func (p *recordingPlugin) OnEnable(ctx *Context) error {
+	*p.enabled = append(*p.enabled, p.name)
+	return nil
+}
+
+func (p *recordingPlugin) OnDisable() {}
+
+func TestResolveOrderUsesRegisteredDepends(t *testing.T) {
+	build := func() Plugin {
+		t.Fatal("resolveOrder constructed a plugin")
+		return nil
+	}
+	Register("order-core", build)
+	Register("order-addon", build, "order-core")
+	Register("order-extra", build, "order-addon")
+	Register("order-orphan", build, "order-missing")
+
+	m := NewManager(nil, command.NewCommandMap(), t.TempDir())
+	got := m.resolveOrder(map[string]string{
+		"order-extra":  "builtin",
+		"order-addon":  "builtin",
+		"order-core":   "builtin",
+		"order-orphan": "builtin",
+	})
+	want := []string{"order-core", "order-addon", "order-extra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolveOrder = %v, want %v", got, want)
+	}
+}
+
+func TestReloadReenablesDependents(t *testing.T) {
+	var enabled []string
+	for _, reg := range []struct {
+		name   string
+		depend []string
+	}{
+		{"reload-base", nil},
+		{"reload-mid", []string{"reload-base"}},
+		{"reload-top", []string{"reload-mid"}},
+	} {
+		name := reg.name
+		Register(name, func() Plugin { return &recordingPlugin{name: name, enabled: &enabled} }, reg.depend...)
+	}
+
+	m := NewManager(nil, command.NewCommandMap(), t.TempDir())
+	for _, name := range []string{"reload-base", "reload-mid", "reload-top"} {
+		if err := m.EnablePlugin(name); err != nil {
+			t.Fatalf("EnablePlugin(%s): %v", name, err)
+		}
+	}
+
+	enabled = nil
+	if err := m.ReloadPlugin("reload-base"); err != nil {
+		t.Fatalf("ReloadPlugin: %v", err)
+	}
+	want := []string{"reload-base", "reload-mid", "reload-top"}
+	if !reflect.DeepEqual(enabled, want) {
+		t.Fatalf("re-enabled %v, want %v", enabled, want)
+	}
+	for _, name := range want {
+		if !m.IsPluginEnabled(name) {
+			t.Errorf("%s is disabled after reload", name)
+		}
+	}
+}
+
+func TestEnableFailsWithoutDataDir(t *testing.T) {
+	var enabled []string
+	Register("datadir", func() Plugin { return &recordingPlugin{name: "datadir", enabled: &enabled} })
+
+	blocker := filepath.Join(t.TempDir(), "plugins")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(nil, command.NewCommandMap(), blocker)
+	if err := m.EnablePlugin("datadir"); err == nil {
+		t.Fatal("EnablePlugin succeeded without a data folder")
+	}
+	if len(enabled) != 0 || m.IsPluginEnabled("datadir") {
+		t.Fatal("OnEnable ran without a data folder")
+	}
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/player"
+)
+
+type Plugin interface {
+	Name() string
+	Version() string
+	OnEnable(ctx *Context) error
+	OnDisable()
+}
+
+type Server interface {
+	GetServerName() string
+	BroadcastMessage(message string)
+	GetPlayer(username string) *player.Player
+	GetOnlinePlayers() []*player.Player
+	GetDefaultLevel() *level.Level
+	GetLevel(name string) *level.Level
+	GetLevelNames() []string
+	GetCurrentTick() int64
+	Stop()
+}
+
+type Factory func() Plugin
+
+type registration struct {
+	factory Factory
+	depend  []string
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]registration)
+)
+
+// Register makes a plugin available to the manager. depend lists the
+// plugins that must be enabled before it; they are read from here so the
+// load order can be worked out without constructing any plugin.
+func Register(name string, factory Factory, depend ...string) {
+	registryMu.Lock()
+	registry[name] = registration{factory: factory, depend: depend}
+	registryMu.Unlock()
+}
+
+func RegisteredPlugins() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getFactory(name string) Factory {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return registry[name].factory
+}
+
+func getDepend(name string) []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return registry[name].depend
+}
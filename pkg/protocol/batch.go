@@ -3,10 +3,7 @@ package protocol
 import (
 	"bytes"
 	"compress/zlib"
-	"encoding/binary"
 	"fmt"
-
-	"github.com/scaxe/scaxe-go/pkg/logger"
 )
 
 type BatchPacket struct {
@@ -52,35 +49,7 @@ func (p *BatchPacket) Decode(stream *BinaryStream) error {
 }
 
 func CreateBatch(packets []DataPacket) ([]byte, error) {
-	var rawBuf bytes.Buffer
-
-	for _, pkt := range packets {
-
-		stream := NewBinaryStream()
-
-		err := pkt.Encode(stream)
-		if err != nil {
-			logger.Error("CreateBatch", "error", err, "packet", pkt.Name())
-			continue
-		}
-		pktData := stream.Bytes()
-
-		binary.Write(&rawBuf, binary.BigEndian, uint32(len(pktData)))
-
-		rawBuf.Write(pktData)
-	}
-
-	var compressedBuf bytes.Buffer
-
-	w := zlib.NewWriter(&compressedBuf)
-	_, err := w.Write(rawBuf.Bytes())
-	if err != nil {
-		w.Close()
-		return nil, err
-	}
-	w.Close()
-
-	return compressedBuf.Bytes(), nil
+	return DefaultCodec.CreateBatch(packets)
 }
 
 func (p *BatchPacket) Compress(data []byte) error {
@@ -110,49 +79,7 @@ func (p *BatchPacket) Decompress() ([]byte, error) {
 }
 
 func DecodePackets(data []byte) ([]DataPacket, error) {
-	var packets []DataPacket
-	buf := bytes.NewBuffer(data)
-
-	for buf.Len() > 0 {
-
-		if buf.Len() < 4 {
-			break
-		}
-		var length uint32
-		if err := binary.Read(buf, binary.BigEndian, &length); err != nil {
-			return nil, err
-		}
-
-		if int64(length) > int64(buf.Len()) {
-			break
-		}
-
-		pktData := make([]byte, length)
-		if _, err := buf.Read(pktData); err != nil {
-			return nil, err
-		}
-
-		if len(pktData) == 0 {
-			continue
-		}
-		pid := pktData[0]
-
-		pkt := GetPacket(pid)
-		if pkt == nil {
-
-			continue
-		}
-
-		stream := NewBinaryStreamFromBytes(pktData[1:])
-		if err := pkt.Decode(stream); err != nil {
-			logger.Error("DecodePackets", "error", err, "packetID", pid)
-			continue
-		}
-
-		packets = append(packets, pkt)
-	}
-
-	return packets, nil
+	return DefaultCodec.DecodePackets(data)
 }
 
 func init() {
@@ -564,11 +564,11 @@ func (b *BinaryStream) ReadSlot() (item.Item, error) {
 			return item.Item{}, err
 		}
 	}
-	_ = nbtData
 
 	return item.Item{
-		ID:    int(id),
-		Count: count,
-		Meta:  int(meta),
+		ID:      int(id),
+		Count:   count,
+		Meta:    int(meta),
+		NBTData: item.ParseCompoundTag(nbtData),
 	}, nil
 }
@@ -28,7 +28,6 @@ func (p *BlockEntityDataPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *BlockEntityDataPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.X, err = stream.ReadInt()
 	if err != nil {
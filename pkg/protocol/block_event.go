@@ -30,7 +30,6 @@ func (p *BlockEventPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *BlockEventPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.X, err = stream.ReadInt()
 	if err != nil {
@@ -40,8 +40,6 @@ func (p *ChangeDimensionPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *ChangeDimensionPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
-	var err error
 	dim, err := stream.ReadInt()
 	if err != nil {
 		return err
@@ -0,0 +1,235 @@
+package protocol
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+var ErrUnsupportedProtocol = errors.New("unsupported protocol version")
+
+// Layout replaces a packet's own Encode/Decode for one game version. Encode
+// is called after the wire ID has been written and Decode after it has been
+// read, mirroring DataPacket.
+type Layout struct {
+	Encode func(pk DataPacket, stream *BinaryStream) error
+	Decode func(pk DataPacket, stream *BinaryStream) error
+}
+
+// Codec maps the shared packet structs onto the wire format of one game
+// version. The structs themselves always use the protocol 70 IDs and field
+// layouts; a codec translates IDs in both directions and swaps in a Layout
+// for packets whose fields differ.
+type Codec struct {
+	Name      string
+	Protocols []int
+
+	toWire   map[byte]byte
+	fromWire map[byte]byte
+	layouts  map[byte]Layout
+}
+
+func NewCodec(name string, protocols ...int) *Codec {
+	return &Codec{
+		Name:      name,
+		Protocols: protocols,
+		toWire:    make(map[byte]byte),
+		fromWire:  make(map[byte]byte),
+		layouts:   make(map[byte]Layout),
+	}
+}
+
+func (c *Codec) String() string {
+	return c.Name
+}
+
+// Map sends packet id as wire on this version.
+func (c *Codec) Map(id, wire byte) {
+	c.toWire[id] = wire
+	c.fromWire[wire] = id
+}
+
+func (c *Codec) SetLayout(id byte, layout Layout) {
+	c.layouts[id] = layout
+}
+
+func (c *Codec) Supports(protocol int) bool {
+	for _, p := range c.Protocols {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Codec) WireID(id byte) (byte, bool) {
+	wire, ok := c.toWire[id]
+	return wire, ok
+}
+
+func (c *Codec) PacketID(wire byte) (byte, bool) {
+	id, ok := c.fromWire[wire]
+	return id, ok
+}
+
+func (c *Codec) Encode(pk DataPacket) ([]byte, error) {
+	stream := NewBinaryStream()
+	if layout, ok := c.layouts[pk.ID()]; ok && layout.Encode != nil {
+		stream.WriteByte(c.toWire[pk.ID()])
+		if err := layout.Encode(pk, stream); err != nil {
+			return nil, err
+		}
+		return stream.Bytes(), nil
+	}
+
+	if err := pk.Encode(stream); err != nil {
+		return nil, err
+	}
+	data := stream.Bytes()
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s encoded to nothing", pk.Name())
+	}
+	wire, ok := c.toWire[data[0]]
+	if !ok {
+		return nil, fmt.Errorf("%s does not exist in %s", pk.Name(), c.Name)
+	}
+	data[0] = wire
+	return data, nil
+}
+
+// Decode returns nil without an error for IDs this version does not define.
+func (c *Codec) Decode(data []byte) (DataPacket, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty packet")
+	}
+	id, ok := c.fromWire[data[0]]
+	if !ok {
+		return nil, nil
+	}
+	pk := GetPacket(id)
+	if pk == nil {
+		return nil, nil
+	}
+
+	stream := NewBinaryStreamFromBytes(data[1:])
+	if layout, ok := c.layouts[id]; ok && layout.Decode != nil {
+		return pk, layout.Decode(pk, stream)
+	}
+	return pk, pk.Decode(stream)
+}
+
+func (c *Codec) CreateBatch(packets []DataPacket) ([]byte, error) {
+	var raw bytes.Buffer
+	for _, pk := range packets {
+		data, err := c.Encode(pk)
+		if err != nil {
+			logger.Error("CreateBatch", "error", err, "packet", pk.Name())
+			continue
+		}
+		binary.Write(&raw, binary.BigEndian, uint32(len(data)))
+		raw.Write(data)
+	}
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(raw.Bytes()); err != nil {
+		w.Close()
+		return nil, err
+	}
+	w.Close()
+	return compressed.Bytes(), nil
+}
+
+func (c *Codec) DecodePackets(data []byte) ([]DataPacket, error) {
+	var packets []DataPacket
+	for len(data) >= 4 {
+		n := binary.BigEndian.Uint32(data)
+		if uint64(n) > uint64(len(data)-4) {
+			break
+		}
+		payload := data[4 : 4+n]
+		data = data[4+n:]
+		if n == 0 {
+			continue
+		}
+
+		pk, err := c.Decode(payload)
+		if err != nil {
+			logger.Error("DecodePackets", "error", err, "packetID", payload[0])
+			continue
+		}
+		if pk != nil {
+			packets = append(packets, pk)
+		}
+	}
+	return packets, nil
+}
+
+var (
+	codecs []*Codec
+
+	// DefaultCodec is used for sessions that have not logged in yet.
+	DefaultCodec *Codec
+)
+
+func RegisterCodec(c *Codec) {
+	codecs = append(codecs, c)
+	if DefaultCodec == nil || c.Supports(ProtocolCurrent) {
+		DefaultCodec = c
+	}
+}
+
+func Codecs() []*Codec {
+	return append([]*Codec(nil), codecs...)
+}
+
+func CodecFor(protocol int) (*Codec, error) {
+	for _, c := range codecs {
+		if c.Supports(protocol) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnsupportedProtocol, protocol)
+}
+
+func SupportedProtocols() []int {
+	var protocols []int
+	for _, c := range codecs {
+		protocols = append(protocols, c.Protocols...)
+	}
+	sort.Ints(protocols)
+	return protocols
+}
+
+// RejectStatus is the PlayStatus sent to a client whose protocol has no
+// codec, telling it whether the client or the server is out of date.
+func RejectStatus(protocol int) int32 {
+	if supported := SupportedProtocols(); len(supported) > 0 && protocol < supported[0] {
+		return PlayStatusLoginFailedClient
+	}
+	return PlayStatusLoginFailedServer
+}
+
+// LoginProtocol reads the protocol version from a login payload (without
+// the packet ID) before the codec is known. Up to 0.14 the payload starts
+// with the username; from 0.15 on it starts with the version itself, which
+// is always below the smallest length-prefixed username.
+func LoginProtocol(payload []byte) (int, error) {
+	if len(payload) < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if v := binary.BigEndian.Uint32(payload); v < 0x10000 {
+		return int(v), nil
+	}
+	n := int(binary.BigEndian.Uint16(payload))
+	if len(payload) < 2+n+4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return int(int32(binary.BigEndian.Uint32(payload[2+n:]))), nil
+}
@@ -0,0 +1,12 @@
+package protocol
+
+// Codec014 speaks 0.14.x, whose IDs and layouts are the ones the packet
+// structs are written against.
+var Codec014 = NewCodec("0.14", 41, 42, 43, 44, 45, 46, 60, 70)
+
+func init() {
+	for id := IDLogin; id <= IDReplaceSelectedItem; id++ {
+		Codec014.Map(id, id)
+	}
+	RegisterCodec(Codec014)
+}
@@ -0,0 +1,280 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type codecCase struct {
+	packet DataPacket
+	wire   byte
+}
+
+func sharedPackets() []DataPacket {
+	text := NewTextPacket()
+	text.TextType = TextTypeTranslation
+	text.Message = "chat.type.announcement"
+	text.Parameters = []string{"Server", "hello"}
+
+	chat := NewTextPacket()
+	chat.TextType = TextTypeChat
+	chat.SourceName = "Alice"
+	chat.Message = "hi"
+
+	start := NewStartGamePacket()
+	start.Seed = 1234
+	start.Generator = 2
+	start.Gamemode = 1
+	start.EntityID = 7
+	start.RuntimeID = 7
+	start.SpawnX, start.SpawnY, start.SpawnZ = 128, 4, -64
+	start.X, start.Y, start.Z = 128.5, 5.62, -63.5
+	start.LevelID = "world"
+
+	chunk := NewFullChunkDataPacket()
+	chunk.ChunkX, chunk.ChunkZ = -3, 9
+	chunk.Order = ChunkOrderLayered
+	chunk.Data = []byte{1, 2, 3, 4, 5}
+
+	move := NewMovePlayerPacket()
+	move.EntityID = 7
+	move.X, move.Y, move.Z = 1, 2, 3
+	move.Yaw, move.BodyYaw, move.Pitch = 90, 90, -10
+	move.OnGround = true
+
+	player := NewAddPlayerPacket()
+	player.UUID = "00112233-4455-6677-8899-aabbccddeeff"
+	player.Username = "Alice"
+	player.EntityID = 7
+
+	return []DataPacket{text, chat, start, chunk, move, player, NewPlayStatusPacket(), NewDisconnectPacket(), NewSetTimePacket()}
+}
+
+func runCodecCases(t *testing.T, codec *Codec, cases []codecCase) {
+	for _, tc := range cases {
+		t.Run(tc.packet.Name(), func(t *testing.T) {
+			data, err := codec.Encode(tc.packet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data[0] != tc.wire {
+				t.Fatalf("wire ID = 0x%02x, want 0x%02x", data[0], tc.wire)
+			}
+
+			stream := NewBinaryStream()
+			tc.packet.Encode(stream)
+			if !bytes.Equal(data[1:], stream.Bytes()[1:]) {
+				t.Errorf("%s body differs from the shared layout", codec.Name)
+			}
+
+			id, ok := codec.PacketID(tc.wire)
+			if !ok || id != tc.packet.ID() {
+				t.Fatalf("PacketID(0x%02x) = 0x%02x, %v", tc.wire, id, ok)
+			}
+			if _, isAddPlayer := tc.packet.(*AddPlayerPacket); isAddPlayer {
+				return
+			}
+			decoded, err := codec.Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, tc.packet) {
+				t.Errorf("decoded %+v, want %+v", decoded, tc.packet)
+			}
+		})
+	}
+}
+
+func testLogin(t *testing.T, codec *Codec, version int32) {
+	uuid := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	login := NewLoginPacket()
+	login.Username = "Alice"
+	login.Protocol = version
+	login.ClientID = 42
+	login.ClientUUID = string(uuid)
+	login.ServerAddress = "127.0.0.1:19132"
+	login.SkinID = "Standard_Steve"
+	login.SkinData = []byte{0xff, 0, 0x7f, 1}
+
+	data, err := codec.Encode(login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != IDLogin {
+		t.Fatalf("login wire ID = 0x%02x", data[0])
+	}
+	got, err := LoginProtocol(data[1:])
+	if err != nil || got != int(version) {
+		t.Fatalf("LoginProtocol = %d, %v, want %d", got, err, version)
+	}
+
+	pk, err := codec.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := pk.(*LoginPacket)
+	if decoded.Username != login.Username || decoded.Protocol != version || decoded.ClientID != login.ClientID {
+		t.Errorf("decoded %q protocol %d client %d", decoded.Username, decoded.Protocol, decoded.ClientID)
+	}
+	if decoded.ClientUUID != formatUUID(uuid) {
+		t.Errorf("uuid = %q, want %q", decoded.ClientUUID, formatUUID(uuid))
+	}
+	if decoded.SkinID != login.SkinID || !bytes.Equal(decoded.SkinData, login.SkinData) || decoded.ServerAddress != login.ServerAddress {
+		t.Errorf("decoded skin %q %x, address %q", decoded.SkinID, decoded.SkinData, decoded.ServerAddress)
+	}
+}
+
+func TestCodec014(t *testing.T) {
+	var cases []codecCase
+	for _, pk := range sharedPackets() {
+		cases = append(cases, codecCase{pk, pk.ID()})
+	}
+	runCodecCases(t, Codec014, cases)
+
+	for _, version := range []int32{45, 60, 70} {
+		testLogin(t, Codec014, version)
+	}
+}
+
+func TestCodec015(t *testing.T) {
+	wire := map[byte]byte{
+		IDText:          0x93,
+		IDStartGame:     0x95,
+		IDFullChunkData: 0xc0,
+		IDMovePlayer:    0x9d,
+		IDAddPlayer:     0x96,
+		IDPlayStatus:    0x90,
+		IDDisconnect:    0x91,
+		IDSetTime:       0x94,
+	}
+	var cases []codecCase
+	for _, pk := range sharedPackets() {
+		cases = append(cases, codecCase{pk, wire[pk.ID()]})
+	}
+	runCodecCases(t, Codec015, cases)
+
+	if pk, err := Codec015.Decode([]byte{IDRiderJump015, 0, 0, 0, 0}); pk != nil || err != nil {
+		t.Errorf("rider jump decoded as %v, %v", pk, err)
+	}
+	for _, version := range []int32{81, 84} {
+		testLogin(t, Codec015, version)
+	}
+}
+
+// login_0_15.hex follows the 0.15 client layout byte for byte: an ES384
+// identity chain, a 64x32 skin and a ClientRandomId outside float64 range.
+func TestCodec015LoginFixture(t *testing.T) {
+	text, err := os.ReadFile("testdata/login_0_15.hex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := hex.DecodeString(strings.Join(strings.Fields(string(text)), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoginProtocol(data[1:]); err != nil || got != 81 {
+		t.Fatalf("LoginProtocol = %d, %v, want 81", got, err)
+	}
+	pk, err := Codec015.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := pk.(*LoginPacket)
+	if login.Username != "Steve_1987" || login.ClientUUID != "a8b6ad3e-8a51-3b62-9e7f-8f7c3dde7b1a" {
+		t.Errorf("identity = %q %q", login.Username, login.ClientUUID)
+	}
+	if login.ClientID != -6147253495473283043 {
+		t.Errorf("ClientID = %d", login.ClientID)
+	}
+	if login.SkinID != "Standard_Custom" || len(login.SkinData) != 64*32*4 {
+		t.Errorf("skin %q with %d bytes", login.SkinID, len(login.SkinData))
+	}
+	if login.ServerAddress != "192.168.1.20:19132" {
+		t.Errorf("ServerAddress = %q", login.ServerAddress)
+	}
+}
+
+func TestCodecFor(t *testing.T) {
+	tests := []struct {
+		protocol int
+		codec    *Codec
+		status   int32
+	}{
+		{38, nil, PlayStatusLoginFailedClient},
+		{46, Codec014, 0},
+		{70, Codec014, 0},
+		{81, Codec015, 0},
+		{84, Codec015, 0},
+		{91, nil, PlayStatusLoginFailedServer},
+	}
+	for _, tc := range tests {
+		codec, err := CodecFor(tc.protocol)
+		if codec != tc.codec {
+			t.Errorf("CodecFor(%d) = %v, want %v", tc.protocol, codec, tc.codec)
+		}
+		if tc.codec == nil {
+			if !errors.Is(err, ErrUnsupportedProtocol) {
+				t.Errorf("CodecFor(%d) error = %v", tc.protocol, err)
+			}
+			if got := RejectStatus(tc.protocol); got != tc.status {
+				t.Errorf("RejectStatus(%d) = %d, want %d", tc.protocol, got, tc.status)
+			}
+		}
+	}
+	if DefaultCodec != Codec014 {
+		t.Errorf("DefaultCodec = %v", DefaultCodec)
+	}
+}
+
+// Decode is handed the body without the ID byte, so every field below would
+// be shifted by one if a packet read its own header again.
+func TestDecodeRoundTrip(t *testing.T) {
+	armor := NewHurtArmorPacket()
+	armor.Health = 17
+
+	tile := NewBlockEntityDataPacket()
+	tile.X, tile.Y, tile.Z, tile.NBTData = 10, 64, -3, []byte{10, 0, 0}
+
+	event := NewBlockEventPacket()
+	event.X, event.Y, event.Z, event.Case1, event.Case2 = 1, 2, 3, 1, 2
+
+	level := NewLevelEventPacket()
+	level.EventID, level.X, level.Y, level.Z, level.Data = 2001, 1.5, 64, -2.5, 9
+
+	data := NewContainerSetDataPacket()
+	data.WindowID, data.Property, data.Value = 2, 1, 200
+
+	take := NewTakeItemEntityPacket()
+	take.Target, take.EntityID = 5, 7
+
+	explode := NewExplodePacket()
+	explode.X, explode.Y, explode.Z, explode.Radius = 1, 2, 3, 4
+	explode.Records = []ExplodeRecord{{X: 1, Y: -1, Z: 0}}
+
+	link := NewSetEntityLinkPacket()
+	link.From, link.To, link.LinkType = 3, 4, 1
+
+	dimension := NewChangeDimensionPacket()
+	dimension.Dimension, dimension.X, dimension.Y, dimension.Z = 1, 8, 70, 8
+
+	for _, pk := range []DataPacket{armor, tile, event, level, data, take, explode, link, dimension} {
+		t.Run(pk.Name(), func(t *testing.T) {
+			encoded, err := Codec014.Encode(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := Codec014.Decode(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, pk) {
+				t.Errorf("decoded %+v, want %+v", decoded, pk)
+			}
+		})
+	}
+}
@@ -26,7 +26,6 @@ func (p *ContainerSetDataPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *ContainerSetDataPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.WindowID, err = stream.ReadByte()
 	if err != nil {
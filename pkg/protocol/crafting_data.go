@@ -170,8 +170,6 @@ func (p *CraftingDataPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *CraftingDataPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
-
 	return nil
 }
 
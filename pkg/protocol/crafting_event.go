@@ -32,7 +32,6 @@ func (p *CraftingEventPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *CraftingEventPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 
 	p.WindowID, err = stream.ReadByte()
@@ -1,5 +1,16 @@
 package protocol
 
+const (
+	EntityEventHurtAnimation  byte = 2
+	EntityEventDeathAnimation byte = 3
+	EntityEventTameFail       byte = 6
+	EntityEventTameSuccess    byte = 7
+	EntityEventShakeWet       byte = 8
+	EntityEventUseItem        byte = 9
+	EntityEventEatGrass       byte = 10
+	EntityEventRespawn        byte = 18
+)
+
 type EntityEventPacket struct {
 	BasePacket
 	EntityID int64
@@ -42,7 +42,6 @@ func (p *ExplodePacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *ExplodePacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.X, err = stream.ReadFloat()
 	if err != nil {
@@ -22,7 +22,6 @@ func (p *HurtArmorPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *HurtArmorPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.Health, err = stream.ReadInt()
 	return err
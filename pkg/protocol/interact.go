@@ -30,7 +30,6 @@ func (p *InteractPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *InteractPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.Action, err = stream.ReadByte()
 	if err != nil {
@@ -51,6 +51,8 @@ const (
 	EventSoundSpell               int16 = 3504
 	EventSoundSplash              int16 = 3506
 	EventSoundGraySplash          int16 = 3507
+	EventBlockStartBreak          int16 = 3600
+	EventBlockStopBreak           int16 = 3601
 	EventSetData                  int16 = 4000
 	EventPlayersSleeping          int16 = 9800
 	EventAddParticleMask          int16 = 0x4000
@@ -86,7 +88,6 @@ func (p *LevelEventPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *LevelEventPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.EventID, err = stream.ReadBEUShort()
 	if err != nil {
@@ -3,6 +3,7 @@ package protocol
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/scaxe/scaxe-go/pkg/logger"
 )
@@ -189,7 +190,9 @@ func (p *LoginPacket) parseClientData() {
 	}
 
 	var claims map[string]interface{}
-	if err := json.Unmarshal([]byte(payload), &claims); err != nil {
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
 		logger.Error("LoginPacket.parseClientData", "error", err)
 		return
 	}
@@ -209,11 +212,22 @@ func (p *LoginPacket) parseClientData() {
 		logger.DebugPacket("LoginPacket.parseClientData", "languageCode", langCode)
 	}
 
-	if deviceOS, ok := claims["DeviceOS"].(float64); ok {
-		p.DeviceOS = int32(deviceOS)
+	if deviceOS, ok := claims["DeviceOS"].(json.Number); ok {
+		n, _ := deviceOS.Int64()
+		p.DeviceOS = int32(n)
 		logger.DebugPacket("LoginPacket.parseClientData", "deviceOS", p.DeviceOS)
 	}
 
+	if skinData, ok := claims["SkinData"].(string); ok {
+		if data, err := base64Decode(skinData); err == nil {
+			p.SkinData = data
+		}
+	}
+
+	if clientID, ok := claims["ClientRandomId"].(json.Number); ok {
+		p.ClientID, _ = clientID.Int64()
+	}
+
 	if deviceModel, ok := claims["DeviceModel"].(string); ok {
 		p.DeviceModel = deviceModel
 		logger.DebugPacket("LoginPacket.parseClientData", "deviceModel", deviceModel)
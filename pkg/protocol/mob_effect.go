@@ -1,5 +1,11 @@
 package protocol
 
+const (
+	MobEffectEventAdd    byte = 1
+	MobEffectEventModify byte = 2
+	MobEffectEventRemove byte = 3
+)
+
 type MobEffectPacket struct {
 	BasePacket
 	EntityID  int64
@@ -16,24 +16,9 @@ const (
 	Protocol_0_15_First = 81
 )
 
-var AcceptedProtocols = []int{
-
-	34,
-
-	37, 38, 39,
-
-	41, 42, 43, 44, 45, 46, 60, 70,
-
-	81, 82, 83, 84,
-}
-
 func IsProtocolSupported(protocol int) bool {
-	for _, p := range AcceptedProtocols {
-		if p == protocol {
-			return true
-		}
-	}
-	return false
+	_, err := CodecFor(protocol)
+	return err == nil
 }
 
 const (
@@ -24,7 +24,6 @@ func (p *PlayerInputPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *PlayerInputPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.MotionX, err = stream.ReadFloat()
 	if err != nil {
@@ -32,7 +32,6 @@ func (p *SetEntityLinkPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *SetEntityLinkPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.From, err = stream.ReadLong()
 	if err != nil {
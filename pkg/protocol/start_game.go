@@ -56,8 +56,50 @@ func (p *StartGamePacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *StartGamePacket) Decode(stream *BinaryStream) error {
-
-	return nil
+	var err error
+	if p.Seed, err = stream.ReadInt(); err != nil {
+		return err
+	}
+	if p.Dimension, err = stream.ReadByte(); err != nil {
+		return err
+	}
+	if p.Generator, err = stream.ReadInt(); err != nil {
+		return err
+	}
+	if p.Gamemode, err = stream.ReadInt(); err != nil {
+		return err
+	}
+	if p.EntityID, err = stream.ReadLong(); err != nil {
+		return err
+	}
+	runtimeID, err := stream.ReadUnsignedVarLong()
+	if err != nil {
+		return err
+	}
+	p.RuntimeID = int64(runtimeID)
+	if p.SpawnX, err = stream.ReadInt(); err != nil {
+		return err
+	}
+	if p.SpawnY, err = stream.ReadInt(); err != nil {
+		return err
+	}
+	if p.SpawnZ, err = stream.ReadInt(); err != nil {
+		return err
+	}
+	if p.X, err = stream.ReadFloat(); err != nil {
+		return err
+	}
+	if p.Y, err = stream.ReadFloat(); err != nil {
+		return err
+	}
+	if p.Z, err = stream.ReadFloat(); err != nil {
+		return err
+	}
+	if err = stream.Skip(3); err != nil {
+		return err
+	}
+	p.LevelID, err = stream.ReadString16()
+	return err
 }
 
 func init() {
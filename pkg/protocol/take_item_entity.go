@@ -25,7 +25,6 @@ func (p *TakeItemEntityPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *TakeItemEntityPacket) Decode(stream *BinaryStream) error {
-	DecodeHeader(stream, p.ID())
 	var err error
 	p.EntityID, err = stream.ReadLong()
 	if err != nil {
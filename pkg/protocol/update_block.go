@@ -63,5 +63,32 @@ func (p *UpdateBlockPacket) Encode(stream *BinaryStream) error {
 }
 
 func (p *UpdateBlockPacket) Decode(stream *BinaryStream) error {
+	count, err := stream.ReadInt()
+	if err != nil {
+		return err
+	}
+	p.Records = nil
+	for i := int32(0); i < count; i++ {
+		var r BlockRecord
+		if r.X, err = stream.ReadInt(); err != nil {
+			return err
+		}
+		if r.Z, err = stream.ReadInt(); err != nil {
+			return err
+		}
+		if r.Y, err = stream.ReadByte(); err != nil {
+			return err
+		}
+		if r.BlockID, err = stream.ReadByte(); err != nil {
+			return err
+		}
+		packed, err := stream.ReadByte()
+		if err != nil {
+			return err
+		}
+		r.Flags = packed >> 4
+		r.BlockMeta = packed & 0x0F
+		p.Records = append(p.Records, r)
+	}
 	return nil
 }
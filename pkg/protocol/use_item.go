@@ -25,6 +25,20 @@ func (p *UseItemPacket) Name() string {
 }
 
 func (p *UseItemPacket) Encode(stream *BinaryStream) error {
+	EncodeHeader(stream, p.ID())
+	stream.WriteInt(p.X)
+	stream.WriteInt(p.Y)
+	stream.WriteInt(p.Z)
+	stream.WriteByte(p.Face)
+	stream.WriteFloat(p.FX)
+	stream.WriteFloat(p.FY)
+	stream.WriteFloat(p.FZ)
+	stream.WriteFloat(p.PosX)
+	stream.WriteFloat(p.PosY)
+	stream.WriteFloat(p.PosZ)
+	stream.WriteShort(0)
+	stream.WriteShort(int16(p.Slot))
+	stream.WriteSlot(p.Item)
 	return nil
 }
 
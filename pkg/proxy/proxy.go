@@ -0,0 +1,265 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/network"
+)
+
+const dialTimeout = 5 * time.Second
+
+// firstEntityID keeps proxy-assigned player IDs clear of the IDs backends
+// hand out to their own entities.
+const firstEntityID int64 = 1 << 40
+
+type Backend struct {
+	Name    string
+	Address string
+}
+
+type Config struct {
+	Address    string
+	Transport  string
+	Secret     string
+	Backends   []Backend
+	MOTD       string
+	MaxPlayers int
+	// DuplicateLogin is "kick-old" or "kick-new", as for the server.
+	DuplicateLogin string
+}
+
+const duplicateLoginKickNew = "kick-new"
+
+// ParseBackends reads "name=host:port" pairs separated by commas. The first
+// backend is where players land after logging in.
+func ParseBackends(list string) ([]Backend, error) {
+	var backends []Backend
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, address, ok := strings.Cut(entry, "=")
+		name, address = strings.TrimSpace(name), strings.TrimSpace(address)
+		if !ok || name == "" || address == "" {
+			return nil, fmt.Errorf("proxy: invalid server entry %q, want name=host:port", entry)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("proxy: server %q listed twice", name)
+		}
+		seen[strings.ToLower(name)] = true
+		backends = append(backends, Backend{Name: name, Address: address})
+	}
+	if len(backends) == 0 {
+		return nil, errors.New("proxy: no servers configured")
+	}
+	return backends, nil
+}
+
+// Proxy terminates client sessions and relays them to backend servers over
+// authenticated links, moving players between backends on request.
+type Proxy struct {
+	cfg       Config
+	transport network.Transport
+
+	mu       sync.RWMutex
+	sessions map[network.Conn]*session
+
+	nextEntityID int64
+}
+
+func New(cfg Config) (*Proxy, error) {
+	if cfg.Secret == "" {
+		return nil, network.ErrLinkNoSecret
+	}
+	if len(cfg.Backends) == 0 {
+		return nil, errors.New("proxy: no servers configured")
+	}
+	transport, err := network.New(cfg.Transport, cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := transport.(*network.LinkTransport); ok {
+		return nil, errors.New("proxy: cannot listen for clients on a proxy link")
+	}
+	return &Proxy{
+		cfg:          cfg,
+		transport:    transport,
+		sessions:     make(map[network.Conn]*session),
+		nextEntityID: firstEntityID,
+	}, nil
+}
+
+func (p *Proxy) Start() error {
+	p.transport.SetHandler(network.Handler{
+		OnConnect:    p.handleConnect,
+		OnDisconnect: p.handleDisconnect,
+		OnPacket:     p.handlePacket,
+	})
+	if err := p.transport.Start(); err != nil {
+		return err
+	}
+	p.updatePong()
+
+	names := make([]string, len(p.cfg.Backends))
+	for i, b := range p.cfg.Backends {
+		names[i] = b.Name + "=" + b.Address
+	}
+	logger.Server("Proxy started", "address", p.transport.Addr().String(), "servers", strings.Join(names, ", "))
+	return nil
+}
+
+func (p *Proxy) Stop() {
+	p.transport.Stop()
+	for _, s := range p.sessionList() {
+		s.close()
+	}
+}
+
+func (p *Proxy) Addr() net.Addr {
+	return p.transport.Addr()
+}
+
+func (p *Proxy) Transport() network.Transport {
+	return p.transport
+}
+
+func (p *Proxy) backend(name string) (Backend, bool) {
+	for _, b := range p.cfg.Backends {
+		if strings.EqualFold(b.Name, name) {
+			return b, true
+		}
+	}
+	return Backend{}, false
+}
+
+func (p *Proxy) backendNames() []string {
+	names := make([]string, len(p.cfg.Backends))
+	for i, b := range p.cfg.Backends {
+		names[i] = b.Name
+	}
+	return names
+}
+
+func (p *Proxy) handleConnect(conn network.Conn) {
+	s := newSession(p, conn, atomic.AddInt64(&p.nextEntityID, 1))
+	p.mu.Lock()
+	p.sessions[conn] = s
+	p.mu.Unlock()
+	logger.Debug("Proxy connection", "address", conn.Address())
+}
+
+func (p *Proxy) handleDisconnect(conn network.Conn) {
+	p.mu.Lock()
+	s := p.sessions[conn]
+	delete(p.sessions, conn)
+	p.mu.Unlock()
+
+	if s != nil {
+		s.close()
+		if s.username() != "" {
+			logger.Server("Player left the proxy", "player", s.username(), "server", s.serverName())
+		}
+		p.updatePong()
+	}
+}
+
+func (p *Proxy) handlePacket(conn network.Conn, data []byte) {
+	p.mu.RLock()
+	s := p.sessions[conn]
+	p.mu.RUnlock()
+	if s != nil && len(data) > 0 {
+		s.handleClient(data)
+	}
+}
+
+func (p *Proxy) sessionList() []*session {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	list := make([]*session, 0, len(p.sessions))
+	for _, s := range p.sessions {
+		list = append(list, s)
+	}
+	return list
+}
+
+func (p *Proxy) find(username string) *session {
+	for _, s := range p.sessionList() {
+		if strings.EqualFold(s.username(), username) {
+			return s
+		}
+	}
+	return nil
+}
+
+func (p *Proxy) onlineCount() int {
+	n := 0
+	for _, s := range p.sessionList() {
+		if s.username() != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (p *Proxy) updatePong() {
+	motd := fmt.Sprintf("MCPE;%s;%d;%s;%d;%d;%d;%s;Survival",
+		p.cfg.MOTD, 60, "0.14.2", p.onlineCount(), p.cfg.MaxPlayers, p.transport.ServerID(), "Proxy")
+	p.transport.SetPongData([]byte(motd))
+}
+
+// Transfer moves an online player to the named backend.
+func (p *Proxy) Transfer(username, server string) error {
+	s := p.find(username)
+	if s == nil {
+		return fmt.Errorf("%s is not online", username)
+	}
+	b, ok := p.backend(server)
+	if !ok {
+		return fmt.Errorf("unknown server %q", server)
+	}
+	return s.connect(b)
+}
+
+// HandleConsoleCommand understands the few commands a proxy needs: list,
+// servers and send <player> <server>.
+func (p *Proxy) HandleConsoleCommand(line string) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return
+	}
+	switch strings.ToLower(strings.TrimPrefix(args[0], "/")) {
+	case "list":
+		var lines []string
+		for _, s := range p.sessionList() {
+			if s.username() != "" {
+				lines = append(lines, s.username()+" ("+s.serverName()+")")
+			}
+		}
+		sort.Strings(lines)
+		logger.Server(fmt.Sprintf("%d players online", len(lines)), "players", strings.Join(lines, ", "))
+	case "servers":
+		for _, b := range p.cfg.Backends {
+			logger.Server("Server", "name", b.Name, "address", b.Address)
+		}
+	case "send":
+		if len(args) != 3 {
+			logger.Warn("Usage: send <player> <server>")
+			return
+		}
+		if err := p.Transfer(args[1], args[2]); err != nil {
+			logger.Warn("Cannot move player", "player", args[1], "error", err)
+		}
+	default:
+		logger.Warn("Unknown proxy command, try list, servers or send <player> <server>", "command", args[0])
+	}
+}
@@ -0,0 +1,31 @@
+package proxy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBackends(t *testing.T) {
+	tests := []struct {
+		list string
+		want []Backend
+		ok   bool
+	}{
+		{"lobby=127.0.0.1:19133", []Backend{{"lobby", "127.0.0.1:19133"}}, true},
+		{" lobby = 127.0.0.1:19133 , games=10.0.0.2:19134,", []Backend{{"lobby", "127.0.0.1:19133"}, {"games", "10.0.0.2:19134"}}, true},
+		{"", nil, false},
+		{"lobby", nil, false},
+		{"=127.0.0.1:19133", nil, false},
+		{"lobby=a:1,LOBBY=b:2", nil, false},
+	}
+	for _, tc := range tests {
+		got, err := ParseBackends(tc.list)
+		if (err == nil) != tc.ok {
+			t.Errorf("ParseBackends(%q) error = %v", tc.list, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseBackends(%q) = %v, want %v", tc.list, got, tc.want)
+		}
+	}
+}
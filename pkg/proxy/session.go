@@ -0,0 +1,539 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+const eyeHeight = 1.62
+
+var errSessionClosed = errors.New("proxy: session closed")
+
+type trackedEntity struct {
+	player bool
+	uuid   uuid.UUID
+}
+
+// session is one client and the backend link currently serving it. The
+// proxy watches just enough of the backend's packets to undo them when the
+// player moves: entities and player list entries are removed, and the new
+// backend's StartGame, which the client would ignore, is replayed as a
+// dimension change and teleport before its chunks arrive.
+type session struct {
+	proxy    *Proxy
+	client   network.Conn
+	entityID int64
+
+	connectMu sync.Mutex
+
+	mu        sync.Mutex
+	codec     *protocol.Codec
+	login     []byte
+	radius    []byte
+	name      string
+	uuid      string
+	link      *network.LinkClient
+	server    string
+	spawned   bool
+	accepted  bool
+	replacing bool
+	switching bool
+	kicked    bool
+	closed    bool
+	entities  map[int64]trackedEntity
+	listed    map[string]bool
+}
+
+func newSession(p *Proxy, client network.Conn, entityID int64) *session {
+	return &session{
+		proxy:    p,
+		client:   client,
+		entityID: entityID,
+		codec:    protocol.DefaultCodec,
+		entities: make(map[int64]trackedEntity),
+		listed:   make(map[string]bool),
+	}
+}
+
+func (s *session) username() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.name
+}
+
+func (s *session) serverName() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.server
+}
+
+func (s *session) close() {
+	s.mu.Lock()
+	s.closed = true
+	link := s.link
+	s.link = nil
+	s.mu.Unlock()
+
+	if link != nil {
+		link.Close()
+	}
+}
+
+func (s *session) send(pk protocol.DataPacket) {
+	s.mu.Lock()
+	codec := s.codec
+	s.mu.Unlock()
+
+	data, err := codec.Encode(pk)
+	if err != nil {
+		logger.Error("Proxy failed to encode packet", "packet", pk.Name(), "error", err)
+		return
+	}
+	s.client.SendPacket(data)
+}
+
+func (s *session) kick(message string) {
+	pk := protocol.NewDisconnectPacket()
+	pk.Message = message
+	s.send(pk)
+	s.client.Close()
+}
+
+func (s *session) handleClient(data []byte) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	if s.login == nil {
+		s.mu.Unlock()
+		s.handleLogin(data)
+		return
+	}
+	if id, ok := s.codec.PacketID(data[0]); ok && id == protocol.IDRequestChunkRadius {
+		s.radius = append([]byte(nil), data...)
+	}
+	link, switching := s.link, s.switching
+	s.mu.Unlock()
+
+	if link != nil && !switching {
+		link.SendPacket(data)
+	}
+}
+
+func (s *session) handleLogin(data []byte) {
+	if data[0] != protocol.IDLogin {
+		return
+	}
+	version, err := protocol.LoginProtocol(data[1:])
+	if err == nil {
+		_, err = protocol.CodecFor(version)
+	}
+	if err != nil {
+		logger.Warn("Proxy rejected login", "address", s.client.Address(), "protocol", version, "error", err)
+		status := protocol.NewPlayStatusPacket()
+		status.Status = protocol.RejectStatus(version)
+		s.send(status)
+		s.client.Close()
+		return
+	}
+
+	codec, _ := protocol.CodecFor(version)
+	pk, err := codec.Decode(data)
+	login, ok := pk.(*protocol.LoginPacket)
+	if err != nil || !ok {
+		logger.Warn("Proxy could not decode login", "address", s.client.Address(), "error", err)
+		s.client.Close()
+		return
+	}
+
+	// An older session with this name is only replaced once the backend
+	// has accepted the new login, so a rejected login cannot kick anyone.
+	if old := s.proxy.find(login.Username); old != nil && old != s && s.proxy.cfg.DuplicateLogin == duplicateLoginKickNew {
+		logger.Info("Proxy rejected duplicate login", "player", login.Username, "address", s.client.Address())
+		s.kick("disconnectionScreen.loggedinOtherLocation")
+		return
+	}
+
+	s.mu.Lock()
+	s.codec = codec
+	s.login = append([]byte(nil), data...)
+	s.name, s.uuid = login.Username, login.ClientUUID
+	s.mu.Unlock()
+
+	logger.Server("Player joined the proxy", "player", login.Username, "address", s.client.Address(), "protocol", version)
+	s.proxy.updatePong()
+
+	first := s.proxy.cfg.Backends[0]
+	if err := s.connect(first); err != nil {
+		s.kick("Could not connect to " + first.Name)
+	}
+}
+
+// kickDuplicates disconnects other sessions using this session's name.
+func (s *session) kickDuplicates() {
+	name := s.username()
+	for _, other := range s.proxy.sessionList() {
+		if other != s && strings.EqualFold(other.username(), name) {
+			logger.Info("Proxy replacing session after duplicate login", "player", name, "old", other.client.Address(), "new", s.client.Address())
+			other.kick("disconnectionScreen.loggedinOtherLocation")
+		}
+	}
+}
+
+// connect links the session to b. The old backend is only dropped once the
+// new one has accepted the link, so a failed move leaves the player where
+// they were.
+func (s *session) connect(b Backend) error {
+	s.connectMu.Lock()
+	defer s.connectMu.Unlock()
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errSessionClosed
+	}
+	if s.link != nil && strings.EqualFold(s.server, b.Name) {
+		s.mu.Unlock()
+		return errors.New("already on " + b.Name)
+	}
+	hello := network.LinkHello{
+		Address:  s.client.Address(),
+		EntityID: s.entityID,
+		Username: s.name,
+		UUID:     s.uuid,
+		Server:   b.Name,
+		Servers:  s.proxy.backendNames(),
+	}
+	s.mu.Unlock()
+
+	link, err := network.DialLink(b.Address, s.proxy.cfg.Secret, hello, dialTimeout)
+	if err != nil {
+		logger.Warn("Proxy could not reach server", "player", hello.Username, "server", b.Name, "error", err)
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		link.Close()
+		return errSessionClosed
+	}
+	old := s.link
+	s.link, s.server = link, b.Name
+	s.kicked = false
+	var prelude [][]byte
+	if s.spawned {
+		s.switching = true
+		prelude = s.clearWorld()
+	}
+	login, radius := s.login, s.radius
+	s.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	for _, data := range prelude {
+		s.client.SendPacket(data)
+	}
+	link.SendPacket(login)
+	if prelude != nil && radius != nil {
+		link.SendPacket(radius)
+	}
+	go s.serve(link)
+
+	logger.Server("Player connected to server", "player", hello.Username, "server", b.Name)
+	return nil
+}
+
+func (s *session) transfer(name string) {
+	b, ok := s.proxy.backend(name)
+	if !ok {
+		logger.Warn("Backend asked for an unknown server", "player", s.username(), "server", name)
+		return
+	}
+	if err := s.connect(b); err != nil {
+		text := protocol.NewTextPacket()
+		text.TextType = protocol.TextTypeRaw
+		text.Message = "§cCould not connect to " + b.Name
+		s.send(text)
+	}
+}
+
+func (s *session) serve(link *network.LinkClient) {
+	err := link.Serve(func(data []byte) {
+		s.handleBackend(link, data)
+	}, func(server string) {
+		go s.transfer(server)
+	})
+
+	s.mu.Lock()
+	current := s.link == link
+	if current {
+		s.link = nil
+	}
+	kicked, closed, server := s.kicked, s.closed, s.server
+	s.mu.Unlock()
+	if !current || closed {
+		return
+	}
+
+	// The backend ended the session on its own. Unless it kicked the
+	// player, fall back to the first server before giving up.
+	if err != nil {
+		logger.Warn("Proxy link failed", "player", s.username(), "server", server, "error", err)
+	}
+	first := s.proxy.cfg.Backends[0]
+	if !kicked && !strings.EqualFold(server, first.Name) && s.connect(first) == nil {
+		return
+	}
+	s.client.Close()
+}
+
+func (s *session) handleBackend(link *network.LinkClient, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	s.mu.Lock()
+	if s.link != link {
+		s.mu.Unlock()
+		return
+	}
+	out := s.rewrite(data)
+	replacing := s.replacing
+	s.replacing = false
+	s.mu.Unlock()
+
+	for _, pk := range out {
+		s.client.SendPacket(pk)
+	}
+	if replacing {
+		s.kickDuplicates()
+	}
+}
+
+// rewrite is called with s.mu held.
+func (s *session) rewrite(data []byte) [][]byte {
+	if id, _ := s.codec.PacketID(data[0]); id != protocol.IDBatch {
+		out, _ := s.inspect(data)
+		return out
+	}
+
+	packets, err := unbatch(data)
+	if err != nil {
+		return [][]byte{data}
+	}
+	var kept [][]byte
+	changed := false
+	for _, pk := range packets {
+		out, modified := s.inspect(pk)
+		changed = changed || modified
+		kept = append(kept, out...)
+	}
+	if !changed {
+		return [][]byte{data}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	batch, err := s.batch(kept)
+	if err != nil {
+		logger.Error("Proxy failed to rebuild batch", "error", err)
+		return kept
+	}
+	return [][]byte{batch}
+}
+
+// inspect tracks what the client has been shown and, while switching,
+// replaces the packets the client must not see twice. It is called with
+// s.mu held.
+func (s *session) inspect(pk []byte) ([][]byte, bool) {
+	keep := [][]byte{pk}
+	id, ok := s.codec.PacketID(pk[0])
+	if !ok {
+		return keep, false
+	}
+
+	switch id {
+	case protocol.IDAddPlayer:
+		if len(pk) >= 19 {
+			raw := pk[1:17]
+			n := int(binary.BigEndian.Uint16(pk[17:19]))
+			if len(pk) >= 19+n+8 {
+				eid := int64(binary.BigEndian.Uint64(pk[19+n:]))
+				uid, _ := uuid.FromBytes(raw)
+				s.entities[eid] = trackedEntity{player: true, uuid: uid}
+			}
+		}
+	case protocol.IDAddEntity, protocol.IDAddItemEntity, protocol.IDAddPainting:
+		if len(pk) >= 9 {
+			s.entities[int64(binary.BigEndian.Uint64(pk[1:]))] = trackedEntity{}
+		}
+	case protocol.IDRemoveEntity, protocol.IDRemovePlayer:
+		if len(pk) >= 9 {
+			delete(s.entities, int64(binary.BigEndian.Uint64(pk[1:])))
+		}
+	case protocol.IDPlayerList:
+		decoded, err := s.codec.Decode(pk)
+		if list, ok := decoded.(*protocol.PlayerListPacket); ok && err == nil {
+			for _, entry := range list.Entries {
+				if list.Type == protocol.PlayerListTypeAdd {
+					s.listed[entry.UUID] = true
+				} else {
+					delete(s.listed, entry.UUID)
+				}
+			}
+		}
+	case protocol.IDDisconnect:
+		s.kicked = true
+	case protocol.IDStartGame:
+		decoded, err := s.codec.Decode(pk)
+		start, ok := decoded.(*protocol.StartGamePacket)
+		if err != nil || !ok {
+			return keep, false
+		}
+		if s.switching {
+			return s.respawn(start), true
+		}
+		s.spawned = true
+	case protocol.IDPlayStatus:
+		if s.accepted && !s.switching {
+			return keep, false
+		}
+		decoded, err := s.codec.Decode(pk)
+		status, ok := decoded.(*protocol.PlayStatusPacket)
+		if err != nil || !ok {
+			return keep, false
+		}
+		if !s.accepted {
+			if status.Status == protocol.PlayStatusLoginSuccess {
+				s.accepted, s.replacing = true, true
+			}
+			return keep, false
+		}
+		switch status.Status {
+		case protocol.PlayStatusLoginSuccess:
+			return nil, true
+		case protocol.PlayStatusPlayerSpawn:
+			s.switching = false
+		}
+	}
+	return keep, false
+}
+
+// clearWorld removes everything the old backend spawned and sends the
+// client into another dimension so it drops its chunks. It is called with
+// s.mu held.
+func (s *session) clearWorld() [][]byte {
+	var out [][]byte
+	add := func(pk protocol.DataPacket) {
+		if data, err := s.codec.Encode(pk); err == nil {
+			out = append(out, data)
+		}
+	}
+
+	for eid, e := range s.entities {
+		if e.player {
+			pk := protocol.NewRemovePlayerPacket()
+			pk.EntityID, pk.UUID = eid, e.uuid
+			add(pk)
+		} else {
+			pk := protocol.NewRemoveEntityPacket()
+			pk.EntityID = eid
+			add(pk)
+		}
+	}
+	if len(s.listed) > 0 {
+		list := protocol.NewPlayerListPacket()
+		list.Type = protocol.PlayerListTypeRemove
+		for id := range s.listed {
+			list.Entries = append(list.Entries, protocol.PlayerListEntry{UUID: id})
+		}
+		add(list)
+	}
+
+	dim := protocol.NewChangeDimensionPacket()
+	dim.Dimension = protocol.DimensionNether
+	dim.Y = 128
+	add(dim)
+
+	s.entities = make(map[int64]trackedEntity)
+	s.listed = make(map[string]bool)
+	return out
+}
+
+// respawn stands in for a second StartGame. It is called with s.mu held.
+func (s *session) respawn(start *protocol.StartGamePacket) [][]byte {
+	dim := protocol.NewChangeDimensionPacket()
+	dim.Dimension = start.Dimension
+	dim.X, dim.Y, dim.Z = start.X, start.Y, start.Z
+
+	mode := protocol.NewSetPlayerGameTypePacket()
+	mode.Gamemode = start.Gamemode
+
+	move := protocol.NewMovePlayerPacket()
+	move.EntityID = s.entityID
+	move.X, move.Y, move.Z = start.X, start.Y+eyeHeight, start.Z
+	move.Mode = protocol.MovePlayerModeReset
+
+	var out [][]byte
+	for _, pk := range []protocol.DataPacket{dim, mode, move} {
+		if data, err := s.codec.Encode(pk); err == nil {
+			out = append(out, data)
+		}
+	}
+	return out
+}
+
+func unbatch(data []byte) ([][]byte, error) {
+	batch := protocol.NewBatchPacket()
+	if err := batch.Decode(protocol.NewBinaryStreamFromBytes(data[1:])); err != nil {
+		return nil, err
+	}
+	raw, err := batch.Decompress()
+	if err != nil {
+		return nil, err
+	}
+
+	var packets [][]byte
+	for len(raw) >= 4 {
+		n := binary.BigEndian.Uint32(raw)
+		if uint64(n) > uint64(len(raw)-4) {
+			break
+		}
+		if n > 0 {
+			packets = append(packets, raw[4:4+n])
+		}
+		raw = raw[4+n:]
+	}
+	return packets, nil
+}
+
+func (s *session) batch(packets [][]byte) ([]byte, error) {
+	var raw bytes.Buffer
+	for _, pk := range packets {
+		binary.Write(&raw, binary.BigEndian, uint32(len(pk)))
+		raw.Write(pk)
+	}
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(raw.Bytes()); err != nil {
+		w.Close()
+		return nil, err
+	}
+	w.Close()
+
+	batch := protocol.NewBatchPacket()
+	batch.Payload = compressed.Bytes()
+	return s.codec.Encode(batch)
+}
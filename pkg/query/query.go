@@ -0,0 +1,206 @@
+package query
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const (
+	typeHandshake byte = 0x09
+	typeStat      byte = 0x00
+
+	tokenLifetime = 30 * time.Second
+	rateWindow    = time.Second
+)
+
+var magic = []byte{0xfe, 0xfd}
+
+type Info struct {
+	MOTD       string
+	GameType   string
+	GameID     string
+	Version    string
+	Engine     string
+	Map        string
+	HostIP     string
+	HostPort   int
+	NumPlayers int
+	MaxPlayers int
+	Whitelist  bool
+	Players    []string
+	Plugins    []string
+}
+
+type Handler struct {
+	info      func() Info
+	rateLimit int
+
+	mu       sync.Mutex
+	salt     [16]byte
+	prevSalt [16]byte
+	rotated  time.Time
+	requests map[string]int
+	window   time.Time
+}
+
+func NewHandler(info func() Info, rateLimit int) *Handler {
+	h := &Handler{
+		info:      info,
+		rateLimit: rateLimit,
+		requests:  make(map[string]int),
+	}
+	h.rotate(time.Now())
+	h.prevSalt = h.salt
+	return h
+}
+
+func IsQuery(data []byte) bool {
+	return len(data) >= 7 && bytes.Equal(data[:2], magic)
+}
+
+func (h *Handler) Handle(addr *net.UDPAddr, data []byte) ([]byte, bool) {
+	if !IsQuery(data) {
+		return nil, false
+	}
+	if !h.allow(addr.IP.String()) {
+		return nil, true
+	}
+
+	packetType := data[2]
+	sessionID := data[3:7]
+
+	switch packetType {
+	case typeHandshake:
+		buf := new(bytes.Buffer)
+		buf.WriteByte(typeHandshake)
+		buf.Write(sessionID)
+		buf.WriteString(strconv.Itoa(int(h.token(addr.IP, false))))
+		buf.WriteByte(0)
+		return buf.Bytes(), true
+
+	case typeStat:
+		if len(data) < 11 {
+			return nil, true
+		}
+		token := int32(binary.BigEndian.Uint32(data[7:11]))
+		if token != h.token(addr.IP, false) && token != h.token(addr.IP, true) {
+			logger.Debug("Query with invalid challenge token", "address", addr.String())
+			return nil, true
+		}
+		info := h.info()
+		if len(data) >= 15 {
+			return fullStat(sessionID, info), true
+		}
+		return basicStat(sessionID, info), true
+	}
+	return nil, true
+}
+
+func (h *Handler) allow(ip string) bool {
+	if h.rateLimit <= 0 {
+		return true
+	}
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if now.Sub(h.rotated) >= tokenLifetime {
+		h.rotate(now)
+	}
+	if now.Sub(h.window) >= rateWindow {
+		h.window = now
+		clear(h.requests)
+	}
+	h.requests[ip]++
+	return h.requests[ip] <= h.rateLimit
+}
+
+func (h *Handler) rotate(now time.Time) {
+	h.prevSalt = h.salt
+	rand.Read(h.salt[:])
+	h.rotated = now
+}
+
+func (h *Handler) token(ip net.IP, previous bool) int32 {
+	h.mu.Lock()
+	if time.Since(h.rotated) >= tokenLifetime {
+		h.rotate(time.Now())
+	}
+	salt := h.salt
+	if previous {
+		salt = h.prevSalt
+	}
+	h.mu.Unlock()
+
+	sum := sha256.Sum256(append(salt[:], ip...))
+	return int32(binary.BigEndian.Uint32(sum[:4]) & 0x7fffffff)
+}
+
+func basicStat(sessionID []byte, info Info) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(typeStat)
+	buf.Write(sessionID)
+	writeString(buf, info.MOTD)
+	writeString(buf, info.GameType)
+	writeString(buf, info.Map)
+	writeString(buf, strconv.Itoa(info.NumPlayers))
+	writeString(buf, strconv.Itoa(info.MaxPlayers))
+	binary.Write(buf, binary.LittleEndian, uint16(info.HostPort))
+	writeString(buf, info.HostIP)
+	return buf.Bytes()
+}
+
+func fullStat(sessionID []byte, info Info) []byte {
+	whitelist := "off"
+	if info.Whitelist {
+		whitelist = "on"
+	}
+	plugins := info.Engine
+	if len(info.Plugins) > 0 {
+		plugins += ": " + strings.Join(info.Plugins, "; ")
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte(typeStat)
+	buf.Write(sessionID)
+	buf.Write([]byte("splitnum\x00\x80\x00"))
+
+	for _, kv := range [][2]string{
+		{"hostname", info.MOTD},
+		{"gametype", info.GameType},
+		{"game_id", info.GameID},
+		{"version", info.Version},
+		{"server_engine", info.Engine},
+		{"plugins", plugins},
+		{"map", info.Map},
+		{"numplayers", strconv.Itoa(info.NumPlayers)},
+		{"maxplayers", strconv.Itoa(info.MaxPlayers)},
+		{"whitelist", whitelist},
+		{"hostip", info.HostIP},
+		{"hostport", strconv.Itoa(info.HostPort)},
+	} {
+		writeString(buf, kv[0])
+		writeString(buf, kv[1])
+	}
+	buf.WriteByte(0)
+
+	buf.Write([]byte("\x01player_\x00\x00"))
+	for _, name := range info.Players {
+		writeString(buf, name)
+	}
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteString(strings.ReplaceAll(s, "\x00", ""))
+	buf.WriteByte(0)
+}
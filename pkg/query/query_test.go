@@ -0,0 +1,146 @@
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testInfo = Info{
+	MOTD:       "A Server",
+	GameType:   "SMP",
+	GameID:     "MINECRAFTPE",
+	Version:    "0.14.3",
+	Engine:     "scaxe",
+	Map:        "world",
+	HostIP:     "127.0.0.1",
+	HostPort:   19132,
+	NumPlayers: 2,
+	MaxPlayers: 20,
+	Players:    []string{"Steve", "Alex"},
+	Plugins:    []string{"Economy 1.0"},
+}
+
+var testAddr = &net.UDPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 50000}
+
+func request(kind byte, payload ...byte) []byte {
+	return append([]byte{0xfe, 0xfd, kind, 0, 0, 0, 7}, payload...)
+}
+
+func handshake(t *testing.T, h *Handler) []byte {
+	t.Helper()
+	resp, handled := h.Handle(testAddr, request(typeHandshake))
+	if !handled || len(resp) < 6 || resp[0] != typeHandshake || !bytes.Equal(resp[1:5], []byte{0, 0, 0, 7}) {
+		t.Fatalf("handshake reply = %q, handled %v", resp, handled)
+	}
+	token, err := strconv.ParseInt(string(bytes.TrimSuffix(resp[5:], []byte{0})), 10, 32)
+	if err != nil {
+		t.Fatalf("handshake token %q: %v", resp[5:], err)
+	}
+	return binary.BigEndian.AppendUint32(nil, uint32(token))
+}
+
+func TestHandshakeAndToken(t *testing.T) {
+	h := NewHandler(func() Info { return testInfo }, 0)
+
+	if _, handled := h.Handle(testAddr, []byte{0x01, 0, 0, 0, 0, 0, 0, 0}); handled {
+		t.Error("non-query packet was handled")
+	}
+	token := handshake(t, h)
+
+	if resp, _ := h.Handle(testAddr, request(typeStat, token...)); resp == nil {
+		t.Error("stat with a valid token was ignored")
+	}
+	if resp, _ := h.Handle(testAddr, request(typeStat, 0, 0, 0, 1)); resp != nil {
+		t.Error("stat with a wrong token was answered")
+	}
+	other := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 50000}
+	if resp, _ := h.Handle(other, request(typeStat, token...)); resp != nil {
+		t.Error("token was accepted from another address")
+	}
+}
+
+func TestTokenRotation(t *testing.T) {
+	h := NewHandler(func() Info { return testInfo }, 0)
+	token := handshake(t, h)
+
+	for i, want := range []bool{true, false} {
+		h.mu.Lock()
+		h.rotated = time.Now().Add(-tokenLifetime)
+		h.mu.Unlock()
+
+		resp, _ := h.Handle(testAddr, request(typeStat, token...))
+		if got := resp != nil; got != want {
+			t.Errorf("after %d rotations: answered = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestStatEncoding(t *testing.T) {
+	h := NewHandler(func() Info { return testInfo }, 0)
+	token := handshake(t, h)
+
+	basic, _ := h.Handle(testAddr, request(typeStat, token...))
+	port := []byte{0xbc, 0x4a}
+	want := "\x00\x00\x00\x00\x07A Server\x00SMP\x00world\x002\x0020\x00" + string(port) + "127.0.0.1\x00"
+	if string(basic) != want {
+		t.Errorf("basic stat = %q, want %q", basic, want)
+	}
+
+	full, _ := h.Handle(testAddr, request(typeStat, append(token, 0, 0, 0, 0)...))
+	if !bytes.HasPrefix(full, []byte("\x00\x00\x00\x00\x07splitnum\x00\x80\x00")) {
+		t.Fatalf("full stat header = %q", full)
+	}
+	body := string(full[16:])
+	kv, players, ok := strings.Cut(body, "\x00\x00\x01player_\x00\x00")
+	if !ok {
+		t.Fatalf("full stat has no player section: %q", body)
+	}
+	fields := strings.Split(kv, "\x00")
+	values := make(map[string]string)
+	for i := 0; i+1 < len(fields); i += 2 {
+		values[fields[i]] = fields[i+1]
+	}
+	for key, want := range map[string]string{
+		"hostname":   "A Server",
+		"plugins":    "scaxe: Economy 1.0",
+		"numplayers": "2",
+		"maxplayers": "20",
+		"whitelist":  "off",
+		"hostport":   "19132",
+	} {
+		if values[key] != want {
+			t.Errorf("%s = %q, want %q", key, values[key], want)
+		}
+	}
+	if players != "Steve\x00Alex\x00\x00" {
+		t.Errorf("players = %q", players)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	h := NewHandler(func() Info { return testInfo }, 3)
+	for i := 0; i < 3; i++ {
+		if resp, _ := h.Handle(testAddr, request(typeHandshake)); resp == nil {
+			t.Fatalf("request %d was dropped", i+1)
+		}
+	}
+	if resp, handled := h.Handle(testAddr, request(typeHandshake)); resp != nil || !handled {
+		t.Errorf("request over the limit: reply %q, handled %v", resp, handled)
+	}
+	other := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 50000}
+	if resp, _ := h.Handle(other, request(typeHandshake)); resp == nil {
+		t.Error("another address was limited too")
+	}
+
+	h.mu.Lock()
+	h.window = time.Now().Add(-rateWindow)
+	h.mu.Unlock()
+	if resp, _ := h.Handle(testAddr, request(typeHandshake)); resp == nil {
+		t.Error("limit was not reset after the window")
+	}
+}
@@ -0,0 +1,333 @@
+package raknet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const clientMTU = 1400
+
+var ErrClientClosed = errors.New("raknet client closed")
+
+type Client struct {
+	conn *net.UDPConn
+	guid uint64
+	mtu  uint16
+
+	mu           sync.Mutex
+	sendSeqNum   uint32
+	messageIndex uint32
+	splitID      uint16
+	splitPackets map[uint16]*splitPacketData
+
+	packets   chan []byte
+	connected chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func Dial(address string, timeout time.Duration) (*Client, error) {
+	raddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:         conn,
+		guid:         rand.Uint64(),
+		mtu:          clientMTU,
+		splitPackets: make(map[uint16]*splitPacketData),
+		packets:      make(chan []byte, 1024),
+		connected:    make(chan struct{}),
+		closed:       make(chan struct{}),
+	}
+
+	deadline := time.Now().Add(timeout)
+	if err := c.openConnection(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go c.readLoop()
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte(IDConnectionRequest)
+	binary.Write(buf, binary.BigEndian, c.guid)
+	binary.Write(buf, binary.BigEndian, uint64(time.Now().UnixMilli()))
+	buf.WriteByte(0)
+	c.Send(buf.Bytes())
+
+	select {
+	case <-c.connected:
+		return c, nil
+	case <-c.closed:
+		return nil, ErrClientClosed
+	case <-time.After(time.Until(deadline)):
+		c.Close()
+		return nil, errors.New("raknet: timed out waiting for connection acceptance")
+	}
+}
+
+func (c *Client) openConnection(deadline time.Time) error {
+	req1 := new(bytes.Buffer)
+	req1.WriteByte(IDOpenConnectionRequest1)
+	req1.Write(RakNetMagic)
+	req1.WriteByte(SupportedProtocols[len(SupportedProtocols)-1])
+	req1.Write(make([]byte, int(c.mtu)-28-req1.Len()))
+	reply1, err := c.expect(req1.Bytes(), IDOpenConnectionReply1, deadline)
+	if err != nil {
+		return err
+	}
+	if len(reply1) >= 28 {
+		c.mtu = binary.BigEndian.Uint16(reply1[26:28])
+	}
+
+	req2 := new(bytes.Buffer)
+	req2.WriteByte(IDOpenConnectionRequest2)
+	req2.Write(RakNetMagic)
+	writeAddress(req2, c.conn.RemoteAddr().(*net.UDPAddr))
+	binary.Write(req2, binary.BigEndian, c.mtu)
+	binary.Write(req2, binary.BigEndian, c.guid)
+	_, err = c.expect(req2.Bytes(), IDOpenConnectionReply2, deadline)
+	return err
+}
+
+func (c *Client) expect(request []byte, reply byte, deadline time.Time) ([]byte, error) {
+	buf := make([]byte, 2048)
+	for time.Now().Before(deadline) {
+		if _, err := c.conn.Write(request); err != nil {
+			return nil, err
+		}
+		c.conn.SetReadDeadline(time.Now().Add(250 * time.Millisecond))
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			return nil, err
+		}
+		if n > 0 && buf[0] == reply {
+			return append([]byte(nil), buf[:n]...), nil
+		}
+		if n > 0 && buf[0] == IDIncompatibleProtocol {
+			return nil, errors.New("raknet: incompatible protocol")
+		}
+		if n > 0 && buf[0] == IDNoFreeIncomingConnections {
+			return nil, errors.New("raknet: server refused the connection")
+		}
+	}
+	return nil, errors.New("raknet: handshake timed out")
+}
+
+func (c *Client) readLoop() {
+	defer c.shutdown(false)
+	buf := make([]byte, 2048)
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				select {
+				case <-c.closed:
+					return
+				default:
+					continue
+				}
+			}
+			return
+		}
+		if n == 0 || buf[0] < IDCustom0 || buf[0] > IDCustomF || n < 4 {
+			continue
+		}
+		data := append([]byte(nil), buf[:n]...)
+		c.sendACK(uint32(data[1]) | uint32(data[2])<<8 | uint32(data[3])<<16)
+
+		for offset := 4; offset < len(data); {
+			pkt, next := decodeEncapsulated(data, offset)
+			if pkt == nil {
+				break
+			}
+			offset = next
+			if !c.handleFrame(pkt) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) handleFrame(pkt *encapsulatedPacket) bool {
+	payload := pkt.payload
+	if pkt.hasSplit {
+		payload = c.reassemble(pkt)
+	}
+	if len(payload) == 0 {
+		return true
+	}
+
+	switch payload[0] {
+	case IDConnectionRequestAccepted:
+		select {
+		case <-c.connected:
+			return true
+		default:
+		}
+		buf := new(bytes.Buffer)
+		buf.WriteByte(IDNewIncomingConnection)
+		writeAddress(buf, c.conn.RemoteAddr().(*net.UDPAddr))
+		for i := 0; i < 10; i++ {
+			writeAddress(buf, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		}
+		binary.Write(buf, binary.BigEndian, uint64(time.Now().UnixMilli()))
+		binary.Write(buf, binary.BigEndian, uint64(time.Now().UnixMilli()))
+		c.Send(buf.Bytes())
+		close(c.connected)
+	case IDDisconnectNotification:
+		return false
+	case 0x8e:
+		select {
+		case c.packets <- payload[1:]:
+		case <-c.closed:
+			return false
+		}
+	default:
+		logger.DebugRaknet("raknet.Client.handleFrame", "unhandled", payload[0])
+	}
+	return true
+}
+
+func (c *Client) reassemble(pkt *encapsulatedPacket) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.splitPackets[pkt.splitID]
+	if !ok {
+		data = &splitPacketData{splitCount: pkt.splitCount, fragments: make(map[uint32][]byte)}
+		c.splitPackets[pkt.splitID] = data
+	}
+	data.fragments[pkt.splitIndex] = pkt.payload
+	if uint32(len(data.fragments)) != data.splitCount {
+		return nil
+	}
+	delete(c.splitPackets, pkt.splitID)
+
+	var result []byte
+	for i := uint32(0); i < data.splitCount; i++ {
+		result = append(result, data.fragments[i]...)
+	}
+	return result
+}
+
+func (c *Client) sendACK(seqNum uint32) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(IDAcknowledge)
+	binary.Write(buf, binary.BigEndian, uint16(1))
+	buf.WriteByte(1)
+	buf.WriteByte(byte(seqNum))
+	buf.WriteByte(byte(seqNum >> 8))
+	buf.WriteByte(byte(seqNum >> 16))
+	c.conn.Write(buf.Bytes())
+}
+
+func (c *Client) Send(payload []byte) {
+	maxPayloadSize := int(c.mtu) - 60
+	if len(payload) <= maxPayloadSize {
+		c.sendFrame(payload, nil)
+		return
+	}
+
+	c.mu.Lock()
+	splitID := c.splitID
+	c.splitID++
+	c.mu.Unlock()
+
+	count := uint32((len(payload) + maxPayloadSize - 1) / maxPayloadSize)
+	for i := uint32(0); i < count; i++ {
+		start := int(i) * maxPayloadSize
+		end := start + maxPayloadSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		split := new(bytes.Buffer)
+		binary.Write(split, binary.BigEndian, count)
+		binary.Write(split, binary.BigEndian, splitID)
+		binary.Write(split, binary.BigEndian, i)
+		c.sendFrame(payload[start:end], split.Bytes())
+	}
+}
+
+func (c *Client) sendFrame(payload, split []byte) {
+	c.mu.Lock()
+	seqNum := c.sendSeqNum
+	c.sendSeqNum++
+	msgIndex := c.messageIndex
+	c.messageIndex++
+	c.mu.Unlock()
+
+	flags := byte(0x40)
+	if split != nil {
+		flags |= 0x10
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte(0x84)
+	buf.WriteByte(byte(seqNum))
+	buf.WriteByte(byte(seqNum >> 8))
+	buf.WriteByte(byte(seqNum >> 16))
+	buf.WriteByte(flags)
+	binary.Write(buf, binary.BigEndian, uint16(len(payload)*8))
+	buf.WriteByte(byte(msgIndex))
+	buf.WriteByte(byte(msgIndex >> 8))
+	buf.WriteByte(byte(msgIndex >> 16))
+	buf.Write(split)
+	buf.Write(payload)
+
+	c.conn.Write(buf.Bytes())
+}
+
+func (c *Client) SendPacket(data []byte) {
+	wrapped := make([]byte, len(data)+1)
+	wrapped[0] = 0x8e
+	copy(wrapped[1:], data)
+	c.Send(wrapped)
+}
+
+func (c *Client) Packets() <-chan []byte {
+	return c.packets
+}
+
+func (c *Client) Done() <-chan struct{} {
+	return c.closed
+}
+
+func (c *Client) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *Client) Close() {
+	c.shutdown(true)
+}
+
+func (c *Client) shutdown(notify bool) {
+	c.closeOnce.Do(func() {
+		select {
+		case <-c.connected:
+			if notify {
+				c.Send([]byte{IDDisconnectNotification})
+			}
+		default:
+		}
+		close(c.closed)
+		c.conn.Close()
+	})
+}
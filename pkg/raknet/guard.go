@@ -0,0 +1,291 @@
+package raknet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const (
+	minMTU = 576
+	maxMTU = 1492
+
+	cookieMask     = 0x3f
+	cookieLifetime = 30 * time.Second
+)
+
+// Limits bound the resources a single address, and all addresses together,
+// can hold on the server. Zero values disable the corresponding check.
+type Limits struct {
+	MaxSessions            int
+	MaxSessionsPerIP       int
+	PacketsPerSecond       int
+	GlobalPacketsPerSecond int
+
+	MaxSplitPackets int
+	MaxSplitCount   int
+	MaxSplitBytes   int
+	SplitTimeout    time.Duration
+
+	SessionTimeout   time.Duration
+	HandshakeTimeout time.Duration
+	BlockDuration    time.Duration
+
+	// Cookies encode a per-address value in the low bits of the MTU sent in
+	// OPEN_CONNECTION_REPLY_1. Clients echo that MTU in REQUEST_2, so a
+	// session is only created for addresses that can receive our replies.
+	//
+	// This is best-effort. RakNet 7/8 clients echo nothing else we control,
+	// so the cookie is only 6 bits wide and either of the current and
+	// previous values is accepted: a spoofed REQUEST_2 with a guessed MTU
+	// gets through about 1 time in 32. It raises the cost of spoofed
+	// handshakes; MaxSessions and MaxSessionsPerIP still bound the damage.
+	Cookies bool
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		MaxSessions:            200,
+		MaxSessionsPerIP:       5,
+		PacketsPerSecond:       1500,
+		GlobalPacketsPerSecond: 30000,
+		MaxSplitPackets:        16,
+		MaxSplitCount:          512,
+		MaxSplitBytes:          2 << 20,
+		SplitTimeout:           10 * time.Second,
+		SessionTimeout:         30 * time.Second,
+		HandshakeTimeout:       10 * time.Second,
+		BlockDuration:          time.Minute,
+		Cookies:                true,
+	}
+}
+
+type GuardStats struct {
+	DroppedPackets    uint64
+	RateLimited       uint64
+	CookieFailures    uint64
+	RejectedSessions  uint64
+	SplitViolations   uint64
+	TimedOutSessions  uint64
+	TimedOutSplits    uint64
+	BlocksIssued      uint64
+	CurrentlyBlocked  int
+	TrackedAddresses  int
+	PacketsThisSecond int
+}
+
+type BlockedSource struct {
+	IP      string
+	Reason  string
+	Until   time.Time
+	Dropped uint64
+}
+
+type ipState struct {
+	packets int
+	window  time.Time
+}
+
+type block struct {
+	reason  string
+	until   time.Time
+	dropped uint64
+}
+
+type guard struct {
+	mu      sync.Mutex
+	limits  *Limits
+	ips     map[string]*ipState
+	blocked map[string]*block
+	stats   GuardStats
+
+	globalPackets int
+	globalWindow  time.Time
+
+	salt     [16]byte
+	prevSalt [16]byte
+	rotated  time.Time
+}
+
+func newGuard(limits *Limits) *guard {
+	g := &guard{
+		limits:  limits,
+		ips:     make(map[string]*ipState),
+		blocked: make(map[string]*block),
+	}
+	g.rotate(time.Now())
+	g.prevSalt = g.salt
+	return g
+}
+
+func (g *guard) allow(ip string) bool {
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if b, ok := g.blocked[ip]; ok {
+		if now.Before(b.until) {
+			b.dropped++
+			g.stats.DroppedPackets++
+			return false
+		}
+		delete(g.blocked, ip)
+	}
+
+	if g.limits.GlobalPacketsPerSecond > 0 {
+		if now.Sub(g.globalWindow) >= time.Second {
+			g.globalWindow = now
+			g.globalPackets = 0
+		}
+		g.globalPackets++
+		if g.globalPackets > g.limits.GlobalPacketsPerSecond {
+			g.stats.DroppedPackets++
+			g.stats.RateLimited++
+			return false
+		}
+	}
+
+	if g.limits.PacketsPerSecond > 0 {
+		st := g.ips[ip]
+		if st == nil {
+			st = &ipState{window: now}
+			g.ips[ip] = st
+		}
+		if now.Sub(st.window) >= time.Second {
+			st.window = now
+			st.packets = 0
+		}
+		st.packets++
+		if st.packets > g.limits.PacketsPerSecond {
+			g.stats.DroppedPackets++
+			g.stats.RateLimited++
+			g.blockLocked(ip, "packet rate", g.limits.BlockDuration, now)
+			return false
+		}
+	}
+	return true
+}
+
+func (g *guard) block(ip, reason string, d time.Duration) {
+	g.mu.Lock()
+	g.blockLocked(ip, reason, d, time.Now())
+	g.mu.Unlock()
+}
+
+func (g *guard) blockLocked(ip, reason string, d time.Duration, now time.Time) {
+	if d <= 0 {
+		return
+	}
+	if b, ok := g.blocked[ip]; ok && b.until.After(now) {
+		return
+	}
+	g.blocked[ip] = &block{reason: reason, until: now.Add(d)}
+	g.stats.BlocksIssued++
+	logger.Warn("RakNet address blocked", "ip", ip, "reason", reason, "duration", d.String())
+}
+
+func (g *guard) isBlocked(ip string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.blocked[ip]
+	return ok && time.Now().Before(b.until)
+}
+
+func (g *guard) unblock(ip string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.blocked[ip]
+	delete(g.blocked, ip)
+	return ok
+}
+
+func (g *guard) count(field *uint64) {
+	g.mu.Lock()
+	*field++
+	g.mu.Unlock()
+}
+
+// sweep drops per-address state that no longer matters so that spoofed
+// sources cannot grow the maps without bound.
+func (g *guard) sweep(now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for ip, st := range g.ips {
+		if now.Sub(st.window) >= time.Second {
+			delete(g.ips, ip)
+		}
+	}
+	for ip, b := range g.blocked {
+		if !now.Before(b.until) {
+			delete(g.blocked, ip)
+		}
+	}
+	if now.Sub(g.rotated) >= cookieLifetime {
+		g.rotate(now)
+	}
+}
+
+func (g *guard) snapshot() (GuardStats, []BlockedSource) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	stats := g.stats
+	stats.CurrentlyBlocked = len(g.blocked)
+	stats.TrackedAddresses = len(g.ips)
+	stats.PacketsThisSecond = g.globalPackets
+
+	blocked := make([]BlockedSource, 0, len(g.blocked))
+	for ip, b := range g.blocked {
+		blocked = append(blocked, BlockedSource{IP: ip, Reason: b.reason, Until: b.until, Dropped: b.dropped})
+	}
+	sort.Slice(blocked, func(i, j int) bool { return blocked[i].Until.Before(blocked[j].Until) })
+	return stats, blocked
+}
+
+func (g *guard) rotate(now time.Time) {
+	g.prevSalt = g.salt
+	rand.Read(g.salt[:])
+	g.rotated = now
+}
+
+func (g *guard) cookie(addr *net.UDPAddr, previous bool) uint16 {
+	g.mu.Lock()
+	salt := g.salt
+	if previous {
+		salt = g.prevSalt
+	}
+	g.mu.Unlock()
+
+	h := sha256.New()
+	h.Write(salt[:])
+	h.Write(addr.IP.To16())
+	binary.Write(h, binary.BigEndian, uint16(addr.Port))
+	return binary.BigEndian.Uint16(h.Sum(nil)) & cookieMask
+}
+
+// replyMTU picks the MTU for OPEN_CONNECTION_REPLY_1. With cookies enabled
+// it is lowered by up to 127 bytes so that its low bits carry the cookie,
+// but never below minMTU; requests close to the minimum may get up to 63
+// bytes more than they asked for instead.
+func (g *guard) replyMTU(addr *net.UDPAddr, requested int) uint16 {
+	mtu := min(max(requested, minMTU), maxMTU)
+	if !g.limits.Cookies {
+		return uint16(mtu)
+	}
+	// minMTU is a multiple of 64, so the cookie bits start out clear.
+	base := max((mtu&^cookieMask)-(cookieMask+1), minMTU)
+	return uint16(base) | g.cookie(addr, false)
+}
+
+func (g *guard) checkCookie(addr *net.UDPAddr, mtu uint16) bool {
+	if !g.limits.Cookies {
+		return true
+	}
+	c := mtu & cookieMask
+	return c == g.cookie(addr, false) || c == g.cookie(addr, true)
+}
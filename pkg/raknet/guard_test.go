@@ -0,0 +1,98 @@
+package raknet
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPacketRateBlocksAddress(t *testing.T) {
+	limits := Limits{PacketsPerSecond: 3, BlockDuration: time.Minute}
+	g := newGuard(&limits)
+
+	for i := 0; i < 3; i++ {
+		if !g.allow("10.0.0.1") {
+			t.Fatalf("packet %d was dropped below the limit", i+1)
+		}
+	}
+	if g.allow("10.0.0.1") {
+		t.Fatal("packet over the limit was allowed")
+	}
+	if !g.allow("10.0.0.2") {
+		t.Fatal("limit leaked to another address")
+	}
+	if !g.isBlocked("10.0.0.1") {
+		t.Fatal("address over the limit was not blocked")
+	}
+
+	stats, blocked := g.snapshot()
+	if stats.BlocksIssued != 1 || len(blocked) != 1 || blocked[0].Reason != "packet rate" {
+		t.Fatalf("stats = %+v, blocked = %+v", stats, blocked)
+	}
+	if !g.unblock("10.0.0.1") || g.isBlocked("10.0.0.1") {
+		t.Fatal("unblock did not lift the block")
+	}
+}
+
+func TestCookie(t *testing.T) {
+	limits := Limits{Cookies: true}
+	g := newGuard(&limits)
+	addr := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 40000}
+
+	for _, requested := range []int{400, 576, 1200, 1464, 1500, 9000} {
+		mtu := g.replyMTU(addr, requested)
+		if mtu < minMTU || int(mtu) > max(requested, minMTU+cookieMask) || mtu > maxMTU {
+			t.Errorf("replyMTU(%d) = %d, out of range", requested, mtu)
+		}
+		if !g.checkCookie(addr, mtu) {
+			t.Errorf("cookie in MTU %d was rejected", mtu)
+		}
+		if g.checkCookie(addr, mtu^1) {
+			t.Errorf("altered MTU %d was accepted", mtu^1)
+		}
+	}
+
+	mtu := g.replyMTU(addr, 1400)
+	g.rotate(time.Now())
+	if !g.checkCookie(addr, mtu) {
+		t.Error("cookie from the previous salt was rejected")
+	}
+}
+
+func TestSplitLimits(t *testing.T) {
+	server := NewServer("127.0.0.1:0")
+	server.Limits = Limits{MaxSplitPackets: 2, MaxSplitCount: 4, MaxSplitBytes: 10, BlockDuration: time.Minute, SplitTimeout: time.Second}
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 9), Port: 1234}
+	session := NewSession(server, addr, 1400, 1)
+
+	split := func(id uint16, index, count uint32, size int) []byte {
+		return session.handleSplitPacket(&encapsulatedPacket{hasSplit: true, splitID: id, splitIndex: index, splitCount: count, payload: make([]byte, size)})
+	}
+
+	split(1, 0, 2, 3)
+	if got := split(1, 1, 2, 3); len(got) != 6 {
+		t.Fatalf("reassembled %d bytes, want 6", len(got))
+	}
+	if session.splitBytes != 0 || len(session.splitPackets) != 0 {
+		t.Fatalf("buffer not released: %d bytes, %d packets", session.splitBytes, len(session.splitPackets))
+	}
+
+	split(2, 0, 3, 4)
+	split(3, 0, 3, 4)
+	split(2, 1, 3, 4)
+	if session.splitBytes != 8 {
+		t.Fatalf("buffered %d bytes past the cap", session.splitBytes)
+	}
+	if server.guard.isBlocked("10.0.0.9") {
+		t.Fatal("full buffer should not block the address")
+	}
+
+	if n, _ := session.expire(time.Now().Add(2 * time.Second)); n != 2 || session.splitBytes != 0 {
+		t.Fatalf("expired %d split packets leaving %d bytes", n, session.splitBytes)
+	}
+
+	split(5, 0, 100, 1)
+	if !server.guard.isBlocked("10.0.0.9") {
+		t.Fatal("oversized split count did not block the address")
+	}
+}
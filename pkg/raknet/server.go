@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/scaxe/scaxe-go/pkg/logger"
@@ -24,6 +25,7 @@ const (
 	IDConnectionRequest         byte = 0x09
 	IDConnectionRequestAccepted byte = 0x10
 	IDNewIncomingConnection     byte = 0x13
+	IDNoFreeIncomingConnections byte = 0x14
 	IDDisconnectNotification    byte = 0x15
 	IDIncompatibleProtocol      byte = 0x19
 	IDAcknowledge               byte = 0xc0
@@ -54,19 +56,33 @@ type Server struct {
 	OnConnect    func(*Session)
 	OnDisconnect func(*Session)
 	OnPacket     func(*Session, []byte)
+	OnRawPacket  func(*net.UDPAddr, []byte) bool
 
-	running bool
+	Limits Limits
+	guard  *guard
+
+	running atomic.Bool
 	stopCh  chan struct{}
 }
 
 func NewServer(address string) *Server {
 	logger.DebugRaknet("raknet.NewServer", "address", address)
-	return &Server{
+	s := &Server{
 		address:  address,
 		serverID: rand.Int63(),
 		sessions: make(map[string]*Session),
+		Limits:   DefaultLimits(),
 		stopCh:   make(chan struct{}),
 	}
+	s.guard = newGuard(&s.Limits)
+	return s
+}
+
+func (s *Server) Addr() net.Addr {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.LocalAddr()
 }
 
 func (s *Server) ServerID() int64 {
@@ -93,9 +109,10 @@ func (s *Server) Start() error {
 		return err
 	}
 
-	s.running = true
+	s.running.Store(true)
 
 	go s.readLoop()
+	go s.tickLoop()
 
 	logger.Info("raknet.Server.Start", "status", "listening", "address", s.address)
 	return nil
@@ -103,7 +120,7 @@ func (s *Server) Start() error {
 
 func (s *Server) Stop() {
 	logger.DebugRaknet("raknet.Server.Stop", "action", "stopping")
-	s.running = false
+	s.running.Store(false)
 	close(s.stopCh)
 	if s.conn != nil {
 		s.conn.Close()
@@ -114,14 +131,14 @@ func (s *Server) Stop() {
 func (s *Server) readLoop() {
 	buf := make([]byte, 2048)
 
-	for s.running {
+	for s.running.Load() {
 		s.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
 		n, addr, err := s.conn.ReadFromUDP(buf)
 		if err != nil {
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 				continue
 			}
-			if !s.running {
+			if !s.running.Load() {
 				return
 			}
 			logger.Error("raknet.readLoop", "error", err)
@@ -139,10 +156,47 @@ func (s *Server) readLoop() {
 	}
 }
 
+func (s *Server) tickLoop() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case now := <-ticker.C:
+			s.guard.sweep(now)
+			s.expireSessions(now)
+		}
+	}
+}
+
+func (s *Server) expireSessions(now time.Time) {
+	for _, session := range s.Sessions() {
+		splits, reason := session.expire(now)
+		for i := 0; i < splits; i++ {
+			s.guard.count(&s.guard.stats.TimedOutSplits)
+		}
+		if reason == "" && s.guard.isBlocked(session.addr.IP.String()) {
+			reason = "address blocked"
+		}
+		if reason == "" {
+			continue
+		}
+		if reason != "address blocked" {
+			s.guard.count(&s.guard.stats.TimedOutSessions)
+		}
+		logger.Info("raknet.expireSessions", "address", session.Address(), "reason", reason)
+		s.removeSession(session.Address())
+	}
+}
+
 func (s *Server) handlePacket(addr *net.UDPAddr, data []byte) {
 	if len(data) == 0 {
 		return
 	}
+	if !s.guard.allow(addr.IP.String()) {
+		return
+	}
 
 	packetID := data[0]
 	addrStr := addr.String()
@@ -168,6 +222,9 @@ func (s *Server) handlePacket(addr *net.UDPAddr, data []byte) {
 	s.sessionsMu.RUnlock()
 
 	if !exists {
+		if s.OnRawPacket != nil && s.OnRawPacket(addr, data) {
+			return
+		}
 		logger.Warn("raknet.handlePacket", "warning", "packet from unknown session", "address", addrStr)
 		return
 	}
@@ -263,14 +320,14 @@ func (s *Server) handleOpenConnectionRequest1(addr *net.UDPAddr, data []byte) {
 		return
 	}
 
-	mtuSize := len(data) + 28
+	mtuSize := s.guard.replyMTU(addr, len(data)+28)
 
 	buf := new(bytes.Buffer)
 	buf.WriteByte(IDOpenConnectionReply1)
 	buf.Write(RakNetMagic)
 	binary.Write(buf, binary.BigEndian, s.serverID)
 	buf.WriteByte(0)
-	binary.Write(buf, binary.BigEndian, uint16(mtuSize))
+	binary.Write(buf, binary.BigEndian, mtuSize)
 
 	s.conn.WriteToUDP(buf.Bytes(), addr)
 	logger.DebugRaknet("raknet.handleOpenConnectionRequest1", "sent", "reply1", "mtu", mtuSize)
@@ -322,11 +379,24 @@ func (s *Server) handleOpenConnectionRequest2(addr *net.UDPAddr, data []byte) {
 
 	logger.DebugRaknet("raknet.handleOpenConnectionRequest2", "from", addr.String(), "mtu", mtu, "clientGuid", clientGuid)
 
-	session := NewSession(s, addr, mtu, clientGuid)
+	if !s.guard.checkCookie(addr, mtu) {
+		s.guard.count(&s.guard.stats.CookieFailures)
+		logger.DebugRaknet("raknet.handleOpenConnectionRequest2", "from", addr.String(), "warning", "bad cookie")
+		return
+	}
+	mtu = min(max(mtu, minMTU), maxMTU)
 
-	s.sessionsMu.Lock()
-	s.sessions[addr.String()] = session
-	s.sessionsMu.Unlock()
+	if reason := s.addSession(addr, mtu, clientGuid); reason != "" {
+		s.guard.count(&s.guard.stats.RejectedSessions)
+		logger.Warn("raknet.handleOpenConnectionRequest2", "warning", "session rejected", "address", addr.String(), "reason", reason)
+
+		buf := new(bytes.Buffer)
+		buf.WriteByte(IDNoFreeIncomingConnections)
+		buf.Write(RakNetMagic)
+		binary.Write(buf, binary.BigEndian, s.serverID)
+		s.conn.WriteToUDP(buf.Bytes(), addr)
+		return
+	}
 
 	buf := new(bytes.Buffer)
 	buf.WriteByte(IDOpenConnectionReply2)
@@ -342,6 +412,43 @@ func (s *Server) handleOpenConnectionRequest2(addr *net.UDPAddr, data []byte) {
 	logger.Info("raknet.handleOpenConnectionRequest2", "sent", "reply2", "session", addr.String(), "mtu", mtu)
 }
 
+func (s *Server) addSession(addr *net.UDPAddr, mtu uint16, clientGuid uint64) string {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	if _, exists := s.sessions[addr.String()]; exists {
+		return ""
+	}
+	if s.Limits.MaxSessions > 0 && len(s.sessions) >= s.Limits.MaxSessions {
+		return "server session limit reached"
+	}
+	if s.Limits.MaxSessionsPerIP > 0 {
+		perIP := 0
+		for _, session := range s.sessions {
+			if session.addr.IP.Equal(addr.IP) {
+				perIP++
+			}
+		}
+		if perIP >= s.Limits.MaxSessionsPerIP {
+			return "per-address session limit reached"
+		}
+	}
+	s.sessions[addr.String()] = NewSession(s, addr, mtu, clientGuid)
+	return ""
+}
+
+func (s *Server) GuardStats() (GuardStats, []BlockedSource) {
+	return s.guard.snapshot()
+}
+
+func (s *Server) Block(ip, reason string, d time.Duration) {
+	s.guard.block(ip, reason, d)
+}
+
+func (s *Server) Unblock(ip string) bool {
+	return s.guard.unblock(ip)
+}
+
 func (s *Server) removeSession(addrStr string) {
 	s.sessionsMu.Lock()
 	session, exists := s.sessions[addrStr]
@@ -356,6 +463,16 @@ func (s *Server) removeSession(addrStr string) {
 	logger.DebugRaknet("raknet.removeSession", "address", addrStr)
 }
 
+func (s *Server) Sessions() []*Session {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+	sessions := make([]*Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
 func (s *Server) SendTo(addr *net.UDPAddr, data []byte) error {
 	_, err := s.conn.WriteToUDP(data, addr)
 	return err
@@ -13,8 +13,30 @@ import (
 type splitPacketData struct {
 	splitCount uint32
 	fragments  map[uint32][]byte
+	size       int
+	created    time.Time
 }
 
+type sentDatagram struct {
+	data []byte
+	sent time.Time
+}
+
+type SessionStats struct {
+	PacketsSent     uint64
+	PacketsReceived uint64
+	BytesSent       uint64
+	BytesReceived   uint64
+	NAKsReceived    uint64
+	Lost            uint64
+	Resends         uint64
+	RTT             time.Duration
+}
+
+// recoveryWindow bounds how many unacknowledged datagrams are kept for
+// resending and RTT.
+const recoveryWindow = 2048
+
 type Session struct {
 	server    *Server
 	addr      *net.UDPAddr
@@ -28,26 +50,32 @@ type Session struct {
 	splitID      uint16
 	messageIndex uint32
 
-	receivedPackets map[uint32]bool
-	ackQueue        []uint32
+	ackQueue []uint32
 
 	splitPackets map[uint16]*splitPacketData
+	splitBytes   int
+
+	recovery map[uint32]sentDatagram
+	stats    SessionStats
 
 	mu sync.Mutex
 
+	created      time.Time
 	lastActivity time.Time
 }
 
 func NewSession(server *Server, addr *net.UDPAddr, mtu uint16, clientID uint64) *Session {
 	logger.DebugRaknet("raknet.NewSession", "address", addr.String(), "mtu", mtu, "clientID", clientID)
+	now := time.Now()
 	return &Session{
-		server:          server,
-		addr:            addr,
-		mtu:             mtu,
-		clientID:        clientID,
-		receivedPackets: make(map[uint32]bool),
-		splitPackets:    make(map[uint16]*splitPacketData),
-		lastActivity:    time.Now(),
+		server:       server,
+		addr:         addr,
+		mtu:          mtu,
+		clientID:     clientID,
+		splitPackets: make(map[uint16]*splitPacketData),
+		recovery:     make(map[uint32]sentDatagram),
+		created:      now,
+		lastActivity: now,
 	}
 }
 
@@ -55,26 +83,32 @@ func (s *Session) Address() string {
 	return s.addr.String()
 }
 
+func (s *Session) Stats() SessionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
 func (s *Session) handleDataPacket(data []byte) {
 	if len(data) < 4 {
 		return
 	}
 
-	s.lastActivity = time.Now()
-
 	seqNum := uint32(data[1]) | uint32(data[2])<<8 | uint32(data[3])<<16
 	logger.DebugRaknet("raknet.Session.handleDataPacket", "seqNum", seqNum, "size", len(data))
 
 	s.mu.Lock()
+	s.lastActivity = time.Now()
 	s.ackQueue = append(s.ackQueue, seqNum)
-	s.receivedPackets[seqNum] = true
+	s.stats.PacketsReceived++
+	s.stats.BytesReceived += uint64(len(data))
 	s.mu.Unlock()
 
 	s.sendACK()
 
 	offset := 4
 	for offset < len(data) {
-		encapsulated, newOffset := s.decodeEncapsulated(data, offset)
+		encapsulated, newOffset := decodeEncapsulated(data, offset)
 		if encapsulated == nil {
 			break
 		}
@@ -96,7 +130,7 @@ type encapsulatedPacket struct {
 	payload      []byte
 }
 
-func (s *Session) decodeEncapsulated(data []byte, offset int) (*encapsulatedPacket, int) {
+func decodeEncapsulated(data []byte, offset int) (*encapsulatedPacket, int) {
 	if offset >= len(data) {
 		return nil, offset
 	}
@@ -224,16 +258,39 @@ func (s *Session) handleSplitPacket(pkt *encapsulatedPacket) []byte {
 		"splitCount", splitCount,
 		"fragmentSize", len(pkt.payload))
 
+	limits := &s.server.Limits
+	if splitCount == 0 || splitIndex >= splitCount || (limits.MaxSplitCount > 0 && splitCount > uint32(limits.MaxSplitCount)) {
+		s.splitViolation("invalid split header")
+		return nil
+	}
+
 	data, exists := s.splitPackets[splitID]
 	if !exists {
+		if limits.MaxSplitPackets > 0 && len(s.splitPackets) >= limits.MaxSplitPackets {
+			s.splitViolation("too many split packets in flight")
+			return nil
+		}
 		data = &splitPacketData{
 			splitCount: splitCount,
 			fragments:  make(map[uint32][]byte),
+			created:    time.Now(),
 		}
 		s.splitPackets[splitID] = data
+	} else if data.splitCount != splitCount {
+		s.splitViolation("split count changed")
+		return nil
 	}
 
+	if _, dup := data.fragments[splitIndex]; dup {
+		return nil
+	}
+	if limits.MaxSplitBytes > 0 && s.splitBytes+len(pkt.payload) > limits.MaxSplitBytes {
+		s.splitViolation("split buffer full")
+		return nil
+	}
 	data.fragments[splitIndex] = pkt.payload
+	data.size += len(pkt.payload)
+	s.splitBytes += len(pkt.payload)
 
 	if uint32(len(data.fragments)) != data.splitCount {
 		logger.DebugRaknet("raknet.handleSplitPacket",
@@ -253,7 +310,7 @@ func (s *Session) handleSplitPacket(pkt *encapsulatedPacket) []byte {
 		frag, ok := data.fragments[i]
 		if !ok {
 			logger.Error("raknet.handleSplitPacket", "error", "missing fragment", "index", i)
-			delete(s.splitPackets, splitID)
+			s.dropSplit(splitID)
 			return nil
 		}
 		totalSize += len(frag)
@@ -264,7 +321,7 @@ func (s *Session) handleSplitPacket(pkt *encapsulatedPacket) []byte {
 		result = append(result, data.fragments[i]...)
 	}
 
-	delete(s.splitPackets, splitID)
+	s.dropSplit(splitID)
 
 	logger.DebugRaknet("raknet.handleSplitPacket",
 		"status", "reassembly complete",
@@ -273,6 +330,49 @@ func (s *Session) handleSplitPacket(pkt *encapsulatedPacket) []byte {
 	return result
 }
 
+func (s *Session) dropSplit(splitID uint16) {
+	if data, ok := s.splitPackets[splitID]; ok {
+		s.splitBytes -= data.size
+		delete(s.splitPackets, splitID)
+	}
+}
+
+// splitViolation is called with s.mu held. Malformed headers are treated as
+// abuse and block the address; a full buffer only drops the fragment.
+func (s *Session) splitViolation(reason string) {
+	s.server.guard.count(&s.server.guard.stats.SplitViolations)
+	logger.Warn("raknet.handleSplitPacket", "warning", reason, "address", s.addr.String())
+	if reason != "split buffer full" {
+		s.server.guard.block(s.addr.IP.String(), reason, s.server.Limits.BlockDuration)
+	}
+}
+
+// expire discards split packets that were never completed and reports
+// whether the session itself should be closed.
+func (s *Session) expire(now time.Time) (int, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limits := &s.server.Limits
+	expired := 0
+	if limits.SplitTimeout > 0 {
+		for id, data := range s.splitPackets {
+			if now.Sub(data.created) > limits.SplitTimeout {
+				s.dropSplit(id)
+				expired++
+			}
+		}
+	}
+
+	switch {
+	case !s.connected && limits.HandshakeTimeout > 0 && now.Sub(s.created) > limits.HandshakeTimeout:
+		return expired, "handshake timeout"
+	case limits.SessionTimeout > 0 && now.Sub(s.lastActivity) > limits.SessionTimeout:
+		return expired, "idle timeout"
+	}
+	return expired, ""
+}
+
 func (s *Session) handleConnectionRequest(data []byte) {
 	if len(data) < 17 {
 		return
@@ -305,7 +405,13 @@ func (s *Session) handleConnectionRequest(data []byte) {
 
 func (s *Session) handleNewIncomingConnection(data []byte) {
 	logger.DebugRaknet("raknet.handleNewIncomingConnection", "address", s.addr.String())
+	s.mu.Lock()
+	already := s.connected
 	s.connected = true
+	s.mu.Unlock()
+	if already {
+		return
+	}
 
 	if s.server.OnConnect != nil {
 		s.server.OnConnect(s)
@@ -313,13 +419,110 @@ func (s *Session) handleNewIncomingConnection(data []byte) {
 }
 
 func (s *Session) handleACK(data []byte) {
-
 	logger.DebugRaknet("raknet.Session.handleACK", "size", len(data))
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, seqNum := range decodeRecords(data) {
+		d, ok := s.recovery[seqNum]
+		if !ok {
+			continue
+		}
+		delete(s.recovery, seqNum)
+		rtt := now.Sub(d.sent)
+		if s.stats.RTT == 0 {
+			s.stats.RTT = rtt
+		} else {
+			s.stats.RTT = (s.stats.RTT*7 + rtt) / 8
+		}
+	}
 }
 
 func (s *Session) handleNAK(data []byte) {
-
 	logger.DebugRaknet("raknet.Session.handleNAK", "size", len(data))
+
+	var resend [][]byte
+	s.mu.Lock()
+	s.stats.NAKsReceived++
+	for _, seqNum := range decodeRecords(data) {
+		d, ok := s.recovery[seqNum]
+		if !ok {
+			continue
+		}
+		delete(s.recovery, seqNum)
+		s.stats.Lost++
+		resend = append(resend, d.data)
+	}
+	s.mu.Unlock()
+
+	// A resent datagram goes out under a new sequence number so the client
+	// can ACK or NAK it on its own; the payload's message indices stay the
+	// same and let the client drop a copy it already has.
+	for _, datagram := range resend {
+		s.mu.Lock()
+		seqNum := s.sendSeqNum
+		s.sendSeqNum++
+		s.stats.Resends++
+		s.mu.Unlock()
+
+		resent := append([]byte(nil), datagram...)
+		resent[1], resent[2], resent[3] = byte(seqNum), byte(seqNum>>8), byte(seqNum>>16)
+		s.sendDatagram(seqNum, resent)
+	}
+}
+
+// decodeRecords expands the ranges of an ACK or NAK. Only recoveryWindow
+// datagrams can be outstanding, so no packet may name more than that.
+func decodeRecords(data []byte) []uint32 {
+	if len(data) < 3 {
+		return nil
+	}
+	count := int(binary.BigEndian.Uint16(data[1:3]))
+	offset := 3
+	var seqNums []uint32
+	for i := 0; i < count && offset < len(data); i++ {
+		single := data[offset] != 0
+		offset++
+		if offset+3 > len(data) {
+			break
+		}
+		start := readTriad(data[offset:])
+		offset += 3
+		end := start
+		if !single {
+			if offset+3 > len(data) {
+				break
+			}
+			end = readTriad(data[offset:])
+			offset += 3
+		}
+		if end < start || end-start >= recoveryWindow {
+			continue
+		}
+		for seqNum := start; seqNum <= end; seqNum++ {
+			if len(seqNums) >= recoveryWindow {
+				return seqNums
+			}
+			seqNums = append(seqNums, seqNum)
+		}
+	}
+	return seqNums
+}
+
+func readTriad(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+func (s *Session) sendDatagram(seqNum uint32, datagram []byte) {
+	s.mu.Lock()
+	s.recovery[seqNum&0xffffff] = sentDatagram{data: datagram, sent: time.Now()}
+	delete(s.recovery, (seqNum-recoveryWindow)&0xffffff)
+	s.stats.PacketsSent++
+	s.stats.BytesSent += uint64(len(datagram))
+	s.mu.Unlock()
+
+	s.server.SendTo(s.addr, datagram)
 }
 
 func (s *Session) sendACK() {
@@ -406,8 +609,7 @@ func (s *Session) sendsingleReliable(payload []byte) {
 
 	buf.Write(payload)
 
-	s.server.SendTo(s.addr, buf.Bytes())
-
+	s.sendDatagram(seqNum, buf.Bytes())
 }
 
 func (s *Session) sendSplitFragment(chunk []byte, splitID uint16, splitCount uint32, splitIndex uint32) {
@@ -441,11 +643,10 @@ func (s *Session) sendSplitFragment(chunk []byte, splitID uint16, splitCount uin
 
 	buf.Write(chunk)
 
-	s.server.SendTo(s.addr, buf.Bytes())
+	s.sendDatagram(seqNum, buf.Bytes())
 }
 
 func (s *Session) SendPacket(data []byte) {
-
 	wrapped := make([]byte, len(data)+1)
 	wrapped[0] = 0x8e
 	copy(wrapped[1:], data)
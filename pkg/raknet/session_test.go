@@ -0,0 +1,72 @@
+package raknet
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNAKResendsUnderNewSequenceNumber(t *testing.T) {
+	serverConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverConn.Close()
+	client, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	server := NewServer("")
+	server.conn = serverConn
+	session := NewSession(server, client.LocalAddr().(*net.UDPAddr), 1400, 1)
+
+	read := func() []byte {
+		buf := make([]byte, 1500)
+		n, _, err := client.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf[:n]
+	}
+
+	session.SendPacket([]byte{0x09, 1, 2, 3})
+	first := read()
+
+	// NAK for sequence number 0, sent as a single record.
+	session.handleNAK([]byte{IDNAcknowledge, 0, 1, 1, 0, 0, 0})
+	resent := read()
+
+	if seq := readTriad(resent[1:]); seq != 1 {
+		t.Errorf("resent sequence number = %d, want 1", seq)
+	}
+	if !bytes.Equal(resent[4:], first[4:]) {
+		t.Error("resent payload differs from the original")
+	}
+
+	// A second NAK for 0 must not resend again; the datagram now lives
+	// under sequence number 1.
+	session.handleNAK([]byte{IDNAcknowledge, 0, 1, 1, 0, 0, 0})
+	session.handleACK([]byte{IDAcknowledge, 0, 1, 1, 1, 0, 0})
+	stats := session.Stats()
+	if stats.Resends != 1 || stats.Lost != 1 || stats.PacketsSent != 2 || stats.RTT == 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestDecodeRecordsCapsTotal(t *testing.T) {
+	// Many ranges, each just inside the window, must not add up to more
+	// than one window of sequence numbers.
+	const ranges = 200
+	data := []byte{IDAcknowledge, byte(ranges >> 8), byte(ranges)}
+	for i := 0; i < ranges; i++ {
+		end := uint32(recoveryWindow - 1)
+		data = append(data, 0, 0, 0, 0, byte(end), byte(end>>8), byte(end>>16))
+	}
+	if got := len(decodeRecords(data)); got != recoveryWindow {
+		t.Errorf("decoded %d sequence numbers, want %d", got, recoveryWindow)
+	}
+}
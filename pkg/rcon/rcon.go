@@ -0,0 +1,281 @@
+package rcon
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const (
+	typeResponseValue int32 = 0
+	typeExecCommand   int32 = 2
+	typeAuthResponse  int32 = 2
+	typeAuth          int32 = 3
+
+	maxRequestSize  = 4096
+	maxResponseBody = 4096
+	idleTimeout     = 5 * time.Minute
+	attemptWindow   = 10 * time.Minute
+)
+
+var errPacketSize = errors.New("invalid rcon packet size")
+
+type Executor func(command string) string
+
+type Config struct {
+	Address         string
+	Password        string
+	MaxConnections  int
+	MaxAttempts     int
+	LockoutDuration time.Duration
+}
+
+type Server struct {
+	cfg      Config
+	execute  Executor
+	listener net.Listener
+
+	mu       sync.Mutex
+	conns    map[net.Conn]bool
+	failures map[string]*failure
+	closed   bool
+}
+
+type failure struct {
+	count       int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+type packet struct {
+	id   int32
+	kind int32
+	body string
+}
+
+func NewServer(cfg Config, execute Executor) *Server {
+	return &Server{
+		cfg:      cfg,
+		execute:  execute,
+		conns:    make(map[net.Conn]bool),
+		failures: make(map[string]*failure),
+	}
+}
+
+func (s *Server) Start() error {
+	if s.cfg.Password == "" {
+		return errors.New("rcon.password must be set to enable RCON")
+	}
+	ln, err := net.Listen("tcp", s.cfg.Address)
+	if err != nil {
+		return err
+	}
+	s.listener = ln
+	go s.acceptLoop()
+	logger.Server("RCON listening", "address", ln.Addr().String())
+	return nil
+}
+
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	conns := make([]net.Conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return
+			}
+			logger.Error("RCON accept failed", "error", err)
+			continue
+		}
+
+		ip := remoteIP(conn)
+		if s.isLocked(ip) {
+			logger.Warn("RCON connection from locked out address", "address", ip)
+			conn.Close()
+			continue
+		}
+
+		s.mu.Lock()
+		if s.cfg.MaxConnections > 0 && len(s.conns) >= s.cfg.MaxConnections {
+			s.mu.Unlock()
+			logger.Warn("RCON connection limit reached", "address", ip)
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		go s.handle(conn, ip)
+	}
+}
+
+func (s *Server) handle(conn net.Conn, ip string) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	authed := false
+	for {
+		conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		pk, err := readPacket(r)
+		if err != nil {
+			if err != io.EOF {
+				logger.Debug("RCON connection closed", "address", ip, "error", err)
+			}
+			return
+		}
+
+		switch {
+		case pk.kind == typeAuth:
+			if subtle.ConstantTimeCompare([]byte(pk.body), []byte(s.cfg.Password)) != 1 {
+				s.recordFailure(ip)
+				logger.Warn("RCON authentication failed", "address", ip)
+				writePacket(conn, packet{id: -1, kind: typeAuthResponse})
+				return
+			}
+			s.clearFailures(ip)
+			authed = true
+			logger.Server("RCON client authenticated", "address", ip)
+			if err := writePacket(conn, packet{id: pk.id, kind: typeResponseValue}); err != nil {
+				return
+			}
+			if err := writePacket(conn, packet{id: pk.id, kind: typeAuthResponse}); err != nil {
+				return
+			}
+
+		case !authed:
+			return
+
+		case pk.kind == typeExecCommand:
+			logger.Server("RCON command", "address", ip, "command", pk.body)
+			if err := writeResponse(conn, pk.id, s.execute(pk.body)); err != nil {
+				return
+			}
+
+		case pk.kind == typeResponseValue:
+			if err := writePacket(conn, packet{id: pk.id, kind: typeResponseValue}); err != nil {
+				return
+			}
+			if err := writePacket(conn, packet{id: pk.id, kind: typeResponseValue, body: "\x00\x01\x00\x00"}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) isLocked(ip string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.failures[ip]
+	return f != nil && time.Now().Before(f.lockedUntil)
+}
+
+func (s *Server) recordFailure(ip string) {
+	if s.cfg.MaxAttempts <= 0 {
+		return
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.failures[ip]
+	if f == nil || now.Sub(f.first) > attemptWindow {
+		f = &failure{first: now}
+		s.failures[ip] = f
+	}
+	f.count++
+	if f.count >= s.cfg.MaxAttempts {
+		f.lockedUntil = now.Add(s.cfg.LockoutDuration)
+		f.count = 0
+		f.first = now
+		logger.Warn("RCON address locked out", "address", ip, "until", f.lockedUntil.Format(time.RFC3339))
+	}
+}
+
+func (s *Server) clearFailures(ip string) {
+	s.mu.Lock()
+	delete(s.failures, ip)
+	s.mu.Unlock()
+}
+
+func remoteIP(conn net.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+	return host
+}
+
+func readPacket(r io.Reader) (packet, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return packet{}, err
+	}
+	if size < 10 || size > maxRequestSize+10 {
+		return packet{}, errPacketSize
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return packet{}, err
+	}
+	return packet{
+		id:   int32(binary.LittleEndian.Uint32(buf[0:4])),
+		kind: int32(binary.LittleEndian.Uint32(buf[4:8])),
+		body: string(buf[8 : len(buf)-2]),
+	}, nil
+}
+
+func writePacket(w io.Writer, pk packet) error {
+	buf := make([]byte, 14+len(pk.body))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(10+len(pk.body)))
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(pk.id))
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(pk.kind))
+	copy(buf[12:], pk.body)
+	_, err := w.Write(buf)
+	return err
+}
+
+func writeResponse(w io.Writer, id int32, body string) error {
+	for {
+		chunk := body
+		if len(chunk) > maxResponseBody {
+			chunk = chunk[:maxResponseBody]
+		}
+		if err := writePacket(w, packet{id: id, kind: typeResponseValue, body: chunk}); err != nil {
+			return err
+		}
+		body = body[len(chunk):]
+		if body == "" {
+			return nil
+		}
+	}
+}
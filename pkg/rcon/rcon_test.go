@@ -0,0 +1,157 @@
+package rcon
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, cfg Config, execute Executor) string {
+	t.Helper()
+	cfg.Address = "127.0.0.1:0"
+	cfg.Password = "secret"
+	s := NewServer(cfg, execute)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	return s.listener.Addr().String()
+}
+
+type client struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) *client {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &client{conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *client) send(t *testing.T, id, kind int32, body string) {
+	t.Helper()
+	if err := writePacket(c.conn, packet{id: id, kind: kind, body: body}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *client) read(t *testing.T) packet {
+	t.Helper()
+	pk, err := readPacket(c.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk
+}
+
+// closed reports whether the server hung up without sending anything.
+func (c *client) closed() bool {
+	_, err := c.r.ReadByte()
+	return err != nil
+}
+
+func (c *client) auth(t *testing.T, password string) bool {
+	t.Helper()
+	c.send(t, 1, typeAuth, password)
+	pk := c.read(t)
+	if pk.id == -1 {
+		return false
+	}
+	if pk.kind != typeResponseValue {
+		t.Fatalf("first auth reply kind = %d", pk.kind)
+	}
+	if pk = c.read(t); pk.id != 1 || pk.kind != typeAuthResponse {
+		t.Fatalf("auth response = %+v", pk)
+	}
+	return true
+}
+
+func TestAuthLockout(t *testing.T) {
+	addr := startServer(t, Config{MaxAttempts: 2, LockoutDuration: time.Minute}, func(string) string { return "" })
+
+	for i := 0; i < 2; i++ {
+		c := dial(t, addr)
+		if c.auth(t, "wrong") {
+			t.Fatal("wrong password accepted")
+		}
+		if !c.closed() {
+			t.Error("connection left open after a failed login")
+		}
+	}
+	if c := dial(t, addr); !c.closed() {
+		t.Error("locked out address was not disconnected")
+	}
+}
+
+func TestUnauthenticatedCommand(t *testing.T) {
+	ran := false
+	addr := startServer(t, Config{}, func(string) string { ran = true; return "" })
+	c := dial(t, addr)
+	c.send(t, 5, typeExecCommand, "stop")
+	if !c.closed() || ran {
+		t.Error("command ran without authentication")
+	}
+}
+
+func TestConnectionLimit(t *testing.T) {
+	addr := startServer(t, Config{MaxConnections: 1}, func(string) string { return "" })
+
+	first := dial(t, addr)
+	if !first.auth(t, "secret") {
+		t.Fatal("auth failed")
+	}
+	if c := dial(t, addr); !c.closed() {
+		t.Error("connection over the limit was accepted")
+	}
+}
+
+func TestMultiPacketResponse(t *testing.T) {
+	output := strings.Repeat("x", 2*maxResponseBody+100)
+	addr := startServer(t, Config{}, func(cmd string) string {
+		if cmd != "list" {
+			return "unknown"
+		}
+		return output
+	})
+	c := dial(t, addr)
+	if !c.auth(t, "secret") {
+		t.Fatal("auth failed")
+	}
+
+	c.send(t, 7, typeExecCommand, "list")
+	c.send(t, 8, typeResponseValue, "")
+
+	var got strings.Builder
+	var sizes []int
+	for {
+		pk := c.read(t)
+		if pk.id == 8 {
+			if pk.body != "" {
+				t.Fatalf("terminator echo body = %q", pk.body)
+			}
+			break
+		}
+		if pk.id != 7 || pk.kind != typeResponseValue {
+			t.Fatalf("response packet = id %d kind %d", pk.id, pk.kind)
+		}
+		sizes = append(sizes, len(pk.body))
+		got.WriteString(pk.body)
+	}
+	if got.String() != output {
+		t.Errorf("reassembled %d bytes, want %d", got.Len(), len(output))
+	}
+	if len(sizes) != 3 || sizes[0] != maxResponseBody || sizes[2] != 100 {
+		t.Errorf("packet sizes = %v", sizes)
+	}
+	if pk := c.read(t); pk.id != 8 || pk.body != "\x00\x01\x00\x00" {
+		t.Errorf("terminator trailer = %+v", pk)
+	}
+}
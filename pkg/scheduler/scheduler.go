@@ -1,5 +1,11 @@
 package scheduler
 
+import (
+	"sync"
+
+	"github.com/scaxe/scaxe-go/pkg/timings"
+)
+
 type Task interface {
 	Name() string
 	OnRun(currentTick int64)
@@ -100,6 +106,7 @@ func (h *TaskHandler) Remove() {
 
 func (h *TaskHandler) Run(currentTick int64) {
 	if !h.cancelled && h.task != nil {
+		defer timings.StartChild(timings.SchedulerHeartbeat, "Task: "+h.task.Name())()
 		h.task.OnRun(currentTick)
 	}
 }
@@ -109,6 +116,10 @@ type Scheduler struct {
 	queue       []*TaskHandler
 	nextID      int
 	currentTick int64
+
+	// heartbeatMu stops servers sharing the global scheduler in one
+	// process, such as a proxy and its backends, from running tasks at once.
+	heartbeatMu sync.Mutex
 }
 
 func NewScheduler() *Scheduler {
@@ -202,6 +213,8 @@ func (s *Scheduler) IsQueued(taskID int) bool {
 }
 
 func (s *Scheduler) MainThreadHeartbeat(currentTick int64) {
+	s.heartbeatMu.Lock()
+	defer s.heartbeatMu.Unlock()
 	s.currentTick = currentTick
 
 	for s.isReady(currentTick) {
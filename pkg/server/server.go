@@ -4,28 +4,39 @@ import (
 	"fmt"
 	"math"
 	"math/rand/v2"
+	"net/http"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/scaxe/scaxe-go/internal/version"
+	"github.com/scaxe/scaxe-go/pkg/backup"
 	"github.com/scaxe/scaxe-go/pkg/block"
+	"github.com/scaxe/scaxe-go/pkg/capture"
 	"github.com/scaxe/scaxe-go/pkg/command"
 	"github.com/scaxe/scaxe-go/pkg/command/defaults"
 	"github.com/scaxe/scaxe-go/pkg/config"
 	"github.com/scaxe/scaxe-go/pkg/entity"
 	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/inventory"
 	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/item/enchantment"
 	"github.com/scaxe/scaxe-go/pkg/level"
 	"github.com/scaxe/scaxe-go/pkg/level/anvil"
 	"github.com/scaxe/scaxe-go/pkg/logger"
 	luapkg "github.com/scaxe/scaxe-go/pkg/lua"
+	"github.com/scaxe/scaxe-go/pkg/metrics"
+	"github.com/scaxe/scaxe-go/pkg/network"
 	"github.com/scaxe/scaxe-go/pkg/permission"
 	"github.com/scaxe/scaxe-go/pkg/player"
+	"github.com/scaxe/scaxe-go/pkg/plugin"
 	"github.com/scaxe/scaxe-go/pkg/protocol"
-	"github.com/scaxe/scaxe-go/pkg/raknet"
+	"github.com/scaxe/scaxe-go/pkg/query"
+	"github.com/scaxe/scaxe-go/pkg/rcon"
 	"github.com/scaxe/scaxe-go/pkg/scheduler"
+	"github.com/scaxe/scaxe-go/pkg/timings"
+	"github.com/scaxe/scaxe-go/pkg/webadmin"
 )
 
 const (
@@ -38,7 +49,7 @@ type Server struct {
 
 	Config *config.ServerConfig
 
-	RakNet  *raknet.Server
+	Network network.Transport
 	Address string
 
 	Players       map[string]*player.Player
@@ -59,12 +70,26 @@ type Server struct {
 	packetBuffersMu sync.Mutex
 
 	stopChan chan struct{}
+	tickDone chan struct{}
+
+	// tickMu serializes packet handlers with the tick, so handlers running
+	// on network goroutines see the same world state as the main thread.
+	tickMu sync.Mutex
 
 	CommandMap *command.CommandMap
 
-	OpManager *permission.OpManager
+	OpManager       *permission.OpManager
+	BanList         *permission.BanList
+	PermissionStore *permission.Store
+	Backups         *backup.Manager
+	pprofServer     *http.Server
+	metricsServer   *http.Server
+	Query           *query.Handler
+	Rcon            *rcon.Server
+	WebAdmin        *webadmin.Server
 
 	PluginManager *luapkg.PluginManager
+	NativePlugins *plugin.Manager
 }
 
 func NewServer(cfg *config.ServerConfig) *Server {
@@ -90,9 +115,42 @@ func NewServer(cfg *config.ServerConfig) *Server {
 func (s *Server) Start() error {
 	logger.Server("Starting server", "address", s.Address)
 
+	block.Registry.Init()
 	permission.RegisterDefaultPermissions()
+	s.PermissionStore = permission.NewStore("permissions.yml")
+	if err := s.PermissionStore.Load(); err != nil {
+		logger.Error("Failed to load permissions.yml", "error", err)
+	}
+	permission.GlobalManager.SetStore(s.PermissionStore)
+
+	s.Backups = backup.NewManager("backups", "worlds", s.Config.BackupRetention)
+
+	timings.SetSpikeThreshold(time.Duration(s.Config.TimingsSpikeThreshold) * time.Millisecond)
+	if s.Config.Timings {
+		timings.Enable()
+	}
+	if s.Config.PprofAddress != "" {
+		srv, err := timings.ServePprof(s.Config.PprofAddress)
+		if err != nil {
+			logger.Error("Failed to start pprof endpoint", "error", err)
+		}
+		s.pprofServer = srv
+	}
+	if s.Config.MetricsAddress != "" {
+		s.startMetrics()
+	}
 
-	s.RakNet = raknet.NewServer(s.Address)
+	transport, err := network.New(s.Config.NetworkTransport, s.Address)
+	if err != nil {
+		return err
+	}
+	s.Network = transport
+	if rak, ok := transport.(*network.RakNetTransport); ok {
+		rak.Limits = s.rakNetLimits()
+	}
+	if link, ok := transport.(*network.LinkTransport); ok {
+		s.configureProxyLink(link)
+	}
 
 	s.CommandMap = command.NewCommandMap()
 	s.CommandMap.Register(defaults.NewListCommand(s))
@@ -104,6 +162,10 @@ func (s *Server) Start() error {
 	if err := s.OpManager.Load(); err != nil {
 		logger.Error("Failed to load ops.json", "error", err)
 	}
+	s.BanList = permission.NewBanList("banned-players.json")
+	if err := s.BanList.Load(); err != nil {
+		logger.Error("Failed to load banned-players.json", "error", err)
+	}
 
 	motd := fmt.Sprintf("MCPE;%s;%d;%s;%d;%d;%d;%s;Survival",
 		s.Config.MOTD,
@@ -111,16 +173,22 @@ func (s *Server) Start() error {
 		"0.14.2",
 		s.GetOnlineCount(),
 		s.Config.MaxPlayers,
-		s.RakNet.ServerID(),
+		s.Network.ServerID(),
 		s.Config.LevelName,
 	)
-	s.RakNet.SetPongData([]byte(motd))
+	s.Network.SetPongData([]byte(motd))
 
-	s.RakNet.OnConnect = s.handleConnect
-	s.RakNet.OnDisconnect = s.handleDisconnect
-	s.RakNet.OnPacket = s.handlePacket
+	s.Network.SetHandler(network.Handler{
+		OnConnect:    s.handleConnect,
+		OnDisconnect: s.handleDisconnect,
+		OnPacket:     s.handlePacket,
+	})
+	if raw, ok := s.Network.(network.RawPacketTransport); ok && s.Config.EnableQuery {
+		s.Query = query.NewHandler(s.queryInfo, s.Config.QueryRateLimit)
+		raw.SetRawHandler(s.handleRawPacket)
+	}
 
-	if err := s.RakNet.Start(); err != nil {
+	if err := s.Network.Start(); err != nil {
 		return err
 	}
 
@@ -137,6 +205,7 @@ func (s *Server) Start() error {
 	}
 
 	s.Level = level.NewLevel(s.Config.LevelName, levelPath, provider, s.Config.LevelType)
+	s.Level.SetPlayerProvider(s.levelPlayers(s.Level))
 	s.Levels[s.Config.LevelName] = s.Level
 
 	spawn := s.Level.GetSpawnLocation()
@@ -151,17 +220,57 @@ func (s *Server) Start() error {
 	}
 	logger.Server("Spawn area ready", "chunks", (spawnChunkRadius*2+1)*(spawnChunkRadius*2+1))
 
+	s.NativePlugins = plugin.NewManager(NewPluginServerAdapter(s), s.CommandMap, "plugins")
+	if err := s.NativePlugins.LoadAll(); err != nil {
+		logger.Warn("Failed to load some native plugins", "error", err)
+	}
+	defaults.SetNativePluginManager(s.NativePlugins)
+
 	s.PluginManager = luapkg.NewPluginManager(NewServerAPIAdapter(s), "plugins")
+	limits := luapkg.DefaultLimits()
+	limits.CallbackTimeout = time.Duration(s.Config.LuaCallbackTimeout) * time.Millisecond
+	limits.MemoryLimit = uint64(s.Config.LuaMemoryLimit) << 20
+	limits.MaxFaults = s.Config.LuaMaxFaults
+	s.PluginManager.SetLimits(limits)
 	if err := s.PluginManager.LoadAll(); err != nil {
 		logger.Warn("Failed to load some plugins", "error", err)
 	}
 	defaults.SetPluginManager(s.PluginManager)
 
+	if s.Config.BackupInterval > 0 {
+		scheduler.RunRepeating("auto-backup", s.Config.BackupInterval*60*TicksPerSecond, func(int64) {
+			s.backupAll()
+		})
+	}
+
+	if s.Config.EnableRcon {
+		s.startRcon()
+	}
+	if s.Config.WebAdminAddress != "" {
+		s.startWebAdmin()
+	}
+
+	s.tickDone = make(chan struct{})
 	go s.tickLoop()
 
 	return nil
 }
 
+func (s *Server) backupAll() {
+	s.mu.RLock()
+	levels := make([]*level.Level, 0, len(s.Levels))
+	for _, lvl := range s.Levels {
+		levels = append(levels, lvl)
+	}
+	s.mu.RUnlock()
+
+	for _, lvl := range levels {
+		if err := s.Backups.Create(lvl, nil); err != nil {
+			logger.Warn("Skipped automatic backup", "world", lvl.Name, "error", err)
+		}
+	}
+}
+
 func (s *Server) StopChan() <-chan struct{} {
 	return s.stopChan
 }
@@ -180,6 +289,9 @@ func (s *Server) Stop() {
 	if s.PluginManager != nil {
 		s.PluginManager.DisableAll()
 	}
+	if s.NativePlugins != nil {
+		s.NativePlugins.DisableAll()
+	}
 
 	select {
 	case <-s.stopChan:
@@ -187,9 +299,19 @@ func (s *Server) Stop() {
 	default:
 		close(s.stopChan)
 	}
+	if s.tickDone != nil {
+		<-s.tickDone
+	}
 
+	s.tickMu.Lock()
 	logger.Debug("Disconnecting all players")
+	s.mu.RLock()
+	sessions := make([]*player.Player, 0, len(s.Players))
 	for _, p := range s.Players {
+		sessions = append(sessions, p)
+	}
+	s.mu.RUnlock()
+	for _, p := range sessions {
 		p.Kick("Server closed", false)
 	}
 
@@ -200,10 +322,24 @@ func (s *Server) Stop() {
 			logger.Debug("Saved level", "name", name)
 		}
 	}
+	s.tickMu.Unlock()
+
+	if s.pprofServer != nil {
+		s.pprofServer.Close()
+	}
+	if s.metricsServer != nil {
+		s.metricsServer.Close()
+	}
+	if s.Rcon != nil {
+		s.Rcon.Close()
+	}
+	if s.WebAdmin != nil {
+		s.WebAdmin.Close()
+	}
 
 	logger.Debug("Stopping network interfaces")
-	if s.RakNet != nil {
-		s.RakNet.Stop()
+	if s.Network != nil {
+		s.Network.Stop()
 	}
 
 	logger.Server("Server stopped")
@@ -221,6 +357,12 @@ func (s *Server) GetOnlineCount() int {
 	return len(s.PlayersByName)
 }
 
+func (s *Server) GetCurrentTick() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.CurrentTick
+}
+
 func (s *Server) GetTPS() float64 {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -292,9 +434,9 @@ func (s *Server) broadcastPacketExcept(pkt protocol.DataPacket, except *player.P
 }
 
 func (s *Server) sendPacketUnsafe(p *player.Player, pkt protocol.DataPacket) {
-	stream := protocol.NewBinaryStream()
-	pkt.Encode(stream)
-	p.Session.SendPacket(stream.Bytes())
+	if data := p.EncodePacket(pkt); data != nil {
+		p.Session.SendPacket(data)
+	}
 }
 
 func (s *Server) updatePlayerListAdd(p *player.Player) {
@@ -432,13 +574,16 @@ func (s *Server) syncInventory(p *player.Player) {
 func (s *Server) tickLoop() {
 	ticker := time.NewTicker(TickDuration)
 	defer ticker.Stop()
+	defer close(s.tickDone)
 
 	for {
 		select {
 		case <-s.stopChan:
 			return
 		case <-ticker.C:
+			s.tickMu.Lock()
 			s.tick()
+			s.tickMu.Unlock()
 		}
 	}
 }
@@ -449,13 +594,17 @@ func (s *Server) NextEntityID() int64 {
 
 func (s *Server) tick() {
 	tickStart := time.Now()
+	stopTick := timings.Start(timings.FullTick)
 
 	s.mu.Lock()
 	s.CurrentTick++
 	s.mu.Unlock()
 
 	if s.Level != nil {
+		stop := timings.Start(timings.LevelTick)
 		s.Level.Tick()
+		stop()
+		stop = timings.Start(timings.BlockBroadcast)
 		if len(s.Level.PendingBlockUpdates) > 0 {
 			for _, upd := range s.Level.PendingBlockUpdates {
 				updPk := protocol.NewUpdateBlockPacket(upd.X, upd.Y, upd.Z, upd.ID, upd.Meta)
@@ -463,6 +612,10 @@ func (s *Server) tick() {
 			}
 			s.Level.PendingBlockUpdates = s.Level.PendingBlockUpdates[:0]
 		}
+		stop()
+		stop = timings.Start(timings.EntityBroadcast)
+		s.broadcastEntityUpdates(s.Level)
+		stop()
 	}
 
 	for _, p := range s.GetOnlinePlayers() {
@@ -474,18 +627,25 @@ func (s *Server) tick() {
 			}()
 
 			if p.LoadingChunks && !p.IsSpawned() {
+				stop := timings.Start(timings.ChunkSends)
 				s.checkChunks(p)
+				stop()
 				s.tryFirstSpawn(p)
 			}
 
 			if p.IsSpawned() {
+				stop := timings.Start(timings.PlayerTick)
 				p.Tick(s.CurrentTick)
+				stop()
+				stop = timings.Start(timings.ChunkSends)
 				s.checkChunks(p)
+				stop()
 			}
 		}()
 	}
 
 	if s.Level != nil {
+		stop := timings.Start(timings.EntityMovement)
 		pk := protocol.NewMoveEntityPacket()
 		for _, e := range s.Level.GetEntities() {
 			hasMove := e.HasMovementUpdate()
@@ -511,6 +671,7 @@ func (s *Server) tick() {
 				}
 			}
 		}
+		stop()
 	}
 
 	s.mu.Lock()
@@ -521,34 +682,56 @@ func (s *Server) tick() {
 	s.mu.Unlock()
 
 	if s.PluginManager != nil {
+		stop := timings.Start(timings.PluginTick)
 		s.PluginManager.Tick(currentTick)
+		stop()
 	}
 
+	stop := timings.Start(timings.PacketFlush)
 	s.flushPackets()
+	stop()
+
+	stop = timings.Start(timings.SchedulerHeartbeat)
 	scheduler.GetGlobalScheduler().MainThreadHeartbeat(currentTick)
+	stop()
+	stopTick()
+	timings.EndTick(currentTick)
 }
 
-func (s *Server) handleConnect(session *raknet.Session) {
+func (s *Server) handleConnect(session network.Conn) {
 	addr := session.Address()
 	logger.Server("New connection", "address", addr)
 
-	p := player.NewPlayer(session, addr, 0)
+	p := player.NewPlayer(capture.Wrap(session), addr, 0)
+	p.SetItemDropFunc(s.dropItem)
+	adoptProxyIdentity(p, session)
 
 	s.mu.Lock()
 	s.Players[addr] = p
 	s.mu.Unlock()
 }
 
-func (s *Server) handleDisconnect(session *raknet.Session) {
+func (s *Server) handleDisconnect(session network.Conn) {
 	addr := session.Address()
 
-	s.mu.Lock()
+	s.mu.RLock()
 	p, exists := s.Players[addr]
+	s.mu.RUnlock()
 	if !exists {
-		s.mu.Unlock()
 		return
 	}
+	// A session the server closed itself reports back from inside the kick,
+	// which already holds tickMu; only drops from the network take it here.
+	if p.IsConnected() {
+		s.tickMu.Lock()
+		defer s.tickMu.Unlock()
+	}
 
+	s.mu.Lock()
+	if s.Players[addr] != p {
+		s.mu.Unlock()
+		return
+	}
 	delete(s.Players, addr)
 	s.mu.Unlock()
 
@@ -566,10 +749,10 @@ func (s *Server) UpdatePong() {
 		"0.14.2",
 		s.GetOnlineCount(),
 		s.Config.MaxPlayers,
-		s.RakNet.ServerID(),
+		s.Network.ServerID(),
 		s.Config.LevelName,
 	)
-	s.RakNet.SetPongData([]byte(motd))
+	s.Network.SetPongData([]byte(motd))
 }
 
 func (s *Server) handlePlayerQuit(p *player.Player) {
@@ -578,7 +761,9 @@ func (s *Server) handlePlayerQuit(p *player.Player) {
 	event.Call(quitEvt)
 
 	s.mu.Lock()
-	delete(s.PlayersByName, username)
+	if s.PlayersByName[username] == p {
+		delete(s.PlayersByName, username)
+	}
 	s.mu.Unlock()
 
 	s.UpdatePong()
@@ -602,11 +787,14 @@ func (s *Server) handlePlayerQuit(p *player.Player) {
 	logger.PlayerLeave(username, "disconnected")
 }
 
-func (s *Server) handlePacket(session *raknet.Session, data []byte) {
+func (s *Server) handlePacket(session network.Conn, data []byte) {
 	if len(data) == 0 {
 		return
 	}
 
+	s.tickMu.Lock()
+	defer s.tickMu.Unlock()
+
 	addr := session.Address()
 	s.mu.RLock()
 	p, exists := s.Players[addr]
@@ -617,20 +805,26 @@ func (s *Server) handlePacket(session *raknet.Session, data []byte) {
 		return
 	}
 
-	packetID := data[0]
-	pkt := protocol.GetPacket(packetID)
-	if pkt == nil {
-		logger.Debug("Unknown packet", "id", fmt.Sprintf("0x%02x", packetID), "from", addr)
+	if p.Codec == nil && data[0] == protocol.IDLogin && !s.negotiateCodec(p, data) {
+		return
+	}
+	codec := p.PacketCodec()
+
+	pkt, err := codec.Decode(data)
+	if pkt == nil && err == nil {
+		logger.Debug("Unknown packet", "id", fmt.Sprintf("0x%02x", data[0]), "from", addr, "codec", codec.Name)
 		return
 	}
+	packetID, _ := codec.PacketID(data[0])
 
-	logger.PacketIn(pkt.Name(), addr, "id", fmt.Sprintf("0x%02x", packetID), "size", len(data))
+	logger.PacketIn(pkt.Name(), addr, "id", fmt.Sprintf("0x%02x", data[0]), "size", len(data))
+	metrics.PacketIn(packetID, len(data))
 
-	stream := protocol.NewBinaryStreamFromBytes(data[1:])
-	if err := pkt.Decode(stream); err != nil {
+	if err != nil {
 		logger.Error("Failed to decode packet", "packet", pkt.Name(), "error", err)
 		return
 	}
+	s.recordInbound(p, pkt, data)
 
 	switch pk := pkt.(type) {
 	case *protocol.LoginPacket:
@@ -655,26 +849,38 @@ func (s *Server) handlePacket(session *raknet.Session, data []byte) {
 		s.handleDropItem(p, pk)
 	case *protocol.ContainerSetSlotPacket:
 		s.handleContainerSetSlot(p, pk)
+	case *protocol.MobArmorEquipmentPacket:
+		p.HandleArmorEquipment(pk.Slots)
+	case *protocol.ContainerClosePacket:
+		p.HandleContainerClose(pk.WindowID)
+	case *protocol.InteractPacket:
+		s.handleInteract(p, pk)
+	case *protocol.EntityEventPacket:
+		p.HandleEntityEvent(pk.Event)
 	default:
 		logger.Debug("Unhandled packet", "packet", pkt.Name())
 	}
 }
 
 func (s *Server) sendPacket(p *player.Player, pkt protocol.DataPacket) {
-	stream := protocol.NewBinaryStream()
-	pkt.Encode(stream)
+	data := p.EncodePacket(pkt)
+	if data == nil {
+		return
+	}
 
 	s.packetBuffersMu.Lock()
-	s.packetBuffers[p] = append(s.packetBuffers[p], stream.Bytes())
+	s.packetBuffers[p] = append(s.packetBuffers[p], data)
 	s.packetBuffersMu.Unlock()
 
 	logger.PacketOut(pkt.Name(), p.GetAddress(), "id", fmt.Sprintf("0x%02x", pkt.ID()), "buffered", true)
 }
 
 func (s *Server) sendPacketImmediate(p *player.Player, pkt protocol.DataPacket) {
-	stream := protocol.NewBinaryStream()
-	pkt.Encode(stream)
-	p.Session.SendPacket(stream.Bytes())
+	data := p.EncodePacket(pkt)
+	if data == nil {
+		return
+	}
+	p.Session.SendPacket(data)
 
 	logger.PacketOut(pkt.Name(), p.GetAddress(), "id", fmt.Sprintf("0x%02x", pkt.ID()))
 }
@@ -704,17 +910,20 @@ func (s *Server) handleLogin(p *player.Player, pkt *protocol.LoginPacket) {
 		return
 	}
 
-	if !protocol.IsProtocolSupported(int(pkt.Protocol)) {
-		logger.Warn("Unsupported protocol", "player", pkt.Username, "protocol", pkt.Protocol)
+	if !s.admitLogin(p, pkt) {
+		return
 	}
 
 	logger.Debug("Login Check", "online", s.GetOnlineCount(), "max", s.Config.MaxPlayers)
 	if s.GetOnlineCount() >= s.Config.MaxPlayers {
-		p.Kick("disconnectionScreen.serverFull", false)
+		p.Kick(player.DisconnectServerFull, false)
 		return
 	}
 
 	p.HandleLogin(pkt.Username, pkt.ClientUUID, pkt.SkinID, pkt.SkinData, pkt.Protocol)
+	if !p.IsConnected() {
+		return
+	}
 	p.ClientID = uint64(pkt.ClientID)
 	p.SetGamemode(s.Config.Gamemode)
 
@@ -801,7 +1010,7 @@ func (s *Server) handleLogin(p *player.Player, pkt *protocol.LoginPacket) {
 
 	logger.Server("Sending game data", "player", pkt.Username, "packets", len(batchPackets))
 
-	batchPayload, err := protocol.CreateBatch(batchPackets)
+	batchPayload, err := p.PacketCodec().CreateBatch(batchPackets)
 	if err != nil {
 		logger.Error("Failed to create batch", "error", err)
 		return
@@ -1021,7 +1230,7 @@ func (s *Server) checkChunks(p *player.Player) {
 	}
 
 	if len(chunkPackets) > 0 {
-		batchPayload, err := protocol.CreateBatch(chunkPackets)
+		batchPayload, err := p.PacketCodec().CreateBatch(chunkPackets)
 		if err != nil {
 			logger.Error("Failed to create chunk batch", "error", err)
 		} else {
@@ -1103,6 +1312,12 @@ func (s *Server) handleMovePlayer(p *player.Player, pkt *protocol.MovePlayerPack
 func (s *Server) handlePlayerAction(p *player.Player, pkt *protocol.PlayerActionPacket) {
 	p.HandleAction(pkt.Action)
 
+	switch pkt.Action {
+	case protocol.ActionStartBreak:
+		p.BroadcastBreakProgress(pkt.X, pkt.Y, pkt.Z, true)
+	case protocol.ActionAbortBreak, protocol.ActionStopBreak:
+		p.BroadcastBreakProgress(pkt.X, pkt.Y, pkt.Z, false)
+	}
 	if pkt.Action == 2 {
 		s.breakBlock(p, pkt.X, pkt.Y, pkt.Z)
 	}
@@ -1122,6 +1337,11 @@ func (s *Server) breakBlock(p *player.Player, x, y, z int32) {
 	}
 	held := p.Inventory.GetItemInHand()
 	breakEvt := event.NewBlockBreakEvent(int(x), int(y), int(z), int(bid), int(meta), p.GetEntityID(), int(held.ID))
+	breakEvt.ExpToDrop = block.GetDropExperience(uint8(bid), meta, held)
+	breakEvt.FastBreak = p.Gamemode == 1
+	if !breakEvt.FastBreak && !p.FinishBreak(uint8(bid)) {
+		breakEvt.SetCancelled(true)
+	}
 	event.Call(breakEvt)
 	if breakEvt.IsCancelled() {
 		revertPk := protocol.NewUpdateBlockPacket(x, int32(y), z, bid, meta)
@@ -1129,8 +1349,7 @@ func (s *Server) breakBlock(p *player.Player, x, y, z int32) {
 		return
 	}
 
-	tool := item.NewItem(0, 0, 0)
-	drops := block.GetDrops(uint8(bid), uint8(meta), tool)
+	drops := block.GetDrops(uint8(bid), uint8(meta), held)
 
 	chunk.SetBlock(int(x&0xf), int(y), int(z&0xf), 0, 0)
 
@@ -1148,6 +1367,18 @@ func (s *Server) breakBlock(p *player.Player, x, y, z int32) {
 				s.dropItem(float32(x)+0.5, float32(y)+0.5, float32(z)+0.5, drop)
 			}
 		}
+		entity.DropExperience(s.Level, entity.NewVector3(float64(x)+0.5, float64(y), float64(z)+0.5), breakEvt.ExpToDrop)
+		if item.IsTool(held.ID) {
+			hardness := block.Registry.GetHardness(bid)
+			toolType := item.ToolTypeNone
+			if behavior := block.Registry.GetBehavior(bid); behavior != nil {
+				toolType = behavior.GetToolType()
+			}
+			if held.UseOn(item.UseTypeBreak, false, hardness, toolType, enchantment.UnbreakingLevel(held)) {
+				held = item.Air()
+			}
+			p.Inventory.SetItemInHand(held)
+		}
 	}
 }
 
@@ -1402,7 +1633,7 @@ func (s *Server) handleUseItem(p *player.Player, pkt *protocol.UseItemPacket) {
 			logger.Player("Used item on block", "player", p.Username, "item", held.ID, "x", pkt.X, "y", pkt.Y, "z", pkt.Z)
 		}
 	} else if pkt.Face == 0xff {
-
+		p.StartUsingItem()
 		logger.Player("Used item in air", "player", p.Username, "item", pkt.Item.ID)
 	}
 }
@@ -1427,6 +1658,10 @@ func (s *Server) handleBlockActivation(p *player.Player, bid, meta byte, x, y, z
 		result = block.FurnaceOnActivate()
 	case block.WORKBENCH:
 		result = block.CraftingTableOnActivate()
+	case block.ENCHANTING_TABLE:
+		result = block.EnchantingTableOnActivate()
+	case block.ANVIL:
+		result = block.AnvilOnActivate()
 	case block.HOPPER_BLOCK:
 		result = block.ActivateResult{
 			Handled:       true,
@@ -1536,6 +1771,20 @@ func (s *Server) handleBlockActivation(p *player.Player, bid, meta byte, x, y, z
 	}
 }
 func (s *Server) openContainerFor(p *player.Player, invType int, x, y, z int32) {
+	switch invType {
+	case block.InventoryTypeEnchant:
+		menu := inventory.NewFakeBlockMenu(nil, int(x), int(y), int(z))
+		inv := inventory.NewEnchantInventory(menu, s.countBookshelves(x, y, z))
+		menu.SetInventory(inv)
+		p.OpenInventory(inv)
+		return
+	case block.InventoryTypeAnvil:
+		menu := inventory.NewFakeBlockMenu(nil, int(x), int(y), int(z))
+		inv := inventory.NewAnvilInventory(menu)
+		menu.SetInventory(inv)
+		p.OpenInventory(inv)
+		return
+	}
 	windowID := byte(2)
 
 	openPk := protocol.NewContainerOpenPacket()
@@ -1557,6 +1806,22 @@ func (s *Server) openContainerFor(p *player.Player, invType int, x, y, z int32)
 	logger.Player("Opened container", "player", p.Username,
 		"type", invType, "x", x, "y", y, "z", z)
 }
+func (s *Server) countBookshelves(x, y, z int32) int {
+	count := 0
+	for dx := int32(-2); dx <= 2; dx++ {
+		for dz := int32(-2); dz <= 2; dz++ {
+			if dx > -2 && dx < 2 && dz > -2 && dz < 2 {
+				continue
+			}
+			for dy := int32(0); dy <= 1; dy++ {
+				if s.Level.GetBlockId(x+dx, y+dy, z+dz) == block.BOOKSHELF {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
 func (s *Server) getContainerSlotCount(invType int) int {
 	switch invType {
 	case block.InventoryTypeChest:
@@ -1613,46 +1878,34 @@ func (s *Server) handleContainerSetSlot(p *player.Player, pkt *protocol.Containe
 		}
 		p.Inventory.SetItem(int(pkt.Slot), pkt.Item)
 		logger.Player("Container set slot", "player", p.Username, "slot", pkt.Slot, "item", pkt.Item.ID, "meta", pkt.Item.Meta, "count", pkt.Item.Count)
+		return
 	}
+	if pkt.WindowID == inventory.SpecialArmor {
+		p.HandleArmorSlot(int(pkt.Slot), pkt.Item)
+		return
+	}
+	p.HandleContainerSlot(pkt.WindowID, int(pkt.Slot), pkt.Item)
 }
 
 func (s *Server) handleSpawnEgg(p *player.Player, networkID int, x, y, z float64) {
-	var mob *entity.Animal
-
-	switch networkID {
-	case entity.CowNetworkID:
-		mob = entity.NewCow()
-	case entity.PigNetworkID:
-		mob = entity.NewPig()
-	case entity.SheepNetworkID:
-		mob = entity.NewSheep().Animal
-	case entity.ChickenNetworkID:
-		mob = entity.NewChicken().Animal
-	default:
+	mob := entity.CreatePassiveMob(networkID)
+	if mob == nil {
 		logger.Player("Unknown spawn egg", "player", p.Username, "networkID", networkID)
 		return
 	}
+	animal := mob.GetAnimal()
 
-	mob.Entity.SetPosition(entity.NewVector3(x, y, z))
-	mob.Entity.Level = s.Level
-	mob.Entity.Yaw = float64(p.Yaw)
+	animal.Entity.SetPosition(entity.NewVector3(x, y, z))
+	animal.Entity.Level = s.Level
+	animal.Entity.Yaw = float64(p.Yaw)
 
-	s.Level.AddEntity(mob.Entity)
-
-	pk := protocol.NewAddEntityPacket()
-	pk.EntityID = mob.Entity.GetID()
-	pk.Type = int32(mob.Entity.NetworkID)
-	pk.X = float32(x)
-	pk.Y = float32(y)
-	pk.Z = float32(z)
-	pk.Yaw = float32(mob.Entity.Yaw)
-	pk.Pitch = float32(mob.Entity.Pitch)
-	s.BroadcastPacket(pk)
+	s.Level.AddEntity(mob)
+	s.BroadcastPacket(s.newAddEntityPacket(mob))
 
-	logger.Player("Spawned mob", "player", p.Username, "type", mob.MobName, "networkID", networkID,
+	logger.Player("Spawned mob", "player", p.Username, "type", animal.MobName, "networkID", networkID,
 		"pos", fmt.Sprintf("%.1f,%.1f,%.1f", x, y, z),
-		"entityID", mob.Entity.GetID(),
+		"entityID", mob.GetID(),
 		"bb", fmt.Sprintf("%.1f,%.1f,%.1f -> %.1f,%.1f,%.1f",
-			mob.Entity.BoundingBox.MinX, mob.Entity.BoundingBox.MinY, mob.Entity.BoundingBox.MinZ,
-			mob.Entity.BoundingBox.MaxX, mob.Entity.BoundingBox.MaxY, mob.Entity.BoundingBox.MaxZ))
+			animal.Entity.BoundingBox.MinX, animal.Entity.BoundingBox.MinY, animal.Entity.BoundingBox.MinZ,
+			animal.Entity.BoundingBox.MaxX, animal.Entity.BoundingBox.MaxY, animal.Entity.BoundingBox.MaxZ))
 }
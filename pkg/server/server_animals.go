@@ -0,0 +1,195 @@
+package server
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/player"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+type tameableEntity interface {
+	IsTamed() bool
+	IsOwner(e entity.IEntity) bool
+	TryTame(owner entity.IEntity) bool
+	ToggleSitting()
+	GetTameItemID() int
+}
+
+type metadataHolder interface {
+	GetMetadata() *entity.MetadataStore
+}
+
+func (s *Server) levelPlayers(lvl *level.Level) level.PlayerProvider {
+	return func() []entity.IEntity {
+		var players []entity.IEntity
+		for _, p := range s.GetOnlinePlayers() {
+			if p.Spawned && p.Human.Level == lvl {
+				players = append(players, p)
+			}
+		}
+		return players
+	}
+}
+
+func (s *Server) newAddEntityPacket(e entity.IEntity) protocol.DataPacket {
+	pos := e.GetPosition()
+	if ie, ok := e.(*entity.ItemEntity); ok {
+		pk := protocol.NewAddItemEntityPacket()
+		pk.EntityID = ie.GetID()
+		pk.X = float32(pos.X)
+		pk.Y = float32(pos.Y)
+		pk.Z = float32(pos.Z)
+		pk.SpeedX = float32(ie.Motion.X)
+		pk.SpeedY = float32(ie.Motion.Y)
+		pk.SpeedZ = float32(ie.Motion.Z)
+		pk.Item = ie.Item
+		return pk
+	}
+
+	pk := protocol.NewAddEntityPacket()
+	pk.EntityID = e.GetID()
+	pk.Type = int32(e.GetNetworkID())
+	pk.X = float32(pos.X)
+	pk.Y = float32(pos.Y)
+	pk.Z = float32(pos.Z)
+	pk.Yaw = float32(e.GetYaw())
+	pk.Pitch = float32(e.GetPitch())
+	if mh, ok := e.(metadataHolder); ok {
+		pk.Metadata = mh.GetMetadata().Encode()
+	}
+	return pk
+}
+
+func (s *Server) broadcastEntityMetadata(e entity.IEntity) {
+	mh, ok := e.(metadataHolder)
+	if !ok {
+		return
+	}
+	pk := protocol.NewSetEntityDataPacket()
+	pk.EntityID = e.GetID()
+	pk.Metadata = mh.GetMetadata().Encode()
+	s.BroadcastPacket(pk)
+}
+
+func (s *Server) broadcastEntityUpdates(lvl *level.Level) {
+	updates := lvl.DrainEntityUpdates()
+
+	for _, e := range updates.Spawns {
+		s.BroadcastPacket(s.newAddEntityPacket(e))
+		if _, ok := e.(*entity.ItemEntity); ok {
+			s.broadcastEntityMetadata(e)
+		}
+	}
+	for _, id := range updates.Despawns {
+		pk := protocol.NewRemoveEntityPacket()
+		pk.EntityID = id
+		s.broadcastToLevel(lvl, pk)
+	}
+	for _, ev := range updates.Events {
+		pk := protocol.NewEntityEventPacket()
+		pk.EntityID = ev.EntityID
+		pk.Event = ev.Event
+		s.BroadcastPacket(pk)
+	}
+	for _, e := range updates.Metadata {
+		s.broadcastEntityMetadata(e)
+	}
+}
+
+func (s *Server) handleInteract(p *player.Player, pk *protocol.InteractPacket) {
+	if pk.Action == protocol.ActionRightClick && s.interactWithAnimal(p, pk.Target) {
+		return
+	}
+	p.HandleInteract(pk.Target, pk.Action)
+}
+
+func (s *Server) interactWithAnimal(p *player.Player, targetID int64) bool {
+	if !p.Spawned || !p.IsAlive() || p.IsSpectator() {
+		return false
+	}
+	lvl, ok := p.Human.Level.(*level.Level)
+	if !ok || lvl == nil {
+		return false
+	}
+	target, ok := lvl.GetEntityByID(targetID).(entity.Breedable)
+	if !ok {
+		return false
+	}
+	animal := target.GetAnimal()
+	if p.Position.DistanceSquared(animal.Entity.Position) > 8.0*8.0 {
+		return false
+	}
+
+	held := p.Inventory.GetItemInHand()
+	tameable, isTameable := target.(tameableEntity)
+
+	switch {
+	case isTameable && !tameable.IsTamed():
+		if held.ID != tameable.GetTameItemID() {
+			return false
+		}
+		s.consumeHeldItem(p, held)
+		tamed := tameable.TryTame(p)
+		logger.Player("Taming attempt", "player", p.Username, "entity", targetID, "tamed", tamed)
+		return true
+
+	case animal.CanBeFedWith(held.ID):
+		if !animal.Feed(held.ID) {
+			return false
+		}
+		s.consumeHeldItem(p, held)
+		return true
+
+	case isTameable && tameable.IsOwner(p):
+		tameable.ToggleSitting()
+		return true
+
+	case held.ID == item.SHEARS:
+		sheep, ok := target.(*entity.Sheep)
+		if !ok || !sheep.Shear() {
+			return false
+		}
+		if p.GetGamemode() == 0 {
+			held.Meta++
+			if held.Meta >= held.GetMaxDurability() {
+				held = item.NewItem(0, 0, 0)
+			}
+			p.Inventory.SetItemInHand(held)
+			s.syncInventory(p)
+		}
+		return true
+
+	case held.ID == item.BUCKET && held.Meta == 0 && animal.CanBeMilked():
+		milk := item.NewItem(item.BUCKET, 1, 1)
+		if p.GetGamemode() != 0 {
+			return true
+		}
+		if held.Count > 1 {
+			held.Count--
+			p.Inventory.SetItemInHand(held)
+			for _, left := range p.Inventory.AddItem(milk) {
+				pos := p.Position
+				s.dropItem(float32(pos.X), float32(pos.Y+1), float32(pos.Z), left)
+			}
+		} else {
+			p.Inventory.SetItemInHand(milk)
+		}
+		s.syncInventory(p)
+		return true
+	}
+	return false
+}
+
+func (s *Server) consumeHeldItem(p *player.Player, held item.Item) {
+	if p.GetGamemode() != 0 {
+		return
+	}
+	held.Count--
+	if held.Count <= 0 {
+		held = item.NewItem(0, 0, 0)
+	}
+	p.Inventory.SetItemInHand(held)
+	s.syncInventory(p)
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/capture"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/player"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+var errPlayerNotFound = errors.New("player not found")
+
+func (s *Server) recordInbound(p *player.Player, pkt protocol.DataPacket, data []byte) {
+	conn, ok := p.Session.(*capture.Conn)
+	if !ok {
+		return
+	}
+	if login, ok := pkt.(*protocol.LoginPacket); ok && !p.LoggedIn && s.autoCapture(login.Username) && !conn.Recording() {
+		if _, err := conn.Start(s.Config.PacketCaptureDir, login.Username); err != nil {
+			logger.Error("Failed to start packet capture", "player", login.Username, "error", err)
+		}
+	}
+	conn.Received(data)
+}
+
+func (s *Server) autoCapture(username string) bool {
+	for _, name := range strings.Split(s.Config.PacketCapturePlayers, ",") {
+		if strings.EqualFold(strings.TrimSpace(name), username) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) captureConn(name string) (*capture.Conn, string, error) {
+	p := s.GetPlayer(name)
+	if p == nil {
+		return nil, "", errPlayerNotFound
+	}
+	conn, ok := p.Session.(*capture.Conn)
+	if !ok {
+		return nil, "", errors.New("connection does not support capture")
+	}
+	return conn, p.Username, nil
+}
+
+func (s *Server) StartCapture(name string) (string, error) {
+	conn, username, err := s.captureConn(name)
+	if err != nil {
+		return "", err
+	}
+	return conn.Start(s.Config.PacketCaptureDir, username)
+}
+
+func (s *Server) StopCapture(name string) (string, error) {
+	conn, _, err := s.captureConn(name)
+	if err != nil {
+		return "", err
+	}
+	return conn.Stop()
+}
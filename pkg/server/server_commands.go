@@ -13,7 +13,7 @@ func (s *Server) GetStartTime() time.Time {
 }
 
 func (s *Server) GetRakNetSessionCount() int {
-	if s.RakNet != nil {
+	if s.Network != nil {
 
 		return len(s.Players)
 	}
@@ -5,10 +5,12 @@ import (
 
 	"github.com/scaxe/scaxe-go/pkg/command"
 	"github.com/scaxe/scaxe-go/pkg/command/defaults"
+	"github.com/scaxe/scaxe-go/pkg/entity"
 	"github.com/scaxe/scaxe-go/pkg/item"
 	"github.com/scaxe/scaxe-go/pkg/level"
 	"github.com/scaxe/scaxe-go/pkg/level/anvil"
 	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/player"
 	"github.com/scaxe/scaxe-go/pkg/protocol"
 )
 
@@ -24,6 +26,29 @@ func (s *Server) IsOp(name string) bool {
 	return s.OpManager.IsOpByName(name)
 }
 
+// AddBan records the ban and disconnects the player if they are online.
+func (s *Server) AddBan(name, reason, source string) {
+	s.BanList.Add(name, reason, source)
+	if p := s.findLoggedIn(name, nil); p != nil {
+		p.Kick(banMessage(reason), false)
+	}
+}
+
+func (s *Server) RemoveBan(name string) {
+	s.BanList.Remove(name)
+}
+
+func (s *Server) IsBanned(name string) bool {
+	return s.BanList.IsBanned(name)
+}
+
+func banMessage(reason string) string {
+	if reason == "" {
+		return "You are banned"
+	}
+	return "You are banned: " + reason
+}
+
 func (s *Server) HandleConsoleCommand(cmdLine string) {
 	if cmdLine == "" {
 		return
@@ -58,6 +83,7 @@ func (s *Server) registerConsoleCommands() {
 	s.CommandMap.Register(defaults.NewBanListCommand())
 	s.CommandMap.Register(defaults.NewWhitelistCommand(s))
 	s.CommandMap.Register(defaults.NewDefaultGamemodeCommand(s))
+	s.CommandMap.Register(defaults.NewPermCommand(s, s.PermissionStore))
 
 	s.CommandMap.Register(defaults.NewEffectCommand(s))
 	s.CommandMap.Register(defaults.NewEnchantCommand(s))
@@ -81,10 +107,14 @@ func (s *Server) registerConsoleCommands() {
 	s.CommandMap.Register(defaults.NewGcCommand())
 	s.CommandMap.Register(defaults.NewTimingsCommand())
 	s.CommandMap.Register(defaults.NewRestartCommand())
-	s.CommandMap.Register(defaults.NewBackupCommand())
+	s.CommandMap.Register(defaults.NewBackupCommand(s, s.Backups))
+	s.CommandMap.Register(defaults.NewRestoreCommand(s, s.Backups))
 	s.CommandMap.Register(defaults.NewChunkInfoCommand(s))
-	s.CommandMap.Register(defaults.NewBiomeCommand())
+	s.CommandMap.Register(defaults.NewBiomeCommand(s))
 	s.CommandMap.Register(defaults.NewDumpMemoryCommand())
+	s.CommandMap.Register(defaults.NewCaptureCommand(s))
+	s.CommandMap.Register(defaults.NewNetStatsCommand(s))
+	s.CommandMap.Register(defaults.NewServerCommand(s))
 
 	s.CommandMap.Register(defaults.NewBanCidCommand(s))
 	s.CommandMap.Register(defaults.NewPardonCidCommand())
@@ -98,13 +128,38 @@ func (s *Server) registerConsoleCommands() {
 	s.CommandMap.Register(defaults.NewLvdatCommand())
 	s.CommandMap.Register(defaults.NewLuaPluginCommand())
 
-	s.CommandMap.RegisterAlias("gm", "gamemode")
-	s.CommandMap.RegisterAlias("tp", "teleport")
-	s.CommandMap.RegisterAlias("w", "tell")
-	s.CommandMap.RegisterAlias("msg", "tell")
-	s.CommandMap.RegisterAlias("unban", "pardon")
-	s.CommandMap.RegisterAlias("unban-ip", "pardon-ip")
-	s.CommandMap.RegisterAlias("?", "help")
+	s.CommandMap.SetTargetProvider(s)
+	if err := s.CommandMap.LoadAliases("commands.yml"); err != nil {
+		logger.Error("Failed to load command aliases", "error", err)
+	}
+}
+
+func (s *Server) GetTargetPlayers() []entity.IEntity {
+	players := s.GetOnlinePlayers()
+	targets := make([]entity.IEntity, 0, len(players))
+	for _, p := range players {
+		targets = append(targets, p)
+	}
+	return targets
+}
+
+func (s *Server) GetTargetEntities(sender command.CommandSender) []entity.IEntity {
+	lvl, _ := s.GetLevelManager().GetDefaultLevel().(*level.Level)
+	if la, ok := sender.(defaults.LevelAware); ok {
+		if l, ok := la.GetLevel().(*level.Level); ok && l != nil {
+			lvl = l
+		}
+	}
+	if lvl == nil {
+		return nil
+	}
+	var targets []entity.IEntity
+	for _, e := range lvl.GetEntities() {
+		if _, isPlayer := e.(command.PlayerSender); !isPlayer {
+			targets = append(targets, e)
+		}
+	}
+	return targets
 }
 
 func (s *Server) GetPlayerByName(name string) command.PlayerSender {
@@ -203,6 +258,7 @@ func (m *ServerLevelManager) LoadLevel(name string) (interface{}, error) {
 	}
 
 	lvl := level.NewLevel(name, levelPath, provider, "normal")
+	lvl.SetPlayerProvider(m.server.levelPlayers(lvl))
 	m.server.Levels[name] = lvl
 	logger.Info("Loaded level", "name", name)
 	return lvl, nil
@@ -224,6 +280,7 @@ func (m *ServerLevelManager) GenerateLevel(name string, generatorName string, se
 
 	lvl := level.NewLevel(name, levelPath, provider, generatorName)
 	lvl.Seed = seed
+	lvl.SetPlayerProvider(m.server.levelPlayers(lvl))
 
 	m.server.Levels[name] = lvl
 	logger.Info("Generated level", "name", name, "generator", generatorName, "seed", seed)
@@ -248,3 +305,53 @@ func (m *ServerLevelManager) UnloadLevel(name string) bool {
 	logger.Info("Unloaded level", "name", name)
 	return true
 }
+
+// ReloadLevel closes a loaded level, runs swap while its files are free and
+// loads it again. This works for the default level too; its players wait
+// outside any level and are put back at spawn once it is loaded.
+func (m *ServerLevelManager) ReloadLevel(name string, swap func() error) (interface{}, error) {
+	s := m.server
+	s.mu.Lock()
+	old, exists := s.Levels[name]
+	if !exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("level '%s' is not loaded", name)
+	}
+	delete(s.Levels, name)
+	s.mu.Unlock()
+
+	var players []*player.Player
+	for _, p := range s.GetOnlinePlayers() {
+		if p.Human.Level == old {
+			old.RemoveEntity(p)
+			players = append(players, p)
+		}
+	}
+	old.Close()
+	logger.Info("Unloaded level", "name", name)
+
+	err := swap()
+
+	provider, perr := anvil.NewAnvilProvider(old.Path)
+	if perr != nil {
+		return nil, perr
+	}
+	generatorName := "normal"
+	if old == s.Level {
+		generatorName = s.Config.LevelType
+	}
+	lvl := level.NewLevel(name, old.Path, provider, generatorName)
+	lvl.SetPlayerProvider(s.levelPlayers(lvl))
+	s.mu.Lock()
+	s.Levels[name] = lvl
+	if s.Level == old {
+		s.Level = lvl
+	}
+	s.mu.Unlock()
+	logger.Info("Loaded level", "name", name)
+
+	for _, p := range players {
+		p.SwitchLevel(lvl)
+	}
+	return lvl, err
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/player"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+const (
+	DuplicateLoginKickOld = "kick-old"
+	DuplicateLoginKickNew = "kick-new"
+)
+
+// admitLogin runs the checks a login has to pass before the player is added
+// to the server, disconnecting the session when one fails.
+func (s *Server) admitLogin(p *player.Player, pkt *protocol.LoginPacket) bool {
+	if !player.IsValidUsername(pkt.Username) {
+		logger.Warn("Rejected login", "address", p.GetAddress(), "name", pkt.Username, "reason", "invalid name")
+		p.Kick(player.DisconnectInvalidName, false)
+		return false
+	}
+	if proxyLoginMismatch(p, pkt.Username) {
+		logger.Warn("Rejected login", "address", p.GetAddress(), "name", pkt.Username, "reason", "does not match proxy identity")
+		p.Kick(player.DisconnectNoReason, false)
+		return false
+	}
+	if ban, ok := s.BanList.Get(pkt.Username); ok {
+		logger.Info("Rejected login", "player", pkt.Username, "address", p.GetAddress(), "reason", "banned")
+		p.Kick(banMessage(ban.Reason), false)
+		return false
+	}
+	if !player.IsValidSkin(pkt.SkinData) {
+		logger.Warn("Rejected login", "player", pkt.Username, "skinSize", len(pkt.SkinData), "reason", "invalid skin")
+		p.Kick(player.DisconnectInvalidSkin, false)
+		return false
+	}
+
+	preLogin := event.NewPlayerPreLoginEvent(pkt.Username, p.GetID(), p.IPAddress, p.Port)
+	event.Call(preLogin)
+	if preLogin.IsCancelled() {
+		msg := preLogin.GetKickMessage()
+		if msg == "" {
+			msg = player.DisconnectNoReason
+		}
+		logger.Info("Login cancelled by plugin", "player", pkt.Username, "message", msg)
+		p.Kick(msg, false)
+		return false
+	}
+
+	if existing := s.findLoggedIn(pkt.Username, p); existing != nil {
+		if s.Config.DuplicateLogin == DuplicateLoginKickNew {
+			logger.Info("Rejected duplicate login", "player", pkt.Username, "address", p.GetAddress(), "online", existing.GetAddress())
+			p.Kick(player.DisconnectOtherLocation, false)
+			return false
+		}
+		logger.Info("Replacing session after duplicate login", "player", pkt.Username, "old", existing.GetAddress(), "new", p.GetAddress())
+		s.mu.Lock()
+		if s.PlayersByName[existing.Username] == existing {
+			delete(s.PlayersByName, existing.Username)
+		}
+		s.mu.Unlock()
+		existing.Kick(player.DisconnectOtherLocation, false)
+		// A plugin may cancel the kick, but two sessions for one name
+		// cannot be allowed to stay online.
+		if existing.IsConnected() {
+			existing.Close()
+		}
+	}
+
+	return true
+}
+
+// findLoggedIn looks a name up the way the client compares them, ignoring
+// case.
+func (s *Server) findLoggedIn(name string, except *player.Player) *player.Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for username, p := range s.PlayersByName {
+		if p != except && strings.EqualFold(username, name) {
+			return p
+		}
+	}
+	return nil
+}
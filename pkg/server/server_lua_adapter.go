@@ -1,9 +1,18 @@
 package server
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/scaxe/scaxe-go/pkg/command"
+	"github.com/scaxe/scaxe-go/pkg/entity"
+	"github.com/scaxe/scaxe-go/pkg/entity/effect"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/level"
 	luapkg "github.com/scaxe/scaxe-go/pkg/lua"
+	"github.com/scaxe/scaxe-go/pkg/permission"
 	"github.com/scaxe/scaxe-go/pkg/player"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
 )
 
 type ServerAPIAdapter struct {
@@ -65,6 +74,38 @@ func (a *ServerAPIAdapter) GetLevel() luapkg.LevelAPI {
 	return &LevelAPIAdapter{server: a.server}
 }
 
+func (a *ServerAPIAdapter) GetLevelByName(name string) luapkg.LevelAPI {
+	a.server.mu.RLock()
+	lvl := a.server.Levels[name]
+	a.server.mu.RUnlock()
+	if lvl == nil {
+		return nil
+	}
+	return &LevelAPIAdapter{server: a.server, level: lvl}
+}
+
+func (a *ServerAPIAdapter) GetLevelNames() []string {
+	names := a.server.GetLevelManager().GetLevelNames()
+	sort.Strings(names)
+	return names
+}
+
+func (a *ServerAPIAdapter) LoadLevel(name string) error {
+	_, err := a.server.GetLevelManager().LoadLevel(name)
+	return err
+}
+
+func (a *ServerAPIAdapter) RegisterPermission(name, description, defaultValue string) {
+	permission.GlobalManager.AddPermission(permission.NewPermission(name, description, defaultValue))
+}
+
+func (a *ServerAPIAdapter) GetPermissionStore() luapkg.PermissionStoreAPI {
+	if a.server.PermissionStore == nil {
+		return nil
+	}
+	return a.server.PermissionStore
+}
+
 func (a *ServerAPIAdapter) RegisterCommand(cmd command.Command) {
 	a.server.CommandMap.Register(cmd)
 }
@@ -78,9 +119,7 @@ func (a *ServerAPIAdapter) Stop() {
 }
 
 func (a *ServerAPIAdapter) GetCurrentTick() int64 {
-	a.server.mu.RLock()
-	defer a.server.mu.RUnlock()
-	return a.server.CurrentTick
+	return a.server.GetCurrentTick()
 }
 
 type PlayerAPIAdapter struct {
@@ -136,53 +175,246 @@ func (p *PlayerAPIAdapter) GetEntityID() int64 {
 	return p.player.GetEntityID()
 }
 
+func (p *PlayerAPIAdapter) GetLevelName() string {
+	return p.player.GetLevelName()
+}
+
+func (p *PlayerAPIAdapter) SwitchLevel(name string) bool {
+	p.server.mu.RLock()
+	lvl := p.server.Levels[name]
+	p.server.mu.RUnlock()
+	if lvl == nil {
+		return false
+	}
+	return p.player.SwitchLevel(lvl)
+}
+
+func (p *PlayerAPIAdapter) GetInventory() []luapkg.ItemStack {
+	contents := p.player.Inventory.GetContents()
+	stacks := make([]luapkg.ItemStack, 0, len(contents))
+	for slot, it := range contents {
+		if it.ID == 0 || it.Count <= 0 {
+			continue
+		}
+		stacks = append(stacks, luapkg.ItemStack{Slot: slot, ID: it.ID, Meta: it.Meta, Count: it.Count})
+	}
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].Slot < stacks[j].Slot })
+	return stacks
+}
+
+func (p *PlayerAPIAdapter) GetItemInHand() luapkg.ItemStack {
+	it := p.player.Inventory.GetItemInHand()
+	return luapkg.ItemStack{Slot: p.player.Inventory.GetHeldItemSlot(), ID: it.ID, Meta: it.Meta, Count: it.Count}
+}
+
+func (p *PlayerAPIAdapter) SetSlot(slot int, stack luapkg.ItemStack) bool {
+	if slot < 0 || slot >= p.player.Inventory.GetSize() {
+		return false
+	}
+	if err := p.player.Inventory.SetItem(slot, item.NewItem(stack.ID, stack.Meta, stack.Count)); err != nil {
+		return false
+	}
+	p.server.syncInventory(p.player)
+	return true
+}
+
+func (p *PlayerAPIAdapter) AddItem(stack luapkg.ItemStack) int {
+	leftover := 0
+	for _, it := range p.player.Inventory.AddItem(item.NewItem(stack.ID, stack.Meta, stack.Count)) {
+		leftover += it.Count
+	}
+	p.server.syncInventory(p.player)
+	return leftover
+}
+
+func (p *PlayerAPIAdapter) RemoveItem(stack luapkg.ItemStack) int {
+	inv := p.player.Inventory
+	removed := 0
+	for slot := 0; slot < inv.GetSize() && removed < stack.Count; slot++ {
+		it := inv.GetItem(slot)
+		if it.ID != stack.ID || it.Count <= 0 || (stack.Meta >= 0 && it.Meta != stack.Meta) {
+			continue
+		}
+		take := stack.Count - removed
+		if take > it.Count {
+			take = it.Count
+		}
+		it.Count -= take
+		if it.Count <= 0 {
+			it = item.NewItem(0, 0, 0)
+		}
+		inv.SetItem(slot, it)
+		removed += take
+	}
+	if removed > 0 {
+		p.server.syncInventory(p.player)
+	}
+	return removed
+}
+
+func (p *PlayerAPIAdapter) ClearInventory() {
+	p.player.Inventory.ClearAll()
+	p.server.syncInventory(p.player)
+}
+
+func (p *PlayerAPIAdapter) HasPermission(name string) bool {
+	return p.player.HasPermission(name)
+}
+
+func (p *PlayerAPIAdapter) SetPermission(name string, value bool) {
+	p.player.SetPermission(name, value)
+}
+
+func (p *PlayerAPIAdapter) UnsetPermission(name string) {
+	p.player.UnsetPermission(name)
+}
+
+func (p *PlayerAPIAdapter) AddEffect(id, duration, amplifier int, particles bool) {
+	e := effect.NewEffect(id, duration, amplifier)
+	e.Particles = particles
+	p.player.AddEffect(e)
+}
+
+func (p *PlayerAPIAdapter) RemoveEffect(id int) {
+	p.player.RemoveEffect(id)
+}
+
 type LevelAPIAdapter struct {
 	server *Server
+	level  *level.Level
+}
+
+func (l *LevelAPIAdapter) lvl() *level.Level {
+	if l.level != nil {
+		return l.level
+	}
+	return l.server.Level
 }
 
 func (l *LevelAPIAdapter) GetBlock(x, y, z int32) (id, meta uint8) {
-	if l.server.Level == nil {
+	lvl := l.lvl()
+	if lvl == nil {
 		return 0, 0
 	}
-	bs := l.server.Level.GetBlock(x, y, z)
+	bs := lvl.GetBlock(x, y, z)
 	return bs.ID, bs.Meta
 }
 
 func (l *LevelAPIAdapter) SetBlock(x, y, z int32, id, meta uint8) {
-	if l.server.Level == nil {
+	lvl := l.lvl()
+	if lvl == nil {
 		return
 	}
-	l.server.Level.SetBlock(x, y, z, id, meta, true)
+	lvl.SetBlock(x, y, z, id, meta, true)
 }
 
 func (l *LevelAPIAdapter) GetTime() int64 {
-	if l.server.Level == nil {
+	lvl := l.lvl()
+	if lvl == nil {
 		return 0
 	}
-	return l.server.Level.GetTime()
+	return lvl.GetTime()
 }
 
 func (l *LevelAPIAdapter) SetTime(t int64) {
-	if l.server.Level == nil {
+	lvl := l.lvl()
+	if lvl == nil {
 		return
 	}
-	l.server.Level.SetTime(t)
+	lvl.SetTime(t)
 }
 
 func (l *LevelAPIAdapter) GetSeed() int64 {
-	if l.server.Level == nil {
+	lvl := l.lvl()
+	if lvl == nil {
 		return 0
 	}
-	return l.server.Level.GetSeed()
+	return lvl.GetSeed()
 }
 
 func (l *LevelAPIAdapter) GetSpawnLocation() (x, y, z float64) {
-	if l.server.Level == nil {
+	lvl := l.lvl()
+	if lvl == nil {
 		return 0, 0, 0
 	}
-	spawn := l.server.Level.GetSpawnLocation()
+	spawn := lvl.GetSpawnLocation()
 	if spawn == nil {
 		return 0, 64, 0
 	}
 	return spawn.X, spawn.Y, spawn.Z
 }
+
+func (l *LevelAPIAdapter) GetName() string {
+	lvl := l.lvl()
+	if lvl == nil {
+		return ""
+	}
+	return lvl.Name
+}
+
+func (l *LevelAPIAdapter) SpawnEntity(networkID int, x, y, z float64) (int64, error) {
+	lvl := l.lvl()
+	if lvl == nil {
+		return 0, fmt.Errorf("level not loaded")
+	}
+	mob := entity.CreatePassiveMob(networkID)
+	if mob == nil {
+		return 0, fmt.Errorf("unsupported entity type %d", networkID)
+	}
+	animal := mob.GetAnimal()
+	animal.Entity.SetPosition(entity.NewVector3(x, y, z))
+	animal.Entity.Level = lvl
+	lvl.SpawnEntity(mob)
+	return mob.GetID(), nil
+}
+
+func (l *LevelAPIAdapter) RemoveEntity(id int64) bool {
+	lvl := l.lvl()
+	if lvl == nil {
+		return false
+	}
+	e := lvl.GetEntityByID(id)
+	if e == nil {
+		return false
+	}
+	if _, isPlayer := e.(*player.Player); isPlayer {
+		return false
+	}
+	e.Close()
+	lvl.RemoveEntity(e)
+	return true
+}
+
+func (l *LevelAPIAdapter) GetEntities() []luapkg.EntityInfo {
+	lvl := l.lvl()
+	if lvl == nil {
+		return nil
+	}
+	entities := lvl.GetEntities()
+	infos := make([]luapkg.EntityInfo, 0, len(entities))
+	for _, e := range entities {
+		pos := e.GetPosition()
+		infos = append(infos, luapkg.EntityInfo{ID: e.GetID(), NetworkID: e.GetNetworkID(), X: pos.X, Y: pos.Y, Z: pos.Z})
+	}
+	return infos
+}
+
+func (l *LevelAPIAdapter) AddParticle(particle int, x, y, z float64, data int) {
+	if lvl := l.lvl(); lvl != nil {
+		l.server.broadcastToLevel(lvl, level.NewParticlePacket(float32(x), float32(y), float32(z), particle, int32(data)))
+	}
+}
+
+func (l *LevelAPIAdapter) PlaySound(sound int, x, y, z float64, pitch float64) {
+	if lvl := l.lvl(); lvl != nil {
+		l.server.broadcastToLevel(lvl, level.NewSoundPacket(float32(x), float32(y), float32(z), int16(sound), float32(pitch)))
+	}
+}
+
+func (s *Server) broadcastToLevel(lvl *level.Level, pk protocol.DataPacket) {
+	for _, p := range s.GetOnlinePlayers() {
+		if p.Spawned && p.Human.Level == lvl {
+			s.sendPacket(p, pk)
+		}
+	}
+}
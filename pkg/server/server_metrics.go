@@ -0,0 +1,99 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/metrics"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+	"github.com/scaxe/scaxe-go/pkg/raknet"
+	"github.com/scaxe/scaxe-go/pkg/timings"
+)
+
+func (s *Server) startMetrics() {
+	metrics.SetPacketNamer(func(id byte) string {
+		if constructor, ok := protocol.PacketPool[id]; ok {
+			return constructor().Name()
+		}
+		return fmt.Sprintf("0x%02x", id)
+	})
+	metrics.Register(s.collectMetrics)
+
+	srv, err := metrics.Serve(s.Config.MetricsAddress)
+	if err != nil {
+		logger.Error("Failed to start metrics endpoint", "error", err)
+	}
+	s.metricsServer = srv
+}
+
+func (s *Server) collectMetrics(w *metrics.Writer) {
+	w.Gauge("scaxe_tps", "Ticks per second averaged over the last 20 ticks.", s.GetTPS())
+	w.Gauge("scaxe_mspt", "Milliseconds per tick averaged over the last 20 ticks.", s.GetMSPT())
+	w.Counter("scaxe_ticks_total", "Ticks processed since start.", float64(s.GetCurrentTick()))
+	w.Gauge("scaxe_players_online", "Players that have completed login.", float64(s.GetOnlineCount()))
+	w.Gauge("scaxe_players_max", "Configured player limit.", float64(s.Config.MaxPlayers))
+
+	s.mu.RLock()
+	levels := make([]*level.Level, 0, len(s.Levels))
+	for _, lvl := range s.Levels {
+		levels = append(levels, lvl)
+	}
+	s.mu.RUnlock()
+
+	for _, lvl := range levels {
+		w.Gauge("scaxe_level_chunks_loaded", "Chunks loaded in memory, by level.", float64(lvl.GetLoadedChunkCount()), "level", lvl.Name)
+	}
+	for _, lvl := range levels {
+		w.Gauge("scaxe_level_entities", "Entities in the level.", float64(len(lvl.GetEntities())), "level", lvl.Name)
+	}
+	for _, lvl := range levels {
+		w.Gauge("scaxe_level_tiles", "Tile entities in the level.", float64(lvl.Tiles.Count()), "level", lvl.Name)
+	}
+
+	if s.Network != nil {
+		conns := s.Network.Conns()
+		w.Gauge("scaxe_raknet_sessions", "Open RakNet sessions.", float64(len(conns)))
+
+		var sessions []network.Conn
+		var stats []raknet.SessionStats
+		for _, conn := range conns {
+			if sc, ok := conn.(interface{ Stats() raknet.SessionStats }); ok {
+				sessions = append(sessions, conn)
+				stats = append(stats, sc.Stats())
+			}
+		}
+		for i, session := range sessions {
+			w.Gauge("scaxe_raknet_session_rtt_seconds", "Smoothed round-trip time per session, from datagram ACKs.", stats[i].RTT.Seconds(), "address", session.Address())
+		}
+		for i, session := range sessions {
+			w.Counter("scaxe_raknet_session_datagrams_sent_total", "Datagrams sent per session, including resends.", float64(stats[i].PacketsSent), "address", session.Address())
+		}
+		for i, session := range sessions {
+			w.Counter("scaxe_raknet_session_datagrams_received_total", "Datagrams received per session.", float64(stats[i].PacketsReceived), "address", session.Address())
+		}
+		for i, session := range sessions {
+			w.Counter("scaxe_raknet_session_naks_total", "NAKs received per session.", float64(stats[i].NAKsReceived), "address", session.Address())
+		}
+		for i, session := range sessions {
+			w.Counter("scaxe_raknet_session_lost_datagrams_total", "Datagrams the client reported missing in a NAK, per session.", float64(stats[i].Lost), "address", session.Address())
+		}
+		for i, session := range sessions {
+			w.Counter("scaxe_raknet_session_resends_total", "Datagrams resent under a new sequence number after a NAK, per session.", float64(stats[i].Resends), "address", session.Address())
+		}
+	}
+
+	for _, r := range timings.Report() {
+		if !strings.HasPrefix(r.Name, "Plugin: ") {
+			continue
+		}
+		name, phase := strings.TrimPrefix(r.Name, "Plugin: "), "events"
+		if strings.HasSuffix(name, " (tick)") {
+			phase = "tick"
+		}
+		name = strings.TrimSuffix(strings.TrimSuffix(name, " (events)"), " (tick)")
+		w.Counter("scaxe_plugin_time_seconds_total", "Time spent in plugin code while timings are enabled.", r.Total.Seconds(), "plugin", name, "phase", phase)
+	}
+}
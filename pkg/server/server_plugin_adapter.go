@@ -0,0 +1,56 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/scaxe/scaxe-go/pkg/level"
+	"github.com/scaxe/scaxe-go/pkg/player"
+)
+
+type PluginServerAdapter struct {
+	server *Server
+}
+
+func NewPluginServerAdapter(s *Server) *PluginServerAdapter {
+	return &PluginServerAdapter{server: s}
+}
+
+func (a *PluginServerAdapter) GetServerName() string {
+	return a.server.Config.ServerName
+}
+
+func (a *PluginServerAdapter) BroadcastMessage(message string) {
+	a.server.BroadcastMessage(message)
+}
+
+func (a *PluginServerAdapter) GetPlayer(username string) *player.Player {
+	return a.server.GetPlayer(username)
+}
+
+func (a *PluginServerAdapter) GetOnlinePlayers() []*player.Player {
+	return a.server.GetOnlinePlayers()
+}
+
+func (a *PluginServerAdapter) GetDefaultLevel() *level.Level {
+	return a.server.Level
+}
+
+func (a *PluginServerAdapter) GetLevel(name string) *level.Level {
+	a.server.mu.RLock()
+	defer a.server.mu.RUnlock()
+	return a.server.Levels[name]
+}
+
+func (a *PluginServerAdapter) GetLevelNames() []string {
+	names := a.server.GetLevelManager().GetLevelNames()
+	sort.Strings(names)
+	return names
+}
+
+func (a *PluginServerAdapter) GetCurrentTick() int64 {
+	return a.server.GetCurrentTick()
+}
+
+func (a *PluginServerAdapter) Stop() {
+	a.server.Stop()
+}
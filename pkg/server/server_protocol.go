@@ -0,0 +1,33 @@
+package server
+
+import (
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/player"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+)
+
+// negotiateCodec picks the session's codec from the version in its login
+// packet. Versions without a codec are told which side is outdated and
+// disconnected before anything else is decoded.
+func (s *Server) negotiateCodec(p *player.Player, data []byte) bool {
+	version, err := protocol.LoginProtocol(data[1:])
+	if err != nil {
+		logger.Warn("Malformed login packet", "address", p.GetAddress(), "error", err)
+		p.Close()
+		return false
+	}
+
+	codec, err := protocol.CodecFor(version)
+	if err != nil {
+		logger.Warn("Rejected login", "address", p.GetAddress(), "protocol", version, "supported", protocol.SupportedProtocols())
+		status := protocol.NewPlayStatusPacket()
+		status.Status = protocol.RejectStatus(version)
+		s.sendPacketImmediate(p, status)
+		p.Close()
+		return false
+	}
+
+	p.Codec = codec
+	logger.Debug("Negotiated protocol", "address", p.GetAddress(), "protocol", version, "codec", codec.Name)
+	return true
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/scaxe/scaxe-go/pkg/capture"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/player"
+)
+
+var ErrNotProxied = errors.New("not connected through a proxy")
+
+func (s *Server) configureProxyLink(link *network.LinkTransport) {
+	link.Secret = s.Config.ProxySecret
+	link.Trusted = nil
+	for _, addr := range strings.Split(s.Config.ProxyTrustedAddresses, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			link.Trusted = append(link.Trusted, addr)
+		}
+	}
+}
+
+func linkConn(conn network.Conn) *network.LinkConn {
+	if c, ok := conn.(*capture.Conn); ok {
+		conn = c.Conn
+	}
+	lc, _ := conn.(*network.LinkConn)
+	return lc
+}
+
+// adoptProxyIdentity gives a proxied player the entity ID the proxy chose,
+// so the ID the client learned from its first StartGame stays valid on
+// every backend it is moved to.
+func adoptProxyIdentity(p *player.Player, conn network.Conn) {
+	if lc := linkConn(conn); lc != nil && lc.Hello().EntityID != 0 {
+		p.ID = lc.Hello().EntityID
+	}
+}
+
+func proxyLoginMismatch(p *player.Player, username string) bool {
+	lc := linkConn(p.Session)
+	return lc != nil && !strings.EqualFold(lc.Hello().Username, username)
+}
+
+// ProxyServers lists the backends known to the proxy in front of this
+// server and the one the named player is on. Without a player name any
+// proxied session answers.
+func (s *Server) ProxyServers(name string) ([]string, string, error) {
+	if name != "" {
+		p := s.GetPlayer(name)
+		if p == nil {
+			return nil, "", fmt.Errorf("%s is not online", name)
+		}
+		lc := linkConn(p.Session)
+		if lc == nil {
+			return nil, "", ErrNotProxied
+		}
+		return lc.Hello().Servers, lc.Hello().Server, nil
+	}
+
+	for _, conn := range s.Network.Conns() {
+		if lc := linkConn(conn); lc != nil {
+			return lc.Hello().Servers, lc.Hello().Server, nil
+		}
+	}
+	return nil, "", ErrNotProxied
+}
+
+func (s *Server) TransferPlayer(name, server string) error {
+	p := s.GetPlayer(name)
+	if p == nil {
+		return fmt.Errorf("%s is not online", name)
+	}
+	lc := linkConn(p.Session)
+	if lc == nil {
+		return ErrNotProxied
+	}
+	hello := lc.Hello()
+	if strings.EqualFold(hello.Server, server) {
+		return fmt.Errorf("already on %s", hello.Server)
+	}
+	for _, known := range hello.Servers {
+		if strings.EqualFold(known, server) {
+			return lc.Transfer(known)
+		}
+	}
+	return fmt.Errorf("unknown server %q", server)
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net"
+
+	"github.com/scaxe/scaxe-go/internal/version"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/query"
+)
+
+func (s *Server) handleRawPacket(addr *net.UDPAddr, data []byte) bool {
+	response, handled := s.Query.Handle(addr, data)
+	if response != nil {
+		s.Network.(network.RawPacketTransport).SendTo(addr, response)
+	}
+	return handled
+}
+
+func (s *Server) queryInfo() query.Info {
+	info := query.Info{
+		MOTD:       s.Config.MOTD,
+		GameType:   "SMP",
+		GameID:     "MINECRAFTPE",
+		Version:    "0.14.2",
+		Engine:     "SCAXE-GO " + version.String(),
+		Map:        s.Config.LevelName,
+		HostIP:     s.Config.ServerIP,
+		HostPort:   s.Config.ServerPort,
+		MaxPlayers: s.Config.MaxPlayers,
+		Whitelist:  s.Config.WhiteList,
+	}
+	for _, p := range s.GetOnlinePlayers() {
+		info.Players = append(info.Players, p.Username)
+	}
+	info.NumPlayers = len(info.Players)
+
+	if s.NativePlugins != nil {
+		info.Plugins = append(info.Plugins, s.NativePlugins.GetPluginNames()...)
+	}
+	if s.PluginManager != nil {
+		info.Plugins = append(info.Plugins, s.PluginManager.GetPluginNames()...)
+	}
+	return info
+}
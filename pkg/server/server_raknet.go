@@ -0,0 +1,33 @@
+package server
+
+import (
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/raknet"
+)
+
+func (s *Server) rakNetLimits() raknet.Limits {
+	c := s.Config
+	return raknet.Limits{
+		MaxSessions:            c.RakNetMaxSessions,
+		MaxSessionsPerIP:       c.RakNetMaxSessionsPerIP,
+		PacketsPerSecond:       c.RakNetPacketsPerSecond,
+		GlobalPacketsPerSecond: c.RakNetGlobalPacketsPerSecond,
+		MaxSplitPackets:        c.RakNetMaxSplitPackets,
+		MaxSplitCount:          c.RakNetMaxSplitCount,
+		MaxSplitBytes:          c.RakNetMaxSplitBytes,
+		SplitTimeout:           time.Duration(c.RakNetSplitTimeout) * time.Second,
+		SessionTimeout:         time.Duration(c.RakNetSessionTimeout) * time.Second,
+		HandshakeTimeout:       time.Duration(c.RakNetHandshakeTimeout) * time.Second,
+		BlockDuration:          time.Duration(c.RakNetBlockDuration) * time.Second,
+		Cookies:                c.RakNetCookies,
+	}
+}
+
+func (s *Server) RakNetServer() *raknet.Server {
+	if rak, ok := s.Network.(*network.RakNetTransport); ok {
+		return rak.Server
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/rcon"
+	"github.com/scaxe/scaxe-go/pkg/scheduler"
+)
+
+const rconCommandTimeout = 10 * time.Second
+
+func (s *Server) startRcon() {
+	s.Rcon = rcon.NewServer(rcon.Config{
+		Address:         net.JoinHostPort(s.Config.ServerIP, strconv.Itoa(s.Config.RconPort)),
+		Password:        s.Config.RconPassword,
+		MaxConnections:  s.Config.RconMaxConnections,
+		MaxAttempts:     s.Config.RconMaxAttempts,
+		LockoutDuration: time.Duration(s.Config.RconLockout) * time.Minute,
+	}, s.executeRemoteCommand)
+	if err := s.Rcon.Start(); err != nil {
+		logger.Error("Failed to start RCON", "error", err)
+		s.Rcon = nil
+	}
+}
+
+func (s *Server) executeRemoteCommand(cmdLine string) string {
+	if cmdLine == "" {
+		return ""
+	}
+	sender := rcon.NewSender()
+	done := make(chan bool, 1)
+	scheduler.RunAsync(nil, func(interface{}) {
+		done <- s.CommandMap.Dispatch(sender, cmdLine)
+	})
+
+	select {
+	case found := <-done:
+		if !found {
+			return "Unknown command. Type \"help\" for help.\n"
+		}
+	case <-time.After(rconCommandTimeout):
+		return sender.Output() + "Command timed out.\n"
+	}
+	return sender.Output()
+}
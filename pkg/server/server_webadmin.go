@@ -0,0 +1,202 @@
+package server
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/scaxe/scaxe-go/internal/version"
+	"github.com/scaxe/scaxe-go/pkg/logger"
+	"github.com/scaxe/scaxe-go/pkg/scheduler"
+	"github.com/scaxe/scaxe-go/pkg/webadmin"
+)
+
+const webAdminTimeout = 10 * time.Second
+
+var errMainThreadTimeout = errors.New("timed out waiting for the server thread")
+
+func (s *Server) startWebAdmin() {
+	s.WebAdmin = webadmin.NewServer(webadmin.Config{
+		Address: s.Config.WebAdminAddress,
+		Token:   s.Config.WebAdminToken,
+	}, &webAdminBackend{s: s})
+	if err := s.WebAdmin.Start(); err != nil {
+		logger.Error("Failed to start web admin", "error", err)
+		s.WebAdmin = nil
+	}
+}
+
+// runOnMainThread hands fn to the tick loop, the same way RCON commands
+// run, and waits for it.
+func (s *Server) runOnMainThread(fn func()) error {
+	done := make(chan struct{})
+	scheduler.RunAsync(nil, func(interface{}) {
+		fn()
+		close(done)
+	})
+	select {
+	case <-done:
+		return nil
+	case <-time.After(webAdminTimeout):
+		return errMainThreadTimeout
+	}
+}
+
+type webAdminBackend struct {
+	s *Server
+}
+
+func (b *webAdminBackend) Status() webadmin.Status {
+	s := b.s
+	return webadmin.Status{
+		Name:       s.Config.ServerName,
+		Version:    version.String(),
+		MOTD:       s.Config.MOTD,
+		Online:     s.GetOnlineCount(),
+		MaxPlayers: s.Config.MaxPlayers,
+		TPS:        s.GetTPS(),
+		MSPT:       s.GetMSPT(),
+		Tick:       s.GetCurrentTick(),
+		Uptime:     time.Since(s.StartTime).Seconds(),
+	}
+}
+
+func (b *webAdminBackend) Players() []webadmin.Player {
+	online := b.s.GetOnlinePlayers()
+	players := make([]webadmin.Player, 0, len(online))
+	for _, p := range online {
+		info := webadmin.Player{
+			Name:     p.GetName(),
+			Address:  p.GetAddress(),
+			Level:    p.GetLevelName(),
+			Ping:     p.GetPing(),
+			Gamemode: p.GetGamemode(),
+			Op:       b.s.IsOp(p.GetName()),
+		}
+		if pos := p.GetPosition(); pos != nil {
+			info.X, info.Y, info.Z = pos.X, pos.Y, pos.Z
+		}
+		players = append(players, info)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].Name < players[j].Name })
+	return players
+}
+
+func (b *webAdminBackend) Levels() []webadmin.Level {
+	s := b.s
+	perLevel := make(map[string]int)
+	for _, p := range s.GetOnlinePlayers() {
+		perLevel[p.GetLevelName()]++
+	}
+
+	s.mu.RLock()
+	levels := make([]webadmin.Level, 0, len(s.Levels))
+	for name, lvl := range s.Levels {
+		if lvl == nil {
+			continue
+		}
+		levels = append(levels, webadmin.Level{
+			Name:     name,
+			Default:  lvl == s.Level,
+			Chunks:   lvl.GetLoadedChunkCount(),
+			Entities: len(lvl.GetEntities()),
+			Players:  perLevel[name],
+			Time:     lvl.GetTime(),
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Name < levels[j].Name })
+	return levels
+}
+
+func (b *webAdminBackend) Plugins() []webadmin.Plugin {
+	plugins := []webadmin.Plugin{}
+	if pm := b.s.PluginManager; pm != nil {
+		for _, p := range pm.GetPlugins() {
+			plugins = append(plugins, webadmin.Plugin{Name: p.Meta.Name, Version: p.Meta.Version, Kind: "lua", Enabled: p.Enabled})
+		}
+	}
+	if m := b.s.NativePlugins; m != nil {
+		for _, name := range m.GetPluginNames() {
+			info := webadmin.Plugin{Name: name, Kind: "native", Enabled: m.IsPluginEnabled(name)}
+			if p := m.GetPlugin(name); p != nil {
+				info.Version = p.Version()
+			}
+			plugins = append(plugins, info)
+		}
+	}
+	return plugins
+}
+
+func (b *webAdminBackend) Execute(cmdLine string) (string, error) {
+	sender := webadmin.NewSender()
+	found := false
+	if err := b.s.runOnMainThread(func() {
+		found = b.s.CommandMap.Dispatch(sender, cmdLine)
+	}); err != nil {
+		return sender.Output(), err
+	}
+	if !found {
+		return sender.Output(), errors.New("unknown command, type \"help\" for help")
+	}
+	return sender.Output(), nil
+}
+
+func (b *webAdminBackend) Kick(name, reason string) error {
+	if reason == "" {
+		reason = "Kicked by admin"
+	}
+	var err error
+	if runErr := b.s.runOnMainThread(func() {
+		p := b.s.findLoggedIn(name, nil)
+		if p == nil {
+			err = webadmin.ErrPlayerNotFound
+			return
+		}
+		logger.Server("Kicked from web admin", "player", p.GetName(), "reason", reason)
+		p.Kick(reason, false)
+	}); runErr != nil {
+		return runErr
+	}
+	return err
+}
+
+func (b *webAdminBackend) Ban(name, reason string) error {
+	if reason == "" {
+		reason = "Banned by operator"
+	}
+	return b.s.runOnMainThread(func() {
+		logger.Server("Banned from web admin", "player", name, "reason", reason)
+		b.s.AddBan(name, reason, "WebAdmin")
+	})
+}
+
+// SetOp follows /op: granting needs the player online so the op can be
+// tied to their client ID, while revoking works for anyone.
+func (b *webAdminBackend) SetOp(name string, op bool) error {
+	var err error
+	if runErr := b.s.runOnMainThread(func() {
+		p := b.s.findLoggedIn(name, nil)
+		if !op {
+			b.s.RemoveOp(name)
+			if p != nil {
+				p.SetOp(false)
+				p.SendMessage("§7You are no longer op!")
+			}
+			logger.Server("De-opped from web admin", "player", name)
+			return
+		}
+		if p == nil {
+			err = webadmin.ErrPlayerNotFound
+			return
+		}
+		b.s.AddOp(p.GetName(), int64(p.ClientID))
+		p.SetOp(true)
+		p.SendMessage("§7You are now op!")
+		logger.Server("Opped from web admin", "player", p.GetName())
+	}); runErr != nil {
+		return runErr
+	}
+	return err
+}
@@ -0,0 +1,355 @@
+package testclient
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+	"github.com/scaxe/scaxe-go/pkg/raknet"
+)
+
+const (
+	DefaultTimeout = 5 * time.Second
+	eyeHeight      = 1.62
+)
+
+var ErrTimeout = errors.New("testclient: timed out waiting for packet")
+
+type link interface {
+	SendPacket(data []byte)
+	Packets() <-chan []byte
+	Done() <-chan struct{}
+	LocalAddr() net.Addr
+	Close()
+}
+
+type Client struct {
+	Username string
+	Protocol int32
+
+	rak link
+
+	mu       sync.Mutex
+	codec    *protocol.Codec
+	inbox    []protocol.DataPacket
+	history  []protocol.DataPacket
+	notify   chan struct{}
+	entityID int64
+	x, y, z  float32
+	yaw      float32
+	pitch    float32
+	spawned  bool
+}
+
+func Dial(address, username string) (*Client, error) {
+	rak, err := raknet.Dial(address, DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(rak, username), nil
+}
+
+func DialMemory(transport *network.MemoryTransport, username string) (*Client, error) {
+	conn, err := transport.Dial()
+	if err != nil {
+		return nil, err
+	}
+	return newClient(conn, username), nil
+}
+
+func newClient(l link, username string) *Client {
+	c := &Client{
+		Username: username,
+		Protocol: protocol.ProtocolCurrent,
+		rak:      l,
+		codec:    protocol.DefaultCodec,
+		notify:   make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+func Connect(address, username string) (*Client, error) {
+	c, err := Dial(address, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.join(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func ConnectMemory(transport *network.MemoryTransport, username string) (*Client, error) {
+	c, err := DialMemory(transport, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.join(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) join() error {
+	if err := c.Login(); err != nil {
+		c.Close()
+		return err
+	}
+	if err := c.WaitForSpawn(DefaultTimeout); err != nil {
+		c.Close()
+		return err
+	}
+	return nil
+}
+
+func (c *Client) readLoop() {
+	for {
+		select {
+		case data := <-c.rak.Packets():
+			for _, pk := range decode(c.packetCodec(), data) {
+				c.push(pk)
+			}
+		case <-c.rak.Done():
+			// Packets that arrived just before the disconnect, such as the
+			// Disconnect packet itself, are still worth delivering.
+			for drained := false; !drained; {
+				select {
+				case data := <-c.rak.Packets():
+					for _, pk := range decode(c.packetCodec(), data) {
+						c.push(pk)
+					}
+				default:
+					drained = true
+				}
+			}
+			c.mu.Lock()
+			close(c.notify)
+			c.notify = nil
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+func decode(codec *protocol.Codec, data []byte) []protocol.DataPacket {
+	pk, err := codec.Decode(data)
+	if pk == nil || err != nil {
+		return nil
+	}
+	batch, ok := pk.(*protocol.BatchPacket)
+	if !ok {
+		return []protocol.DataPacket{pk}
+	}
+	raw, err := batch.Decompress()
+	if err != nil {
+		return nil
+	}
+	packets, _ := codec.DecodePackets(raw)
+	return packets
+}
+
+func (c *Client) packetCodec() *protocol.Codec {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.codec
+}
+
+func (c *Client) push(pk protocol.DataPacket) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch p := pk.(type) {
+	case *protocol.StartGamePacket:
+		c.entityID = p.EntityID
+		c.x, c.y, c.z = p.X, p.Y+eyeHeight, p.Z
+	case *protocol.MovePlayerPacket:
+		if p.EntityID == c.entityID {
+			c.x, c.y, c.z = p.X, p.Y, p.Z
+			c.yaw, c.pitch = p.Yaw, p.Pitch
+		}
+	case *protocol.PlayStatusPacket:
+		if p.Status == protocol.PlayStatusPlayerSpawn {
+			c.spawned = true
+		}
+	}
+
+	c.inbox = append(c.inbox, pk)
+	c.history = append(c.history, pk)
+	if c.notify != nil {
+		close(c.notify)
+		c.notify = make(chan struct{})
+	}
+}
+
+func (c *Client) SendPacket(pk protocol.DataPacket) error {
+	data, err := c.packetCodec().Encode(pk)
+	if err != nil {
+		return err
+	}
+	c.rak.SendPacket(data)
+	return nil
+}
+
+func (c *Client) Expect(timeout time.Duration, match func(protocol.DataPacket) bool) (protocol.DataPacket, error) {
+	deadline := time.After(timeout)
+	for {
+		c.mu.Lock()
+		for i, pk := range c.inbox {
+			if match(pk) {
+				c.inbox = append(c.inbox[:i:i], c.inbox[i+1:]...)
+				c.mu.Unlock()
+				return pk, nil
+			}
+		}
+		notify := c.notify
+		c.mu.Unlock()
+
+		if notify == nil {
+			return nil, raknet.ErrClientClosed
+		}
+		select {
+		case <-notify:
+		case <-deadline:
+			return nil, ErrTimeout
+		}
+	}
+}
+
+func ExpectPacket[T protocol.DataPacket](c *Client, timeout time.Duration, match func(T) bool) (T, error) {
+	pk, err := c.Expect(timeout, func(pk protocol.DataPacket) bool {
+		t, ok := pk.(T)
+		return ok && (match == nil || match(t))
+	})
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("%w: %T", err, zero)
+	}
+	return pk.(T), nil
+}
+
+func (c *Client) Received() []protocol.DataPacket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]protocol.DataPacket(nil), c.history...)
+}
+
+func (c *Client) Drain() {
+	c.mu.Lock()
+	c.inbox = nil
+	c.mu.Unlock()
+}
+
+func (c *Client) EntityID() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entityID
+}
+
+func (c *Client) Position() (x, y, z float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.x, c.y, c.z
+}
+
+func (c *Client) Spawned() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.spawned
+}
+
+func (c *Client) Login() error {
+	uuid := make([]byte, 16)
+	rand.Read(uuid)
+
+	// Unsupported versions are sent in the default layout so tests can check
+	// how the server turns them away.
+	if codec, err := protocol.CodecFor(int(c.Protocol)); err == nil {
+		c.mu.Lock()
+		c.codec = codec
+		c.mu.Unlock()
+	}
+
+	pk := protocol.NewLoginPacket()
+	pk.Username = c.Username
+	pk.Protocol = c.Protocol
+	pk.ClientID = int64(uuid[0])<<8 | int64(uuid[1])
+	pk.ClientUUID = string(uuid)
+	pk.ServerAddress = c.rak.LocalAddr().String()
+	pk.SkinID = "Standard_Steve"
+	pk.SkinData = make([]byte, 64*32*4)
+	if err := c.SendPacket(pk); err != nil {
+		return err
+	}
+
+	status, err := ExpectPacket(c, DefaultTimeout, func(p *protocol.PlayStatusPacket) bool { return true })
+	if err != nil {
+		return err
+	}
+	if status.Status != protocol.PlayStatusLoginSuccess {
+		return fmt.Errorf("testclient: login rejected with status %d", status.Status)
+	}
+	_, err = ExpectPacket[*protocol.StartGamePacket](c, DefaultTimeout, nil)
+	return err
+}
+
+func (c *Client) WaitForSpawn(timeout time.Duration) error {
+	radius := protocol.NewRequestChunkRadiusPacket()
+	radius.Radius = 4
+	if err := c.SendPacket(radius); err != nil {
+		return err
+	}
+	_, err := ExpectPacket(c, timeout, func(p *protocol.PlayStatusPacket) bool {
+		return p.Status == protocol.PlayStatusPlayerSpawn
+	})
+	return err
+}
+
+func (c *Client) Move(x, y, z float32) error {
+	c.mu.Lock()
+	c.x, c.y, c.z = x, y, z
+	pk := protocol.NewMovePlayerPacket()
+	pk.EntityID = c.entityID
+	pk.X, pk.Y, pk.Z = x, y, z
+	pk.Yaw, pk.BodyYaw, pk.Pitch = c.yaw, c.yaw, c.pitch
+	pk.OnGround = true
+	c.mu.Unlock()
+	return c.SendPacket(pk)
+}
+
+func (c *Client) Chat(message string) error {
+	pk := protocol.NewTextPacket()
+	pk.TextType = protocol.TextTypeChat
+	pk.SourceName = c.Username
+	pk.Message = message
+	return c.SendPacket(pk)
+}
+
+func (c *Client) BreakBlock(x, y, z int32) error {
+	pk := protocol.NewRemoveBlockPacket()
+	pk.EntityID = c.EntityID()
+	pk.X, pk.Y, pk.Z = x, byte(y), z
+	return c.SendPacket(pk)
+}
+
+func (c *Client) UseItem(x, y, z int32, face byte, slot int32, it item.Item) error {
+	px, py, pz := c.Position()
+	pk := protocol.NewUseItemPacket()
+	pk.X, pk.Y, pk.Z = x, y, z
+	pk.Face = face
+	pk.FX, pk.FY, pk.FZ = 0.5, 0.5, 0.5
+	pk.PosX, pk.PosY, pk.PosZ = px, py, pz
+	pk.Slot = slot
+	pk.Item = it
+	return c.SendPacket(pk)
+}
+
+func (c *Client) Close() {
+	c.rak.Close()
+}
@@ -0,0 +1,509 @@
+package testclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/capture"
+	"github.com/scaxe/scaxe-go/pkg/config"
+	"github.com/scaxe/scaxe-go/pkg/event"
+	"github.com/scaxe/scaxe-go/pkg/item"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/player"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+	"github.com/scaxe/scaxe-go/pkg/proxy"
+	"github.com/scaxe/scaxe-go/pkg/webadmin"
+)
+
+func TestLoginAndSpawn(t *testing.T) {
+	srv := StartServer(t)
+	c := srv.Connect(t, "Alice")
+
+	if c.EntityID() == 0 {
+		t.Fatal("StartGame did not assign an entity ID")
+	}
+	if _, err := ExpectPacket[*protocol.FullChunkDataPacket](c, DefaultTimeout, nil); err != nil {
+		t.Fatal(err)
+	}
+	if srv.GetPlayer("Alice") == nil {
+		t.Fatal("server does not list Alice as online")
+	}
+}
+
+func TestMovementIsBroadcast(t *testing.T) {
+	srv := StartServer(t)
+	alice := srv.Connect(t, "Alice")
+	bob := srv.Connect(t, "Bob")
+
+	x, y, z := bob.Position()
+	if err := bob.Move(x+2, y, z+1); err != nil {
+		t.Fatal(err)
+	}
+
+	pk, err := ExpectPacket(alice, DefaultTimeout, func(p *protocol.MovePlayerPacket) bool {
+		return p.EntityID == bob.EntityID() && p.X == x+2
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.Z != z+1 {
+		t.Errorf("broadcast Z = %v, want %v", pk.Z, z+1)
+	}
+}
+
+func TestChat(t *testing.T) {
+	srv := StartServer(t)
+	alice := srv.Connect(t, "Alice")
+	bob := srv.Connect(t, "Bob")
+
+	if err := alice.Chat("hello bob"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ExpectPacket(bob, DefaultTimeout, func(p *protocol.TextPacket) bool {
+		return p.TextType == protocol.TextTypeChat && p.SourceName == "Alice" && p.Message == "hello bob"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBreakAndPlaceBlock(t *testing.T) {
+	srv := StartServer(t, func(cfg *config.ServerConfig) { cfg.Gamemode = 1 })
+	alice := srv.Connect(t, "Alice")
+	bob := srv.Connect(t, "Bob")
+
+	x, _, z := alice.Position()
+	bx, bz := int32(x), int32(z)
+	by := int32(127)
+	srv.Do(t, func() {
+		for by > 0 && srv.Level.GetBlockId(bx, by, bz) == 0 {
+			by--
+		}
+	})
+	if by == 0 {
+		t.Fatal("no ground below the spawn point")
+	}
+
+	if err := alice.BreakBlock(bx, by, bz); err != nil {
+		t.Fatal(err)
+	}
+	isUpdate := func(y int32, id byte) func(*protocol.UpdateBlockPacket) bool {
+		return func(p *protocol.UpdateBlockPacket) bool {
+			r := p.Records[0]
+			return r.X == bx && int32(r.Y) == y && r.Z == bz && r.BlockID == id
+		}
+	}
+	if _, err := ExpectPacket(bob, DefaultTimeout, isUpdate(by, 0)); err != nil {
+		t.Fatal(err)
+	}
+	var id byte
+	srv.Do(t, func() { id = srv.Level.GetBlockId(bx, by, bz) })
+	if id != 0 {
+		t.Fatalf("block at %d,%d,%d = %d after break, want air", bx, by, bz, id)
+	}
+
+	srv.Do(t, func() { srv.GetPlayer("Alice").Inventory.SetItemInHand(item.NewItem(1, 0, 1)) })
+	if err := alice.UseItem(bx, by-1, bz, 1, 0, item.NewItem(1, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpectPacket(bob, DefaultTimeout, isUpdate(by, 1)); err != nil {
+		t.Fatal(err)
+	}
+	srv.Do(t, func() { id = srv.Level.GetBlockId(bx, by, bz) })
+	if id != 1 {
+		t.Fatalf("block at %d,%d,%d = %d after place, want stone", bx, by, bz, id)
+	}
+}
+
+func TestMemoryTransport(t *testing.T) {
+	srv := StartServer(t, func(cfg *config.ServerConfig) { cfg.NetworkTransport = network.TransportMemory })
+	alice := srv.Connect(t, "Alice")
+	bob := srv.Connect(t, "Bob")
+
+	if err := bob.Chat("over memory"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ExpectPacket(alice, DefaultTimeout, func(p *protocol.TextPacket) bool {
+		return p.SourceName == "Bob" && p.Message == "over memory"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice.Close()
+	deadline := time.Now().Add(DefaultTimeout)
+	for srv.GetPlayer("Alice") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Alice still online after closing the connection")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMixedVersions(t *testing.T) {
+	srv := StartServer(t)
+	alice := srv.ConnectProtocol(t, "Alice", 70)
+	bob := srv.ConnectProtocol(t, "Bob", 84)
+
+	if _, err := ExpectPacket[*protocol.FullChunkDataPacket](bob, DefaultTimeout, nil); err != nil {
+		t.Fatal(err)
+	}
+	var got *protocol.Codec
+	srv.Do(t, func() { got = srv.GetPlayer("Bob").Codec })
+	if got != protocol.Codec015 {
+		t.Fatalf("Bob negotiated %v", got)
+	}
+
+	if err := bob.Chat("from 0.15"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpectPacket(alice, DefaultTimeout, func(p *protocol.TextPacket) bool {
+		return p.SourceName == "Bob" && p.Message == "from 0.15"
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	x, y, z := alice.Position()
+	if err := alice.Move(x+1, y, z); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpectPacket(bob, DefaultTimeout, func(p *protocol.MovePlayerPacket) bool {
+		return p.EntityID == alice.EntityID() && p.X == x+1
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnsupportedProtocolRejected(t *testing.T) {
+	srv := StartServer(t)
+	for _, tc := range []struct {
+		protocol int32
+		status   int32
+	}{
+		{38, protocol.PlayStatusLoginFailedClient},
+		{91, protocol.PlayStatusLoginFailedServer},
+	} {
+		c := srv.Dial(t, "Old")
+		c.Protocol = tc.protocol
+		if err := c.Login(); err == nil {
+			t.Fatalf("protocol %d was accepted", tc.protocol)
+		}
+		var status int32 = -1
+		for _, pk := range c.Received() {
+			if p, ok := pk.(*protocol.PlayStatusPacket); ok {
+				status = p.Status
+			}
+		}
+		if status != tc.status {
+			t.Errorf("protocol %d got status %d, want %d", tc.protocol, status, tc.status)
+		}
+		if srv.GetPlayer("Old") != nil {
+			t.Errorf("protocol %d player is online", tc.protocol)
+		}
+	}
+}
+
+func disconnectMessage(c *Client) string {
+	for _, pk := range c.Received() {
+		if d, ok := pk.(*protocol.DisconnectPacket); ok {
+			return d.Message
+		}
+	}
+	return ""
+}
+
+func TestDuplicateLogin(t *testing.T) {
+	t.Run("kick-old", func(t *testing.T) {
+		srv := StartServer(t)
+		first := srv.Connect(t, "Alice")
+		second := srv.Connect(t, "alice")
+
+		if _, err := ExpectPacket[*protocol.DisconnectPacket](first, DefaultTimeout, nil); err != nil {
+			t.Fatal(err)
+		}
+		if msg := disconnectMessage(first); msg != player.DisconnectOtherLocation {
+			t.Errorf("old session kicked with %q", msg)
+		}
+		if p := srv.GetPlayer("alice"); p == nil || srv.GetOnlineCount() != 1 {
+			t.Fatalf("online = %d, new session listed = %v", srv.GetOnlineCount(), p != nil)
+		}
+		if err := second.Chat("still here"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("kick-old cancelled", func(t *testing.T) {
+		event.Register("PlayerKickEvent", func(e event.Event) {
+			e.(*event.PlayerKickEvent).SetCancelled(true)
+		}, event.PriorityNormal, "e2e")
+		t.Cleanup(func() { event.GetGlobalManager().UnregisterPlugin("e2e") })
+
+		srv := StartServer(t)
+		srv.Connect(t, "Alice")
+		old := srv.GetPlayer("Alice")
+		srv.Connect(t, "Alice")
+
+		waitFor(t, "old session stayed connected after its kick was cancelled", func() bool {
+			return !old.IsConnected()
+		})
+		if n := srv.GetOnlineCount(); n != 1 {
+			t.Errorf("online = %d, want 1", n)
+		}
+	})
+
+	t.Run("kick-new", func(t *testing.T) {
+		srv := StartServer(t, func(cfg *config.ServerConfig) { cfg.DuplicateLogin = "kick-new" })
+		first := srv.Connect(t, "Alice")
+		second := srv.Dial(t, "Alice")
+
+		if err := second.Login(); err == nil {
+			t.Fatal("duplicate login was accepted")
+		}
+		if disconnectMessage(second) == "" {
+			t.Error("new session was not sent a disconnect screen")
+		}
+		if srv.GetPlayer("Alice") == nil {
+			t.Fatal("original session was dropped")
+		}
+		if disconnectMessage(first) != "" {
+			t.Error("original session was kicked")
+		}
+	})
+}
+
+func TestInvalidLogin(t *testing.T) {
+	srv := StartServer(t)
+
+	c := srv.Dial(t, "no/slashes")
+	if err := c.Login(); err == nil {
+		t.Fatal("invalid name was accepted")
+	}
+	if msg := disconnectMessage(c); msg != player.DisconnectInvalidName {
+		t.Errorf("invalid name kicked with %q", msg)
+	}
+}
+
+func TestPacketCapture(t *testing.T) {
+	srv := StartServer(t, func(cfg *config.ServerConfig) { cfg.PacketCapturePlayers = "Alice" })
+	alice := srv.Connect(t, "Alice")
+	if err := alice.Chat("captured"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpectPacket(alice, DefaultTimeout, func(p *protocol.TextPacket) bool { return p.Message == "captured" }); err != nil {
+		t.Fatal(err)
+	}
+	path, err := srv.StopCapture("Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := capture.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for {
+		rec, err := r.Next()
+		if err != nil {
+			break
+		}
+		pk, _ := capture.Decode(rec.Data)
+		if pk != nil {
+			seen[rec.Direction.String()+" "+pk.Name()] = true
+		}
+	}
+	for _, want := range []string{"C->S LoginPacket", "S->C StartGamePacket", "S->C FullChunkDataPacket", "C->S TextPacket", "S->C TextPacket"} {
+		if !seen[want] {
+			t.Errorf("capture is missing %s", want)
+		}
+	}
+}
+
+func startBackend(t *testing.T, name string) *TestServer {
+	return StartServer(t, func(cfg *config.ServerConfig) {
+		cfg.NetworkTransport = network.TransportProxy
+		cfg.ProxySecret = "secret"
+		cfg.LevelName = name
+	})
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(DefaultTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	lobby := startBackend(t, "lobby")
+	games := startBackend(t, "games")
+
+	p, err := proxy.New(proxy.Config{
+		Transport: network.TransportMemory,
+		Secret:    "secret",
+		Backends: []proxy.Backend{
+			{Name: "lobby", Address: lobby.Address},
+			{Name: "games", Address: games.Address},
+		},
+		MaxPlayers: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(p.Stop)
+
+	alice, err := ConnectMemory(p.Transport().(*network.MemoryTransport), "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(alice.Close)
+
+	id := alice.EntityID()
+	if pl := lobby.GetPlayer("Alice"); pl == nil || pl.ID != id {
+		t.Fatalf("lobby player = %v, client entity ID %d", pl, id)
+	}
+
+	alice.Drain()
+	if err := alice.Chat("/server games"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpectPacket[*protocol.ChangeDimensionPacket](alice, DefaultTimeout, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ExpectPacket(alice, DefaultTimeout, func(p *protocol.PlayStatusPacket) bool {
+		return p.Status == protocol.PlayStatusPlayerSpawn
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	starts := 0
+	for _, pk := range alice.Received() {
+		if _, ok := pk.(*protocol.StartGamePacket); ok {
+			starts++
+		}
+	}
+	if starts != 1 || alice.EntityID() != id {
+		t.Fatalf("client saw %d StartGame packets, entity ID %d, want 1 and %d", starts, alice.EntityID(), id)
+	}
+
+	if pl := games.GetPlayer("Alice"); pl == nil || pl.ID != id {
+		t.Fatalf("games player = %v, want entity ID %d", pl, id)
+	}
+	waitFor(t, "Alice still on the lobby after moving", func() bool { return lobby.GetPlayer("Alice") == nil })
+
+	if err := alice.Chat("made it"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpectPacket(alice, DefaultTimeout, func(p *protocol.TextPacket) bool { return p.Message == "made it" }); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("bad secret", func(t *testing.T) {
+		link, err := network.DialLink(games.Address, "wrong", network.LinkHello{Address: "127.0.0.1:1", Username: "Mallory"}, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer link.Close()
+		received := 0
+		link.Serve(func([]byte) { received++ }, func(string) {})
+		if received != 0 || len(games.Network.Conns()) != 1 {
+			t.Fatalf("forged link got %d packets, %d links open", received, len(games.Network.Conns()))
+		}
+	})
+
+	t.Run("duplicate login", func(t *testing.T) {
+		transport := p.Transport().(*network.MemoryTransport)
+		lobby.BanList.Add("Alice", "test", "test")
+		rejected, err := DialMemory(transport, "Alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rejected.Close()
+		if err := rejected.Login(); err == nil {
+			t.Fatal("banned login was accepted")
+		}
+		if msg := disconnectMessage(alice); msg != "" {
+			t.Fatalf("rejected login kicked the online session with %q", msg)
+		}
+
+		lobby.BanList.Remove("Alice")
+		replacement, err := ConnectMemory(transport, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer replacement.Close()
+		waitFor(t, "old session was not kicked", func() bool { return disconnectMessage(alice) != "" })
+		if msg := disconnectMessage(alice); msg != player.DisconnectOtherLocation {
+			t.Errorf("old session kicked with %q", msg)
+		}
+	})
+}
+
+func TestWebAdmin(t *testing.T) {
+	srv := StartServer(t, func(cfg *config.ServerConfig) {
+		cfg.WebAdminAddress = "127.0.0.1:0"
+		cfg.WebAdminToken = "token"
+	})
+	alice := srv.Connect(t, "Alice")
+	base := "http://" + srv.WebAdmin.Addr().String()
+
+	call := func(method, path, body string, out interface{}) int {
+		t.Helper()
+		req, err := http.NewRequest(method, base+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer token")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if out != nil {
+			if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return res.StatusCode
+	}
+
+	var players []webadmin.Player
+	if status := call("GET", "/api/players", "", &players); status != http.StatusOK || len(players) != 1 || players[0].Name != "Alice" {
+		t.Fatalf("players = %d %+v", status, players)
+	}
+
+	if status := call("POST", "/api/players/Alice/ban", `{"reason":"griefing"}`, nil); status != http.StatusOK {
+		t.Fatalf("ban returned %d", status)
+	}
+	if _, err := ExpectPacket[*protocol.DisconnectPacket](alice, DefaultTimeout, nil); err != nil {
+		t.Fatal(err)
+	}
+	if msg := disconnectMessage(alice); !strings.Contains(msg, "griefing") {
+		t.Errorf("kicked with %q", msg)
+	}
+	if err := srv.Dial(t, "alice").Login(); err == nil {
+		t.Fatal("banned player logged in again")
+	}
+
+	var result struct{ Output, Error string }
+	if status := call("POST", "/api/command", `{"command":"pardon Alice"}`, &result); status != http.StatusOK || result.Error != "" {
+		t.Fatalf("pardon = %d %+v", status, result)
+	}
+	srv.Connect(t, "Alice")
+}
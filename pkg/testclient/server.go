@@ -0,0 +1,104 @@
+package testclient
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/config"
+	"github.com/scaxe/scaxe-go/pkg/network"
+	"github.com/scaxe/scaxe-go/pkg/protocol"
+	"github.com/scaxe/scaxe-go/pkg/scheduler"
+	"github.com/scaxe/scaxe-go/pkg/server"
+)
+
+type TestServer struct {
+	*server.Server
+	Dir     string
+	Address string
+}
+
+// StartServer runs a server on a loopback port inside a temporary working
+// directory. The server uses process-wide state, so tests using it must not
+// run in parallel.
+func StartServer(t testing.TB, configure ...func(*config.ServerConfig)) *TestServer {
+	t.Helper()
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	cfg := config.DefaultConfig()
+	cfg.ServerIP = "127.0.0.1"
+	cfg.ServerPort = 0
+	cfg.LevelType = "flat"
+	cfg.EnableQuery = false
+	cfg.BackupInterval = 0
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	srv := server.NewServer(cfg)
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Stop)
+
+	return &TestServer{Server: srv, Dir: dir, Address: srv.Network.Addr().String()}
+}
+
+// Do runs fn on the server's main thread and waits for it to finish. Tests
+// must read and change world and player state through it.
+func (s *TestServer) Do(t testing.TB, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	scheduler.RunAsync(nil, func(interface{}) {
+		fn()
+		close(done)
+	})
+	select {
+	case <-done:
+	case <-time.After(DefaultTimeout):
+		t.Fatal("main thread did not run the fixture")
+	}
+}
+
+func (s *TestServer) Connect(t testing.TB, username string) *Client {
+	t.Helper()
+	return s.ConnectProtocol(t, username, protocol.ProtocolCurrent)
+}
+
+// ConnectProtocol logs in with the given protocol version, using the
+// matching codec on the client side.
+func (s *TestServer) ConnectProtocol(t testing.TB, username string, version int32) *Client {
+	t.Helper()
+	c := s.Dial(t, username)
+	c.Protocol = version
+	if err := c.join(); err != nil {
+		t.Fatalf("connect %s: %v", username, err)
+	}
+	return c
+}
+
+// Dial opens a session without logging in.
+func (s *TestServer) Dial(t testing.TB, username string) *Client {
+	t.Helper()
+	var c *Client
+	var err error
+	if memory, ok := s.Network.(*network.MemoryTransport); ok {
+		c, err = DialMemory(memory, username)
+	} else {
+		c, err = Dial(s.Address, username)
+	}
+	if err != nil {
+		t.Fatalf("dial %s: %v", username, err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
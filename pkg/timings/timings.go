@@ -0,0 +1,377 @@
+package timings
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	FullTick           = "Full Server Tick"
+	LevelTick          = "Level Tick"
+	EntityTick         = "Entity Tick"
+	TileTick           = "Tile Tick"
+	ScheduledUpdates   = "Scheduled Block Updates"
+	PressurePlates     = "Pressure Plates"
+	ChunkTick          = "Chunk Tick"
+	Weather            = "Weather"
+	BlockBroadcast     = "Block Update Broadcast"
+	EntityBroadcast    = "Entity Update Broadcast"
+	PlayerTick         = "Player Tick"
+	ChunkSends         = "Chunk Sends"
+	EntityMovement     = "Entity Movement Broadcast"
+	PluginTick         = "Plugin Tick"
+	PluginHandlers     = "Plugin Event Handlers"
+	PacketFlush        = "Packet Flush"
+	SchedulerHeartbeat = "Scheduler"
+)
+
+var parents = map[string]string{
+	LevelTick:          FullTick,
+	EntityTick:         LevelTick,
+	TileTick:           LevelTick,
+	ScheduledUpdates:   LevelTick,
+	PressurePlates:     LevelTick,
+	ChunkTick:          LevelTick,
+	Weather:            LevelTick,
+	BlockBroadcast:     FullTick,
+	EntityBroadcast:    FullTick,
+	PlayerTick:         FullTick,
+	ChunkSends:         FullTick,
+	EntityMovement:     FullTick,
+	PluginTick:         FullTick,
+	PacketFlush:        FullTick,
+	SchedulerHeartbeat: FullTick,
+}
+
+type Timer struct {
+	name   string
+	parent string
+	mu     sync.Mutex
+	count  int64
+	total  time.Duration
+	max    time.Duration
+	inTick time.Duration
+}
+
+func (t *Timer) add(d time.Duration) {
+	t.mu.Lock()
+	t.count++
+	t.total += d
+	t.inTick += d
+	if d > t.max {
+		t.max = d
+	}
+	t.mu.Unlock()
+}
+
+type Record struct {
+	Name    string
+	Parent  string
+	Depth   int
+	Count   int64
+	Total   time.Duration
+	Max     time.Duration
+	PerTick time.Duration
+	Percent float64
+}
+
+type Spike struct {
+	Tick     int64
+	Time     time.Time
+	Duration time.Duration
+	Timers   []Record
+}
+
+const maxSpikes = 20
+
+var (
+	enabled        atomic.Bool
+	ticks          atomic.Int64
+	spikeThreshold atomic.Int64
+
+	mu      sync.Mutex
+	timers  = make(map[string]*Timer)
+	spikes  []Spike
+	started = time.Now()
+)
+
+func init() {
+	spikeThreshold.Store(int64(100 * time.Millisecond))
+}
+
+func noop() {}
+
+func Enable() {
+	if !enabled.Swap(true) {
+		Reset()
+	}
+}
+
+func Disable() {
+	enabled.Store(false)
+}
+
+func IsEnabled() bool {
+	return enabled.Load()
+}
+
+func SetSpikeThreshold(d time.Duration) {
+	spikeThreshold.Store(int64(d))
+}
+
+func GetSpikeThreshold() time.Duration {
+	return time.Duration(spikeThreshold.Load())
+}
+
+func Reset() {
+	mu.Lock()
+	timers = make(map[string]*Timer)
+	spikes = nil
+	started = time.Now()
+	mu.Unlock()
+	ticks.Store(0)
+}
+
+func get(name, parent string) *Timer {
+	mu.Lock()
+	defer mu.Unlock()
+	t, ok := timers[name]
+	if !ok {
+		if parent == "" {
+			parent = parents[name]
+		}
+		t = &Timer{name: name, parent: parent}
+		timers[name] = t
+	}
+	return t
+}
+
+func Start(name string) func() {
+	return StartChild("", name)
+}
+
+func StartChild(parent, name string) func() {
+	if !enabled.Load() {
+		return noop
+	}
+	t := get(name, parent)
+	begin := time.Now()
+	return func() { t.add(time.Since(begin)) }
+}
+
+func EntityTimer(e interface{}) string {
+	t := reflect.TypeOf(e)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "Entity: unknown"
+	}
+	return "Entity: " + t.Name()
+}
+
+func PluginTickTimer(plugin string) string {
+	return "Plugin: " + plugin + " (tick)"
+}
+
+func PluginEventsTimer(plugin string) string {
+	return "Plugin: " + plugin + " (events)"
+}
+
+func HandlerTimer(plugin, eventName string) string {
+	return plugin + " / " + eventName
+}
+
+func EndTick(tick int64) {
+	if !enabled.Load() {
+		return
+	}
+	ticks.Add(1)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var full time.Duration
+	if t := timers[FullTick]; t != nil {
+		t.mu.Lock()
+		full = t.inTick
+		t.mu.Unlock()
+	}
+
+	spike := full >= GetSpikeThreshold()
+	var records []Record
+	for _, t := range timers {
+		t.mu.Lock()
+		if spike && t.inTick > 0 {
+			records = append(records, Record{Name: t.name, Parent: t.parent, Total: t.inTick, Max: t.inTick})
+		}
+		t.inTick = 0
+		t.mu.Unlock()
+	}
+	if !spike {
+		return
+	}
+	spikes = append(spikes, Spike{Tick: tick, Time: time.Now(), Duration: full, Timers: tree(records, full, 0)})
+	if len(spikes) > maxSpikes {
+		spikes = spikes[len(spikes)-maxSpikes:]
+	}
+}
+
+func Spikes() []Spike {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Spike, len(spikes))
+	copy(out, spikes)
+	return out
+}
+
+func Report() []Record {
+	mu.Lock()
+	list := make([]*Timer, 0, len(timers))
+	for _, t := range timers {
+		list = append(list, t)
+	}
+	mu.Unlock()
+
+	records := make([]Record, 0, len(list))
+	var full time.Duration
+	for _, t := range list {
+		t.mu.Lock()
+		r := Record{Name: t.name, Parent: t.parent, Count: t.count, Total: t.total, Max: t.max}
+		t.mu.Unlock()
+		if r.Name == FullTick {
+			full = r.Total
+		}
+		records = append(records, r)
+	}
+	return tree(records, full, ticks.Load())
+}
+
+func tree(records []Record, full time.Duration, n int64) []Record {
+	children := make(map[string][]Record)
+	known := make(map[string]bool, len(records))
+	for _, r := range records {
+		known[r.Name] = true
+	}
+	for i := range records {
+		r := &records[i]
+		if n > 0 {
+			r.PerTick = r.Total / time.Duration(n)
+		}
+		if full > 0 {
+			r.Percent = float64(r.Total) / float64(full) * 100
+		}
+		parent := r.Parent
+		if !known[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], *r)
+	}
+
+	var out []Record
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		list := children[parent]
+		sort.Slice(list, func(i, j int) bool { return list[i].Total > list[j].Total })
+		for _, r := range list {
+			r.Depth = depth
+			out = append(out, r)
+			walk(r.Name, depth+1)
+		}
+	}
+	walk("", 0)
+	return out
+}
+
+func Ticks() int64 {
+	return ticks.Load()
+}
+
+func Since() time.Time {
+	mu.Lock()
+	defer mu.Unlock()
+	return started
+}
+
+func WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Timings since %s (%d ticks)\n\n", Since().Format(time.RFC3339), Ticks()); err != nil {
+		return err
+	}
+	writeTextTable(w, Report(), true)
+
+	for _, s := range Spikes() {
+		fmt.Fprintf(w, "\nLag spike at tick %d (%s): %s\n", s.Tick, s.Time.Format(time.RFC3339), ms(s.Duration))
+		writeTextTable(w, s.Timers, false)
+	}
+	return nil
+}
+
+func writeTextTable(w io.Writer, records []Record, totals bool) {
+	if totals {
+		fmt.Fprintf(w, "%-48s %10s %12s %12s %12s %7s\n", "Name", "Count", "Total", "Avg/tick", "Max", "%Tick")
+	}
+	for _, r := range records {
+		name := strings.Repeat("  ", r.Depth) + r.Name
+		if totals {
+			fmt.Fprintf(w, "%-48s %10d %12s %12s %12s %6.1f%%\n", name, r.Count, ms(r.Total), ms(r.PerTick), ms(r.Max), r.Percent)
+		} else {
+			fmt.Fprintf(w, "  %-46s %12s %6.1f%%\n", name, ms(r.Total), r.Percent)
+		}
+	}
+}
+
+func WriteHTML(w io.Writer) error {
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>Timings</title>\n")
+	fmt.Fprintf(w, "<style>body{font-family:monospace}td,th{padding:2px 8px;text-align:right}td:first-child,th:first-child{text-align:left}</style></head><body>\n")
+	fmt.Fprintf(w, "<h1>Timings</h1><p>Since %s, %d ticks</p>\n<table>\n", html.EscapeString(Since().Format(time.RFC3339)), Ticks())
+	fmt.Fprintf(w, "<tr><th>Name</th><th>Count</th><th>Total</th><th>Avg/tick</th><th>Max</th><th>%%Tick</th></tr>\n")
+	for _, r := range Report() {
+		fmt.Fprintf(w, "<tr><td style=\"padding-left:%dem\">%s</td><td>%d</td><td>%s</td><td>%s</td><td>%s</td><td>%.1f%%</td></tr>\n",
+			r.Depth*2, html.EscapeString(r.Name), r.Count, ms(r.Total), ms(r.PerTick), ms(r.Max), r.Percent)
+	}
+	fmt.Fprintf(w, "</table>\n")
+
+	for _, s := range Spikes() {
+		fmt.Fprintf(w, "<h2>Lag spike at tick %d (%s): %s</h2>\n<table>\n", s.Tick, html.EscapeString(s.Time.Format(time.RFC3339)), ms(s.Duration))
+		for _, r := range s.Timers {
+			fmt.Fprintf(w, "<tr><td style=\"padding-left:%dem\">%s</td><td>%s</td><td>%.1f%%</td></tr>\n",
+				r.Depth*2, html.EscapeString(r.Name), ms(r.Total), r.Percent)
+		}
+		fmt.Fprintf(w, "</table>\n")
+	}
+	_, err := fmt.Fprintf(w, "</body></html>\n")
+	return err
+}
+
+func Export(dir, format string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	ext, write := ".html", WriteHTML
+	if format == "text" {
+		ext, write = ".txt", WriteText
+	}
+	path := filepath.Join(dir, "timings-"+time.Now().Format("20060102-150405")+ext)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(d.Microseconds())/1000)
+}
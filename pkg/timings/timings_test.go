@@ -0,0 +1,108 @@
+package timings
+
+import (
+	"testing"
+	"time"
+)
+
+func setup(t *testing.T) {
+	t.Helper()
+	Enable()
+	Reset()
+	threshold := GetSpikeThreshold()
+	t.Cleanup(func() {
+		Disable()
+		Reset()
+		SetSpikeThreshold(threshold)
+	})
+}
+
+func record(parent, name string, d time.Duration) {
+	get(name, parent).add(d)
+}
+
+func TestReportTree(t *testing.T) {
+	setup(t)
+	SetSpikeThreshold(time.Hour)
+
+	for i := 0; i < 2; i++ {
+		record("", FullTick, 40*time.Millisecond)
+		record("", LevelTick, 20*time.Millisecond)
+		record("", EntityTick, 15*time.Millisecond)
+		record("", PluginTick, 10*time.Millisecond)
+		record(PluginTick, PluginTickTimer("economy"), 8*time.Millisecond)
+		record("missing parent", "Orphan", time.Millisecond)
+		EndTick(int64(i))
+	}
+
+	want := []struct {
+		name    string
+		depth   int
+		perTick time.Duration
+		percent float64
+	}{
+		{FullTick, 0, 40 * time.Millisecond, 100},
+		{LevelTick, 1, 20 * time.Millisecond, 50},
+		{EntityTick, 2, 15 * time.Millisecond, 37.5},
+		{PluginTick, 1, 10 * time.Millisecond, 25},
+		{"Plugin: economy (tick)", 2, 8 * time.Millisecond, 20},
+		{"Orphan", 0, time.Millisecond, 2.5},
+	}
+	report := Report()
+	if len(report) != len(want) {
+		t.Fatalf("report has %d records, want %d: %+v", len(report), len(want), report)
+	}
+	for i, w := range want {
+		r := report[i]
+		if r.Name != w.name || r.Depth != w.depth || r.PerTick != w.perTick || r.Percent != w.percent || r.Count != 2 {
+			t.Errorf("record %d = %s depth %d %s/tick %.1f%% x%d, want %s depth %d %s/tick %.1f%% x2",
+				i, r.Name, r.Depth, r.PerTick, r.Percent, r.Count, w.name, w.depth, w.perTick, w.percent)
+		}
+	}
+	if Ticks() != 2 {
+		t.Errorf("Ticks = %d, want 2", Ticks())
+	}
+}
+
+func TestSpikeThreshold(t *testing.T) {
+	setup(t)
+	SetSpikeThreshold(50 * time.Millisecond)
+
+	ticks := []struct {
+		full, level time.Duration
+	}{
+		{49 * time.Millisecond, 30 * time.Millisecond},
+		{50 * time.Millisecond, 0},
+		{80 * time.Millisecond, 60 * time.Millisecond},
+	}
+	for i, tick := range ticks {
+		record("", FullTick, tick.full)
+		if tick.level > 0 {
+			record("", LevelTick, tick.level)
+		}
+		EndTick(int64(i))
+	}
+
+	spikes := Spikes()
+	if len(spikes) != 2 {
+		t.Fatalf("captured %d spikes, want 2", len(spikes))
+	}
+	if spikes[0].Tick != 1 || spikes[0].Duration != 50*time.Millisecond || len(spikes[0].Timers) != 1 {
+		t.Errorf("spike at the threshold = %+v", spikes[0])
+	}
+	// Only time spent in the spiking tick counts, not the running totals.
+	s := spikes[1]
+	if s.Tick != 2 || len(s.Timers) != 2 || s.Timers[1].Name != LevelTick || s.Timers[1].Total != 60*time.Millisecond || s.Timers[1].Percent != 75 {
+		t.Errorf("second spike = %+v", s)
+	}
+}
+
+func TestDisabledTimersAreNoops(t *testing.T) {
+	Disable()
+	Reset()
+	Start(FullTick)()
+	EndTick(1)
+	if len(Report()) != 0 || Ticks() != 0 {
+		t.Error("disabled timings recorded data")
+	}
+}
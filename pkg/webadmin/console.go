@@ -0,0 +1,143 @@
+package webadmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const (
+	consoleBacklog = 200
+	clientBuffer   = 256
+)
+
+type consoleMessage struct {
+	Type string `json:"type"`
+	Line string `json:"line"`
+}
+
+// console fans logger output out to WebSocket clients. New clients get the
+// most recent lines first so the panel does not open on an empty screen.
+type console struct {
+	mu      sync.Mutex
+	backlog []string
+	clients map[chan consoleMessage]struct{}
+	cancel  func()
+}
+
+func newConsole() *console {
+	return &console{clients: make(map[chan consoleMessage]struct{})}
+}
+
+func (c *console) start() {
+	c.cancel = logger.Subscribe(c.publish)
+}
+
+func (c *console) stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.mu.Lock()
+	for ch := range c.clients {
+		delete(c.clients, ch)
+		close(ch)
+	}
+	c.mu.Unlock()
+}
+
+// publish runs under the logger's lock, so it never blocks: a client that
+// falls a full buffer behind misses lines.
+func (c *console) publish(line string) {
+	line = strings.TrimRight(line, "\n")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.backlog) == consoleBacklog {
+		c.backlog = append(c.backlog[:0], c.backlog[1:]...)
+	}
+	c.backlog = append(c.backlog, line)
+	for ch := range c.clients {
+		select {
+		case ch <- consoleMessage{Type: "log", Line: line}:
+		default:
+		}
+	}
+}
+
+func (c *console) subscribe() (chan consoleMessage, []string) {
+	ch := make(chan consoleMessage, clientBuffer)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clients[ch] = struct{}{}
+	return ch, append([]string(nil), c.backlog...)
+}
+
+func (c *console) unsubscribe(ch chan consoleMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.clients[ch]; ok {
+		delete(c.clients, ch)
+		close(ch)
+	}
+}
+
+// handleConsole streams log lines to the client and runs every text
+// message it sends as a command, answering with the command's output.
+func (s *Server) handleConsole(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	ch, backlog := s.console.subscribe()
+	defer s.console.unsubscribe(ch)
+	logger.Debug("Web admin console opened", "address", r.RemoteAddr)
+
+	for _, line := range backlog {
+		if writeMessage(ws, consoleMessage{Type: "log", Line: line}) != nil {
+			return
+		}
+	}
+
+	// The channel closes when the client leaves or the panel shuts down;
+	// either way the socket goes with it.
+	go func() {
+		defer ws.Close()
+		for msg := range ch {
+			if writeMessage(ws, msg) != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		text, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		command := strings.TrimPrefix(strings.TrimSpace(text), "/")
+		if command == "" {
+			continue
+		}
+		logger.Server("Web admin command", "address", r.RemoteAddr, "command", command)
+		output, err := s.backend.Execute(command)
+		if output = strings.TrimRight(output, "\n"); output != "" {
+			writeMessage(ws, consoleMessage{Type: "output", Line: output})
+		}
+		if err != nil {
+			writeMessage(ws, consoleMessage{Type: "error", Line: err.Error()})
+		}
+	}
+}
+
+func writeMessage(ws *wsConn, msg consoleMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return ws.WriteText(data)
+}
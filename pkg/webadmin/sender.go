@@ -0,0 +1,61 @@
+package webadmin
+
+import (
+	"strings"
+	"sync"
+)
+
+// Sender collects the output of a command run from the panel. It has every
+// permission, like the console.
+type Sender struct {
+	mu     sync.Mutex
+	output strings.Builder
+}
+
+func NewSender() *Sender {
+	return &Sender{}
+}
+
+func (s *Sender) SendMessage(message string) {
+	s.mu.Lock()
+	s.output.WriteString(stripFormatting(message))
+	s.output.WriteByte('\n')
+	s.mu.Unlock()
+}
+
+func (s *Sender) GetName() string {
+	return "WebAdmin"
+}
+
+func (s *Sender) IsOp() bool {
+	return true
+}
+
+func (s *Sender) HasPermission(name string) bool {
+	return true
+}
+
+func (s *Sender) Output() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.output.String()
+}
+
+func stripFormatting(message string) string {
+	if !strings.ContainsRune(message, '§') {
+		return message
+	}
+	var b strings.Builder
+	skip := false
+	for _, r := range message {
+		switch {
+		case skip:
+			skip = false
+		case r == '§':
+			skip = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,239 @@
+"use strict";
+
+const gamemodes = ["Survival", "Creative", "Adventure", "Spectator"];
+const maxConsoleLines = 1000;
+
+let token = localStorage.getItem("scaxe-admin-token") || "";
+let socket = null;
+let refreshTimer = null;
+
+const $ = (id) => document.getElementById(id);
+
+async function api(method, path, body) {
+	const res = await fetch(path, {
+		method,
+		headers: {
+			"Authorization": "Bearer " + token,
+			"Content-Type": "application/json",
+		},
+		body: body === undefined ? undefined : JSON.stringify(body),
+	});
+	const data = await res.json().catch(() => ({}));
+	if (res.status === 401) {
+		signOut("Invalid token");
+		throw new Error("unauthorized");
+	}
+	if (!res.ok) {
+		throw new Error(data.error || res.statusText);
+	}
+	return data;
+}
+
+function cell(row, text, className) {
+	const td = row.insertCell();
+	td.textContent = text;
+	if (className) {
+		td.className = className;
+	}
+	return td;
+}
+
+function fillTable(tbody, items, columns, render) {
+	tbody.replaceChildren();
+	if (items.length === 0) {
+		const row = tbody.insertRow();
+		const td = cell(row, "None", "empty");
+		td.colSpan = columns;
+		return;
+	}
+	for (const item of items) {
+		render(tbody.insertRow(), item);
+	}
+}
+
+function button(label, onClick, className) {
+	const b = document.createElement("button");
+	b.type = "button";
+	b.textContent = label;
+	if (className) {
+		b.className = className;
+	}
+	b.addEventListener("click", onClick);
+	return b;
+}
+
+async function playerAction(name, action) {
+	let body = {};
+	if (action === "kick" || action === "ban") {
+		const reason = prompt(`Reason to ${action} ${name}:`, "");
+		if (reason === null) {
+			return;
+		}
+		body.reason = reason;
+	}
+	try {
+		await api("POST", `/api/players/${encodeURIComponent(name)}/${action}`, body);
+		refresh();
+	} catch (err) {
+		appendConsole(`${action} ${name}: ${err.message}`, "error");
+	}
+}
+
+function formatUptime(seconds) {
+	const h = Math.floor(seconds / 3600);
+	const m = Math.floor((seconds % 3600) / 60);
+	return `${h}h ${m}m`;
+}
+
+function renderStatus(status) {
+	$("server-name").textContent = status.motd || status.name;
+	const entries = [
+		["Players", `${status.online}/${status.max_players}`],
+		["TPS", status.tps.toFixed(1)],
+		["MSPT", status.mspt.toFixed(1)],
+		["Uptime", formatUptime(status.uptime)],
+		["Version", status.version],
+	];
+	const dl = $("status");
+	dl.replaceChildren();
+	for (const [name, value] of entries) {
+		const dt = document.createElement("dt");
+		dt.textContent = name;
+		const dd = document.createElement("dd");
+		dd.textContent = value;
+		const group = document.createElement("div");
+		group.append(dt, dd);
+		dl.append(group);
+	}
+}
+
+async function refresh() {
+	try {
+		const [status, players, levels, plugins] = await Promise.all([
+			api("GET", "/api/status"),
+			api("GET", "/api/players"),
+			api("GET", "/api/levels"),
+			api("GET", "/api/plugins"),
+		]);
+		renderStatus(status);
+
+		fillTable($("players"), players, 6, (row, p) => {
+			cell(row, p.op ? p.name + " (op)" : p.name);
+			cell(row, p.level);
+			cell(row, `${p.x.toFixed(1)}, ${p.y.toFixed(1)}, ${p.z.toFixed(1)}`);
+			cell(row, `${p.ping} ms`);
+			cell(row, gamemodes[p.gamemode] || p.gamemode);
+			const actions = cell(row, "", "actions");
+			actions.append(
+				button(p.op ? "Deop" : "Op", () => playerAction(p.name, p.op ? "deop" : "op")),
+				button("Kick", () => playerAction(p.name, "kick")),
+				button("Ban", () => playerAction(p.name, "ban"), "danger"),
+			);
+		});
+		fillTable($("levels"), levels, 5, (row, l) => {
+			cell(row, l.default ? l.name + " (default)" : l.name);
+			cell(row, l.chunks);
+			cell(row, l.entities);
+			cell(row, l.players);
+			cell(row, l.time);
+		});
+		fillTable($("plugins"), plugins, 4, (row, p) => {
+			cell(row, p.name);
+			cell(row, p.version);
+			cell(row, p.kind);
+			cell(row, p.enabled ? "Enabled" : "Disabled");
+		});
+	} catch (err) {
+		if (err.message !== "unauthorized") {
+			appendConsole("Refresh failed: " + err.message, "error");
+		}
+	}
+}
+
+function appendConsole(line, className) {
+	const out = $("console");
+	const atBottom = out.scrollTop + out.clientHeight >= out.scrollHeight - 4;
+	const div = document.createElement("div");
+	div.textContent = line;
+	if (className) {
+		div.className = className;
+	}
+	out.append(div);
+	while (out.childElementCount > maxConsoleLines) {
+		out.firstElementChild.remove();
+	}
+	if (atBottom) {
+		out.scrollTop = out.scrollHeight;
+	}
+}
+
+function connectConsole() {
+	const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+	socket = new WebSocket(`${scheme}//${location.host}/api/console?token=${encodeURIComponent(token)}`);
+	socket.addEventListener("message", (ev) => {
+		const msg = JSON.parse(ev.data);
+		appendConsole(msg.line, msg.type === "log" ? "" : msg.type);
+	});
+	socket.addEventListener("close", () => {
+		if (socket !== null && token) {
+			appendConsole("Console disconnected, reconnecting...", "error");
+			setTimeout(connectConsole, 3000);
+		}
+	});
+}
+
+function signIn() {
+	$("login").hidden = true;
+	$("panel").hidden = false;
+	$("console").replaceChildren();
+	refresh();
+	refreshTimer = setInterval(refresh, 2000);
+	connectConsole();
+}
+
+function signOut(message) {
+	token = "";
+	localStorage.removeItem("scaxe-admin-token");
+	clearInterval(refreshTimer);
+	if (socket) {
+		const s = socket;
+		socket = null;
+		s.close();
+	}
+	$("panel").hidden = true;
+	$("login").hidden = false;
+	$("login-error").textContent = message || "";
+}
+
+$("login").addEventListener("submit", async (ev) => {
+	ev.preventDefault();
+	token = $("token").value;
+	try {
+		await api("GET", "/api/status");
+		localStorage.setItem("scaxe-admin-token", token);
+		signIn();
+	} catch (err) {
+		if (err.message !== "unauthorized") {
+			$("login-error").textContent = err.message;
+		}
+	}
+});
+
+$("logout").addEventListener("click", () => signOut());
+
+$("command").addEventListener("submit", (ev) => {
+	ev.preventDefault();
+	const line = $("command-line").value.trim();
+	if (!line || !socket || socket.readyState !== WebSocket.OPEN) {
+		return;
+	}
+	appendConsole("> " + line, "output");
+	socket.send(line);
+	$("command-line").value = "";
+});
+
+if (token) {
+	signIn();
+} else {
+	signOut();
+}
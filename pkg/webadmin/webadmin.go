@@ -0,0 +1,286 @@
+package webadmin
+
+import (
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+const maxRequestBody = 64 << 10
+
+//go:embed static
+var static embed.FS
+
+var ErrPlayerNotFound = errors.New("player not found")
+
+type Status struct {
+	Name       string  `json:"name"`
+	Version    string  `json:"version"`
+	MOTD       string  `json:"motd"`
+	Online     int     `json:"online"`
+	MaxPlayers int     `json:"max_players"`
+	TPS        float64 `json:"tps"`
+	MSPT       float64 `json:"mspt"`
+	Tick       int64   `json:"tick"`
+	Uptime     float64 `json:"uptime"`
+}
+
+type Player struct {
+	Name     string  `json:"name"`
+	Address  string  `json:"address"`
+	Level    string  `json:"level"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Z        float64 `json:"z"`
+	Ping     int     `json:"ping"`
+	Gamemode int     `json:"gamemode"`
+	Op       bool    `json:"op"`
+}
+
+type Level struct {
+	Name     string `json:"name"`
+	Default  bool   `json:"default"`
+	Chunks   int    `json:"chunks"`
+	Entities int    `json:"entities"`
+	Players  int    `json:"players"`
+	Time     int64  `json:"time"`
+}
+
+type Plugin struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Backend is the view of the game server the panel works with. Methods
+// that change state are expected to run on the server's main thread.
+type Backend interface {
+	Status() Status
+	Players() []Player
+	Levels() []Level
+	Plugins() []Plugin
+
+	Execute(command string) (string, error)
+	Kick(name, reason string) error
+	Ban(name, reason string) error
+	SetOp(name string, op bool) error
+}
+
+type Config struct {
+	Address string
+	Token   string
+}
+
+// Server serves the JSON API, the console WebSocket and the bundled UI.
+// Every API request must carry the token, either as a bearer token or, for
+// WebSocket connections where browsers cannot set headers, as the token
+// query parameter.
+type Server struct {
+	cfg     Config
+	backend Backend
+	console *console
+	http    *http.Server
+	addr    net.Addr
+}
+
+func NewServer(cfg Config, backend Backend) *Server {
+	s := &Server{
+		cfg:     cfg,
+		backend: backend,
+		console: newConsole(),
+	}
+	s.http = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+func (s *Server) Start() error {
+	if s.cfg.Token == "" {
+		return errors.New("web-admin-token must be set to enable the web admin")
+	}
+	ln, err := net.Listen("tcp", s.cfg.Address)
+	if err != nil {
+		return err
+	}
+	s.addr = ln.Addr()
+	s.console.start()
+	go func() {
+		if err := s.http.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logger.Error("Web admin stopped", "error", err)
+		}
+	}()
+	logger.Server("Web admin listening", "address", ln.Addr().String())
+	return nil
+}
+
+func (s *Server) Addr() net.Addr {
+	return s.addr
+}
+
+func (s *Server) Close() {
+	s.console.stop()
+	s.http.Close()
+}
+
+func (s *Server) Handler() http.Handler {
+	ui, _ := fs.Sub(static, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServer(http.FS(ui)))
+	mux.HandleFunc("GET /api/status", s.auth(s.handleStatus))
+	mux.HandleFunc("GET /api/players", s.auth(s.handlePlayers))
+	mux.HandleFunc("GET /api/levels", s.auth(s.handleLevels))
+	mux.HandleFunc("GET /api/plugins", s.auth(s.handlePlugins))
+	mux.HandleFunc("POST /api/command", s.auth(s.handleCommand))
+	mux.HandleFunc("POST /api/players/{name}/kick", s.auth(s.handleKick))
+	mux.HandleFunc("POST /api/players/{name}/ban", s.auth(s.handleBan))
+	mux.HandleFunc("POST /api/players/{name}/op", s.auth(s.handleOp(true)))
+	mux.HandleFunc("POST /api/players/{name}/deop", s.auth(s.handleOp(false)))
+	mux.HandleFunc("GET /api/console", s.consoleAuth(s.handleConsole))
+	return mux
+}
+
+func (s *Server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return s.checkToken(next, bearerToken)
+}
+
+// consoleAuth also takes the token from the query string, because browsers
+// cannot set headers on a WebSocket handshake. Tokens in URLs end up in
+// logs and history, so no other endpoint accepts them.
+func (s *Server) consoleAuth(next http.HandlerFunc) http.HandlerFunc {
+	return s.checkToken(next, func(r *http.Request) string {
+		if token := bearerToken(r); token != "" {
+			return token
+		}
+		return r.URL.Query().Get("token")
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return token
+}
+
+func (s *Server) checkToken(next http.HandlerFunc, tokenOf func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := tokenOf(r)
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Token)) != 1 {
+			logger.Warn("Web admin request refused", "address", r.RemoteAddr, "path", r.URL.Path)
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.backend.Status())
+}
+
+func (s *Server) handlePlayers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.backend.Players())
+}
+
+func (s *Server) handleLevels(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.backend.Levels())
+}
+
+func (s *Server) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.backend.Plugins())
+}
+
+func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Command string `json:"command"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	req.Command = strings.TrimPrefix(strings.TrimSpace(req.Command), "/")
+	if req.Command == "" {
+		writeError(w, http.StatusBadRequest, "command is empty")
+		return
+	}
+
+	logger.Server("Web admin command", "address", r.RemoteAddr, "command", req.Command)
+	output, err := s.backend.Execute(req.Command)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]string{"output": output, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"output": output})
+}
+
+func (s *Server) handleKick(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.respond(w, s.backend.Kick(r.PathValue("name"), req.Reason))
+}
+
+func (s *Server) handleBan(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.respond(w, s.backend.Ban(r.PathValue("name"), req.Reason))
+}
+
+func (s *Server) handleOp(op bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.respond(w, s.backend.SetOp(r.PathValue("name"), op))
+	}
+}
+
+func (s *Server) respond(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	case errors.Is(err, ErrPlayerNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// readJSON decodes an optional request body; an empty body leaves v as is.
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
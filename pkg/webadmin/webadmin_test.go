@@ -0,0 +1,205 @@
+package webadmin
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scaxe/scaxe-go/pkg/logger"
+)
+
+type fakeBackend struct {
+	kicked   []string
+	commands []string
+}
+
+func (b *fakeBackend) Status() Status {
+	return Status{Name: "test", Online: 1, MaxPlayers: 20, TPS: 20}
+}
+
+func (b *fakeBackend) Players() []Player {
+	return []Player{{Name: "Alice", Level: "world", X: 1, Y: 64, Z: 2}}
+}
+
+func (b *fakeBackend) Levels() []Level   { return []Level{{Name: "world", Default: true}} }
+func (b *fakeBackend) Plugins() []Plugin { return []Plugin{} }
+
+func (b *fakeBackend) Execute(command string) (string, error) {
+	b.commands = append(b.commands, command)
+	return "ran " + command + "\n", nil
+}
+
+func (b *fakeBackend) Kick(name, reason string) error {
+	if name != "Alice" {
+		return ErrPlayerNotFound
+	}
+	b.kicked = append(b.kicked, name+":"+reason)
+	return nil
+}
+
+func (b *fakeBackend) Ban(name, reason string) error    { return nil }
+func (b *fakeBackend) SetOp(name string, op bool) error { return nil }
+
+func request(t *testing.T, srv *httptest.Server, method, path, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var out map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&out)
+	return res.StatusCode, out
+}
+
+func TestAPI(t *testing.T) {
+	backend := &fakeBackend{}
+	srv := httptest.NewServer(NewServer(Config{Token: "s3cret"}, backend).Handler())
+	defer srv.Close()
+
+	tests := []struct {
+		method, path, token, body string
+		status                    int
+	}{
+		{"GET", "/api/status", "", "", http.StatusUnauthorized},
+		{"GET", "/api/status", "wrong", "", http.StatusUnauthorized},
+		{"GET", "/api/status?token=s3cret", "", "", http.StatusUnauthorized},
+		{"POST", "/api/command?token=s3cret", "", `{"command":"list"}`, http.StatusUnauthorized},
+		{"GET", "/api/status", "s3cret", "", http.StatusOK},
+		{"POST", "/api/players/Alice/kick", "s3cret", `{"reason":"spam"}`, http.StatusOK},
+		{"POST", "/api/players/Bob/kick", "s3cret", "", http.StatusNotFound},
+		{"POST", "/api/command", "s3cret", `{"command":"/list"}`, http.StatusOK},
+		{"POST", "/api/command", "s3cret", `{"command":" "}`, http.StatusBadRequest},
+		{"POST", "/api/command", "s3cret", `not json`, http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		if status, body := request(t, srv, tc.method, tc.path, tc.token, tc.body); status != tc.status {
+			t.Errorf("%s %s = %d %v, want %d", tc.method, tc.path, status, body, tc.status)
+		}
+	}
+
+	if len(backend.kicked) != 1 || backend.kicked[0] != "Alice:spam" {
+		t.Errorf("kicked = %v", backend.kicked)
+	}
+	if len(backend.commands) != 1 || backend.commands[0] != "list" {
+		t.Errorf("commands = %v", backend.commands)
+	}
+
+	res, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(page), "app.js") {
+		t.Errorf("UI returned %d", res.StatusCode)
+	}
+}
+
+func dialConsole(t *testing.T, srv *httptest.Server, token string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	req := "GET /api/console?token=" + token + " HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	res, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status %d", res.StatusCode)
+	}
+	if got := res.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Sec-WebSocket-Accept = %q", got)
+	}
+	return conn, r
+}
+
+func writeClientFrame(t *testing.T, conn net.Conn, text string) {
+	t.Helper()
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opText, 0x80 | byte(len(text))}
+	frame = append(frame, mask[:]...)
+	for i := 0; i < len(text); i++ {
+		frame = append(frame, text[i]^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readServerMessage(t *testing.T, r *bufio.Reader) consoleMessage {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	size := int(header[1] & 0x7f)
+	if size == 126 {
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		size = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	var msg consoleMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatalf("frame %q: %v", payload, err)
+	}
+	return msg
+}
+
+func TestConsole(t *testing.T) {
+	backend := &fakeBackend{}
+	admin := NewServer(Config{Token: "s3cret"}, backend)
+	admin.console.start()
+	defer admin.console.stop()
+	srv := httptest.NewServer(admin.Handler())
+	defer srv.Close()
+
+	logger.Info("before connect")
+	conn, r := dialConsole(t, srv, "s3cret")
+
+	seen := func(want consoleMessage) {
+		t.Helper()
+		for {
+			msg := readServerMessage(t, r)
+			if msg.Type == want.Type && strings.Contains(msg.Line, want.Line) {
+				return
+			}
+		}
+	}
+	seen(consoleMessage{Type: "log", Line: "before connect"})
+
+	logger.Info("after connect")
+	seen(consoleMessage{Type: "log", Line: "after connect"})
+
+	writeClientFrame(t, conn, "say hi")
+	seen(consoleMessage{Type: "output", Line: "ran say hi"})
+}